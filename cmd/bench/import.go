@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/dataset"
+)
+
+type importFlags struct {
+	queries  *string
+	qrels    *string
+	corpus   *string
+	rules    *string
+	urlRules stringList
+	sample   *int
+	seed     *int64
+	minGrade *int
+	keep     *bool
+	suite    *string
+	tags     *string
+	out      *string
+}
+
+// stringList is a flag that may be repeated; each use adds a value.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func parseImportFlags(args []string) (*importFlags, error) {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags := &importFlags{
+		queries:  fs.String("queries", "", "Queries file: BEIR JSONL or MS MARCO/TREC TSV (.gz is decompressed)"),
+		qrels:    fs.String("qrels", "", "Relevance judgments: BEIR TSV/JSONL or TREC qrels (.gz is decompressed)"),
+		corpus:   fs.String("corpus", "", "Optional JSONL corpus whose url fields map document IDs to URLs"),
+		rules:    fs.String("url-rules", "", "TOML file of [[url_rules]] match/url pairs mapping document IDs to URLs"),
+		sample:   fs.Int("sample", 0, "Import this many randomly drawn queries (0 = all)"),
+		seed:     fs.Int64("seed", 1, "Seed for -sample; the same seed draws the same queries"),
+		minGrade: fs.Int("min-grade", 1, "Lowest relevance grade that counts as relevant"),
+		keep:     fs.Bool("keep-unmapped", false, "Keep document IDs no corpus entry or rule maps to a URL; they match any result whose URL contains them"),
+		suite:    fs.String("suite", "", "Suite name prefixed to test names (default: the output file name)"),
+		tags:     fs.String("tags", "", "Comma-separated tags added to every imported test"),
+		out:      fs.String("out", "", "Suite file to write, for a config to include"),
+	}
+	fs.Var(&flags.urlRules, "url-rule", "Map document IDs to URLs as 'regexp=>template', e.g. '^(\\d+)$=>https://example.com/$1' (repeatable; applied after -url-rules)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *flags.queries == "" || *flags.qrels == "" || *flags.out == "" {
+		return nil, fmt.Errorf("import requires -queries, -qrels and -out")
+	}
+	if *flags.sample < 0 {
+		return nil, fmt.Errorf("sample must be >= 0")
+	}
+	return flags, nil
+}
+
+// runImport implements `import`: it turns an IR dataset's queries and qrels
+// into a suite file of search tests with graded expected URLs.
+func runImport(args []string) error {
+	flags, err := parseImportFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	queries, err := dataset.LoadQueries(*flags.queries)
+	if err != nil {
+		return err
+	}
+	qrels, err := dataset.LoadQrels(*flags.qrels)
+	if err != nil {
+		return err
+	}
+	mapper, err := urlMapper(flags, qrels)
+	if err != nil {
+		return err
+	}
+
+	tests, err := dataset.Import(queries, qrels, dataset.Options{
+		Sample:       *flags.sample,
+		Seed:         *flags.seed,
+		MinGrade:     *flags.minGrade,
+		URLs:         mapper,
+		KeepUnmapped: *flags.keep,
+		Tags:         splitList(*flags.tags),
+	})
+	if err != nil {
+		return fmt.Errorf("error importing %s: %w", *flags.queries, err)
+	}
+	if err := config.SaveSuite(*flags.out, *flags.suite, tests); err != nil {
+		return fmt.Errorf("error writing suite: %w", err)
+	}
+
+	fmt.Printf("✓ Imported %d of %d queries into %s\n", len(tests), len(queries), *flags.out)
+	if unmapped := countUnmapped(qrels, mapper, *flags.minGrade); unmapped > 0 {
+		if *flags.keep {
+			fmt.Printf("  Kept %d relevant document IDs without a URL; they match results whose URLs contain them.\n", unmapped)
+		} else {
+			fmt.Printf("  Dropped %d relevant documents without a URL; add -url-rule or -corpus to map them.\n", unmapped)
+		}
+	}
+	return nil
+}
+
+// countUnmapped counts the relevant judgments whose documents map to no URL.
+func countUnmapped(qrels []dataset.Judgment, mapper dataset.URLMapper, minGrade int) int {
+	if minGrade <= 0 {
+		minGrade = 1
+	}
+	unmapped := 0
+	for _, j := range qrels {
+		if _, ok := mapper.Map(j.DocID); !ok && j.Grade >= minGrade {
+			unmapped++
+		}
+	}
+	return unmapped
+}
+
+// urlMapper builds the document ID mapping from the corpus and rule flags.
+// Only the URLs of judged documents are read from the corpus.
+func urlMapper(flags *importFlags, qrels []dataset.Judgment) (dataset.URLMapper, error) {
+	var mapper dataset.URLMapper
+	if *flags.rules != "" {
+		rules, err := dataset.LoadURLRules(*flags.rules)
+		if err != nil {
+			return mapper, err
+		}
+		mapper.Rules = rules
+	}
+	for _, raw := range flags.urlRules {
+		rule, err := dataset.ParseURLRule(raw)
+		if err != nil {
+			return mapper, err
+		}
+		mapper.Rules = append(mapper.Rules, rule)
+	}
+	if *flags.corpus != "" {
+		judged := make(map[string]bool, len(qrels))
+		for _, j := range qrels {
+			judged[j.DocID] = true
+		}
+		urls, err := dataset.LoadCorpusURLs(*flags.corpus, judged)
+		if err != nil {
+			return mapper, err
+		}
+		mapper.Corpus = urls
+	}
+	return mapper, nil
+}
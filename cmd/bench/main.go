@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/lamim/SanityWebEval/internal/config"
 	"github.com/lamim/SanityWebEval/internal/debug"
 	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/notify"
 	"github.com/lamim/SanityWebEval/internal/progress"
 	"github.com/lamim/SanityWebEval/internal/providers"
 	"github.com/lamim/SanityWebEval/internal/providers/brave"
@@ -25,10 +27,14 @@ import (
 	"github.com/lamim/SanityWebEval/internal/providers/tavily"
 	"github.com/lamim/SanityWebEval/internal/quality"
 	"github.com/lamim/SanityWebEval/internal/report"
+	"github.com/lamim/SanityWebEval/internal/robustness"
+	"github.com/lamim/SanityWebEval/internal/slo"
+	"github.com/lamim/SanityWebEval/internal/tracing"
 )
 
 type cliFlags struct {
 	configPath       *string
+	profile          *string
 	outputDir        *string
 	providersFlag    *string
 	format           *string
@@ -43,25 +49,52 @@ type cliFlags struct {
 	includeLocal     *bool
 	qualityMode      *bool
 	includeJina      *bool
+	perturb          *string
+	faults           *bool
+	pricing          *string
+	reprice          *string
+	project          *string
+	debugFormat      *string
+	debugHAR         *string
+	junit            *string
+	events           *string
+	run              *string
+	tags             *string
+	skipTags         *string
+	types            *string
 }
 
 func parseFlags() *cliFlags {
 	return &cliFlags{
 		configPath:       flag.String("config", "config.toml", "Path to configuration file"),
+		profile:          flag.String("profile", "", "Apply the named [profiles.<name>] overrides of the config's general settings"),
 		outputDir:        flag.String("output", "", "Output directory for reports (overrides config)"),
-		providersFlag:    flag.String("providers", "all", "Providers to test: all, firecrawl, tavily, local, brave, exa, mixedbread, jina"),
-		format:           flag.String("format", "all", "Report format: all, html, md, json"),
+		providersFlag:    flag.String("providers", "all", "Providers to test: all, firecrawl, tavily, local, brave, exa, mixedbread, jina, or instance names from [providers.<name>] config sections"),
+		format:           flag.String("format", "all", "Report format: all, html, md, json, csv, parquet (csv and parquet are raw results exports and may be combined with all)"),
 		mode:             flag.String("mode", string(providers.ModeNormalized), "Benchmark mode: normalized or native"),
 		repeats:          flag.Int("repeats", 3, "How many repeated runs per test/provider"),
 		capabilityPolicy: flag.String("capability-policy", "strict", "Normalized-mode policy for emulated operations: strict or tagged"),
-		noProgress:       flag.Bool("no-progress", false, "Disable progress bar (useful for CI)"),
+		noProgress:       flag.Bool("no-progress", false, "Disable the live dashboard and print plain progress lines (automatic when stdout is not a terminal)"),
 		debugMode:        flag.Bool("debug", false, "Enable debug logging with request/response data"),
 		debugFullMode:    flag.Bool("debug-full", false, "Enable full debug logging with complete request/response bodies and timing breakdown"),
+		debugFormat:      flag.String("debug-format", "json", "Debug log format: json, har (HTTP Archive 1.2) or all"),
+		debugHAR:         flag.String("debug-har", "", "Convert the provider logs in an existing debug directory to HAR files and exit"),
 		quickMode:        flag.Bool("quick", false, "Run quick test with reduced test set and shorter timeouts"),
 		noSearch:         flag.Bool("no-search", false, "Exclude search tests"),
+		run:              flag.String("run", "", "Run only tests whose name matches this regular expression"),
+		tags:             flag.String("tags", "", "Run only tests with any of these comma-separated tags"),
+		skipTags:         flag.String("skip-tags", "", "Skip tests with any of these comma-separated tags"),
+		types:            flag.String("types", "", "Run only these comma-separated test types: search, extract, crawl, structured_extract"),
 		includeLocal:     flag.Bool("local", false, "Include local provider (excluded by default)"),
 		qualityMode:      flag.Bool("quality", false, "Enable relevance/scoring metrics (search model-assisted + extract/crawl heuristics; requires EMBEDDING_* and RERANKER_* env vars)"),
 		includeJina:      flag.Bool("jina", false, "Include Jina provider (excluded by default due to high cost and slow search)"),
+		faults:           flag.Bool("faults", false, "Route provider APIs through a local fault-injection proxy using the [faults] rates from the config"),
+		perturb:          flag.String("perturb", "", "Query perturbations run after each search test: all or comma-separated typo, paraphrase, case, reorder"),
+		pricing:          flag.String("pricing", "", "Pricing profile TOML file (overrides the config's [pricing] section)"),
+		reprice:          flag.String("reprice", "", "Re-price an existing report.json under the pricing profile and write new reports instead of running tests"),
+		project:          flag.String("project", "", "Monthly workload to project costs for, e.g. searches=2M,extracts=50k,crawl_pages=10k (overrides [projection])"),
+		junit:            flag.String("junit", "", "Write assertion results as JUnit XML to this file (default: <output>/junit.xml when the config has assertions)"),
+		events:           flag.String("events", "", "Write run progress as JSON Lines events to this file, or - for stdout (other output then goes to stderr)"),
 	}
 }
 
@@ -84,15 +117,40 @@ func loadEnvFile() {
 	}
 }
 
+// subcommands run instead of a one-shot benchmark when named as the first
+// argument.
+var subcommands = map[string]func(args []string) error{
+	"import":   runImport,
+	"monitor":  runMonitor,
+	"serve":    runServe,
+	"validate": runValidate,
+}
+
 func main() {
+	if len(os.Args) > 1 && subcommands[os.Args[1]] != nil {
+		if err := subcommands[os.Args[1]](os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	flags := parseFlags()
 	flag.Parse()
 
 	loadEnvFile()
 
-	providerNames, err := parseProviders(*flags.providersFlag, *flags.includeLocal, *flags.includeJina)
+	if *flags.debugHAR != "" {
+		if err := convertDebugToHAR(*flags.debugHAR); err != nil {
+			fmt.Fprintf(os.Stderr, "Error converting debug logs: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	debugFormat, err := debug.ParseFormat(*flags.debugFormat)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing providers: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error parsing debug format: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -102,27 +160,54 @@ func main() {
 		os.Exit(1)
 	}
 
-	cfg, err := config.Load(*flags.configPath)
+	cfg, err := config.LoadProfile(*flags.configPath, *flags.profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Named instances from [providers.<name>] sections are selectable too.
+	providerNames, err := parseProviders(*flags.providersFlag, *flags.includeLocal, *flags.includeJina, cfg.Instances()...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing providers: %v\n", err)
+		os.Exit(1)
+	}
+
 	if *flags.outputDir != "" {
 		cfg.General.OutputDir = *flags.outputDir
 	}
 
-	if *flags.quickMode {
-		cfg = applyQuickMode(cfg)
+	pricing, err := resolvePricing(cfg, *flags.pricing)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading pricing: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Apply test type filters
-	if *flags.noSearch {
-		cfg.Tests = filterTests(cfg.Tests, *flags.noSearch)
-		if len(cfg.Tests) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: no tests match the specified filters\n")
+	workload, err := resolveWorkload(cfg, *flags.project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing projection workload: %v\n", err)
+		os.Exit(1)
+	}
+
+	notifier, err := notify.NewSender(cfg.Notifications)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring notifications: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *flags.reprice != "" {
+		if err := repriceReport(*flags.reprice, pricing, workload, formats, cfg.General.OutputDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error re-pricing report: %v\n", err)
 			os.Exit(1)
 		}
+		return
+	}
+
+	resolved := cfg
+	cfg, selection, err := selectTests(cfg, flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error selecting tests: %v\n", err)
+		os.Exit(1)
 	}
 
 	finalOutputDir, err := ensureOutputDir(cfg.General.OutputDir)
@@ -130,11 +215,29 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
 		os.Exit(1)
 	}
+	// Keep the resolved config, before test selection, next to the reports so
+	// the run can be reproduced with -config <output>/config.toml.
+	if err := resolved.Save(filepath.Join(finalOutputDir, "config.toml")); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save resolved config: %v\n", err)
+	}
 	cfg.General.OutputDir = finalOutputDir
 
+	// Open the event stream before anything is printed, since "-" moves the
+	// human-readable output to stderr.
+	var eventLog *evaluator.EventLog
+	closeEvents := func() error { return nil }
+	if *flags.events != "" {
+		eventLog, closeEvents, err = openEventLog(*flags.events)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening event stream: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Enable debug mode if debug-full is set
 	enableDebug := *flags.debugMode || *flags.debugFullMode
 	debugLogger := debug.NewLogger(enableDebug, *flags.debugFullMode, cfg.General.OutputDir)
+	_ = debugLogger.SetFormat(debugFormat) // validated above
 
 	// Initialize quality scorer if enabled
 	var scorer *quality.Scorer
@@ -160,6 +263,10 @@ func main() {
 		fmt.Printf("🚫 No-search mode: running %d non-search tests\n\n", len(cfg.Tests))
 	}
 
+	if selection.Run != "" || len(selection.Tags) > 0 || len(selection.SkipTags) > 0 || len(selection.Types) > 0 {
+		fmt.Printf("🔎 Selected %s\n\n", selection)
+	}
+
 	if enableDebug {
 		if *flags.debugFullMode {
 			fmt.Printf("🐛 Debug-full mode enabled: complete bodies + timing breakdown\n")
@@ -169,7 +276,7 @@ func main() {
 		}
 	}
 
-	provs := initializeProviders(providerNames, debugLogger)
+	provs := initializeProviders(providerNames, cfg, debugLogger)
 
 	if len(provs) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: no providers initialized. Check API keys for selected providers: %s\n", strings.Join(providerNames, ", "))
@@ -193,6 +300,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	perturbations, err := robustness.ParsePerturbationKinds(*flags.perturb)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing perturbations: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Calculate total tests
 	totalTests := len(cfg.Tests) * len(provs) * *flags.repeats
 
@@ -202,13 +315,29 @@ func main() {
 		progressProviderNames = append(progressProviderNames, p.Name())
 	}
 
-	// Create progress manager
-	prog := progress.NewManager(totalTests, progressProviderNames, !*flags.noProgress)
+	// Show the live dashboard on interactive terminals; CI logs and pipes get plain lines
+	prog := progress.NewManager(totalTests, progressProviderNames, !*flags.noProgress && progress.DashboardSupported())
 
 	runnerOpts := evaluator.RunnerOptions{
 		Mode:             mode,
 		Repeats:          *flags.repeats,
 		CapabilityPolicy: capabilityPolicy,
+		Perturbations:    perturbations,
+		Pricing:          &pricing,
+	}
+	if *flags.faults {
+		faultCfg, err := cfg.Faults.ProxyConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing faults: %v\n", err)
+			os.Exit(1)
+		}
+		runnerOpts.Faults = &faultCfg
+		fmt.Println("💥 Fault injection enabled: provider APIs run through a local proxy, one test per provider at a time")
+		fmt.Println()
+	}
+	runnerOpts.Tracer = startTracing()
+	if eventLog != nil {
+		runnerOpts.OnEvent = eventLog.Write
 	}
 
 	// Create runner with progress manager, debug logger, and optional quality scorer
@@ -222,6 +351,15 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error running benchmarks: %v\n", err)
 		os.Exit(1)
 	}
+	shutdownTracing(ctx, runnerOpts.Tracer)
+	if eventLog != nil {
+		if err := eventLog.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: event stream incomplete: %v\n", err)
+		}
+	}
+	if err := closeEvents(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close event stream: %v\n", err)
+	}
 
 	// Finalize debug logging
 	if enableDebug {
@@ -233,7 +371,107 @@ func main() {
 	}
 
 	// Generate reports
-	generateReports(formats, runner.GetCollector(), cfg.General.OutputDir)
+	collector := runner.GetCollector()
+	collector.SetWorkload(workload)
+	collector.SetSelection(selection)
+	generateReports(formats, collector, cfg.General.OutputDir)
+
+	passed := checkAssertions(cfg, collector.GetResults(), *flags.junit)
+	sendNotifications(ctx, notifier, cfg, collector)
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+// sendNotifications posts the run summary, and any golden regressions and
+// assertion violations, to the configured webhooks. Failures are warnings.
+func sendNotifications(ctx context.Context, sender *notify.Sender, cfg *config.Config, collector *benchmetrics.Collector) {
+	if !cfg.Notifications.Enabled() {
+		return
+	}
+	notifications, err := notify.ForRun(cfg, collector)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to check golden baseline: %v\n", err)
+	}
+	for _, n := range notifications {
+		if !cfg.Notifications.Wants(n.Event) {
+			continue
+		}
+		if err := sender.Send(ctx, n); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to send %s notification: %v\n", n.Event, err)
+			continue
+		}
+		fmt.Printf("🔔 Sent %s notification\n", n.Event)
+	}
+}
+
+// checkAssertions evaluates the config's assertions against the results and
+// writes them as JUnit XML. It reports whether every assertion held; with no
+// assertions and no -junit path it does nothing.
+func checkAssertions(cfg *config.Config, results []benchmetrics.Result, junitPath string) bool {
+	if !cfg.HasAssertions() && junitPath == "" {
+		return true
+	}
+	cases := slo.Evaluate(cfg, results)
+	if junitPath == "" {
+		junitPath = filepath.Join(cfg.General.OutputDir, "junit.xml")
+	}
+	if err := slo.WriteJUnitFile(junitPath, cases); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JUnit report: %v\n", err)
+	} else {
+		fmt.Printf("✓ Generated JUnit report: %s\n", junitPath)
+	}
+
+	failed := slo.Failures(cases)
+	if failed == 0 {
+		fmt.Printf("\n✅ Assertions passed for all %d test/provider pairs\n", len(cases))
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "\n❌ Assertions failed for %d of %d test/provider pairs:\n", failed, len(cases))
+	for _, c := range cases {
+		for _, failure := range c.Failures {
+			fmt.Fprintf(os.Stderr, "  [%s] %s: %s\n", c.Provider, c.Test, failure)
+		}
+	}
+	return false
+}
+
+// openEventLog opens the -events destination and returns a func that closes
+// it. For "-" the events go to stdout and os.Stdout is pointed at stderr, so
+// the banner, progress lines and summary cannot interleave with the stream.
+func openEventLog(path string) (*evaluator.EventLog, func() error, error) {
+	if path == "-" {
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		return evaluator.NewEventLog(stdout), func() error { return nil }, nil
+	}
+	// #nosec G304 - the path is chosen by the user running the benchmark
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create event file: %w", err)
+	}
+	return evaluator.NewEventLog(f), f.Close, nil
+}
+
+// startTracing creates a tracer from the OTEL_* environment variables, or
+// returns nil when tracing is not configured.
+func startTracing() *tracing.Tracer {
+	tracer, err := tracing.FromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error configuring tracing: %v\n", err)
+		os.Exit(1)
+	}
+	if tracer != nil {
+		fmt.Printf("🔭 Tracing enabled: exporting spans to %s\n\n", tracer.Endpoint())
+	}
+	return tracer
+}
+
+// shutdownTracing exports any spans still buffered by the tracer.
+func shutdownTracing(ctx context.Context, tracer *tracing.Tracer) {
+	if err := tracer.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to export traces: %v\n", err)
+	}
 }
 
 func printBanner() {
@@ -271,87 +509,114 @@ func printSummary(collector *benchmetrics.Collector) {
 	fmt.Println("View detailed results in the output directory.")
 }
 
-func initializeProviders(providerNames []string, debugLogger *debug.Logger) []providers.Provider {
+func initializeProviders(providerNames []string, cfg *config.Config, debugLogger *debug.Logger) []providers.Provider {
 	var provs []providers.Provider
 
 	for _, name := range providerNames {
-		switch name {
-		case "firecrawl":
-			client, err := firecrawl.NewClient()
-			debugLogger.LogProviderInit("firecrawl", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Firecrawl: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Firecrawl provider\n")
+		providerType := cfg.ProviderType(name)
+		settings := cfg.ProviderSettings(name)
 
+		var (
+			client providers.Provider
+			label  string
+			err    error
+		)
+		switch providerType {
+		case "firecrawl":
+			client, err = newProvider(firecrawl.NewClientWithSettings, settings)
+			label = "Firecrawl"
 		case "tavily":
-			client, err := tavily.NewClient()
-			debugLogger.LogProviderInit("tavily", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Tavily: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Tavily provider\n")
-
+			client, err = newProvider(tavily.NewClientWithSettings, settings)
+			label = "Tavily"
 		case "local":
-			client, err := local.NewClient()
-			debugLogger.LogProviderInit("local", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Local crawler: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Local crawler provider (no API key required)\n")
-			fmt.Printf("  Note: Local provider does not support search operations (extract/crawl only)\n")
-
+			client, err = newProvider(local.NewClientWithSettings, settings)
+			label = "Local crawler"
 		case "brave":
-			client, err := brave.NewClient()
-			debugLogger.LogProviderInit("brave", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Brave: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Brave Search provider\n")
-
+			client, err = newProvider(brave.NewClientWithSettings, settings)
+			label = "Brave Search"
 		case "exa":
-			client, err := exa.NewClient()
-			debugLogger.LogProviderInit("exa", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Exa: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Exa AI provider\n")
-
+			client, err = newProvider(exa.NewClientWithSettings, settings)
+			label = "Exa AI"
 		case "mixedbread":
-			client, err := mixedbread.NewClient()
-			debugLogger.LogProviderInit("mixedbread", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Mixedbread: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Mixedbread AI provider\n")
-
+			client, err = newProvider(mixedbread.NewClientWithSettings, settings)
+			label = "Mixedbread AI"
 		case "jina":
-			client, err := jina.NewClient()
-			debugLogger.LogProviderInit("jina", err)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to initialize Jina: %v\n", err)
-				continue
-			}
-			provs = append(provs, client)
-			fmt.Printf("✓ Initialized Jina AI provider (Reader + Search)\n")
+			client, err = newProvider(jina.NewClientWithSettings, settings)
+			label = "Jina AI"
+		default:
+			continue
+		}
+
+		debugLogger.LogProviderInit(name, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize %s: %v\n", instanceLabel(label, name, providerType), err)
+			continue
+		}
+		provs = append(provs, client)
+		fmt.Printf("✓ Initialized %s provider%s\n", instanceLabel(label, name, providerType), providerInitNote(providerType))
+		if urls := describeBaseURLs(cfg, name); urls != "" {
+			fmt.Printf("  Base URL: %s\n", urls)
+		}
+		if providerType == "local" {
+			fmt.Printf("  Note: Local provider does not support search operations (extract/crawl only)\n")
 		}
 	}
 
 	return provs
 }
 
+// newProvider adapts a typed client constructor to providers.Provider,
+// keeping a failed constructor's nil client from becoming a non-nil interface.
+func newProvider[C providers.Provider](constructor func(providers.Settings) (C, error), settings providers.Settings) (providers.Provider, error) {
+	client, err := constructor(settings)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// instanceLabel names a provider in init messages, adding the instance name
+// for named instances such as "Firecrawl (firecrawl-selfhosted)".
+func instanceLabel(label, name, providerType string) string {
+	if name == providerType {
+		return label
+	}
+	return fmt.Sprintf("%s (%s)", label, name)
+}
+
+func providerInitNote(providerType string) string {
+	switch providerType {
+	case "local":
+		return " (no API key required)"
+	case "jina":
+		return " (Reader + Search)"
+	default:
+		return ""
+	}
+}
+
+// describeBaseURLs lists base URLs configured for a provider, or "" when it
+// uses the defaults.
+func describeBaseURLs(cfg *config.Config, name string) string {
+	p, ok := cfg.Providers[name]
+	if !ok {
+		return ""
+	}
+	var parts []string
+	if p.BaseURL != "" {
+		parts = append(parts, p.BaseURL)
+	}
+	endpoints := make([]string, 0, len(p.BaseURLs))
+	for endpoint := range p.BaseURLs {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		parts = append(parts, endpoint+"="+p.BaseURLs[endpoint])
+	}
+	return strings.Join(parts, ", ")
+}
+
 func generateReports(formats []string, collector *benchmetrics.Collector, outputDir string) {
 	fmt.Println("\nGenerating reports...")
 	gen := report.NewGenerator(collector, outputDir)
@@ -376,6 +641,18 @@ func generateReports(formats []string, collector *benchmetrics.Collector, output
 			} else {
 				fmt.Printf("✓ Generated JSON report: %s/report.json\n", outputDir)
 			}
+		case "csv":
+			if err := gen.GenerateCSV(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating CSV export: %v\n", err)
+			} else {
+				fmt.Printf("✓ Generated CSV export: %s/results.csv\n", outputDir)
+			}
+		case "parquet":
+			if err := gen.GenerateParquet(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error generating Parquet export: %v\n", err)
+			} else {
+				fmt.Printf("✓ Generated Parquet export: %s/results.parquet\n", outputDir)
+			}
 		case "all":
 			if err := gen.GenerateAll(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error generating reports: %v\n", err)
@@ -388,6 +665,66 @@ func generateReports(formats []string, collector *benchmetrics.Collector, output
 	printSummary(collector)
 }
 
+// resolvePricing returns the pricing profile from the -pricing file when
+// given, otherwise from the config's [pricing] section.
+func resolvePricing(cfg *config.Config, pricingFile string) (benchmetrics.PricingProfile, error) {
+	if pricingFile == "" {
+		return cfg.Pricing.Profile()
+	}
+	profile, err := config.LoadPricingProfile(pricingFile)
+	if err != nil {
+		return benchmetrics.PricingProfile{}, err
+	}
+	return benchmetrics.DefaultPricingProfile().Merge(profile), nil
+}
+
+// convertDebugToHAR writes HAR files for the provider logs in a debug directory.
+func convertDebugToHAR(dir string) error {
+	written, err := debug.ConvertDirToHAR(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range written {
+		fmt.Printf("✓ HAR written: %s\n", path)
+	}
+	return nil
+}
+
+// resolveWorkload returns the projection workload from the -project flag
+// when given, otherwise from the config's [projection] section.
+func resolveWorkload(cfg *config.Config, project string) (benchmetrics.Workload, error) {
+	if strings.TrimSpace(project) == "" {
+		return cfg.Projection, nil
+	}
+	return benchmetrics.ParseWorkload(project)
+}
+
+// repriceReport prices an earlier run's results under a pricing profile and
+// writes new reports, without re-running any tests. A non-zero workload
+// replaces the one recorded in the report.
+func repriceReport(reportPath string, pricing benchmetrics.PricingProfile, workload benchmetrics.Workload, formats []string, outputBase string) error {
+	collector, err := report.LoadJSON(reportPath)
+	if err != nil {
+		return err
+	}
+	if !workload.IsZero() {
+		collector.SetWorkload(workload)
+	}
+	previous := "unknown"
+	if profile, ok := collector.PricingProfile(); ok {
+		previous = profile.Name
+	}
+	collector.Reprice(pricing)
+
+	outputDir, err := ensureOutputDir(outputBase)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	fmt.Printf("💲 Re-priced %d results from %s: %s → %s\n", len(collector.GetResults()), reportPath, previous, pricing.Name)
+	generateReports(formats, collector, outputDir)
+	return nil
+}
+
 // initializeQualityScorer creates a quality scorer from environment variables
 func initializeQualityScorer() (*quality.Scorer, error) {
 	// Check required environment variables
@@ -422,7 +759,9 @@ func initializeQualityScorer() (*quality.Scorer, error) {
 	return scorer, nil
 }
 
-func parseProviders(s string, includeLocal bool, includeJina bool) ([]string, error) {
+// parseProviders resolves the -providers flag. Named instances configured in
+// [providers.<name>] sections are valid names and are included in "all".
+func parseProviders(s string, includeLocal bool, includeJina bool, instances ...string) ([]string, error) {
 	// Default providers excludes Local (opt-in, no API key needed) and Jina (opt-in, high cost).
 	defaultProviders := []string{"firecrawl", "tavily", "brave", "exa", "mixedbread"}
 	validProviders := map[string]struct{}{
@@ -435,6 +774,10 @@ func parseProviders(s string, includeLocal bool, includeJina bool) ([]string, er
 		"jina":       {},
 	}
 	allNames := []string{"firecrawl", "tavily", "local", "brave", "exa", "mixedbread", "jina"}
+	for _, instance := range instances {
+		validProviders[instance] = struct{}{}
+		allNames = append(allNames, instance)
+	}
 
 	input := strings.ToLower(strings.TrimSpace(s))
 	if input == "" {
@@ -450,6 +793,7 @@ func parseProviders(s string, includeLocal bool, includeJina bool) ([]string, er
 		if includeJina {
 			selected = append(selected, "jina")
 		}
+		selected = append(selected, instances...)
 	} else {
 		seen := make(map[string]struct{})
 		var invalid []string
@@ -480,17 +824,22 @@ func parseProviders(s string, includeLocal bool, includeJina bool) ([]string, er
 	return selected, nil
 }
 
+// validFormatsHelp lists the -format values for error messages.
+const validFormatsHelp = "all, html, md, json, csv, parquet"
+
 func parseFormats(s string) ([]string, error) {
 	validFormats := map[string]struct{}{
-		"all":  {},
-		"html": {},
-		"md":   {},
-		"json": {},
+		"all":     {},
+		"html":    {},
+		"md":      {},
+		"json":    {},
+		"csv":     {},
+		"parquet": {},
 	}
 
 	input := strings.ToLower(strings.TrimSpace(s))
 	if input == "" {
-		return nil, fmt.Errorf("format cannot be empty (valid values: all, html, md, json)")
+		return nil, fmt.Errorf("format cannot be empty (valid values: %s)", validFormatsHelp)
 	}
 
 	seen := make(map[string]struct{})
@@ -501,7 +850,7 @@ func parseFormats(s string) ([]string, error) {
 			return nil, fmt.Errorf("format list contains an empty entry")
 		}
 		if _, ok := validFormats[f]; !ok {
-			return nil, fmt.Errorf("invalid format: %s (valid values: all, html, md, json)", f)
+			return nil, fmt.Errorf("invalid format: %s (valid values: %s)", f, validFormatsHelp)
 		}
 		if _, ok := seen[f]; ok {
 			continue
@@ -510,12 +859,13 @@ func parseFormats(s string) ([]string, error) {
 		formats = append(formats, f)
 	}
 
-	if len(formats) == 1 && formats[0] == "all" {
-		return formats, nil
-	}
-
+	// The raw results exports are not part of 'all', so they may be added to it.
 	if _, hasAll := seen["all"]; hasAll {
-		return nil, fmt.Errorf("format 'all' cannot be combined with other formats")
+		for _, f := range formats {
+			if f != "all" && f != "csv" && f != "parquet" {
+				return nil, fmt.Errorf("format 'all' cannot be combined with %s (only with csv or parquet)", f)
+			}
+		}
 	}
 
 	return formats, nil
@@ -553,19 +903,44 @@ func ensureOutputDir(baseDir string) (string, error) {
 	return sessionDir, nil
 }
 
-// filterTests filters tests based on no-search flag
-func filterTests(tests []config.TestConfig, noSearch bool) []config.TestConfig {
-	if !noSearch {
-		return tests
+// selectTests narrows cfg to the tests chosen by -run, -tags, -skip-tags,
+// -types and -no-search, then applies -quick to what is left. It returns the
+// selection so the reports can record it.
+func selectTests(cfg *config.Config, flags *cliFlags) (*config.Config, benchmetrics.Selection, error) {
+	selection := benchmetrics.Selection{
+		Run:        *flags.run,
+		Tags:       splitList(*flags.tags),
+		SkipTags:   splitList(*flags.skipTags),
+		Types:      splitList(*flags.types),
+		NoSearch:   *flags.noSearch,
+		Quick:      *flags.quickMode,
+		Configured: len(cfg.Tests),
+	}
+	tests, err := config.SelectTests(cfg.Tests, selection)
+	if err != nil {
+		return nil, selection, err
 	}
+	selected := *cfg
+	selected.Tests = tests
+	if selection.Quick {
+		selected = *applyQuickMode(&selected)
+	}
+	if len(selected.Tests) == 0 {
+		return nil, selection, fmt.Errorf("no tests match the specified filters")
+	}
+	selection.Selected = len(selected.Tests)
+	return &selected, selection, nil
+}
 
-	var filtered []config.TestConfig
-	for _, test := range tests {
-		if test.Type != "search" {
-			filtered = append(filtered, test)
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var values []string
+	for _, raw := range strings.Split(s, ",") {
+		if value := strings.TrimSpace(raw); value != "" {
+			values = append(values, value)
 		}
 	}
-	return filtered
+	return values
 }
 
 func intPtr(v int) *int {
@@ -583,18 +958,13 @@ func cloneProviderConcurrency(overrides map[string]int) map[string]int {
 	return cloned
 }
 
-// applyQuickMode modifies the configuration for quick testing
+// applyQuickMode returns a copy of the configuration for quick testing: only
+// the tests and the timeout change, every other section is kept.
 func applyQuickMode(cfg *config.Config) *config.Config {
-	// Create a copy of the config
-	quickCfg := &config.Config{
-		General: config.GeneralConfig{
-			Concurrency:         cfg.General.Concurrency,
-			ProviderConcurrency: cloneProviderConcurrency(cfg.General.ProviderConcurrency),
-			Timeout:             "30s",
-			OutputDir:           cfg.General.OutputDir,
-		},
-		Tests: []config.TestConfig{},
-	}
+	quickCfg := *cfg
+	quickCfg.General.ProviderConcurrency = cloneProviderConcurrency(cfg.General.ProviderConcurrency)
+	quickCfg.General.Timeout = "30s"
+	quickCfg.Tests = []config.TestConfig{}
 
 	// Select up to 3 tests: one of each type (search, extract, crawl)
 	var hasSearch, hasExtract, hasCrawl bool
@@ -641,5 +1011,5 @@ func applyQuickMode(cfg *config.Config) *config.Config {
 		}
 	}
 
-	return quickCfg
+	return &quickCfg
 }
@@ -5,9 +5,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/lamim/SanityWebEval/internal/benchmetrics"
 	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/debug"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/local"
 )
 
 func TestParseProviders_All(t *testing.T) {
@@ -124,6 +129,54 @@ func TestParseProviders_ExplicitJinaWithoutFlag(t *testing.T) {
 	}
 }
 
+func TestParseProviders_NamedInstances(t *testing.T) {
+	result, err := parseProviders("all", false, false, "firecrawl-selfhosted")
+	if err != nil {
+		t.Fatalf("parseProviders returned error: %v", err)
+	}
+	if len(result) != 6 || result[5] != "firecrawl-selfhosted" {
+		t.Fatalf("expected configured instance appended to 'all', got %v", result)
+	}
+
+	result, err = parseProviders("firecrawl, FIRECRAWL-SELFHOSTED", false, false, "firecrawl-selfhosted")
+	if err != nil {
+		t.Fatalf("parseProviders returned error: %v", err)
+	}
+	if len(result) != 2 || result[1] != "firecrawl-selfhosted" {
+		t.Fatalf("expected [firecrawl firecrawl-selfhosted], got %v", result)
+	}
+
+	if _, err := parseProviders("firecrawl-selfhosted", false, false); err == nil {
+		t.Fatal("expected error for an instance that is not configured")
+	}
+}
+
+func TestInitializeProviders_NamedInstance(t *testing.T) {
+	t.Setenv("SELFHOSTED_FIRECRAWL_KEY", "local-key")
+	cfg := &config.Config{Providers: map[string]config.ProviderConfig{
+		"firecrawl-selfhosted": {
+			Type:      "firecrawl",
+			BaseURL:   "http://127.0.0.1:3002/v2",
+			APIKeyEnv: "SELFHOSTED_FIRECRAWL_KEY",
+		},
+	}}
+
+	provs := initializeProviders([]string{"firecrawl-selfhosted", "local"}, cfg, debug.NewLogger(false, false, t.TempDir()))
+	if len(provs) != 2 {
+		t.Fatalf("expected 2 providers, got %d", len(provs))
+	}
+	if provs[0].Name() != "firecrawl-selfhosted" || providers.TypeOf(provs[0]) != "firecrawl" {
+		t.Errorf("unexpected instance %s (%s)", provs[0].Name(), providers.TypeOf(provs[0]))
+	}
+	urls := provs[0].(providers.BaseURLOverrider).BaseURLs()
+	if urls[providers.EndpointAPI] != "http://127.0.0.1:3002/v2" {
+		t.Errorf("unexpected base URL: %v", urls)
+	}
+	if provs[1].Name() != "local" {
+		t.Errorf("expected local provider, got %s", provs[1].Name())
+	}
+}
+
 func TestApplyQuickMode_NormalizesCrawlDepthToOne(t *testing.T) {
 	cfg := &config.Config{
 		General: config.GeneralConfig{
@@ -171,6 +224,46 @@ func TestApplyQuickMode_NormalizesCrawlDepthToOne(t *testing.T) {
 	}
 }
 
+func TestMonitorConfig_UsesQuickSubsetUnlessFull(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{Concurrency: 2, Timeout: "90s", FixturesDir: "fixtures"},
+		Tests: []config.TestConfig{
+			{Name: "search 1", Type: "search", Query: "q"},
+			{Name: "search 2", Type: "search", Query: "q2"},
+			{Name: "extract", Type: "extract", URL: "https://example.com"},
+		},
+	}
+
+	reduced := monitorConfig(cfg, false)
+	if len(reduced.Tests) != 2 || reduced.General.Timeout != "30s" {
+		t.Fatalf("expected quick subset with 30s timeout, got %d tests, %s", len(reduced.Tests), reduced.General.Timeout)
+	}
+	if reduced.General.FixturesDir != "fixtures" {
+		t.Error("expected the rest of the config to be kept")
+	}
+	if len(cfg.Tests) != 3 || cfg.General.Timeout != "90s" {
+		t.Error("expected the original config to be left unchanged")
+	}
+	if full := monitorConfig(cfg, true); len(full.Tests) != 3 {
+		t.Errorf("expected all tests with -full, got %d", len(full.Tests))
+	}
+}
+
+func TestParseMonitorFlags(t *testing.T) {
+	flags, err := parseMonitorFlags([]string{"-interval", "30s", "-listen", "127.0.0.1:0", "-cycles", "2"})
+	if err != nil {
+		t.Fatalf("parseMonitorFlags failed: %v", err)
+	}
+	if *flags.interval != 30*time.Second || *flags.listen != "127.0.0.1:0" || *flags.cycles != 2 {
+		t.Errorf("unexpected flags: interval %s, listen %s, cycles %d", *flags.interval, *flags.listen, *flags.cycles)
+	}
+	for _, args := range [][]string{{"-interval", "0s"}, {"-cycles", "-1"}, {"-unknown"}} {
+		if _, err := parseMonitorFlags(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
 func TestParseFormats_All(t *testing.T) {
 	result, err := parseFormats("all")
 	if err != nil {
@@ -227,6 +320,20 @@ func TestParseFormats_AllWithOthers(t *testing.T) {
 	}
 }
 
+func TestParseFormats_AllWithExports(t *testing.T) {
+	result, err := parseFormats("all,csv,parquet")
+	if err != nil {
+		t.Fatalf("parseFormats returned error: %v", err)
+	}
+	expected := []string{"all", "csv", "parquet"}
+	if strings.Join(result, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if _, err := parseFormats("all,csv,md"); err == nil {
+		t.Fatal("expected error when combining all with a report format")
+	}
+}
+
 func TestParseMode(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -486,3 +593,256 @@ func TestPrintSummary_EmptyCollector(t *testing.T) {
 	c := benchmetrics.NewCollector()
 	printSummary(c)
 }
+
+func TestCheckAssertions(t *testing.T) {
+	outputDir := t.TempDir()
+	cfg, err := config.Parse([]byte(`
+[general]
+output_dir = "`+filepath.ToSlash(outputDir)+`"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+
+[tests.assertions]
+must_succeed = true
+`), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	passing := []benchmetrics.Result{{TestName: "Search", TestType: "search", Provider: "exa", Success: true}}
+	if !checkAssertions(cfg, passing, "") {
+		t.Error("expected assertions to pass")
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "junit.xml"))
+	if err != nil || !strings.Contains(string(data), `<testcase name="Search" classname="exa.search"`) {
+		t.Errorf("expected default JUnit report, got %v:\n%s", err, data)
+	}
+
+	failing := []benchmetrics.Result{{TestName: "Search", TestType: "search", Provider: "exa", Error: "HTTP 500"}}
+	junitPath := filepath.Join(t.TempDir(), "results.xml")
+	if checkAssertions(cfg, failing, junitPath) {
+		t.Error("expected assertions to fail")
+	}
+	if _, err := os.Stat(junitPath); err != nil {
+		t.Errorf("expected JUnit report at the -junit path: %v", err)
+	}
+
+	cfg.Tests[0].Assertions = config.AssertionsConfig{}
+	if !checkAssertions(cfg, failing, "") {
+		t.Error("expected no check without assertions")
+	}
+}
+
+func TestApplyQuickMode_KeepsAssertions(t *testing.T) {
+	mustSucceed := true
+	cfg := &config.Config{
+		Assertions: config.AssertionsConfig{Assertions: config.Assertions{MustSucceed: &mustSucceed}},
+		Tests:      []config.TestConfig{{Name: "search", Type: "search", Query: "q"}},
+	}
+	if quick := applyQuickMode(cfg); !quick.HasAssertions() {
+		t.Error("expected quick mode to keep the global assertions")
+	}
+}
+
+func TestApplyQuickMode_KeepsNotifications(t *testing.T) {
+	cfg := &config.Config{
+		Notifications: config.NotificationsConfig{Webhooks: []config.WebhookConfig{{URL: "https://hooks.example.com"}}},
+		Tests:         []config.TestConfig{{Name: "search", Type: "search", Query: "q"}},
+	}
+	if quick := applyQuickMode(cfg); !quick.Notifications.Enabled() {
+		t.Error("expected quick mode to keep the webhooks")
+	}
+}
+
+func TestApplyQuickMode_KeepsProviders(t *testing.T) {
+	cfg := &config.Config{
+		Providers: map[string]config.ProviderConfig{
+			"exa-eu": {Type: "exa", BaseURL: "https://eu.exa.example"},
+		},
+		Tests: []config.TestConfig{{Name: "search", Type: "search", Query: "q"}},
+	}
+	quick := applyQuickMode(cfg)
+	if got := quick.Providers["exa-eu"]; got.Type != "exa" || got.BaseURL != "https://eu.exa.example" {
+		t.Errorf("expected quick mode to keep the provider instances, got %+v", quick.Providers)
+	}
+	if len(quick.Tests) != 1 || quick.General.Timeout != "30s" {
+		t.Errorf("expected the quick test subset with a 30s timeout, got %d tests, %s", len(quick.Tests), quick.General.Timeout)
+	}
+}
+
+func TestApplyQuickMode_KeepsFixturesDir(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{FixturesDir: "/srv/fixtures"},
+		Tests:   []config.TestConfig{{Name: "extract", Type: "extract", URL: "fixture://report.pdf"}},
+	}
+	if quick := applyQuickMode(cfg); quick.General.FixturesDir != "/srv/fixtures" {
+		t.Errorf("expected quick mode to keep fixtures_dir, got %q", quick.General.FixturesDir)
+	}
+}
+
+func TestApplyQuickMode_KeepsFaults(t *testing.T) {
+	cfg := &config.Config{
+		Faults: config.FaultsConfig{Seed: 7, ServerErrorRate: 0.2},
+		Tests:  []config.TestConfig{{Name: "search", Type: "search", Query: "q"}},
+	}
+	if quick := applyQuickMode(cfg); quick.Faults != cfg.Faults {
+		t.Errorf("expected quick mode to keep the fault rates, got %+v", quick.Faults)
+	}
+}
+
+func TestOpenEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	log, closeLog, err := openEventLog(path)
+	if err != nil {
+		t.Fatalf("openEventLog failed: %v", err)
+	}
+	log.Write(evaluator.Event{Type: evaluator.EventRunStarted})
+	if err := closeLog(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), `{"type":"run_started"`) || !strings.HasSuffix(string(data), "}\n") {
+		t.Errorf("expected one JSON line, got %v: %q", err, data)
+	}
+
+	stdout := os.Stdout
+	t.Cleanup(func() { os.Stdout = stdout })
+	if _, _, err := openEventLog("-"); err != nil {
+		t.Fatalf("openEventLog(-) failed: %v", err)
+	}
+	if os.Stdout != os.Stderr {
+		t.Error("expected human-readable output moved to stderr")
+	}
+}
+
+func TestSelectTests(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{Timeout: "45s"},
+		Tests: []config.TestConfig{
+			{Name: "Search - Papers", Type: "search", Query: "q", Tags: []string{"academic"}},
+			{Name: "Extract - Paper", Type: "extract", URL: "https://arxiv.org", Tags: []string{"academic"}},
+			{Name: "Extract - Blog", Type: "extract", URL: "https://example.com", Tags: []string{"academic", "slow"}},
+			{Name: "Crawl - Docs", Type: "crawl", URL: "https://go.dev", Tags: []string{"code"}},
+		},
+	}
+	empty, quick, noSearch := "", false, false
+	flagsFor := func(run, tags, skipTags, types string) *cliFlags {
+		return &cliFlags{run: &run, tags: &tags, skipTags: &skipTags, types: &types, quickMode: &quick, noSearch: &noSearch}
+	}
+
+	selected, selection, err := selectTests(cfg, flagsFor("", "academic, ", "slow", "search,extract"))
+	if err != nil {
+		t.Fatalf("selectTests failed: %v", err)
+	}
+	if len(selected.Tests) != 2 || selected.Tests[1].Name != "Extract - Paper" || len(cfg.Tests) != 4 {
+		t.Errorf("unexpected selection %+v, or the config was modified", selected.Tests)
+	}
+	if got := selection.String(); got != "2 of 4 tests: -tags academic -skip-tags slow -types search,extract" {
+		t.Errorf("unexpected selection description %q", got)
+	}
+
+	quick = true
+	selected, selection, err = selectTests(cfg, flagsFor("", "academic", "", ""))
+	if err != nil {
+		t.Fatalf("selectTests failed: %v", err)
+	}
+	if len(selected.Tests) != 2 || selected.General.Timeout != "30s" || selection.Selected != 2 || !selection.Quick {
+		t.Errorf("expected quick mode over the tagged tests, got %+v, %+v", selected.Tests, selection)
+	}
+
+	if _, _, err := selectTests(cfg, flagsFor("Nothing", empty, empty, empty)); err == nil {
+		t.Error("expected an error when no test matches")
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	content := "[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"q\"\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := runValidate([]string{"-config", configPath, "-providers", "firecrawl,local", "-repeats", "1"}); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+	if err := runValidate([]string{"-config", configPath, "-profile", "ci"}); err == nil || !strings.Contains(err.Error(), "is invalid") {
+		t.Errorf("expected an unknown profile to fail validation, got %v", err)
+	}
+
+	// The capabilities the plan uses must match the clients'.
+	client, err := local.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if providerCapabilities("local") != client.Capabilities() {
+		t.Error("providerCapabilities(local) differs from the client's capabilities")
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"queries.tsv":  "1\twhat is bm25\n2\tdense retrieval\n3\tunjudged\n",
+		"qrels.tsv":    "query-id\tcorpus-id\tscore\n1\twiki:Okapi_BM25\t2\n1\tD7\t1\n2\tD8\t1\n3\tD9\t0\n",
+		"corpus.jsonl": `{"_id": "D7", "url": "https://example.com/bm25"}` + "\n",
+		"config.toml":  "include = [\"msmarco.toml\"]\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(dir, "msmarco.toml")
+	err := runImport([]string{
+		"-queries", filepath.Join(dir, "queries.tsv"),
+		"-qrels", filepath.Join(dir, "qrels.tsv"),
+		"-corpus", filepath.Join(dir, "corpus.jsonl"),
+		"-url-rule", `^wiki:(.+)$=>https://en.wikipedia.org/wiki/$1`,
+		"-tags", "msmarco,dev",
+		"-out", out,
+	})
+	if err != nil {
+		t.Fatalf("runImport failed: %v", err)
+	}
+
+	cfg, err := config.Load(filepath.Join(dir, "config.toml"))
+	if err != nil {
+		t.Fatalf("loading the imported suite failed: %v", err)
+	}
+	if len(cfg.Tests) != 1 {
+		t.Fatalf("expected 1 test (query 2's only document has no URL), got %d", len(cfg.Tests))
+	}
+	test := cfg.Tests[0]
+	if test.Name != "msmarco/1: what is bm25" || test.Query != "what is bm25" {
+		t.Errorf("unexpected test %+v", test)
+	}
+	want := []string{"https://en.wikipedia.org/wiki/Okapi_BM25", "https://example.com/bm25"}
+	if strings.Join(test.ExpectedURLs, " ") != strings.Join(want, " ") || test.Relevance[want[0]] != 2 {
+		t.Errorf("expected graded URLs %v, got %v %v", want, test.ExpectedURLs, test.Relevance)
+	}
+	if err := runValidate([]string{"-config", filepath.Join(dir, "config.toml"), "-providers", "local", "-local"}); err != nil {
+		t.Errorf("expected the imported suite to validate, got %v", err)
+	}
+
+	err = runImport([]string{
+		"-queries", filepath.Join(dir, "queries.tsv"),
+		"-qrels", filepath.Join(dir, "qrels.tsv"),
+		"-keep-unmapped",
+		"-out", out,
+	})
+	if err != nil {
+		t.Fatalf("runImport -keep-unmapped failed: %v", err)
+	}
+	if cfg, err = config.Load(filepath.Join(dir, "config.toml")); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Tests) != 2 || strings.Join(cfg.Tests[1].ExpectedURLs, " ") != "D8" {
+		t.Errorf("expected -keep-unmapped to keep document IDs, got %+v", cfg.Tests)
+	}
+
+	if err := runImport([]string{"-queries", filepath.Join(dir, "queries.tsv")}); err == nil {
+		t.Error("expected an error without -qrels and -out")
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/debug"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/monitor"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/quality"
+)
+
+type monitorFlags struct {
+	configPath    *string
+	profile       *string
+	providersFlag *string
+	includeLocal  *bool
+	includeJina   *bool
+	mode          *string
+	qualityMode   *bool
+	pricing       *string
+	listen        *string
+	interval      *time.Duration
+	cycles        *int
+	full          *bool
+}
+
+func parseMonitorFlags(args []string) (*monitorFlags, error) {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	flags := &monitorFlags{
+		configPath:    fs.String("config", "config.toml", "Path to configuration file"),
+		profile:       fs.String("profile", "", "Apply the named [profiles.<name>] overrides of the config's general settings"),
+		providersFlag: fs.String("providers", "all", "Providers to monitor: all, a provider name, or instance names from [providers.<name>] config sections"),
+		includeLocal:  fs.Bool("local", false, "Include local provider (excluded by default)"),
+		includeJina:   fs.Bool("jina", false, "Include Jina provider (excluded by default due to high cost and slow search)"),
+		mode:          fs.String("mode", string(providers.ModeNormalized), "Benchmark mode: normalized or native"),
+		qualityMode:   fs.Bool("quality", false, "Score results so quality gauges are exported (requires EMBEDDING_* and RERANKER_* env vars)"),
+		pricing:       fs.String("pricing", "", "Pricing profile TOML file (overrides the config's [pricing] section)"),
+		listen:        fs.String("listen", ":9464", "Address to serve /metrics on"),
+		interval:      fs.Duration("interval", monitor.DefaultInterval, "Time between the starts of monitor cycles"),
+		cycles:        fs.Int("cycles", 0, "Stop after this many cycles (0 = run until interrupted)"),
+		full:          fs.Bool("full", false, "Run every configured test each cycle instead of the quick subset"),
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *flags.interval <= 0 {
+		return nil, fmt.Errorf("interval must be > 0")
+	}
+	if *flags.cycles < 0 {
+		return nil, fmt.Errorf("cycles must be >= 0")
+	}
+	return flags, nil
+}
+
+// runMonitor implements `monitor`: it runs a reduced test set on an interval
+// and serves the results as Prometheus/OpenMetrics metrics.
+func runMonitor(args []string) error {
+	flags, err := parseMonitorFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	loadEnvFile()
+
+	cfg, err := config.LoadProfile(*flags.configPath, *flags.profile)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	providerNames, err := parseProviders(*flags.providersFlag, *flags.includeLocal, *flags.includeJina, cfg.Instances()...)
+	if err != nil {
+		return fmt.Errorf("error parsing providers: %w", err)
+	}
+	mode, err := parseMode(*flags.mode)
+	if err != nil {
+		return fmt.Errorf("error parsing mode: %w", err)
+	}
+	pricing, err := resolvePricing(cfg, *flags.pricing)
+	if err != nil {
+		return fmt.Errorf("error loading pricing: %w", err)
+	}
+	var scorer *quality.Scorer
+	if *flags.qualityMode {
+		if scorer, err = initializeQualityScorer(); err != nil {
+			return fmt.Errorf("-quality flag set but failed to initialize: %w", err)
+		}
+	}
+
+	provs := initializeProviders(providerNames, cfg, debug.NewLogger(false, false, ""))
+	if len(provs) == 0 {
+		return fmt.Errorf("no providers initialized. Check API keys for selected providers: %s", strings.Join(providerNames, ", "))
+	}
+	runCfg := monitorConfig(cfg, *flags.full)
+	if len(runCfg.Tests) == 0 {
+		return fmt.Errorf("no tests to monitor")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	metrics := monitor.NewMetrics(nil)
+	addr, shutdown, err := serveMetrics(ctx, *flags.listen, metrics)
+	if err != nil {
+		return err
+	}
+	defer shutdown()
+
+	tracer := startTracing()
+	defer shutdownTracing(context.Background(), tracer)
+
+	fmt.Printf("📈 Monitoring %d tests against %d providers every %s\n", len(runCfg.Tests), len(provs), *flags.interval)
+	fmt.Printf("   Metrics: http://%s/metrics\n\n", addr)
+
+	opts := evaluator.DefaultRunnerOptions()
+	opts.Mode = mode
+	opts.Pricing = &pricing
+	opts.Tracer = tracer
+	m := &monitor.Monitor{
+		Interval: *flags.interval,
+		Cycles:   *flags.cycles,
+		Metrics:  metrics,
+		Run: func(ctx context.Context) ([]benchmetrics.Result, error) {
+			runner := evaluator.NewRunner(runCfg, provs, nil, nil, scorer, opts)
+			err := runner.Run(ctx)
+			return runner.GetCollector().GetResults(), err
+		},
+		Logf: func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+		},
+	}
+	return m.Loop(ctx)
+}
+
+// serveMetrics serves /metrics and a /healthz liveness probe on listen in the
+// background. It returns the bound address and a function that stops the
+// server.
+func serveMetrics(ctx context.Context, listen string, metrics *monitor.Metrics) (string, func(), error) {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", listen)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Error serving metrics: %v\n", err)
+		}
+	}()
+	shutdown := func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}
+	return listener.Addr().String(), shutdown, nil
+}
+
+// monitorConfig returns the config a monitor cycle runs: the quick-mode test
+// subset unless full is set, with the rest of the configuration unchanged.
+func monitorConfig(cfg *config.Config, full bool) *config.Config {
+	runCfg := *cfg
+	if !full {
+		quick := applyQuickMode(cfg)
+		runCfg.Tests = quick.Tests
+		runCfg.General.Timeout = quick.General.Timeout
+	}
+	return &runCfg
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/debug"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/server"
+)
+
+type serveFlags struct {
+	configPath  *string
+	configDir   *string
+	outputDir   *string
+	listen      *string
+	maxRuns     *int
+	queueSize   *int
+	allowInline *bool
+}
+
+// apiTokenEnv names the environment variable holding the API token.
+const apiTokenEnv = "BENCH_API_TOKEN"
+
+func parseServeFlags(args []string) (*serveFlags, error) {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags := &serveFlags{
+		configPath:  fs.String("config", "config.toml", "Base configuration file; its [general] provider concurrency limits apply across all runs"),
+		configDir:   fs.String("config-dir", "", "Directory of named configs runs can select (default: the base config's directory)"),
+		outputDir:   fs.String("output", "", "Directory runs and their reports are written to (default: <output_dir>/runs from the base config)"),
+		listen:      fs.String("listen", "127.0.0.1:8080", "Address to serve the API on; use :8080 to accept remote clients"),
+		maxRuns:     fs.Int("max-runs", server.DefaultMaxRuns, "Runs executing at once"),
+		queueSize:   fs.Int("queue", server.DefaultQueueSize, "Runs waiting to execute before submissions are refused"),
+		allowInline: fs.Bool("allow-inline-config", false, "Accept inline config_toml submissions (requires "+apiTokenEnv+")"),
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *flags.maxRuns <= 0 {
+		return nil, fmt.Errorf("max-runs must be > 0")
+	}
+	if *flags.queueSize <= 0 {
+		return nil, fmt.Errorf("queue must be > 0")
+	}
+	return flags, nil
+}
+
+// runServe implements `serve`: a REST API to submit runs, follow their
+// progress and fetch their reports.
+func runServe(args []string) error {
+	flags, err := parseServeFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	loadEnvFile()
+
+	base, err := config.Load(*flags.configPath)
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	configDir := *flags.configDir
+	if configDir == "" {
+		configDir = filepath.Dir(*flags.configPath)
+	}
+	if configDir, err = filepath.Abs(configDir); err != nil {
+		return fmt.Errorf("invalid config directory: %w", err)
+	}
+	outputDir := *flags.outputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(base.General.OutputDir, "runs")
+	}
+
+	tracer := startTracing()
+	defer shutdownTracing(context.Background(), tracer)
+
+	srv, err := server.New(server.Options{
+		ConfigDir:         configDir,
+		OutputDir:         outputDir,
+		MaxRuns:           *flags.maxRuns,
+		QueueSize:         *flags.queueSize,
+		Limits:            base.General,
+		Providers:         serveProviders,
+		Tracer:            tracer,
+		Token:             os.Getenv(apiTokenEnv),
+		AllowInlineConfig: *flags.allowInline,
+	})
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", *flags.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *flags.listen, err)
+	}
+	httpServer := &http.Server{Handler: srv.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("🌐 Serving the benchmark API on http://%s (configs: %s, runs: %s, %d at a time)\n", listener.Addr(), configDir, outputDir, *flags.maxRuns)
+	if tcp, ok := listener.Addr().(*net.TCPAddr); ok && !tcp.IP.IsLoopback() && os.Getenv(apiTokenEnv) == "" {
+		fmt.Fprintf(os.Stderr, "Warning: the API accepts remote clients without authentication; set %s to require a token\n", apiTokenEnv)
+	}
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
+	return nil
+}
+
+// serveProviders initializes the providers a submitted run selects, or the
+// default set when it selects none.
+func serveProviders(cfg *config.Config, names []string) ([]providers.Provider, error) {
+	selection := "all"
+	if len(names) > 0 {
+		selection = strings.Join(names, ",")
+	}
+	providerNames, err := parseProviders(selection, false, false, cfg.Instances()...)
+	if err != nil {
+		return nil, err
+	}
+	return initializeProviders(providerNames, cfg, debug.NewLogger(false, false, "")), nil
+}
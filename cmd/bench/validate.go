@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/brave"
+	"github.com/lamim/SanityWebEval/internal/providers/exa"
+	"github.com/lamim/SanityWebEval/internal/providers/firecrawl"
+	"github.com/lamim/SanityWebEval/internal/providers/jina"
+	"github.com/lamim/SanityWebEval/internal/providers/local"
+	"github.com/lamim/SanityWebEval/internal/providers/mixedbread"
+	"github.com/lamim/SanityWebEval/internal/providers/tavily"
+)
+
+type validateFlags struct {
+	configPath       *string
+	profile          *string
+	providersFlag    *string
+	includeLocal     *bool
+	includeJina      *bool
+	mode             *string
+	repeats          *int
+	capabilityPolicy *string
+	pricing          *string
+	// selection holds the test selectors shared with a run.
+	selection *cliFlags
+}
+
+func parseValidateFlags(args []string) (*validateFlags, error) {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags := &validateFlags{
+		configPath:       fs.String("config", "config.toml", "Path to configuration file"),
+		profile:          fs.String("profile", "", "Apply the named [profiles.<name>] overrides of the config's general settings"),
+		providersFlag:    fs.String("providers", "all", "Providers to plan for: all, a provider name, or instance names from [providers.<name>] config sections"),
+		includeLocal:     fs.Bool("local", false, "Include local provider (excluded by default)"),
+		includeJina:      fs.Bool("jina", false, "Include Jina provider (excluded by default due to high cost and slow search)"),
+		mode:             fs.String("mode", string(providers.ModeNormalized), "Benchmark mode: normalized or native"),
+		repeats:          fs.Int("repeats", 3, "How many repeated runs per test/provider"),
+		capabilityPolicy: fs.String("capability-policy", "strict", "Normalized-mode policy for emulated operations: strict or tagged"),
+		pricing:          fs.String("pricing", "", "Pricing profile TOML file (overrides the config's [pricing] section)"),
+		selection: &cliFlags{
+			run:       fs.String("run", "", "Plan only tests whose name matches this regular expression"),
+			tags:      fs.String("tags", "", "Plan only tests with any of these comma-separated tags"),
+			skipTags:  fs.String("skip-tags", "", "Skip tests with any of these comma-separated tags"),
+			types:     fs.String("types", "", "Plan only these comma-separated test types: search, extract, crawl, structured_extract"),
+			noSearch:  fs.Bool("no-search", false, "Exclude search tests"),
+			quickMode: fs.Bool("quick", false, "Plan the quick-mode test subset"),
+		},
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// runValidate implements `validate`: it checks the config and the suite files
+// it includes, then prints the test × provider × repeat plan a run with the
+// same flags would execute and its estimated cost, without calling any
+// provider.
+func runValidate(args []string) error {
+	flags, err := parseValidateFlags(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, issues := config.Validate(*flags.configPath, *flags.profile)
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, issue)
+	}
+	if config.HasErrors(issues) {
+		return fmt.Errorf("%s is invalid", *flags.configPath)
+	}
+	fmt.Printf("✓ %s is valid (%d tests)\n\n", *flags.configPath, len(cfg.Tests))
+
+	providerNames, err := parseProviders(*flags.providersFlag, *flags.includeLocal, *flags.includeJina, cfg.Instances()...)
+	if err != nil {
+		return fmt.Errorf("error parsing providers: %w", err)
+	}
+	mode, err := parseMode(*flags.mode)
+	if err != nil {
+		return fmt.Errorf("error parsing mode: %w", err)
+	}
+	policy, err := parseCapabilityPolicy(*flags.capabilityPolicy)
+	if err != nil {
+		return fmt.Errorf("error parsing capability policy: %w", err)
+	}
+	if *flags.repeats < 1 {
+		return fmt.Errorf("repeats must be > 0")
+	}
+	pricing, err := resolvePricing(cfg, *flags.pricing)
+	if err != nil {
+		return fmt.Errorf("error loading pricing: %w", err)
+	}
+	cfg, selection, err := selectTests(cfg, flags.selection)
+	if err != nil {
+		return fmt.Errorf("error selecting tests: %w", err)
+	}
+
+	planned := make([]evaluator.PlannedProvider, 0, len(providerNames))
+	for _, name := range providerNames {
+		providerType := cfg.ProviderType(name)
+		planned = append(planned, evaluator.PlannedProvider{
+			Name:         name,
+			Type:         providerType,
+			Capabilities: providerCapabilities(providerType),
+		})
+	}
+	opts := evaluator.DefaultRunnerOptions()
+	opts.Mode = mode
+	opts.Repeats = *flags.repeats
+	opts.CapabilityPolicy = policy
+	opts.Pricing = &pricing
+	steps := evaluator.Plan(cfg, planned, opts)
+
+	if selection.IsZero() {
+		fmt.Printf("Plan: %d tests × %d providers × %d repeats (%s mode, %s policy)\n",
+			len(cfg.Tests), len(planned), opts.Repeats, mode, policy)
+	} else {
+		fmt.Printf("Plan: %s × %d providers × %d repeats (%s mode, %s policy)\n",
+			selection, len(planned), opts.Repeats, mode, policy)
+	}
+	printPlan(os.Stdout, steps, pricing)
+	return nil
+}
+
+// printPlan writes the plan as a table followed by its totals.
+func printPlan(w io.Writer, steps []evaluator.PlanStep, pricing benchmetrics.PricingProfile) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TEST\tTYPE\tPROVIDER\tSUPPORT\tRUNS\tEST. COST\tNOTE")
+	runs, skipped, cost := 0, 0, 0.0
+	for _, step := range steps {
+		note := step.SkipReason
+		if note == "" && step.ExclusionReason != "" {
+			note = "excluded from primary comparisons: " + step.ExclusionReason
+		}
+		if step.Runs == 0 {
+			skipped++
+		}
+		runs += step.Runs
+		cost += step.EstimatedCostUSD
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t$%.4f\t%s\n",
+			step.Test, step.TestType, step.Provider, step.Support, step.Runs, step.EstimatedCostUSD, note)
+	}
+	_ = tw.Flush()
+	_, _ = fmt.Fprintf(w, "\n%d runs, %d of %d pairings skipped, estimated cost $%.4f (%s pricing; estimates assume typical usage per operation)\n",
+		runs, skipped, len(steps), cost, pricing.Name)
+}
+
+// providerCapabilities returns a provider type's support levels without
+// creating a client, so no API key is needed.
+func providerCapabilities(providerType string) providers.CapabilitySet {
+	switch providerType {
+	case "firecrawl":
+		return (&firecrawl.Client{}).Capabilities()
+	case "tavily":
+		return (&tavily.Client{}).Capabilities()
+	case "local":
+		return (&local.Client{}).Capabilities()
+	case "brave":
+		return (&brave.Client{}).Capabilities()
+	case "exa":
+		return (&exa.Client{}).Capabilities()
+	case "mixedbread":
+		return (&mixedbread.Client{}).Capabilities()
+	case "jina":
+		return (&jina.Client{}).Capabilities()
+	default:
+		return providers.CapabilitySet{}
+	}
+}
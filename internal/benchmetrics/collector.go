@@ -11,6 +11,7 @@ import (
 type Result struct {
 	TestName            string        `json:"test_name"`
 	Provider            string        `json:"provider"`
+	ProviderType        string        `json:"provider_type,omitempty"` // set for named instances
 	TestType            string        `json:"test_type"`
 	RunMode             string        `json:"mode,omitempty"`
 	Repeat              int           `json:"repeat,omitempty"`
@@ -41,6 +42,100 @@ type Result struct {
 	RerankerScore     float64                `json:"reranker_score,omitempty"`
 	DomainScores      map[string]float64     `json:"domain_scores,omitempty"`
 	RawQualityMetrics map[string]interface{} `json:"raw_quality_metrics,omitempty"`
+
+	// Freshness is set for search tests that requested a time range.
+	Freshness *FreshnessStats `json:"freshness,omitempty"`
+
+	// Structured is set for structured_extract tests.
+	Structured *StructuredStats `json:"structured,omitempty"`
+
+	// Document is set for extract tests that target PDF, DOCX or text documents
+	// or that carry a reference text.
+	Document *DocumentStats `json:"document,omitempty"`
+
+	// Language is set for tests tagged with a language.
+	Language *LanguageStats `json:"language,omitempty"`
+
+	// Perturbations holds the perturbed query variants run after a successful
+	// search test. Their cost and latency are not included in the fields above.
+	Perturbations []PerturbationResult `json:"perturbations,omitempty"`
+
+	// Faults is set when the test ran through the fault-injection proxy.
+	Faults *FaultStats `json:"faults,omitempty"`
+
+	// Output holds the returned results or content unless capture is disabled.
+	Output *Output `json:"output,omitempty"`
+}
+
+// FaultStats records what the fault-injection proxy saw during one test.
+type FaultStats struct {
+	Attempts      int            `json:"attempts"`       // requests that reached the proxy
+	Injected      map[string]int `json:"injected"`       // injected faults by kind, latency included
+	Faulted       int            `json:"faulted"`        // attempts that got a fault other than latency
+	Retries       int            `json:"retries"`        // attempts beyond the operation's logical request count
+	WastedRetries int            `json:"wasted_retries"` // retries in a test that still failed
+}
+
+// PerturbationResult compares one perturbed variant of a search query with
+// the original query's results.
+type PerturbationResult struct {
+	Kind          string        `json:"kind"` // typo, paraphrase, case or reorder
+	Query         string        `json:"query"`
+	Success       bool          `json:"success"`
+	Error         string        `json:"error,omitempty"`
+	Latency       time.Duration `json:"latency"`
+	CreditsUsed   int           `json:"credits_used,omitempty"`
+	CostUSD       float64       `json:"cost_usd"`
+	ResultsCount  int           `json:"results_count"`
+	URLOverlap    float64       `json:"url_overlap"` // % of the original result URLs also returned
+	QualityScore  float64       `json:"quality_score,omitempty"`
+	QualityScored bool          `json:"quality_scored,omitempty"`
+	QualityDelta  float64       `json:"quality_delta,omitempty"` // variant minus original quality score
+}
+
+// LanguageStats records whether returned content is in the test's language.
+// Items are search results, crawled pages or a single extracted document.
+type LanguageStats struct {
+	Expected string `json:"expected"` // ISO 639-1 code
+	Items    int    `json:"items"`
+	Detected int    `json:"detected"` // items long enough for a language guess
+	Matched  int    `json:"matched"`  // detected items in the expected language
+	// HeuristicsSkipped is set when the quality heuristics have no phrase
+	// lists for the language and their locale-dependent checks were skipped.
+	HeuristicsSkipped bool `json:"heuristics_skipped,omitempty"`
+}
+
+// FreshnessStats records how a search result set complied with a time-range filter.
+type FreshnessStats struct {
+	TimeRange       string    `json:"time_range"`
+	WindowHours     float64   `json:"window_hours"`
+	TotalResults    int       `json:"total_results"`
+	DatedResults    int       `json:"dated_results"`
+	UndatedResults  int       `json:"undated_results"`
+	InWindowResults int       `json:"in_window_results"`
+	AgesHours       []float64 `json:"ages_hours,omitempty"`
+}
+
+// StructuredStats records schema validity and field accuracy for a structured extraction.
+type StructuredStats struct {
+	SchemaValid    bool     `json:"schema_valid"`
+	SchemaErrors   []string `json:"schema_errors,omitempty"`
+	ExpectedFields int      `json:"expected_fields"`
+	MatchedFields  int      `json:"matched_fields"`
+	FieldAccuracy  float64  `json:"field_accuracy"` // 0-100
+}
+
+// DocumentStats records how a provider handled a non-HTML document.
+type DocumentStats struct {
+	DocumentType       string  `json:"document_type"`           // pdf, docx, text or html
+	ReportedType       string  `json:"reported_type,omitempty"` // type reported by the provider, when known
+	Handled            bool    `json:"handled"`                 // text returned for the expected type
+	ReferenceAvailable bool    `json:"reference_available"`
+	TextFidelity       float64 `json:"text_fidelity"` // 0-100, token F1 against the reference text
+	ExpectedPages      *int    `json:"expected_pages,omitempty"`
+	Pages              int     `json:"pages,omitempty"` // 0 when the provider does not report pages
+	ExpectedTables     *int    `json:"expected_tables,omitempty"`
+	Tables             int     `json:"tables"` // Markdown tables in the extracted content
 }
 
 // Summary contains aggregated metrics for a provider
@@ -88,14 +183,47 @@ type Summary struct {
 	ReliabilityAdjustedQuality float64        `json:"reliability_adjusted_quality"`
 	QualityScoreDist           map[string]int `json:"quality_score_dist"` // distribution buckets
 
+	// Freshness filter compliance (search tests with time_range only)
+	FreshnessTests          int     `json:"freshness_tests,omitempty"`
+	FreshnessComplianceRate float64 `json:"freshness_compliance_rate,omitempty"` // % of dated results inside the window
+	FreshnessUndatedShare   float64 `json:"freshness_undated_share,omitempty"`   // % of results without a date
+	FreshnessMedianAgeHours float64 `json:"freshness_median_age_hours,omitempty"`
+
+	// Structured extraction (structured_extract tests only)
+	StructuredTests           int     `json:"structured_tests,omitempty"`
+	StructuredSchemaValidRate float64 `json:"structured_schema_valid_rate,omitempty"` // % of outputs passing schema validation
+	StructuredFieldAccuracy   float64 `json:"structured_field_accuracy,omitempty"`    // mean field accuracy over tests with expected values
+
+	// Language match (tests tagged with a language only)
+	LanguageTests     int     `json:"language_tests,omitempty"`
+	LanguageMatchRate float64 `json:"language_match_rate,omitempty"` // % of detected items in the expected language
+
+	// Query perturbation robustness (search tests run with perturbations only)
+	PerturbationVariants     int     `json:"perturbation_variants,omitempty"`
+	PerturbationSuccessRate  float64 `json:"perturbation_success_rate,omitempty"`
+	PerturbationURLOverlap   float64 `json:"perturbation_url_overlap,omitempty"`   // mean % of original URLs returned by successful variants
+	PerturbationQualityDelta float64 `json:"perturbation_quality_delta,omitempty"` // mean variant-minus-original quality over scored variants
+	PerturbationCostUSD      float64 `json:"perturbation_cost_usd,omitempty"`
+
+	// Fault injection (runs through the fault-injection proxy only)
+	FaultTests         int     `json:"fault_tests,omitempty"`
+	FaultsInjected     int     `json:"faults_injected,omitempty"` // excluding latency
+	FaultRetries       int     `json:"fault_retries,omitempty"`
+	FaultWastedRetries int     `json:"fault_wasted_retries,omitempty"`
+	FaultRecoveryRate  float64 `json:"fault_recovery_rate,omitempty"` // % of tests hit by a fault that still succeeded
+
 	// Error breakdown
 	ErrorBreakdown map[string]int `json:"error_breakdown,omitempty"`
 }
 
 // Collector handles collection and aggregation of test results
 type Collector struct {
-	results []Result
-	mu      sync.RWMutex
+	results       []Result
+	pricing       *PricingProfile
+	workload      *Workload
+	selection     *Selection
+	outputSidecar bool
+	mu            sync.RWMutex
 }
 
 // NewCollector creates a new metrics collector
@@ -217,9 +345,13 @@ func (c *Collector) ComputeSummary(provider string) *Summary {
 		costUSD := r.CostUSD
 		if costUSD <= 0 {
 			if costCalc == nil {
-				costCalc = NewCostCalculator()
+				costCalc = c.costCalculator()
+			}
+			pricedAs := provider
+			if r.ProviderType != "" {
+				pricedAs = r.ProviderType
 			}
-			costUSD = costCalc.CalculateProviderCost(provider, r.CreditsUsed, r.TestType)
+			costUSD = costCalc.CalculateProviderCost(pricedAs, r.CreditsUsed, r.TestType)
 		}
 		totalCostUSD += costUSD
 
@@ -292,9 +424,9 @@ func (c *Collector) ComputeSummary(provider string) *Summary {
 
 	// Calculate latency percentiles
 	if len(latencies) > 0 {
-		summary.P50Latency = calculatePercentileDuration(latencies, 0.50)
-		summary.P95Latency = calculatePercentileDuration(latencies, 0.95)
-		summary.P99Latency = calculatePercentileDuration(latencies, 0.99)
+		summary.P50Latency = PercentileDuration(latencies, 0.50)
+		summary.P95Latency = PercentileDuration(latencies, 0.95)
+		summary.P99Latency = PercentileDuration(latencies, 0.99)
 	}
 
 	// Calculate average quality score
@@ -307,9 +439,146 @@ func (c *Collector) ComputeSummary(provider string) *Summary {
 		summary.ReliabilityAdjustedQuality = summary.AvgQualityScore * (summary.SuccessRate / 100) * (summary.QualityCoveragePct / 100)
 	}
 
+	computeFreshnessSummary(summary, results)
+	computeStructuredSummary(summary, results)
+	computeLanguageSummary(summary, results)
+	computePerturbationSummary(summary, results)
+	computeFaultSummary(summary, results)
+
 	return summary
 }
 
+// computeFreshnessSummary pools freshness stats across a provider's successful
+// time-range tests so rates are weighted by result count, not by test.
+func computeFreshnessSummary(summary *Summary, results []Result) {
+	var total, dated, undated, inWindow int
+	var ages []float64
+	for _, r := range results {
+		if r.Skipped || !r.Success || r.Freshness == nil {
+			continue
+		}
+		summary.FreshnessTests++
+		total += r.Freshness.TotalResults
+		dated += r.Freshness.DatedResults
+		undated += r.Freshness.UndatedResults
+		inWindow += r.Freshness.InWindowResults
+		ages = append(ages, r.Freshness.AgesHours...)
+	}
+	if dated > 0 {
+		summary.FreshnessComplianceRate = float64(inWindow) / float64(dated) * 100
+	}
+	if total > 0 {
+		summary.FreshnessUndatedShare = float64(undated) / float64(total) * 100
+	}
+	if len(ages) > 0 {
+		sort.Float64s(ages)
+		mid := len(ages) / 2
+		if len(ages)%2 == 0 {
+			summary.FreshnessMedianAgeHours = (ages[mid-1] + ages[mid]) / 2
+		} else {
+			summary.FreshnessMedianAgeHours = ages[mid]
+		}
+	}
+}
+
+// computeStructuredSummary averages schema validity and field accuracy over a
+// provider's successful structured extractions.
+func computeStructuredSummary(summary *Summary, results []Result) {
+	valid, withExpected := 0, 0
+	accuracyTotal := 0.0
+	for _, r := range results {
+		if r.Skipped || !r.Success || r.Structured == nil {
+			continue
+		}
+		summary.StructuredTests++
+		if r.Structured.SchemaValid {
+			valid++
+		}
+		if r.Structured.ExpectedFields > 0 {
+			withExpected++
+			accuracyTotal += r.Structured.FieldAccuracy
+		}
+	}
+	if summary.StructuredTests > 0 {
+		summary.StructuredSchemaValidRate = float64(valid) / float64(summary.StructuredTests) * 100
+	}
+	if withExpected > 0 {
+		summary.StructuredFieldAccuracy = accuracyTotal / float64(withExpected)
+	}
+}
+
+// computeLanguageSummary pools detected items across a provider's successful
+// language-tagged tests.
+func computeLanguageSummary(summary *Summary, results []Result) {
+	detected, matched := 0, 0
+	for _, r := range results {
+		if r.Skipped || !r.Success || r.Language == nil {
+			continue
+		}
+		summary.LanguageTests++
+		detected += r.Language.Detected
+		matched += r.Language.Matched
+	}
+	if detected > 0 {
+		summary.LanguageMatchRate = float64(matched) / float64(detected) * 100
+	}
+}
+
+// computePerturbationSummary averages URL overlap and quality change over the
+// perturbed variants of a provider's search tests.
+func computePerturbationSummary(summary *Summary, results []Result) {
+	succeeded, scored := 0, 0
+	overlapTotal, deltaTotal := 0.0, 0.0
+	for _, r := range results {
+		for _, p := range r.Perturbations {
+			summary.PerturbationVariants++
+			summary.PerturbationCostUSD += p.CostUSD
+			if !p.Success {
+				continue
+			}
+			succeeded++
+			overlapTotal += p.URLOverlap
+			if p.QualityScored {
+				scored++
+				deltaTotal += p.QualityDelta
+			}
+		}
+	}
+	if summary.PerturbationVariants > 0 {
+		summary.PerturbationSuccessRate = float64(succeeded) / float64(summary.PerturbationVariants) * 100
+	}
+	if succeeded > 0 {
+		summary.PerturbationURLOverlap = overlapTotal / float64(succeeded)
+	}
+	if scored > 0 {
+		summary.PerturbationQualityDelta = deltaTotal / float64(scored)
+	}
+}
+
+// computeFaultSummary totals proxy-observed retries and how often tests hit by
+// an injected fault still succeeded.
+func computeFaultSummary(summary *Summary, results []Result) {
+	faultedTests, recovered := 0, 0
+	for _, r := range results {
+		if r.Skipped || r.Faults == nil {
+			continue
+		}
+		summary.FaultTests++
+		summary.FaultsInjected += r.Faults.Faulted
+		summary.FaultRetries += r.Faults.Retries
+		summary.FaultWastedRetries += r.Faults.WastedRetries
+		if r.Faults.Faulted > 0 {
+			faultedTests++
+			if r.Success {
+				recovered++
+			}
+		}
+	}
+	if faultedTests > 0 {
+		summary.FaultRecoveryRate = float64(recovered) / float64(faultedTests) * 100
+	}
+}
+
 // getQualityBucket returns a bucket label for a quality score
 func getQualityBucket(score float64) string {
 	switch {
@@ -326,8 +595,8 @@ func getQualityBucket(score float64) string {
 	}
 }
 
-// calculatePercentileDuration calculates the percentile of a duration slice
-func calculatePercentileDuration(durations []time.Duration, percentile float64) time.Duration {
+// PercentileDuration returns the nearest-rank percentile (0-1) of durations.
+func PercentileDuration(durations []time.Duration, percentile float64) time.Duration {
 	if len(durations) == 0 {
 		return 0
 	}
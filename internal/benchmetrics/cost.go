@@ -1,70 +1,43 @@
 // Package benchmetrics provides cost calculation utilities for benchmark results.
 package benchmetrics
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
-// CostCalculator computes USD costs for different providers based on their pricing models.
-// All costs are in USD (US Dollars).
+// CostCalculator computes USD costs for different providers from a pricing
+// profile. All costs are in USD (US Dollars).
 type CostCalculator struct {
-	// Pricing rates (USD per unit)
-	// Firecrawl: $0.005 per credit (pay-as-you-go rate)
-	firecrawlPerCredit float64
-
-	// Tavily: $0.008 per credit (pay-as-you-go rate)
-	tavilyPerCredit float64
-
-	// Brave: $0.005 per request ($5 per 1,000 requests)
-	bravePerRequest float64
-
-	// Exa: dollar-based pricing
-	// Search: $0.005 per search (1-25 results, fast/neural)
-	// Contents: $0.001 per page retrieved
-	exaPerSearch  float64
-	exaPerContent float64
-
-	// Jina: $0.02 per million tokens = $0.00000002 per token
-	jinaPerToken float64
-
-	// Mixedbread: $0.0075 per query ($7.50 per 1K queries with rerank)
-	mixedbreadPerQuery float64
+	profile PricingProfile
+	// used counts units already charged per provider price list, so graduated
+	// tiers step down as a run accumulates usage.
+	used map[string]float64
 
 	mu sync.RWMutex
 }
 
-// NewCostCalculator creates a new cost calculator with default pricing.
-// All prices are in USD and based on official pay-as-you-go rates as of 2025.
+// NewCostCalculator creates a new cost calculator with the default
+// pay-as-you-go pricing.
 func NewCostCalculator() *CostCalculator {
+	return NewCostCalculatorWithProfile(DefaultPricingProfile())
+}
+
+// NewCostCalculatorWithProfile creates a cost calculator for a pricing profile.
+func NewCostCalculatorWithProfile(profile PricingProfile) *CostCalculator {
 	return &CostCalculator{
-		// Firecrawl: Hobby plan pay-as-you-go rate
-		// Source: https://www.firecrawl.dev/pricing
-		firecrawlPerCredit: 0.005,
-
-		// Tavily: Pay-as-you-go rate
-		// Source: https://docs.tavily.com/documentation/api-credits
-		tavilyPerCredit: 0.008,
-
-		// Brave: $5 per 1,000 requests (Pro AI tier)
-		// Source: https://api-dashboard.search.brave.com/app/plans
-		bravePerRequest: 0.005,
-
-		// Exa: Fast/neural search 1-25 results tier
-		// Contents retrieval rate
-		// Source: https://exa.ai/pricing
-		exaPerSearch:  0.005,
-		exaPerContent: 0.001,
-
-		// Jina: $0.02 per million tokens
-		// Note: search has a minimum of 10,000 tokens per request
-		// Source: https://jina.ai/pricing/
-		jinaPerToken: 0.02 / 1000000,
-
-		// Mixedbread: $7.50 per 1K queries (search with rerank)
-		// The benchmark always uses rerank which costs $7.50/1K
-		// Source: https://www.mixedbread.com/pricing
-		mixedbreadPerQuery: 0.0075,
+		profile: DefaultPricingProfile().Merge(profile),
+		used:    make(map[string]float64),
 	}
 }
 
+// Profile returns the pricing profile the calculator applies.
+func (cc *CostCalculator) Profile() PricingProfile {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.profile.Merge(PricingProfile{})
+}
+
 // CostResult holds the calculated USD costs for a provider.
 type CostResult struct {
 	// TotalCost is the total USD cost for all operations
@@ -88,54 +61,39 @@ type CostResult struct {
 
 // CalculateFirecrawlCost computes USD cost for Firecrawl based on credits used.
 // Firecrawl charges per credit: scrape=1, crawl=1/page, map=1/page, search=2/10 results.
-func (cc *CostCalculator) CalculateFirecrawlCost(creditsUsed int, _ string) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-	return float64(creditsUsed) * cc.firecrawlPerCredit
+func (cc *CostCalculator) CalculateFirecrawlCost(creditsUsed int, testType string) float64 {
+	return cc.CalculateProviderCost("firecrawl", creditsUsed, testType)
 }
 
 // CalculateTavilyCost computes USD cost for Tavily based on credits used.
 // Tavily charges: search=1-2 credits, extract=1-2 credits per 5 URLs, map=1 credit.
-func (cc *CostCalculator) CalculateTavilyCost(creditsUsed int, _ string) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-	return float64(creditsUsed) * cc.tavilyPerCredit
+func (cc *CostCalculator) CalculateTavilyCost(creditsUsed int, testType string) float64 {
+	return cc.CalculateProviderCost("tavily", creditsUsed, testType)
 }
 
 // CalculateBraveCost computes USD cost for Brave based on requests made.
-// Brave charges per request: $5 per 1,000 requests (Pro AI tier).
-func (cc *CostCalculator) CalculateBraveCost(requestsMade int, _ string) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-	return float64(requestsMade) * cc.bravePerRequest
+func (cc *CostCalculator) CalculateBraveCost(requestsMade int, testType string) float64 {
+	return cc.CalculateProviderCost("brave", requestsMade, testType)
 }
 
 // CalculateExaCost computes USD cost for Exa based on operations.
-// Exa charges: search=$5 per 1K, contents=$1 per 1K pages.
-func (cc *CostCalculator) CalculateExaCost(creditsUsed int, _ string, isContentFetch bool) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-
-	if isContentFetch {
-		return float64(creditsUsed) * cc.exaPerContent
+// Exa prices searches and content retrieval separately.
+func (cc *CostCalculator) CalculateExaCost(creditsUsed int, testType string, isContentFetch bool) float64 {
+	if isContentFetch && testType == "search" {
+		testType = "extract"
 	}
-	return float64(creditsUsed) * cc.exaPerSearch
+	return cc.CalculateProviderCost("exa", creditsUsed, testType)
 }
 
 // CalculateJinaCost computes USD cost for Jina based on tokens used.
-// Jina charges $0.02 per million tokens with a minimum of 10,000 tokens per search request.
-func (cc *CostCalculator) CalculateJinaCost(tokensUsed int, _ string) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-	return float64(tokensUsed) * cc.jinaPerToken
+// Jina search has a minimum of 10,000 tokens per request.
+func (cc *CostCalculator) CalculateJinaCost(tokensUsed int, testType string) float64 {
+	return cc.CalculateProviderCost("jina", tokensUsed, testType)
 }
 
 // CalculateMixedbreadCost computes USD cost for Mixedbread based on queries made.
-// Mixedbread charges $7.50 per 1,000 search queries (with rerank).
-func (cc *CostCalculator) CalculateMixedbreadCost(queriesMade int, _ string) float64 {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-	return float64(queriesMade) * cc.mixedbreadPerQuery
+func (cc *CostCalculator) CalculateMixedbreadCost(queriesMade int, testType string) float64 {
+	return cc.CalculateProviderCost("mixedbread", queriesMade, testType)
 }
 
 // CalculateLocalCost always returns 0 as local provider is free.
@@ -143,31 +101,41 @@ func (cc *CostCalculator) CalculateLocalCost(_ int, _ string) float64 {
 	return 0
 }
 
-// CalculateProviderCost computes the USD cost for any provider based on its name.
-// This is a convenience method that routes to the appropriate calculator.
+// CalculateProviderCost quotes the USD cost of usage on its own, pricing
+// tiers from zero volume. Credits are in the provider's billing unit:
+// credits, requests, tokens or queries. Unknown providers cost 0.
 func (cc *CostCalculator) CalculateProviderCost(provider string, creditsUsed int, testType string) float64 {
-	switch provider {
-	case "firecrawl":
-		return cc.CalculateFirecrawlCost(creditsUsed, testType)
-	case "tavily":
-		return cc.CalculateTavilyCost(creditsUsed, testType)
-	case "brave":
-		// For Brave, creditsUsed represents request count
-		return cc.CalculateBraveCost(creditsUsed, testType)
-	case "exa":
-		isContentFetch := testType == "extract" || testType == "crawl"
-		return cc.CalculateExaCost(creditsUsed, testType, isContentFetch)
-	case "jina":
-		// For Jina, creditsUsed represents token count
-		return cc.CalculateJinaCost(creditsUsed, testType)
-	case "mixedbread":
-		// For Mixedbread, creditsUsed represents query count
-		return cc.CalculateMixedbreadCost(creditsUsed, testType)
-	case "local":
-		return cc.CalculateLocalCost(creditsUsed, testType)
-	default:
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	pricing, ok := cc.profile.For(provider, "")
+	if !ok {
+		return 0
+	}
+	op, _ := pricing.operation(testType)
+	return op.Cost(0, float64(creditsUsed))
+}
+
+// Charge prices usage on top of the units already charged to the same price
+// list, so graduated tiers apply across a run. Named instances fall back to
+// their provider type's pricing.
+func (cc *CostCalculator) Charge(provider, providerType string, creditsUsed int, testType string) float64 {
+	return cc.chargeUnits(provider, providerType, float64(creditsUsed), testType)
+}
+
+func (cc *CostCalculator) chargeUnits(provider, providerType string, units float64, testType string) float64 {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	pricing, ok := cc.profile.For(provider, providerType)
+	if !ok {
 		return 0
 	}
+	op, key := pricing.operation(testType)
+	key = strings.ToLower(provider) + "/" + key
+	cost := op.Cost(cc.used[key], units)
+	if units > 0 {
+		cc.used[key] += units
+	}
+	return cost
 }
 
 // GetPricingInfo returns a map of provider pricing information for display.
@@ -175,69 +143,32 @@ func (cc *CostCalculator) GetPricingInfo() map[string]map[string]string {
 	cc.mu.RLock()
 	defer cc.mu.RUnlock()
 
-	return map[string]map[string]string{
-		"firecrawl": {
-			"unit":        "credit",
-			"rate":        "$0.005",
-			"source":      "https://www.firecrawl.dev/pricing",
-			"description": "Scrape/Crawl/Map: 1 credit/page, Search: 2 credits/10 results",
-		},
-		"tavily": {
-			"unit":        "credit",
-			"rate":        "$0.008",
-			"source":      "https://docs.tavily.com/documentation/api-credits",
-			"description": "Search: 1-2 credits, Extract: 1-2 credits/5 URLs, Map: 1 credit",
-		},
-		"brave": {
-			"unit":        "request",
-			"rate":        "$0.005",
-			"source":      "https://api-dashboard.search.brave.com/app/plans",
-			"description": "$5 per 1,000 requests (Pro AI tier)",
-		},
-		"exa": {
-			"unit":        "request",
-			"rate":        "$0.005 (search), $0.001 (content)",
-			"source":      "https://exa.ai/pricing",
-			"description": "Search: $5/1K, Contents: $1/1K pages",
-		},
-		"jina": {
-			"unit":        "token",
-			"rate":        "$0.02 per million",
-			"source":      "https://jina.ai/pricing/",
-			"description": "Token-based billing; min 10K tokens per search request",
-		},
-		"mixedbread": {
-			"unit":        "query",
-			"rate":        "$0.0075",
-			"source":      "https://www.mixedbread.com/pricing",
-			"description": "$7.50 per 1,000 search queries (with rerank)",
-		},
-		"local": {
-			"unit":        "N/A",
-			"rate":        "$0",
-			"source":      "N/A",
-			"description": "Local crawling - no API costs",
-		},
+	info := make(map[string]map[string]string, len(cc.profile.Providers))
+	for name, pricing := range cc.profile.Providers {
+		source := pricing.Source
+		if source == "" {
+			source = "N/A"
+		}
+		info[name] = map[string]string{
+			"plan":        pricing.Plan,
+			"unit":        pricing.Unit,
+			"rate":        pricing.PriceSummary(),
+			"source":      source,
+			"description": pricing.Description,
+		}
 	}
+	return info
 }
 
 // SetCustomRate allows overriding default rates (useful for enterprise pricing).
+// It sets a flat provider-wide unit price; per-operation prices are kept.
 func (cc *CostCalculator) SetCustomRate(provider string, rate float64) {
 	cc.mu.Lock()
 	defer cc.mu.Unlock()
 
-	switch provider {
-	case "firecrawl":
-		cc.firecrawlPerCredit = rate
-	case "tavily":
-		cc.tavilyPerCredit = rate
-	case "brave":
-		cc.bravePerRequest = rate
-	case "exa":
-		cc.exaPerSearch = rate
-	case "jina":
-		cc.jinaPerToken = rate
-	case "mixedbread":
-		cc.mixedbreadPerQuery = rate
-	}
+	provider = strings.ToLower(provider)
+	pricing := cc.profile.Providers[provider]
+	pricing.UnitPrice = rate
+	pricing.Tiers = nil
+	cc.profile.Providers[provider] = pricing
 }
@@ -0,0 +1,39 @@
+package benchmetrics
+
+import "strings"
+
+// pageTokens is the typical size of an extracted page in tokens, for
+// providers billed by tokens.
+const pageTokens = 2500
+
+// EstimateUnits returns the billing units a provider type typically uses for
+// one operation, in the unit its pricing counts (see ProviderPricing). Crawls
+// are estimated at pages pages. The figures mirror what the clients report
+// when an API returns no usage, so they suit planning, not billing; price
+// them with CostCalculator.Charge. Unknown provider types estimate 0.
+func EstimateUnits(providerType, testType string, pages int) int {
+	if pages <= 0 {
+		pages = 1
+	}
+	switch strings.ToLower(providerType) {
+	case "firecrawl":
+		// search=2, scrape=1, scrape with JSON format=5, crawl=1/page
+		return map[string]int{"search": 2, "extract": 1, "structured_extract": 5, "crawl": pages}[testType]
+	case "tavily":
+		// The crawl maps the site (1 credit) and extracts each page.
+		return map[string]int{"search": 1, "extract": 1, "structured_extract": 1, "crawl": 1 + pages}[testType]
+	case "brave":
+		// Pages are fetched directly; only the site search is billed.
+		return map[string]int{"search": 1, "crawl": 1}[testType]
+	case "exa":
+		return map[string]int{"search": 1, "extract": 1, "structured_extract": 2, "crawl": 1}[testType]
+	case "jina":
+		// Searches bill at least 10,000 tokens; crawls extract the start page only.
+		return map[string]int{"search": 10000, "extract": pageTokens, "structured_extract": pageTokens, "crawl": pageTokens}[testType]
+	case "mixedbread":
+		// Only searches are billed; pages are fetched directly.
+		return map[string]int{"search": 1}[testType]
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,48 @@
+package benchmetrics
+
+import "time"
+
+// Output is what a provider returned for one test, cut to the capture limits.
+// It backs the HTML report's result explorer.
+type Output struct {
+	Query        string   `json:"query,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	ExpectedURLs []string `json:"expected_urls,omitempty"`
+	// Terms are highlighted in the explorer: the query words and the
+	// expected topics, content and terms of the test.
+	Terms []string `json:"terms,omitempty"`
+	// Items are the search results in rank order, the extracted page or the
+	// crawled pages.
+	Items      []OutputItem `json:"items"`
+	TotalItems int          `json:"total_items"`
+	// Truncated is set when items were dropped or content was shortened.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// OutputItem is one search result or page.
+type OutputItem struct {
+	Rank          int        `json:"rank"`
+	Title         string     `json:"title,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	Content       string     `json:"content,omitempty"`
+	ContentLength int        `json:"content_length"` // before truncation, in characters
+	Score         float64    `json:"score,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	// Matched is set when the URL matches one of the expected URLs.
+	Matched bool `json:"matched,omitempty"`
+}
+
+// SetOutputSidecar records whether outputs are written to a sidecar file
+// instead of the JSON report.
+func (c *Collector) SetOutputSidecar(sidecar bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outputSidecar = sidecar
+}
+
+// OutputSidecar reports whether outputs go to a sidecar file.
+func (c *Collector) OutputSidecar() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.outputSidecar
+}
@@ -0,0 +1,333 @@
+package benchmetrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PricingProfile names a set of provider price lists, such as public
+// pay-as-you-go rates or a negotiated contract.
+type PricingProfile struct {
+	Name      string                     `toml:"name" json:"name"`
+	Providers map[string]ProviderPricing `toml:"providers" json:"providers"`
+}
+
+// ProviderPricing is one provider's price list. Usage is counted in the
+// provider's billing unit (credits, requests, tokens or queries, as reported
+// in CreditsUsed). Operations override the provider-wide price for
+// individual test types.
+type ProviderPricing struct {
+	Plan      string      `toml:"plan" json:"plan,omitempty"`
+	Unit      string      `toml:"unit" json:"unit,omitempty"`
+	UnitPrice float64     `toml:"unit_price" json:"unit_price"`
+	Tiers     []PriceTier `toml:"tiers" json:"tiers,omitempty"`
+	// Operations maps test types (search, extract, crawl, structured_extract)
+	// to their own prices. structured_extract falls back to extract.
+	Operations map[string]OperationPricing `toml:"operations" json:"operations,omitempty"`
+	// MonthlyMinimum is the least the plan bills per month regardless of usage.
+	MonthlyMinimum float64 `toml:"monthly_minimum" json:"monthly_minimum,omitempty"`
+	Source         string  `toml:"source" json:"source,omitempty"`
+	Description    string  `toml:"description" json:"description,omitempty"`
+}
+
+// OperationPricing prices one operation type.
+type OperationPricing struct {
+	UnitPrice float64     `toml:"unit_price" json:"unit_price"`
+	Tiers     []PriceTier `toml:"tiers" json:"tiers,omitempty"`
+}
+
+// PriceTier is a graduated volume tier: units up to UpTo (cumulative) cost
+// UnitPrice each. UpTo 0 marks the open-ended last tier.
+type PriceTier struct {
+	UpTo      float64 `toml:"up_to" json:"up_to"`
+	UnitPrice float64 `toml:"unit_price" json:"unit_price"`
+}
+
+// DefaultPricingProfileName identifies the built-in pay-as-you-go rates.
+const DefaultPricingProfileName = "pay-as-you-go"
+
+// DefaultPricingProfile returns the public pay-as-you-go rates listed in the
+// README's pricing notes.
+func DefaultPricingProfile() PricingProfile {
+	return PricingProfile{
+		Name: DefaultPricingProfileName,
+		Providers: map[string]ProviderPricing{
+			"firecrawl": {
+				Plan:        "Hobby (pay-as-you-go)",
+				Unit:        "credit",
+				UnitPrice:   0.005,
+				Source:      "https://www.firecrawl.dev/pricing",
+				Description: "Scrape/Crawl/Map: 1 credit/page, Search: 2 credits/10 results",
+			},
+			"tavily": {
+				Plan:        "Pay-as-you-go",
+				Unit:        "credit",
+				UnitPrice:   0.008,
+				Source:      "https://docs.tavily.com/documentation/api-credits",
+				Description: "Search: 1-2 credits, Extract: 1-2 credits/5 URLs, Map: 1 credit",
+			},
+			"brave": {
+				Plan:        "Pro AI",
+				Unit:        "request",
+				UnitPrice:   0.005,
+				Source:      "https://api-dashboard.search.brave.com/app/plans",
+				Description: "$5 per 1,000 requests (Pro AI tier)",
+			},
+			"exa": {
+				Plan:      "Pay-as-you-go",
+				Unit:      "request",
+				UnitPrice: 0.005,
+				Operations: map[string]OperationPricing{
+					"extract":            {UnitPrice: 0.001},
+					"crawl":              {UnitPrice: 0.001},
+					"structured_extract": {UnitPrice: 0.001},
+				},
+				Source:      "https://exa.ai/pricing",
+				Description: "Search: $5/1K, Contents: $1/1K pages",
+			},
+			"jina": {
+				Plan:        "Pay-as-you-go",
+				Unit:        "token",
+				UnitPrice:   0.02 / 1000000,
+				Source:      "https://jina.ai/pricing/",
+				Description: "Token-based billing; min 10K tokens per search request",
+			},
+			"mixedbread": {
+				Plan:        "Pay-as-you-go",
+				Unit:        "query",
+				UnitPrice:   0.0075,
+				Source:      "https://www.mixedbread.com/pricing",
+				Description: "$7.50 per 1,000 search queries (with rerank)",
+			},
+			"local": {
+				Unit:        "N/A",
+				Description: "Local crawling - no API costs",
+			},
+		},
+	}
+}
+
+// Merge returns p with the providers in override replacing p's entries.
+// The override's name wins when set.
+func (p PricingProfile) Merge(override PricingProfile) PricingProfile {
+	merged := PricingProfile{Name: p.Name, Providers: make(map[string]ProviderPricing, len(p.Providers)+len(override.Providers))}
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+	for name, pricing := range p.Providers {
+		merged.Providers[name] = pricing
+	}
+	for name, pricing := range override.Providers {
+		merged.Providers[strings.ToLower(strings.TrimSpace(name))] = pricing
+	}
+	return merged
+}
+
+// Validate checks prices are non-negative and tiers are ascending with the
+// open-ended tier last.
+func (p PricingProfile) Validate() error {
+	for name, pricing := range p.Providers {
+		if pricing.UnitPrice < 0 || pricing.MonthlyMinimum < 0 {
+			return fmt.Errorf("pricing for '%s' must not be negative", name)
+		}
+		if err := validateTiers(pricing.Tiers); err != nil {
+			return fmt.Errorf("pricing for '%s': %w", name, err)
+		}
+		for op, opPricing := range pricing.Operations {
+			if opPricing.UnitPrice < 0 {
+				return fmt.Errorf("pricing for '%s' %s must not be negative", name, op)
+			}
+			if err := validateTiers(opPricing.Tiers); err != nil {
+				return fmt.Errorf("pricing for '%s' %s: %w", name, op, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateTiers(tiers []PriceTier) error {
+	prev := 0.0
+	for i, tier := range tiers {
+		if tier.UnitPrice < 0 || tier.UpTo < 0 {
+			return fmt.Errorf("tier %d must not be negative", i+1)
+		}
+		if tier.UpTo == 0 {
+			if i != len(tiers)-1 {
+				return fmt.Errorf("only the last tier may omit up_to")
+			}
+			continue
+		}
+		if tier.UpTo <= prev {
+			return fmt.Errorf("tier up_to values must increase")
+		}
+		prev = tier.UpTo
+	}
+	return nil
+}
+
+// For returns the pricing for a provider instance, falling back to its type.
+func (p PricingProfile) For(provider, providerType string) (ProviderPricing, bool) {
+	if pricing, ok := p.Providers[strings.ToLower(provider)]; ok {
+		return pricing, true
+	}
+	if providerType != "" {
+		pricing, ok := p.Providers[strings.ToLower(providerType)]
+		return pricing, ok
+	}
+	return ProviderPricing{}, false
+}
+
+// operation returns the price list that applies to a test type and a key
+// identifying it, so tier volume is counted per price list.
+func (pp ProviderPricing) operation(testType string) (OperationPricing, string) {
+	if op, ok := pp.Operations[testType]; ok {
+		return op, testType
+	}
+	if testType == "structured_extract" {
+		if op, ok := pp.Operations["extract"]; ok {
+			return op, "extract"
+		}
+	}
+	return OperationPricing{UnitPrice: pp.UnitPrice, Tiers: pp.Tiers}, ""
+}
+
+// Cost prices units of usage bought on top of already-used units.
+func (op OperationPricing) Cost(used, units float64) float64 {
+	if units <= 0 {
+		return 0
+	}
+	if len(op.Tiers) == 0 {
+		return units * op.UnitPrice
+	}
+
+	cost := 0.0
+	position := used
+	remaining := units
+	for i, tier := range op.Tiers {
+		last := i == len(op.Tiers)-1
+		if !last && tier.UpTo > 0 && position >= tier.UpTo {
+			continue
+		}
+		take := remaining
+		if !last && tier.UpTo > 0 {
+			take = min(remaining, tier.UpTo-position)
+		}
+		cost += take * tier.UnitPrice
+		position += take
+		remaining -= take
+		if remaining <= 0 {
+			break
+		}
+	}
+	return cost
+}
+
+// TierSummary describes the tiers for display, e.g. "$0.005 to 100000, then $0.003".
+func (op OperationPricing) TierSummary() string {
+	if len(op.Tiers) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(op.Tiers))
+	for i, tier := range op.Tiers {
+		switch {
+		case tier.UpTo > 0:
+			parts = append(parts, fmt.Sprintf("%s to %g", formatUnitPrice(tier.UnitPrice), tier.UpTo))
+		case i > 0:
+			parts = append(parts, "then "+formatUnitPrice(tier.UnitPrice))
+		default:
+			parts = append(parts, formatUnitPrice(tier.UnitPrice))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// PriceSummary describes the provider-wide and per-operation unit prices.
+func (pp ProviderPricing) PriceSummary() string {
+	base := OperationPricing{UnitPrice: pp.UnitPrice, Tiers: pp.Tiers}
+	summary := formatUnitPrice(pp.UnitPrice)
+	if len(pp.Tiers) > 0 {
+		summary = base.TierSummary()
+	}
+
+	ops := make([]string, 0, len(pp.Operations))
+	for op := range pp.Operations {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		opPricing := pp.Operations[op]
+		price := formatUnitPrice(opPricing.UnitPrice)
+		if len(opPricing.Tiers) > 0 {
+			price = opPricing.TierSummary()
+		}
+		summary += fmt.Sprintf("; %s %s", op, price)
+	}
+	return summary
+}
+
+func formatUnitPrice(price float64) string {
+	if price == 0 {
+		return "$0"
+	}
+	return "$" + strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.8f", price), "0"), ".")
+}
+
+// SetPricingProfile records the pricing profile the results are priced with.
+func (c *Collector) SetPricingProfile(profile PricingProfile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pricing = &profile
+}
+
+// PricingProfile returns the profile the results are priced with and whether
+// one was recorded.
+func (c *Collector) PricingProfile() (PricingProfile, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.pricing == nil {
+		return PricingProfile{}, false
+	}
+	return *c.pricing, true
+}
+
+func (c *Collector) costCalculator() *CostCalculator {
+	if profile, ok := c.PricingProfile(); ok {
+		return NewCostCalculatorWithProfile(profile)
+	}
+	return NewCostCalculator()
+}
+
+// Reprice recomputes every result's cost, and its query perturbations'
+// costs, under profile from the recorded usage. Results are charged in the
+// order they were recorded by timestamp, so graduated tiers apply as they
+// would have during the run.
+func (c *Collector) Reprice(profile PricingProfile) {
+	costs := NewCostCalculatorWithProfile(profile)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	order := make([]int, len(c.results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return c.results[order[a]].Timestamp.Before(c.results[order[b]].Timestamp)
+	})
+
+	for _, i := range order {
+		r := &c.results[i]
+		if r.Skipped {
+			continue
+		}
+		r.CostUSD = costs.Charge(r.Provider, r.ProviderType, r.CreditsUsed, r.TestType)
+		for j := range r.Perturbations {
+			p := &r.Perturbations[j]
+			if p.Success {
+				p.CostUSD = costs.Charge(r.Provider, r.ProviderType, p.CreditsUsed, "search")
+			}
+		}
+	}
+	applied := costs.Profile()
+	c.pricing = &applied
+}
@@ -0,0 +1,157 @@
+package benchmetrics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestOperationPricing_CostTiers(t *testing.T) {
+	op := OperationPricing{Tiers: []PriceTier{
+		{UpTo: 100, UnitPrice: 0.01},
+		{UpTo: 1000, UnitPrice: 0.005},
+		{UnitPrice: 0.001},
+	}}
+
+	tests := []struct {
+		name  string
+		used  float64
+		units float64
+		want  float64
+	}{
+		{"within first tier", 0, 50, 0.5},
+		{"spans first and second tier", 80, 40, 20*0.01 + 20*0.005},
+		{"spans all tiers", 0, 1100, 100*0.01 + 900*0.005 + 100*0.001},
+		{"past last bounded tier", 5000, 10, 0.01},
+		{"no units", 10, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := op.Cost(tt.used, tt.units); !approxEqual(got, tt.want) {
+				t.Errorf("Cost(%v, %v) = %v, want %v", tt.used, tt.units, got, tt.want)
+			}
+		})
+	}
+
+	flat := OperationPricing{UnitPrice: 0.002}
+	if got := flat.Cost(1e6, 10); !approxEqual(got, 0.02) {
+		t.Errorf("flat Cost = %v, want 0.02", got)
+	}
+}
+
+func TestCostCalculator_ChargeAccumulatesTiers(t *testing.T) {
+	profile := PricingProfile{Name: "contract", Providers: map[string]ProviderPricing{
+		"tavily": {Tiers: []PriceTier{{UpTo: 10, UnitPrice: 0.01}, {UnitPrice: 0.001}}},
+	}}
+	costs := NewCostCalculatorWithProfile(profile)
+
+	if got := costs.Charge("tavily", "", 8, "search"); !approxEqual(got, 0.08) {
+		t.Errorf("first charge = %v, want 0.08", got)
+	}
+	// 2 units left in the first tier, 3 in the second.
+	if got := costs.Charge("tavily", "", 5, "search"); !approxEqual(got, 0.023) {
+		t.Errorf("second charge = %v, want 0.023", got)
+	}
+	// Quotes do not consume volume.
+	if got := costs.CalculateProviderCost("tavily", 1, "search"); !approxEqual(got, 0.01) {
+		t.Errorf("quote = %v, want 0.01", got)
+	}
+	if costs.Profile().Name != "contract" {
+		t.Errorf("expected profile name contract, got %q", costs.Profile().Name)
+	}
+}
+
+func TestCostCalculator_OperationsAndInstances(t *testing.T) {
+	costs := NewCostCalculator()
+
+	if got := costs.Charge("exa", "", 1, "search"); !approxEqual(got, 0.005) {
+		t.Errorf("exa search = %v, want 0.005", got)
+	}
+	if got := costs.Charge("exa", "", 1, "structured_extract"); !approxEqual(got, 0.001) {
+		t.Errorf("exa structured_extract = %v, want extract price 0.001", got)
+	}
+	// Named instances without their own entry are priced as their type.
+	if got := costs.Charge("firecrawl-selfhosted", "firecrawl", 2, "extract"); !approxEqual(got, 0.01) {
+		t.Errorf("instance charge = %v, want 0.01", got)
+	}
+	if got := costs.Charge("unknown", "", 5, "search"); got != 0 {
+		t.Errorf("unknown provider charge = %v, want 0", got)
+	}
+}
+
+func TestPricingProfile_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pricing ProviderPricing
+		wantErr bool
+	}{
+		{"flat", ProviderPricing{UnitPrice: 0.01}, false},
+		{"ascending tiers", ProviderPricing{Tiers: []PriceTier{{UpTo: 10, UnitPrice: 0.01}, {UnitPrice: 0.005}}}, false},
+		{"negative price", ProviderPricing{UnitPrice: -1}, true},
+		{"descending tiers", ProviderPricing{Tiers: []PriceTier{{UpTo: 10, UnitPrice: 0.01}, {UpTo: 5, UnitPrice: 0.005}}}, true},
+		{"open tier not last", ProviderPricing{Tiers: []PriceTier{{UnitPrice: 0.01}, {UpTo: 5, UnitPrice: 0.005}}}, true},
+		{"negative operation price", ProviderPricing{Operations: map[string]OperationPricing{"crawl": {UnitPrice: -0.1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := PricingProfile{Name: "test", Providers: map[string]ProviderPricing{"p": tt.pricing}}
+			if err := profile.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCollector_Reprice(t *testing.T) {
+	c := NewCollector()
+	start := time.Now()
+	c.AddResult(Result{
+		Provider:    "brave",
+		TestType:    "search",
+		Success:     true,
+		CreditsUsed: 1,
+		CostUSD:     0.005,
+		Timestamp:   start.Add(time.Second),
+		Perturbations: []PerturbationResult{
+			{Kind: "typo", Success: true, CreditsUsed: 1, CostUSD: 0.005},
+			{Kind: "case", Error: "timeout"},
+		},
+	})
+	c.AddResult(Result{
+		Provider:    "brave",
+		TestType:    "search",
+		Success:     true,
+		CreditsUsed: 1,
+		CostUSD:     0.005,
+		Timestamp:   start,
+	})
+	c.AddResult(Result{Provider: "brave", TestType: "search", Skipped: true, Timestamp: start})
+
+	c.Reprice(PricingProfile{Name: "negotiated", Providers: map[string]ProviderPricing{
+		"brave": {Tiers: []PriceTier{{UpTo: 1, UnitPrice: 0.01}, {UnitPrice: 0.002}}},
+	}})
+
+	results := c.GetResults()
+	// The earlier result takes the first tier.
+	if !approxEqual(results[1].CostUSD, 0.01) {
+		t.Errorf("earliest result cost = %v, want 0.01", results[1].CostUSD)
+	}
+	if !approxEqual(results[0].CostUSD, 0.002) || !approxEqual(results[0].Perturbations[0].CostUSD, 0.002) {
+		t.Errorf("later costs = %v / %v, want 0.002", results[0].CostUSD, results[0].Perturbations[0].CostUSD)
+	}
+	if results[0].Perturbations[1].CostUSD != 0 {
+		t.Errorf("failed perturbation should stay unpriced, got %v", results[0].Perturbations[1].CostUSD)
+	}
+
+	profile, ok := c.PricingProfile()
+	if !ok || profile.Name != "negotiated" {
+		t.Fatalf("expected negotiated profile, got %q (%v)", profile.Name, ok)
+	}
+	if _, ok := profile.Providers["tavily"]; !ok {
+		t.Error("expected default rates for providers the profile does not list")
+	}
+}
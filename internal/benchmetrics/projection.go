@@ -0,0 +1,250 @@
+package benchmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Workload is a monthly usage mix to project costs for.
+type Workload struct {
+	Searches   float64 `toml:"searches" json:"searches"`
+	Extracts   float64 `toml:"extracts" json:"extracts"`
+	CrawlPages float64 `toml:"crawl_pages" json:"crawl_pages"`
+}
+
+// IsZero reports whether the workload has no usage.
+func (w Workload) IsZero() bool {
+	return w.Searches == 0 && w.Extracts == 0 && w.CrawlPages == 0
+}
+
+// Validate checks volumes are non-negative.
+func (w Workload) Validate() error {
+	if w.Searches < 0 || w.Extracts < 0 || w.CrawlPages < 0 {
+		return fmt.Errorf("workload volumes must not be negative")
+	}
+	return nil
+}
+
+// String describes the workload, e.g. "2M searches, 50K extracts, 0 crawl pages".
+func (w Workload) String() string {
+	return fmt.Sprintf("%s searches, %s extracts, %s crawl pages",
+		formatVolume(w.Searches), formatVolume(w.Extracts), formatVolume(w.CrawlPages))
+}
+
+// ParseWorkload parses "searches=2M,extracts=50k,crawl_pages=10000". Volumes
+// accept k, M and B suffixes.
+func ParseWorkload(value string) (Workload, error) {
+	var w Workload
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(part, "=")
+		if !ok {
+			return Workload{}, fmt.Errorf("expected key=volume, got %q", part)
+		}
+		volume, err := parseVolume(strings.TrimSpace(raw))
+		if err != nil {
+			return Workload{}, fmt.Errorf("invalid %s volume: %w", strings.TrimSpace(key), err)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "searches", "search":
+			w.Searches = volume
+		case "extracts", "extract":
+			w.Extracts = volume
+		case "crawl_pages", "crawl":
+			w.CrawlPages = volume
+		default:
+			return Workload{}, fmt.Errorf("unknown workload key %q (valid: searches, extracts, crawl_pages)", key)
+		}
+	}
+	if err := w.Validate(); err != nil {
+		return Workload{}, err
+	}
+	return w, nil
+}
+
+func parseVolume(raw string) (float64, error) {
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(raw, "k"), strings.HasSuffix(raw, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(raw, "m"), strings.HasSuffix(raw, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(raw, "b"), strings.HasSuffix(raw, "B"):
+		multiplier = 1e9
+	}
+	if multiplier != 1 {
+		raw = raw[:len(raw)-1]
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+	return v * multiplier, nil
+}
+
+func formatVolume(v float64) string {
+	switch {
+	case v >= 1e9:
+		return strconv.FormatFloat(v/1e9, 'f', -1, 64) + "B"
+	case v >= 1e6:
+		return strconv.FormatFloat(v/1e6, 'f', -1, 64) + "M"
+	case v >= 1e3:
+		return strconv.FormatFloat(v/1e3, 'f', -1, 64) + "K"
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// OperationProjection is the projected monthly usage of one operation type.
+type OperationProjection struct {
+	TestType string `json:"test_type"`
+	// Volume is operations per month, or pages for crawl.
+	Volume float64 `json:"volume"`
+	// Samples is the number of successful tests the rate was measured on.
+	Samples int `json:"samples"`
+	// UnitsPerOp is the billing units measured per successful operation (per
+	// page for crawl).
+	UnitsPerOp float64 `json:"units_per_op"`
+	// RetryOverhead is executed attempts per success, assuming failed
+	// attempts bill like successful ones.
+	RetryOverhead float64 `json:"retry_overhead"`
+	Units         float64 `json:"units"`
+	CostUSD       float64 `json:"cost_usd"`
+}
+
+// Measured reports whether the operation had successful tests to measure.
+func (op OperationProjection) Measured() bool {
+	return op.Samples > 0
+}
+
+// CostProjection is a provider's projected monthly cost for a workload.
+type CostProjection struct {
+	Provider       string                `json:"provider"`
+	Operations     []OperationProjection `json:"operations"`
+	UsageCostUSD   float64               `json:"usage_cost_usd"`
+	MonthlyMinimum float64               `json:"monthly_minimum,omitempty"`
+	// MonthlyCostUSD is the usage cost or the plan's monthly minimum,
+	// whichever is higher.
+	MonthlyCostUSD float64 `json:"monthly_cost_usd"`
+	// Complete is false when an operation in the workload had no successful
+	// tests to measure, so its cost is missing from the projection.
+	Complete bool `json:"complete"`
+}
+
+// SetWorkload records the monthly workload costs are projected for.
+func (c *Collector) SetWorkload(w Workload) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.workload = &w
+}
+
+// Workload returns the recorded workload and whether one is set.
+func (c *Collector) Workload() (Workload, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.workload == nil || c.workload.IsZero() {
+		return Workload{}, false
+	}
+	return *c.workload, true
+}
+
+// ProjectMonthlyCost projects a provider's monthly cost for a workload from
+// the credits measured per operation in this run, priced with the run's
+// pricing profile. Operations are charged in turn against the same
+// calculator, so operations sharing a price list share its tiers.
+func (c *Collector) ProjectMonthlyCost(provider string, w Workload) CostProjection {
+	results := c.GetResultsByProvider(provider)
+	providerType := ""
+	if len(results) > 0 {
+		providerType = results[0].ProviderType
+	}
+	costs := c.costCalculator()
+
+	projection := CostProjection{Provider: provider, Complete: true}
+	volumes := []struct {
+		testType string
+		volume   float64
+	}{
+		{"search", w.Searches},
+		{"extract", w.Extracts},
+		{"crawl", w.CrawlPages},
+	}
+	for _, v := range volumes {
+		if v.volume <= 0 {
+			continue
+		}
+		op := measureOperation(results, v.testType)
+		op.Volume = v.volume
+		if !op.Measured() {
+			projection.Complete = false
+		} else {
+			op.Units = v.volume * op.UnitsPerOp * op.RetryOverhead
+			op.CostUSD = costs.chargeUnits(provider, providerType, op.Units, v.testType)
+		}
+		projection.UsageCostUSD += op.CostUSD
+		projection.Operations = append(projection.Operations, op)
+	}
+
+	if pricing, ok := costs.Profile().For(provider, providerType); ok {
+		projection.MonthlyMinimum = pricing.MonthlyMinimum
+	}
+	projection.MonthlyCostUSD = max(projection.UsageCostUSD, projection.MonthlyMinimum)
+	return projection
+}
+
+// measureOperation measures billing units per successful operation of a test
+// type, per crawled page for crawl, and the attempts needed per success.
+func measureOperation(results []Result, testType string) OperationProjection {
+	op := OperationProjection{TestType: testType}
+	executed := 0
+	credits, per := 0.0, 0.0
+	for _, r := range results {
+		if r.TestType != testType || r.Skipped {
+			continue
+		}
+		executed++
+		if !r.Success {
+			continue
+		}
+		op.Samples++
+		credits += float64(r.CreditsUsed)
+		if testType == "crawl" {
+			per += float64(r.ResultsCount)
+		} else {
+			per++
+		}
+	}
+	if op.Samples == 0 || per == 0 {
+		op.Samples = 0
+		return op
+	}
+	op.UnitsPerOp = credits / per
+	op.RetryOverhead = float64(executed) / float64(op.Samples)
+	return op
+}
+
+// ParetoFrontier reports which points are on the cost-quality frontier: no
+// other point costs at most as much with at least the same quality and is
+// strictly better on one of them.
+func ParetoFrontier(costs, qualities []float64) []bool {
+	frontier := make([]bool, len(costs))
+	for i := range costs {
+		frontier[i] = true
+		for j := range costs {
+			if i == j {
+				continue
+			}
+			noWorse := costs[j] <= costs[i] && qualities[j] >= qualities[i]
+			better := costs[j] < costs[i] || qualities[j] > qualities[i]
+			if noWorse && better {
+				frontier[i] = false
+				break
+			}
+		}
+	}
+	return frontier
+}
@@ -0,0 +1,81 @@
+package benchmetrics
+
+import "testing"
+
+func TestParseWorkload(t *testing.T) {
+	w, err := ParseWorkload("searches=2M, extracts=50k,crawl_pages=1500")
+	if err != nil {
+		t.Fatalf("ParseWorkload failed: %v", err)
+	}
+	if w.Searches != 2e6 || w.Extracts != 5e4 || w.CrawlPages != 1500 {
+		t.Errorf("unexpected workload: %+v", w)
+	}
+	if got := w.String(); got != "2M searches, 50K extracts, 1.5K crawl pages" {
+		t.Errorf("String() = %q", got)
+	}
+
+	for _, invalid := range []string{"queries=10", "searches", "searches=lots", "extracts=-5"} {
+		if _, err := ParseWorkload(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestProjectMonthlyCost(t *testing.T) {
+	c := NewCollector()
+	// Two search attempts per success, 2 credits per successful search.
+	c.AddResult(Result{Provider: "firecrawl", TestType: "search", Success: true, CreditsUsed: 2})
+	c.AddResult(Result{Provider: "firecrawl", TestType: "search", Error: "timeout"})
+	// 10 credits for 5 pages.
+	c.AddResult(Result{Provider: "firecrawl", TestType: "crawl", Success: true, CreditsUsed: 10, ResultsCount: 5})
+	c.SetPricingProfile(PricingProfile{Name: "growth", Providers: map[string]ProviderPricing{
+		"firecrawl": {
+			Tiers:          []PriceTier{{UpTo: 4000, UnitPrice: 0.001}, {UnitPrice: 0.0005}},
+			MonthlyMinimum: 1,
+		},
+	}})
+
+	p := c.ProjectMonthlyCost("firecrawl", Workload{Searches: 1000, Extracts: 10, CrawlPages: 500})
+	if p.Complete {
+		t.Error("expected incomplete projection without extract measurements")
+	}
+	if len(p.Operations) != 3 {
+		t.Fatalf("expected 3 operations, got %d", len(p.Operations))
+	}
+	search := p.Operations[0]
+	if search.UnitsPerOp != 2 || search.RetryOverhead != 2 || search.Units != 4000 {
+		t.Errorf("unexpected search projection: %+v", search)
+	}
+	if !approxEqual(search.CostUSD, 4) {
+		t.Errorf("search cost = %v, want 4", search.CostUSD)
+	}
+	if p.Operations[1].Measured() {
+		t.Error("expected extract to be unmeasured")
+	}
+	// Crawl shares the provider-wide price list, so its 1000 units fall in the second tier.
+	crawl := p.Operations[2]
+	if crawl.Units != 1000 || !approxEqual(crawl.CostUSD, 0.5) {
+		t.Errorf("unexpected crawl projection: %+v", crawl)
+	}
+	if !approxEqual(p.UsageCostUSD, 4.5) || !approxEqual(p.MonthlyCostUSD, 4.5) {
+		t.Errorf("unexpected totals: usage %v, monthly %v", p.UsageCostUSD, p.MonthlyCostUSD)
+	}
+
+	small := c.ProjectMonthlyCost("firecrawl", Workload{Searches: 10})
+	if !small.Complete || !approxEqual(small.MonthlyCostUSD, 1) {
+		t.Errorf("expected monthly minimum of $1 to apply, got %+v", small)
+	}
+}
+
+func TestParetoFrontier(t *testing.T) {
+	costs := []float64{100, 200, 150, 300, 100}
+	qualities := []float64{60, 80, 55, 80, 60}
+	want := []bool{true, true, false, false, true}
+
+	got := ParetoFrontier(costs, qualities)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("point %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
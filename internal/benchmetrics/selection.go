@@ -0,0 +1,66 @@
+package benchmetrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selection records which of the configured tests a run selected.
+type Selection struct {
+	Run      string   `json:"run,omitempty"`       // regular expression matched against test names
+	Tags     []string `json:"tags,omitempty"`      // tests with any of these tags
+	SkipTags []string `json:"skip_tags,omitempty"` // tests with none of these tags
+	Types    []string `json:"types,omitempty"`     // tests of these types
+	NoSearch bool     `json:"no_search,omitempty"` // search tests excluded
+	Quick    bool     `json:"quick,omitempty"`     // quick mode subset
+	// Selected and Configured count the tests run and the tests in the config.
+	Selected   int `json:"selected"`
+	Configured int `json:"configured"`
+}
+
+// IsZero reports whether the selection keeps every configured test.
+func (s Selection) IsZero() bool {
+	return s.Run == "" && len(s.Tags) == 0 && len(s.SkipTags) == 0 && len(s.Types) == 0 && !s.NoSearch && !s.Quick
+}
+
+// String describes the selection, e.g.
+// "4 of 12 tests: -run 'Extract.*' -tags academic,code".
+func (s Selection) String() string {
+	parts := []string{fmt.Sprintf("%d of %d tests:", s.Selected, s.Configured)}
+	if s.Run != "" {
+		parts = append(parts, fmt.Sprintf("-run '%s'", s.Run))
+	}
+	for _, list := range []struct {
+		flag   string
+		values []string
+	}{{"-tags", s.Tags}, {"-skip-tags", s.SkipTags}, {"-types", s.Types}} {
+		if len(list.values) > 0 {
+			parts = append(parts, list.flag+" "+strings.Join(list.values, ","))
+		}
+	}
+	if s.NoSearch {
+		parts = append(parts, "-no-search")
+	}
+	if s.Quick {
+		parts = append(parts, "-quick")
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetSelection records the test selection the results were run with.
+func (c *Collector) SetSelection(s Selection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.selection = &s
+}
+
+// Selection returns the recorded test selection and whether the run was
+// limited to a subset of the configured tests.
+func (c *Collector) Selection() (Selection, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.selection == nil || c.selection.IsZero() {
+		return Selection{}, false
+	}
+	return *c.selection, true
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Assertions are service-level checks on a test's results, evaluated after
+// the run. Unset fields are not checked.
+type Assertions struct {
+	MaxLatencyMS    *int64   `toml:"max_latency_ms,omitempty"`
+	MinQualityScore *float64 `toml:"min_quality_score,omitempty"` // 0-100
+	MinResults      *int     `toml:"min_results,omitempty"`
+	MaxCostUSD      *float64 `toml:"max_cost_usd,omitempty"`
+	// MustSucceed requires every run to succeed. Failed runs also violate
+	// the other assertions unless MustSucceed is explicitly false, which
+	// checks only the successful runs.
+	MustSucceed *bool `toml:"must_succeed,omitempty"`
+}
+
+// AssertionsConfig holds assertions for every provider and overrides for
+// single providers, keyed by instance name or provider type. It appears as
+// the top-level [assertions] section, applying to every test, and as
+// [tests.assertions] on a test.
+type AssertionsConfig struct {
+	Assertions
+	Providers map[string]Assertions `toml:"providers,omitempty"`
+}
+
+// IsZero reports whether no assertion is set.
+func (a Assertions) IsZero() bool {
+	return a.MaxLatencyMS == nil && a.MinQualityScore == nil && a.MinResults == nil &&
+		a.MaxCostUSD == nil && a.MustSucceed == nil
+}
+
+// merge returns a with the fields set in override replaced.
+func (a Assertions) merge(override Assertions) Assertions {
+	if override.MaxLatencyMS != nil {
+		a.MaxLatencyMS = override.MaxLatencyMS
+	}
+	if override.MinQualityScore != nil {
+		a.MinQualityScore = override.MinQualityScore
+	}
+	if override.MinResults != nil {
+		a.MinResults = override.MinResults
+	}
+	if override.MaxCostUSD != nil {
+		a.MaxCostUSD = override.MaxCostUSD
+	}
+	if override.MustSucceed != nil {
+		a.MustSucceed = override.MustSucceed
+	}
+	return a
+}
+
+// forProvider layers the provider's overrides, by type and then by instance
+// name, over the assertions for every provider.
+func (a AssertionsConfig) forProvider(provider, providerType string) Assertions {
+	out := a.Assertions
+	if providerType != "" && providerType != provider {
+		out = out.merge(a.Providers[providerType])
+	}
+	return out.merge(a.Providers[provider])
+}
+
+// IsZero reports whether no assertion is set for any provider.
+func (a AssertionsConfig) IsZero() bool {
+	if !a.Assertions.IsZero() {
+		return false
+	}
+	for _, p := range a.Providers {
+		if !p.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// AssertionsFor returns the assertions that apply to a test's results for a
+// provider. Test assertions override the top-level [assertions] section, and
+// provider overrides the assertions for every provider at the same level.
+func (c *Config) AssertionsFor(test TestConfig, provider, providerType string) Assertions {
+	provider = strings.ToLower(provider)
+	providerType = strings.ToLower(providerType)
+	return c.Assertions.forProvider(provider, providerType).merge(test.Assertions.forProvider(provider, providerType))
+}
+
+// HasAssertions reports whether the config sets any assertion.
+func (c *Config) HasAssertions() bool {
+	if !c.Assertions.IsZero() {
+		return true
+	}
+	for _, test := range c.Tests {
+		if !test.Assertions.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases the provider keys and validates every assertion.
+func (a *AssertionsConfig) normalize(scope string) error {
+	if err := a.Assertions.validate(scope); err != nil {
+		return err
+	}
+	if len(a.Providers) == 0 {
+		return nil
+	}
+	normalized := make(map[string]Assertions, len(a.Providers))
+	for rawName, p := range a.Providers {
+		name := strings.ToLower(strings.TrimSpace(rawName))
+		if name == "" {
+			return fmt.Errorf("%s: empty provider name", scope)
+		}
+		if _, exists := normalized[name]; exists {
+			return fmt.Errorf("%s: provider '%s' is set more than once", scope, name)
+		}
+		if err := p.validate(fmt.Sprintf("%s (provider '%s')", scope, name)); err != nil {
+			return err
+		}
+		normalized[name] = p
+	}
+	a.Providers = normalized
+	return nil
+}
+
+func (a Assertions) validate(scope string) error {
+	if a.MaxLatencyMS != nil && *a.MaxLatencyMS <= 0 {
+		return fmt.Errorf("%s: max_latency_ms must be > 0", scope)
+	}
+	if a.MinQualityScore != nil && (*a.MinQualityScore < 0 || *a.MinQualityScore > 100) {
+		return fmt.Errorf("%s: min_quality_score must be between 0 and 100", scope)
+	}
+	if a.MinResults != nil && *a.MinResults < 0 {
+		return fmt.Errorf("%s: min_results must be >= 0", scope)
+	}
+	if a.MaxCostUSD != nil && *a.MaxCostUSD < 0 {
+		return fmt.Errorf("%s: max_cost_usd must be >= 0", scope)
+	}
+	return nil
+}
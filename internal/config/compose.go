@@ -0,0 +1,362 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// suiteFile is a file named by a config's include patterns. It contributes
+// tests only; their names are prefixed with the suite name, which defaults
+// to the file name without its extension.
+type suiteFile struct {
+	Suite string       `toml:"suite,omitempty"`
+	Tests []TestConfig `toml:"tests"`
+}
+
+// envPattern matches ${VAR} and ${VAR:-default}, and their escaped forms
+// $${VAR} and $${VAR:-default}.
+var envPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s with the
+// environment. As in the shell, the default also replaces an empty variable.
+// A variable that is unset and has no default is an error. $${VAR} stands
+// for a literal ${VAR}.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	out := envPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		m := envPattern.FindStringSubmatch(ref)
+		value, ok := os.LookupEnv(m[1])
+		if m[2] != "" && value == "" {
+			return m[3]
+		}
+		if ok {
+			return value
+		}
+		missing = append(missing, m[1])
+		return ref
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// escapeEnv escapes the references in s, so that expandEnv gives s back.
+func escapeEnv(s string) (string, error) {
+	return envPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return "$" + ref
+	}), nil
+}
+
+// escapedCopy stores in dst, a pointer, a deep copy of src with every string
+// escaped, so that interpolating dst gives src's strings back. The copy is
+// made by a TOML round trip, so dst holds what a saved file would load as.
+func escapedCopy(src, dst interface{}) error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(src); err != nil {
+		return err
+	}
+	if _, err := toml.Decode(buf.String(), dst); err != nil {
+		return err
+	}
+	return rewriteValue(reflect.ValueOf(dst), escapeEnv)
+}
+
+// secretSections are the parts of a config secrets are referenced from.
+type secretSections struct {
+	providers map[string]ProviderConfig
+	webhooks  []WebhookConfig
+}
+
+// secretSections returns a deep copy of c's provider sections and webhooks,
+// which interpolation does not change.
+func (c *Config) secretSections() *secretSections {
+	sections := &secretSections{}
+	if c.Providers != nil {
+		sections.providers = make(map[string]ProviderConfig, len(c.Providers))
+		for name, p := range c.Providers {
+			p.BaseURLs = maps.Clone(p.BaseURLs)
+			p.Headers = maps.Clone(p.Headers)
+			sections.providers[name] = p
+		}
+	}
+	for _, w := range c.Notifications.Webhooks {
+		w.Events = slices.Clone(w.Events)
+		w.Headers = maps.Clone(w.Headers)
+		sections.webhooks = append(sections.webhooks, w)
+	}
+	return sections
+}
+
+// interpolate expands environment references in every string field of v,
+// which must be a pointer, including strings in slices and maps.
+func interpolate(v interface{}) error {
+	return rewriteValue(reflect.ValueOf(v), expandEnv)
+}
+
+// rewriteValue replaces every string in v, as interpolate describes, with
+// the result of rewrite.
+func rewriteValue(v reflect.Value, rewrite func(string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			expanded, err := rewriteDynamic(v.Interface(), rewrite)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(expanded))
+			return nil
+		}
+		return rewriteValue(v.Elem(), rewrite)
+	case reflect.String:
+		expanded, err := rewrite(v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(expanded)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := rewriteValue(v.Field(i), rewrite); err != nil {
+				return fmt.Errorf("%s: %w", tomlName(v.Type().Field(i)), err)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := rewriteValue(v.Index(i), rewrite); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable, so each is copied, expanded and
+		// stored back.
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			if err := rewriteValue(value, rewrite); err != nil {
+				return fmt.Errorf("%s: %w", iter.Key(), err)
+			}
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+	return nil
+}
+
+// rewriteDynamic rewrites strings in decoded TOML values of unknown type,
+// such as a structured test's expected object.
+func rewriteDynamic(x interface{}, rewrite func(string) (string, error)) (interface{}, error) {
+	switch value := x.(type) {
+	case string:
+		return rewrite(value)
+	case []interface{}:
+		for i, item := range value {
+			expanded, err := rewriteDynamic(item, rewrite)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = expanded
+		}
+	case map[string]interface{}:
+		for key, item := range value {
+			expanded, err := rewriteDynamic(item, rewrite)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			value[key] = expanded
+		}
+	}
+	return x, nil
+}
+
+func tomlName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("toml"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// resolveIncludes appends the tests of the suite files matched by the
+// config's include patterns, resolved against baseDir, and clears the
+// patterns. Relative reference files in a suite resolve against the suite
+// file's directory.
+func (c *Config) resolveIncludes(baseDir string) error {
+	paths, err := includePaths(c.Include, baseDir)
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		tests, err := loadSuite(path)
+		if err != nil {
+			return err
+		}
+		c.Tests = append(c.Tests, tests...)
+	}
+	c.Include = nil
+	return nil
+}
+
+// includePaths returns the files matched by include patterns, in pattern and
+// then file name order, without duplicates. A pattern matching nothing is an
+// error.
+func includePaths(patterns []string, baseDir string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("include pattern %q matches no files", pattern)
+		}
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// loadSuite reads a suite file and returns its tests with prefixed names.
+func loadSuite(path string) ([]TestConfig, error) {
+	// #nosec G304 - Suite files are named by the user's config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite file: %w", err)
+	}
+	var suite suiteFile
+	meta, err := toml.Decode(string(data), &suite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse suite file %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("suite file %s may only set suite and [[tests]], found %s", path, undecoded[0])
+	}
+	if err := interpolate(&suite); err != nil {
+		return nil, fmt.Errorf("suite file %s: %w", path, err)
+	}
+
+	return suite.resolve(path)
+}
+
+// resolve returns the suite's tests named with its prefix, with relative
+// reference files resolved against the directory of the suite file at path.
+func (s suiteFile) resolve(path string) ([]TestConfig, error) {
+	name := strings.TrimSpace(s.Suite)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for i := range s.Tests {
+		test := &s.Tests[i]
+		if test.Name == "" {
+			return nil, fmt.Errorf("suite file %s: test at index %d is missing a name", path, i)
+		}
+		test.Name = name + "/" + test.Name
+		if test.ReferenceFile != "" && !filepath.IsAbs(test.ReferenceFile) {
+			if err := validatePath(test.ReferenceFile); err != nil {
+				return nil, fmt.Errorf("test '%s' has invalid reference_file: %w", test.Name, err)
+			}
+			ref, err := filepath.Abs(filepath.Join(filepath.Dir(path), test.ReferenceFile))
+			if err != nil {
+				return nil, fmt.Errorf("test '%s' has invalid reference_file: %w", test.Name, err)
+			}
+			test.ReferenceFile = ref
+		}
+	}
+	return s.Tests, nil
+}
+
+// applyProfile overrides the general settings with the fields set in the
+// named profile and clears the profiles. An empty name applies none.
+func (c *Config) applyProfile(name string) error {
+	defer func() { c.Profiles = nil }()
+	if name == "" {
+		return nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q: the config defines no [profiles]", name)
+		}
+		return fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", "))
+	}
+
+	g := &c.General
+	if profile.Concurrency > 0 {
+		g.Concurrency = profile.Concurrency
+	}
+	if profile.Timeout != "" {
+		g.Timeout = profile.Timeout
+	}
+	if profile.OutputDir != "" {
+		g.OutputDir = profile.OutputDir
+	}
+	if profile.FixturesDir != "" {
+		g.FixturesDir = profile.FixturesDir
+	}
+	if len(profile.ProviderConcurrency) > 0 {
+		merged := make(map[string]int, len(g.ProviderConcurrency)+len(profile.ProviderConcurrency))
+		for provider, limit := range g.ProviderConcurrency {
+			merged[provider] = limit
+		}
+		for provider, limit := range profile.ProviderConcurrency {
+			merged[provider] = limit
+		}
+		g.ProviderConcurrency = merged
+	}
+	return nil
+}
+
+// SaveSuite writes tests to path as a suite file named suite, for a config to
+// include. Test names are written as given; loading prefixes them.
+func SaveSuite(path, suite string, tests []TestConfig) error {
+	if err := validatePath(path); err != nil {
+		return fmt.Errorf("invalid suite path: %w", err)
+	}
+
+	// Includes are interpolated, so strings are escaped to load as written.
+	var escaped suiteFile
+	if err := escapedCopy(suiteFile{Suite: suite, Tests: tests}, &escaped); err != nil {
+		return fmt.Errorf("failed to escape suite: %w", err)
+	}
+
+	// #nosec G304 - Path validated above, this is intentional file creation
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create suite file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return toml.NewEncoder(f).Encode(escaped)
+}
@@ -2,6 +2,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,12 +10,74 @@ import (
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/document"
+	"github.com/lamim/SanityWebEval/internal/faultproxy"
 )
 
+// FixtureScheme prefixes test URLs that name a file in fixtures_dir, e.g.
+// fixture://report.pdf. The runner serves these from a local HTTP server.
+const FixtureScheme = "fixture://"
+
 // Config represents the main configuration structure
 type Config struct {
-	General GeneralConfig `toml:"general"`
-	Tests   []TestConfig  `toml:"tests"`
+	// Include lists glob patterns, relative to the config file, naming suite
+	// files whose tests are added to Tests. Load resolves and clears it.
+	Include []string `toml:"include,omitempty"`
+	// Profiles are named overrides of the general settings, selected with
+	// LoadProfile. Load applies the chosen profile and clears them.
+	Profiles      map[string]GeneralConfig  `toml:"profiles,omitempty"`
+	General       GeneralConfig             `toml:"general"`
+	Providers     map[string]ProviderConfig `toml:"providers"`
+	Pricing       PricingConfig             `toml:"pricing"`
+	Projection    benchmetrics.Workload     `toml:"projection"`
+	Faults        FaultsConfig              `toml:"faults"`
+	Assertions    AssertionsConfig          `toml:"assertions"`
+	Capture       CaptureConfig             `toml:"capture"`
+	Notifications NotificationsConfig       `toml:"notifications"`
+	Tests         []TestConfig              `toml:"tests"`
+
+	// unexpanded keeps the sections secrets are referenced from as they
+	// were before ${VAR} expansion, for Save.
+	unexpanded *secretSections
+}
+
+// CaptureConfig limits how much of each provider response is kept for the
+// HTML report's result explorer.
+type CaptureConfig struct {
+	Disabled bool `toml:"disabled"`
+	MaxItems int  `toml:"max_items"` // search results or crawled pages per test; default 10
+	MaxChars int  `toml:"max_chars"` // content characters per item; default 2000
+	// Sidecar writes the outputs to outputs.json instead of report.json.
+	Sidecar bool `toml:"sidecar"`
+}
+
+// Limits returns the item and character limits, applying the defaults for
+// unset values.
+func (c CaptureConfig) Limits() (maxItems, maxChars int) {
+	maxItems, maxChars = c.MaxItems, c.MaxChars
+	if maxItems <= 0 {
+		maxItems = 10
+	}
+	if maxChars <= 0 {
+		maxChars = 2000
+	}
+	return maxItems, maxChars
+}
+
+// FaultsConfig sets the fault rates used when the benchmark runs through the
+// fault-injection proxy (-faults). Rates are per-request probabilities; when
+// all are zero, faultproxy.DefaultConfig is used.
+type FaultsConfig struct {
+	Seed              uint64  `toml:"seed"` // fixed seed for a reproducible fault sequence
+	LatencyRate       float64 `toml:"latency_rate"`
+	Latency           string  `toml:"latency"`
+	RateLimitRate     float64 `toml:"rate_limit_rate"`
+	RetryAfter        string  `toml:"retry_after"`
+	ServerErrorRate   float64 `toml:"server_error_rate"`
+	ResetRate         float64 `toml:"reset_rate"`
+	TruncateRate      float64 `toml:"truncate_rate"`
+	MalformedJSONRate float64 `toml:"malformed_json_rate"`
 }
 
 // GeneralConfig contains general settings
@@ -23,6 +86,9 @@ type GeneralConfig struct {
 	ProviderConcurrency map[string]int `toml:"provider_concurrency"`
 	Timeout             string         `toml:"timeout"`
 	OutputDir           string         `toml:"output_dir"`
+	// FixturesDir holds documents served locally for fixture:// test URLs.
+	// Relative paths are resolved against the config file's directory.
+	FixturesDir string `toml:"fixtures_dir"`
 }
 
 func defaultProviderConcurrency() map[string]int {
@@ -40,9 +106,11 @@ func defaultProviderConcurrency() map[string]int {
 // TestConfig represents a single test case
 type TestConfig struct {
 	Name  string `toml:"name"`
-	Type  string `toml:"type"` // search, extract, crawl
+	Type  string `toml:"type"` // search, extract, crawl, structured_extract
 	Query string `toml:"query,omitempty"`
 	URL   string `toml:"url,omitempty"`
+	// Tags group tests for -tags and -skip-tags, e.g. ["academic", "slow"].
+	Tags []string `toml:"tags,omitempty"`
 	// MaxPages and MaxDepth are pointers so explicit zero values in TOML
 	// are distinguishable from unset fields.
 	MaxPages               *int     `toml:"max_pages,omitempty"`
@@ -57,6 +125,33 @@ type TestConfig struct {
 	ExpectedURLPatterns    []string `toml:"expected_url_patterns,omitempty"`
 	ExpectedMaxDepth       *int     `toml:"expected_max_depth,omitempty"`
 	FreshnessReferenceDate string   `toml:"freshness_reference_date,omitempty"`
+	// Relevance grades expected URLs or document IDs of search tests as IR
+	// qrels do, higher being more relevant, and adds an nDCG metric.
+	Relevance map[string]int `toml:"relevance,omitempty"`
+	// Search filters mapped onto providers.SearchOptions and checked for compliance.
+	TimeRange      string   `toml:"time_range,omitempty"` // day, week, month, year
+	IncludeDomains []string `toml:"include_domains,omitempty"`
+	ExcludeDomains []string `toml:"exclude_domains,omitempty"`
+	Country        string   `toml:"country,omitempty"`
+	Language       string   `toml:"language,omitempty"`
+	SafeSearch     string   `toml:"safe_search,omitempty"`
+	// Structured extraction: a JSON Schema (as a JSON string), the object the
+	// provider is expected to return and an optional extraction prompt.
+	Schema           string                 `toml:"schema,omitempty"`
+	Expected         map[string]interface{} `toml:"expected,omitempty"`
+	ExtractionPrompt string                 `toml:"extraction_prompt,omitempty"`
+	// Document extraction: the expected document type (pdf, docx, text; inferred
+	// from the URL extension when empty), a reference text to score fidelity
+	// against (inline or from a file relative to the config) and the expected
+	// structure.
+	DocumentType   string `toml:"document_type,omitempty"`
+	ReferenceText  string `toml:"reference_text,omitempty"`
+	ReferenceFile  string `toml:"reference_file,omitempty"`
+	ExpectedPages  *int   `toml:"expected_pages,omitempty"`
+	ExpectedTables *int   `toml:"expected_tables,omitempty"`
+	// Assertions are SLO checks on this test's results, overriding the
+	// top-level [assertions] section.
+	Assertions AssertionsConfig `toml:"assertions,omitempty"`
 }
 
 // TimeoutDuration parses the timeout string into a Duration
@@ -126,6 +221,14 @@ func validatePath(path string) error {
 
 // Load reads and parses the TOML configuration file
 func Load(path string) (*Config, error) {
+	return LoadProfile(path, "")
+}
+
+// LoadProfile reads and parses the TOML configuration file and applies the
+// named profile; an empty name applies none. Relative paths are resolved
+// against the config file's absolute directory, so the resolved config can
+// be saved elsewhere and loaded again.
+func LoadProfile(path, profile string) (*Config, error) {
 	// Validate path for security
 	if err := validatePath(path); err != nil {
 		return nil, fmt.Errorf("invalid config path: %w", err)
@@ -136,72 +239,377 @@ func Load(path string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config directory: %w", err)
+	}
+	return ParseProfile(data, baseDir, profile)
+}
 
+// Parse parses and validates TOML configuration data. Relative paths in it
+// are resolved against baseDir.
+func Parse(data []byte, baseDir string) (*Config, error) {
+	return ParseProfile(data, baseDir, "")
+}
+
+// ParseProfile parses TOML configuration data, expands ${VAR} and
+// ${VAR:-default} references to environment variables in its strings, adds
+// the tests of included suite files, applies the named profile and validates
+// the result.
+func ParseProfile(data []byte, baseDir, profile string) (*Config, error) {
 	var cfg Config
 	if err := toml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	cfg.unexpanded = cfg.secretSections()
+	if err := interpolate(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to expand config file: %w", err)
+	}
+	if err := cfg.resolveIncludes(baseDir); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyProfile(profile); err != nil {
+		return nil, err
+	}
+	if err := cfg.Prepare(baseDir); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
 
+// ParseInline parses a config submitted to the API server, which runs it with
+// the server's credentials and files. Settings that reach beyond the config
+// itself are rejected: provider sections, which set endpoints, headers and
+// API key variables, includes, profiles, the fixtures directory, reference
+// and pricing files, and notifications. ${VAR} references are not expanded,
+// so the server's environment cannot end up in reports.
+func ParseInline(data []byte) (*Config, error) {
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if keys := cfg.serverSideKeys(); len(keys) > 0 {
+		return nil, fmt.Errorf("inline configs cannot set %s", strings.Join(keys, ", "))
+	}
+	if err := cfg.Prepare(""); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// serverSideKeys returns the settings ParseInline rejects that c sets.
+func (c *Config) serverSideKeys() []string {
+	var keys []string
+	add := func(set bool, key string) {
+		if set {
+			keys = append(keys, key)
+		}
+	}
+	add(len(c.Include) > 0, "include")
+	add(len(c.Profiles) > 0, "profiles")
+	add(len(c.Providers) > 0, "providers")
+	add(c.General.FixturesDir != "", "general.fixtures_dir")
+	add(c.Pricing.File != "", "pricing.file")
+	add(len(c.Notifications.Webhooks) > 0 || c.Notifications.Baseline != "", "notifications")
+	for _, test := range c.Tests {
+		if test.ReferenceFile != "" {
+			keys = append(keys, "tests.reference_file")
+			break
+		}
+	}
+	return keys
+}
+
+// Prepare fills in defaults, resolves relative paths against baseDir and
+// validates the configuration. Parse calls it on every decoded config;
+// configs built in code must call it before they are run.
+func (c *Config) Prepare(baseDir string) error {
 	// Set defaults
-	if cfg.General.Concurrency <= 0 {
-		cfg.General.Concurrency = 5
+	if c.General.Concurrency <= 0 {
+		c.General.Concurrency = 5
 	}
-	if cfg.General.Timeout == "" {
-		cfg.General.Timeout = "30s"
+	if c.General.Timeout == "" {
+		c.General.Timeout = "30s"
 	}
-	if cfg.General.OutputDir == "" {
-		cfg.General.OutputDir = "./results"
+	if c.General.OutputDir == "" {
+		c.General.OutputDir = "./results"
 	}
-	if len(cfg.General.ProviderConcurrency) == 0 {
-		cfg.General.ProviderConcurrency = defaultProviderConcurrency()
+	if len(c.General.ProviderConcurrency) == 0 {
+		c.General.ProviderConcurrency = defaultProviderConcurrency()
 	}
-	normalizedProviderConcurrency, err := normalizeProviderConcurrency(cfg.General.ProviderConcurrency)
+	normalizedProviderConcurrency, err := normalizeProviderConcurrency(c.General.ProviderConcurrency)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	c.General.ProviderConcurrency = normalizedProviderConcurrency
+	if err := c.normalizeProviders(); err != nil {
+		return err
+	}
+
+	if c.General.FixturesDir != "" && !filepath.IsAbs(c.General.FixturesDir) {
+		c.General.FixturesDir = filepath.Join(baseDir, c.General.FixturesDir)
+	}
+
+	if c.Pricing.File != "" && !filepath.IsAbs(c.Pricing.File) {
+		c.Pricing.File = filepath.Join(baseDir, c.Pricing.File)
+	}
+	if _, err := c.Pricing.Profile(); err != nil {
+		return err
+	}
+	if err := c.Projection.Validate(); err != nil {
+		return fmt.Errorf("invalid projection: %w", err)
+	}
+
+	if _, err := c.Faults.ProxyConfig(); err != nil {
+		return err
+	}
+	if err := c.Assertions.normalize("[assertions]"); err != nil {
+		return err
+	}
+	if c.Capture.MaxItems < 0 || c.Capture.MaxChars < 0 {
+		return fmt.Errorf("invalid capture limits: max_items and max_chars must not be negative")
+	}
+	if err := c.Notifications.normalize(baseDir); err != nil {
+		return err
 	}
-	cfg.General.ProviderConcurrency = normalizedProviderConcurrency
 
 	// Validate tests
-	if len(cfg.Tests) == 0 {
-		return nil, fmt.Errorf("no tests defined in configuration")
+	if len(c.Tests) == 0 {
+		return fmt.Errorf("no tests defined in configuration")
 	}
 
-	for i, test := range cfg.Tests {
-		if test.Name == "" {
-			return nil, fmt.Errorf("test at index %d is missing a name", i)
+	for i, test := range c.Tests {
+		if err := validateTest(i, test); err != nil {
+			return err
+		}
+		if err := loadDocumentOptions(&c.Tests[i], c.General.FixturesDir, baseDir); err != nil {
+			return err
+		}
+		if err := c.Tests[i].Assertions.normalize(fmt.Sprintf("test '%s' assertions", test.Name)); err != nil {
+			return err
 		}
-		if test.Type != "search" && test.Type != "extract" && test.Type != "crawl" {
-			return nil, fmt.Errorf("test '%s' has invalid type: %s", test.Name, test.Type)
+		if err := c.Tests[i].normalizeTags(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTest checks the settings of the test at index i that need no files.
+func validateTest(i int, test TestConfig) error {
+	if test.Name == "" {
+		return fmt.Errorf("test at index %d is missing a name", i)
+	}
+	if !containsString(testTypes, test.Type) {
+		return fmt.Errorf("test '%s' has invalid type: %s", test.Name, test.Type)
+	}
+	if test.Type == "search" && test.Query == "" {
+		return fmt.Errorf("test '%s' of type 'search' requires a query", test.Name)
+	}
+	if (test.Type == "extract" || test.Type == "crawl" || test.Type == "structured_extract") && test.URL == "" {
+		return fmt.Errorf("test '%s' of type '%s' requires a URL", test.Name, test.Type)
+	}
+	if test.MaxPages != nil && *test.MaxPages < 0 {
+		return fmt.Errorf("test '%s' has invalid max_pages: %d", test.Name, *test.MaxPages)
+	}
+	if test.MaxDepth != nil && *test.MaxDepth < 0 {
+		return fmt.Errorf("test '%s' has invalid max_depth: %d", test.Name, *test.MaxDepth)
+	}
+	if test.ExpectedMaxDepth != nil && *test.ExpectedMaxDepth < 0 {
+		return fmt.Errorf("test '%s' has invalid expected_max_depth: %d", test.Name, *test.ExpectedMaxDepth)
+	}
+	if err := validateSearchFilters(test); err != nil {
+		return err
+	}
+	for id, grade := range test.Relevance {
+		if grade < 0 {
+			return fmt.Errorf("test '%s' has invalid relevance grade for %s: %d", test.Name, id, grade)
 		}
-		if test.Type == "search" && test.Query == "" {
-			return nil, fmt.Errorf("test '%s' of type 'search' requires a query", test.Name)
+	}
+	if test.Type == "structured_extract" {
+		if _, err := test.JSONSchema(); err != nil {
+			return fmt.Errorf("test '%s' has invalid schema: %w", test.Name, err)
 		}
-		if (test.Type == "extract" || test.Type == "crawl") && test.URL == "" {
-			return nil, fmt.Errorf("test '%s' of type '%s' requires a URL", test.Name, test.Type)
+	}
+	return nil
+}
+
+// ProxyConfig converts the fault settings for the fault-injection proxy.
+func (f FaultsConfig) ProxyConfig() (faultproxy.Config, error) {
+	cfg := faultproxy.Config{
+		Seed:              f.Seed,
+		LatencyRate:       f.LatencyRate,
+		RateLimitRate:     f.RateLimitRate,
+		ServerErrorRate:   f.ServerErrorRate,
+		ResetRate:         f.ResetRate,
+		TruncateRate:      f.TruncateRate,
+		MalformedJSONRate: f.MalformedJSONRate,
+	}
+	if cfg.LatencyRate+cfg.RateLimitRate+cfg.ServerErrorRate+cfg.ResetRate+cfg.TruncateRate+cfg.MalformedJSONRate == 0 {
+		cfg = faultproxy.DefaultConfig()
+		cfg.Seed = f.Seed
+	}
+	defaults := faultproxy.DefaultConfig()
+	cfg.Latency, cfg.RetryAfter = defaults.Latency, defaults.RetryAfter
+	if f.Latency != "" {
+		d, err := time.ParseDuration(f.Latency)
+		if err != nil {
+			return faultproxy.Config{}, fmt.Errorf("invalid faults.latency: %w", err)
 		}
-		if test.MaxPages != nil && *test.MaxPages < 0 {
-			return nil, fmt.Errorf("test '%s' has invalid max_pages: %d", test.Name, *test.MaxPages)
+		cfg.Latency = d
+	}
+	if f.RetryAfter != "" {
+		d, err := time.ParseDuration(f.RetryAfter)
+		if err != nil {
+			return faultproxy.Config{}, fmt.Errorf("invalid faults.retry_after: %w", err)
 		}
-		if test.MaxDepth != nil && *test.MaxDepth < 0 {
-			return nil, fmt.Errorf("test '%s' has invalid max_depth: %d", test.Name, *test.MaxDepth)
+		cfg.RetryAfter = d
+	}
+	if err := cfg.Validate(); err != nil {
+		return faultproxy.Config{}, fmt.Errorf("invalid faults: %w", err)
+	}
+	return cfg, nil
+}
+
+// FreshnessReference returns the "now" used to age results for time_range
+// checks. It parses freshness_reference_date (YYYY-MM-DD or RFC 3339).
+func (t TestConfig) FreshnessReference() (time.Time, bool) {
+	raw := strings.TrimSpace(t.FreshnessReferenceDate)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		return ts, true
+	}
+	if ts, err := time.Parse("2006-01-02", raw); err == nil {
+		// Treat a bare date as the end of that day.
+		return ts.Add(24*time.Hour - time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// JSONSchema parses the test's schema string. The root must describe an object.
+func (t TestConfig) JSONSchema() (map[string]interface{}, error) {
+	if strings.TrimSpace(t.Schema) == "" {
+		return nil, fmt.Errorf("schema is required for structured_extract tests")
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(t.Schema), &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema JSON: %w", err)
+	}
+	if rootType, ok := schema["type"]; ok && rootType != "object" {
+		return nil, fmt.Errorf("schema root type must be object, got %v", rootType)
+	}
+	if _, ok := schema["properties"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("schema must declare properties")
+	}
+	return schema, nil
+}
+
+// IsFixtureURL reports whether the test URL names a file in fixtures_dir.
+func (t TestConfig) IsFixtureURL() bool {
+	return strings.HasPrefix(t.URL, FixtureScheme)
+}
+
+// loadDocumentOptions validates document extraction settings and reads
+// reference_file into ReferenceText.
+func loadDocumentOptions(test *TestConfig, fixturesDir, baseDir string) error {
+	if test.DocumentType != "" && document.ParseFormat(test.DocumentType) == "" {
+		return fmt.Errorf("test '%s' has invalid document_type: %s (expected html, pdf, docx or text)", test.Name, test.DocumentType)
+	}
+	if test.ExpectedPages != nil && *test.ExpectedPages < 0 {
+		return fmt.Errorf("test '%s' has invalid expected_pages: %d", test.Name, *test.ExpectedPages)
+	}
+	if test.ExpectedTables != nil && *test.ExpectedTables < 0 {
+		return fmt.Errorf("test '%s' has invalid expected_tables: %d", test.Name, *test.ExpectedTables)
+	}
+	if test.IsFixtureURL() {
+		if fixturesDir == "" {
+			return fmt.Errorf("test '%s' uses a fixture URL but general.fixtures_dir is not set", test.Name)
 		}
-		if test.ExpectedMaxDepth != nil && *test.ExpectedMaxDepth < 0 {
-			return nil, fmt.Errorf("test '%s' has invalid expected_max_depth: %d", test.Name, *test.ExpectedMaxDepth)
+		if err := validatePath(strings.TrimPrefix(test.URL, FixtureScheme)); err != nil {
+			return fmt.Errorf("test '%s' has invalid fixture URL: %w", test.Name, err)
 		}
 	}
+	if test.ReferenceFile == "" {
+		return nil
+	}
+	if test.ReferenceText != "" {
+		return fmt.Errorf("test '%s' sets both reference_text and reference_file", test.Name)
+	}
+	if err := validatePath(test.ReferenceFile); err != nil {
+		return fmt.Errorf("test '%s' has invalid reference_file: %w", test.Name, err)
+	}
+	refPath := test.ReferenceFile
+	if !filepath.IsAbs(refPath) {
+		refPath = filepath.Join(baseDir, refPath)
+	}
+	// #nosec G304 - Path validated above, reference files are user-provided fixtures
+	data, err := os.ReadFile(refPath)
+	if err != nil {
+		return fmt.Errorf("test '%s' failed to read reference_file: %w", test.Name, err)
+	}
+	test.ReferenceText = string(data)
+	// The text now stands in for the file, so a saved config loads again.
+	test.ReferenceFile = ""
+	return nil
+}
 
-	return &cfg, nil
+func validateSearchFilters(test TestConfig) error {
+	switch test.TimeRange {
+	case "", "day", "week", "month", "year":
+	default:
+		return fmt.Errorf("test '%s' has invalid time_range: %s (expected day, week, month or year)", test.Name, test.TimeRange)
+	}
+	if test.FreshnessReferenceDate != "" {
+		if _, ok := test.FreshnessReference(); !ok {
+			return fmt.Errorf("test '%s' has invalid freshness_reference_date: %s", test.Name, test.FreshnessReferenceDate)
+		}
+	}
+	switch strings.ToLower(test.SafeSearch) {
+	case "", "off", "moderate", "strict":
+	default:
+		return fmt.Errorf("test '%s' has invalid safe_search: %s (expected off, moderate or strict)", test.Name, test.SafeSearch)
+	}
+	if test.Country != "" && len(strings.TrimSpace(test.Country)) < 2 {
+		return fmt.Errorf("test '%s' has invalid country: %s", test.Name, test.Country)
+	}
+	for _, include := range test.IncludeDomains {
+		for _, exclude := range test.ExcludeDomains {
+			if strings.EqualFold(strings.TrimSpace(include), strings.TrimSpace(exclude)) {
+				return fmt.Errorf("test '%s' both includes and excludes domain %s", test.Name, include)
+			}
+		}
+	}
+	return nil
 }
 
-// Save writes the configuration to a TOML file
+// Save writes the configuration to a TOML file only the user can read. For
+// a loaded config this is the fully resolved configuration: includes,
+// environment references and the profile are applied and reference files are
+// inlined. Provider sections and webhooks, where secrets such as tokens in
+// headers and webhook URLs are referenced, are written as they were before
+// ${VAR} expansion; other expanded strings are escaped, so loading the file
+// does not expand them again.
 func (c *Config) Save(path string) error {
 	// Validate path for security
 	if err := validatePath(path); err != nil {
 		return fmt.Errorf("invalid config path: %w", err)
 	}
 
+	out := *c
+	if c.unexpanded != nil {
+		out = Config{}
+		if err := escapedCopy(c, &out); err != nil {
+			return fmt.Errorf("failed to escape config: %w", err)
+		}
+		out.Providers = c.unexpanded.providers
+		out.Notifications.Webhooks = c.unexpanded.webhooks
+	}
+
 	// #nosec G304 - Path validated above, this is intentional file creation
-	f, err := os.Create(path)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
@@ -210,5 +618,5 @@ func (c *Config) Save(path string) error {
 	}()
 
 	encoder := toml.NewEncoder(f)
-	return encoder.Encode(c)
+	return encoder.Encode(out)
 }
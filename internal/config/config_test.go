@@ -3,7 +3,12 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
 )
 
 func TestLoad_ValidConfig(t *testing.T) {
@@ -150,6 +155,32 @@ func TestLoad_MissingFileError(t *testing.T) {
 	}
 }
 
+func TestParse_ResolvesPathsAgainstBaseDir(t *testing.T) {
+	content := `
+[general]
+fixtures_dir = "fixtures"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "golang"
+`
+	cfg, err := Parse([]byte(content), "/srv/configs")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.General.FixturesDir != filepath.Join("/srv/configs", "fixtures") {
+		t.Errorf("FixturesDir = %q, want it resolved against the base dir", cfg.General.FixturesDir)
+	}
+	if cfg.General.Concurrency != 5 || cfg.General.OutputDir != "./results" {
+		t.Errorf("expected defaults to be applied, got %+v", cfg.General)
+	}
+
+	if _, err := Parse([]byte(`[general]`), "."); err == nil {
+		t.Error("expected validation error for a config without tests")
+	}
+}
+
 func TestLoad_InvalidTOMLError(t *testing.T) {
 	content := `this is not valid toml [[[`
 	tmpDir := t.TempDir()
@@ -381,3 +412,1282 @@ func TestConcurrencyForProvider(t *testing.T) {
 		t.Fatalf("expected default concurrency=4 for exa, got %d", got)
 	}
 }
+
+func TestLoad_SearchFilters(t *testing.T) {
+	content := `
+[[tests]]
+name = "Filtered"
+type = "search"
+query = "rust ownership"
+include_domains = ["rust-lang.org"]
+exclude_domains = ["reddit.com"]
+country = "de"
+language = "de"
+safe_search = "strict"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	test := cfg.Tests[0]
+	if len(test.IncludeDomains) != 1 || test.ExcludeDomains[0] != "reddit.com" {
+		t.Errorf("unexpected domain filters: %+v / %+v", test.IncludeDomains, test.ExcludeDomains)
+	}
+	if test.Country != "de" || test.Language != "de" || test.SafeSearch != "strict" {
+		t.Errorf("unexpected locale filters: %+v", test)
+	}
+}
+
+func TestLoad_InvalidSafeSearch(t *testing.T) {
+	content := `
+[[tests]]
+name = "Bad Safe Search"
+type = "search"
+query = "test"
+safe_search = "sometimes"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := Load(configPath)
+	if err == nil {
+		t.Fatal("expected error for invalid safe_search, got nil")
+	}
+	if !strings.Contains(err.Error(), "invalid safe_search") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestLoad_StructuredExtract(t *testing.T) {
+	content := `
+[[tests]]
+name = "Product Page"
+type = "structured_extract"
+url = "https://example.com/product"
+extraction_prompt = "Extract the product details"
+schema = '''
+{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "price": {"type": "number"}}}
+'''
+
+[tests.expected]
+name = "Widget"
+price = 12
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	test := cfg.Tests[0]
+	schema, err := test.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	if _, ok := schema["properties"].(map[string]interface{})["price"]; !ok {
+		t.Errorf("expected price property in schema, got %v", schema)
+	}
+	if test.Expected["name"] != "Widget" || test.Expected["price"] != int64(12) {
+		t.Errorf("unexpected expected values: %#v", test.Expected)
+	}
+	if test.ExtractionPrompt != "Extract the product details" {
+		t.Errorf("unexpected extraction prompt: %q", test.ExtractionPrompt)
+	}
+}
+
+func TestLoad_StructuredExtractRequiresSchema(t *testing.T) {
+	content := `
+[[tests]]
+name = "No Schema"
+type = "structured_extract"
+url = "https://example.com/product"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := Load(configPath)
+	if err == nil {
+		t.Fatal("expected error for missing schema, got nil")
+	}
+	if !strings.Contains(err.Error(), "invalid schema") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestLoad_DocumentExtract(t *testing.T) {
+	content := `
+[general]
+fixtures_dir = "fixtures"
+
+[[tests]]
+name = "Annual Report PDF"
+type = "extract"
+url = "fixture://report.pdf"
+document_type = "pdf"
+reference_file = "fixtures/report.txt"
+expected_pages = 2
+expected_tables = 1
+`
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "fixtures"), 0750); err != nil {
+		t.Fatalf("failed to create fixtures dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "fixtures", "report.txt"), []byte("Revenue grew"), 0644); err != nil {
+		t.Fatalf("failed to write reference file: %v", err)
+	}
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.General.FixturesDir != filepath.Join(tmpDir, "fixtures") {
+		t.Errorf("FixturesDir = %q, want it resolved against the config dir", cfg.General.FixturesDir)
+	}
+	test := cfg.Tests[0]
+	if test.ReferenceText != "Revenue grew" {
+		t.Errorf("ReferenceText = %q, want file contents", test.ReferenceText)
+	}
+	if !test.IsFixtureURL() {
+		t.Error("expected fixture URL")
+	}
+	if test.ExpectedPages == nil || *test.ExpectedPages != 2 || test.ExpectedTables == nil || *test.ExpectedTables != 1 {
+		t.Errorf("unexpected page/table expectations: %v %v", test.ExpectedPages, test.ExpectedTables)
+	}
+}
+
+func TestLoad_DocumentExtractValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "invalid document type",
+			content: `
+[[tests]]
+name = "Sheet"
+type = "extract"
+url = "https://example.com/a.xlsx"
+document_type = "xlsx"
+`,
+			wantErr: "invalid document_type",
+		},
+		{
+			name: "fixture without fixtures dir",
+			content: `
+[[tests]]
+name = "Fixture"
+type = "extract"
+url = "fixture://report.pdf"
+`,
+			wantErr: "fixtures_dir is not set",
+		},
+		{
+			name: "missing reference file",
+			content: `
+[[tests]]
+name = "Missing Reference"
+type = "extract"
+url = "https://example.com/report.pdf"
+reference_file = "missing.txt"
+`,
+			wantErr: "failed to read reference_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+			_, err := Load(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Faults(t *testing.T) {
+	content := `
+[faults]
+seed = 42
+rate_limit_rate = 0.2
+retry_after = "2s"
+server_error_rate = 0.1
+latency_rate = 0.5
+latency = "250ms"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	proxyCfg, err := cfg.Faults.ProxyConfig()
+	if err != nil {
+		t.Fatalf("ProxyConfig failed: %v", err)
+	}
+	if proxyCfg.Seed != 42 || proxyCfg.RateLimitRate != 0.2 || proxyCfg.RetryAfter != 2*time.Second {
+		t.Errorf("unexpected rate limit settings: %+v", proxyCfg)
+	}
+	if proxyCfg.LatencyRate != 0.5 || proxyCfg.Latency != 250*time.Millisecond || proxyCfg.ResetRate != 0 {
+		t.Errorf("unexpected fault rates: %+v", proxyCfg)
+	}
+}
+
+func TestFaultsConfig_DefaultsWhenUnset(t *testing.T) {
+	proxyCfg, err := FaultsConfig{Seed: 7}.ProxyConfig()
+	if err != nil {
+		t.Fatalf("ProxyConfig failed: %v", err)
+	}
+	if proxyCfg.Seed != 7 || proxyCfg.RateLimitRate == 0 || proxyCfg.ServerErrorRate == 0 {
+		t.Errorf("expected default rates with seed 7, got %+v", proxyCfg)
+	}
+}
+
+func TestLoad_InvalidFaults(t *testing.T) {
+	content := `
+[faults]
+rate_limit_rate = 0.8
+server_error_rate = 0.5
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := Load(configPath)
+	if err == nil {
+		t.Fatal("expected error for fault rates summing above 1, got nil")
+	}
+	if !strings.Contains(err.Error(), "invalid faults") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestLoad_ProviderInstances(t *testing.T) {
+	content := `
+[general]
+provider_concurrency = { firecrawl = 2 }
+
+[providers.firecrawl]
+timeout = "90s"
+
+[providers.Firecrawl-SelfHosted]
+type = "Firecrawl"
+base_url = "http://firecrawl.internal:3002/v2"
+api_key_env = "SELFHOSTED_FIRECRAWL_KEY"
+headers = { "X-Team" = "search" }
+proxy = "http://proxy.internal:3128"
+
+[providers.jina-gateway]
+type = "jina"
+base_urls = { reader = "https://reader.internal", search = "https://search.internal" }
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	instances := cfg.Instances()
+	if len(instances) != 2 || instances[0] != "firecrawl-selfhosted" || instances[1] != "jina-gateway" {
+		t.Fatalf("unexpected instances: %v", instances)
+	}
+	if cfg.ProviderType("firecrawl-selfhosted") != "firecrawl" || cfg.ProviderType("tavily") != "tavily" || cfg.ProviderType("unknown") != "" {
+		t.Errorf("unexpected provider types")
+	}
+	if got := cfg.General.ConcurrencyForProvider("firecrawl-selfhosted"); got != 2 {
+		t.Errorf("expected instance to inherit firecrawl concurrency 2, got %d", got)
+	}
+
+	settings := cfg.ProviderSettings("firecrawl-selfhosted")
+	if settings.Name != "firecrawl-selfhosted" || settings.BaseURLs["api"] != "http://firecrawl.internal:3002/v2" {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+	if settings.APIKeyEnv != "SELFHOSTED_FIRECRAWL_KEY" || settings.Headers["X-Team"] != "search" || settings.Proxy != "http://proxy.internal:3128" {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+	if got := cfg.ProviderSettings("firecrawl").Timeout; got != 90*time.Second {
+		t.Errorf("expected firecrawl timeout 90s, got %s", got)
+	}
+	if got := cfg.ProviderSettings("jina-gateway").BaseURLs["search"]; got != "https://search.internal" {
+		t.Errorf("unexpected jina search base URL %q", got)
+	}
+	if got := cfg.ProviderSettings("brave"); got.Name != "brave" || got.BaseURLs != nil {
+		t.Errorf("expected empty settings for unconfigured provider, got %+v", got)
+	}
+}
+
+func TestLoad_InvalidProviderInstances(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		wantErr string
+	}{
+		{"missing type", "[providers.mirror]\nbase_url = \"https://mirror\"", "requires a type"},
+		{"unknown type", "[providers.mirror]\ntype = \"bing\"", "invalid type"},
+		{"retyped builtin", "[providers.tavily]\ntype = \"exa\"", "cannot change its type"},
+		{"bad base url", "[providers.brave-proxy]\ntype = \"brave\"\nbase_url = \"brave.internal\"", "invalid base_url"},
+		{"bad timeout", "[providers.exa]\ntimeout = \"soon\"", "invalid timeout"},
+		{"reserved name", "[providers.all]\ntype = \"exa\"", "invalid provider instance name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := tt.section + "\n\n[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n"
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+			_, err := Load(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_Pricing(t *testing.T) {
+	tmpDir := t.TempDir()
+	pricingFile := `
+name = "enterprise-2026"
+
+[providers.firecrawl]
+plan = "Enterprise"
+unit = "credit"
+monthly_minimum = 500.0
+tiers = [
+  { up_to = 100000, unit_price = 0.002 },
+  { unit_price = 0.001 },
+]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pricing.toml"), []byte(pricingFile), 0644); err != nil {
+		t.Fatalf("failed to write pricing file: %v", err)
+	}
+	content := `
+[pricing]
+file = "pricing.toml"
+
+[pricing.providers.tavily]
+plan = "Project"
+unit = "credit"
+unit_price = 0.0075
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Pricing.File != filepath.Join(tmpDir, "pricing.toml") {
+		t.Errorf("expected pricing file resolved against config dir, got %q", cfg.Pricing.File)
+	}
+	profile, err := cfg.Pricing.Profile()
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	if profile.Name != "custom" {
+		t.Errorf("expected inline prices to rename the profile to custom, got %q", profile.Name)
+	}
+	firecrawl := profile.Providers["firecrawl"]
+	if firecrawl.MonthlyMinimum != 500 || len(firecrawl.Tiers) != 2 {
+		t.Errorf("unexpected firecrawl pricing: %+v", firecrawl)
+	}
+	if profile.Providers["tavily"].UnitPrice != 0.0075 {
+		t.Errorf("expected inline tavily price, got %+v", profile.Providers["tavily"])
+	}
+	if profile.Providers["brave"].UnitPrice != 0.005 {
+		t.Errorf("expected default brave price, got %+v", profile.Providers["brave"])
+	}
+}
+
+func TestPricingConfig_DefaultProfile(t *testing.T) {
+	profile, err := PricingConfig{}.Profile()
+	if err != nil {
+		t.Fatalf("Profile failed: %v", err)
+	}
+	if profile.Name != benchmetrics.DefaultPricingProfileName {
+		t.Errorf("expected default profile, got %q", profile.Name)
+	}
+}
+
+func TestLoad_InvalidPricing(t *testing.T) {
+	content := `
+[pricing.providers.exa]
+tiers = [
+  { up_to = 1000, unit_price = 0.005 },
+  { up_to = 500, unit_price = 0.004 },
+]
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	_, err := Load(configPath)
+	if err == nil || !strings.Contains(err.Error(), "invalid pricing") {
+		t.Errorf("expected invalid pricing error, got %v", err)
+	}
+}
+
+func TestLoad_Projection(t *testing.T) {
+	content := `
+[projection]
+searches = 2000000
+extracts = 50000
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+`
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Projection.Searches != 2e6 || cfg.Projection.Extracts != 5e4 || cfg.Projection.CrawlPages != 0 {
+		t.Errorf("unexpected projection: %+v", cfg.Projection)
+	}
+
+	invalid := strings.Replace(content, "extracts = 50000", "extracts = -1", 1)
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	if _, err := Load(configPath); err == nil || !strings.Contains(err.Error(), "invalid projection") {
+		t.Errorf("expected invalid projection error, got %v", err)
+	}
+}
+
+func TestLoad_Assertions(t *testing.T) {
+	content := `
+[assertions]
+must_succeed = true
+max_latency_ms = 10000
+
+[assertions.providers.firecrawl]
+max_latency_ms = 20000
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+
+[tests.assertions]
+min_results = 3
+min_quality_score = 60
+
+[tests.assertions.providers.Exa]
+min_quality_score = 40
+max_cost_usd = 0.01
+
+[[tests]]
+name = "Extract"
+type = "extract"
+url = "https://example.com"
+`
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.HasAssertions() {
+		t.Fatal("expected HasAssertions to be true")
+	}
+
+	exa := cfg.AssertionsFor(cfg.Tests[0], "exa", "exa")
+	if exa.MustSucceed == nil || !*exa.MustSucceed || *exa.MaxLatencyMS != 10000 || *exa.MinResults != 3 {
+		t.Errorf("expected global and test assertions for exa, got %+v", exa)
+	}
+	if *exa.MinQualityScore != 40 || exa.MaxCostUSD == nil || *exa.MaxCostUSD != 0.01 {
+		t.Errorf("expected exa overrides, got quality %v cost %v", *exa.MinQualityScore, exa.MaxCostUSD)
+	}
+
+	// Instances inherit the overrides of their provider type.
+	selfHosted := cfg.AssertionsFor(cfg.Tests[0], "firecrawl-selfhosted", "firecrawl")
+	if *selfHosted.MaxLatencyMS != 20000 || *selfHosted.MinQualityScore != 60 || selfHosted.MaxCostUSD != nil {
+		t.Errorf("unexpected firecrawl instance assertions: %+v", selfHosted)
+	}
+
+	extract := cfg.AssertionsFor(cfg.Tests[1], "tavily", "tavily")
+	if extract.MinResults != nil || extract.MustSucceed == nil || *extract.MaxLatencyMS != 10000 {
+		t.Errorf("expected only global assertions for the extract test, got %+v", extract)
+	}
+}
+
+func TestLoad_InvalidAssertions(t *testing.T) {
+	cases := []struct {
+		name       string
+		assertions string
+		want       string
+	}{
+		{"zero latency", "[tests.assertions]\nmax_latency_ms = 0", "max_latency_ms must be > 0"},
+		{"quality above 100", "[tests.assertions]\nmin_quality_score = 120", "min_quality_score must be between 0 and 100"},
+		{"negative cost", "[tests.assertions.providers.exa]\nmax_cost_usd = -1", "(provider 'exa'): max_cost_usd"},
+		{"duplicate provider", "[tests.assertions.providers.exa]\nmin_results = 1\n[tests.assertions.providers.EXA]\nmin_results = 2", "set more than once"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := "[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n" + tc.assertions + "\n"
+			_, err := Parse([]byte(content), t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+	if _, err := Parse([]byte("[assertions]\nmin_results = -1\n[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n"), ""); err == nil || !strings.Contains(err.Error(), "[assertions]: min_results") {
+		t.Errorf("expected global assertion error, got %v", err)
+	}
+}
+
+func TestLoad_Capture(t *testing.T) {
+	const tests = "[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n"
+	cfg, err := Parse([]byte(tests), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if items, chars := cfg.Capture.Limits(); cfg.Capture.Disabled || items != 10 || chars != 2000 {
+		t.Errorf("expected capture enabled with default limits, got %+v (%d, %d)", cfg.Capture, items, chars)
+	}
+
+	cfg, err = Parse([]byte("[capture]\nmax_items = 3\nmax_chars = 500\nsidecar = true\n"+tests), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if items, chars := cfg.Capture.Limits(); items != 3 || chars != 500 || !cfg.Capture.Sidecar {
+		t.Errorf("unexpected capture config: %+v", cfg.Capture)
+	}
+
+	if _, err := Parse([]byte("[capture]\nmax_chars = -1\n"+tests), ""); err == nil || !strings.Contains(err.Error(), "invalid capture limits") {
+		t.Errorf("expected capture limit error, got %v", err)
+	}
+}
+
+func TestLoad_Notifications(t *testing.T) {
+	const tests = "[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n"
+	cfg, err := Parse([]byte(`
+[notifications]
+baseline = "golden/baseline.json"
+retries = 0
+
+[[notifications.webhooks]]
+url = "https://hooks.example.com/a"
+events = ["regression", "slo_violation"]
+template = '{"text": {{json .Text}}}'
+
+[[notifications.webhooks]]
+url_env = "BENCH_TEST_WEBHOOK"
+`+tests), "/etc/bench")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	n := cfg.Notifications
+	if !n.Enabled() || len(n.Webhooks) != 2 || n.Baseline != filepath.Join("/etc/bench", "golden/baseline.json") {
+		t.Fatalf("unexpected notifications config: %+v", n)
+	}
+	if n.RetryCount() != 0 || n.TimeoutDuration() != 10*time.Second || n.BackoffDuration() != time.Second || n.Threshold() != 0.1 {
+		t.Errorf("unexpected defaults: retries %d, timeout %s, backoff %s, threshold %g",
+			n.RetryCount(), n.TimeoutDuration(), n.BackoffDuration(), n.Threshold())
+	}
+	if n.Webhooks[0].Wants(NotifyRunCompleted) || !n.Webhooks[0].Wants(NotifyRegression) || !n.Webhooks[1].Wants(NotifyRunCompleted) {
+		t.Error("expected event filters to apply, with all events by default")
+	}
+	t.Setenv("BENCH_TEST_WEBHOOK", "https://hooks.example.com/secret")
+	if got := n.Webhooks[1].Endpoint(); got != "https://hooks.example.com/secret" {
+		t.Errorf("expected URL from url_env, got %q", got)
+	}
+
+	for name, section := range map[string]string{
+		"missing url":   "[[notifications.webhooks]]\nevents = [\"run_completed\"]\n",
+		"invalid event": "[[notifications.webhooks]]\nurl = \"https://x\"\nevents = [\"done\"]\n",
+		"bad timeout":   "[notifications]\ntimeout = \"soon\"\n",
+		"bad retries":   "[notifications]\nretries = -1\n",
+	} {
+		if _, err := Parse([]byte(section+tests), ""); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSelectTests(t *testing.T) {
+	cfg, err := Parse([]byte(`
+[[tests]]
+name = "Search - Papers"
+type = "search"
+query = "transformers"
+tags = ["academic", " Slow "]
+
+[[tests]]
+name = "Extract - Docs"
+type = "extract"
+url = "https://go.dev/doc"
+tags = ["code"]
+
+[[tests]]
+name = "Extract - Paper"
+type = "extract"
+url = "https://arxiv.org/abs/1706.03762"
+tags = ["academic"]
+
+[[tests]]
+name = "Crawl - Docs"
+type = "crawl"
+url = "https://go.dev/doc"
+`), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := cfg.Tests[0].Tags; got[1] != "Slow" || !cfg.Tests[0].HasTag("slow") {
+		t.Errorf("expected trimmed tags matched without case, got %q", got)
+	}
+
+	names := func(sel benchmetrics.Selection) string {
+		t.Helper()
+		tests, err := SelectTests(cfg.Tests, sel)
+		if err != nil {
+			t.Fatalf("SelectTests(%+v) failed: %v", sel, err)
+		}
+		var out []string
+		for _, test := range tests {
+			out = append(out, test.Name)
+		}
+		return strings.Join(out, ", ")
+	}
+	for want, sel := range map[string]benchmetrics.Selection{
+		"Search - Papers, Extract - Docs, Extract - Paper, Crawl - Docs": {},
+		"Extract - Docs, Extract - Paper":                                {Run: "Extract.*"},
+		"Search - Papers, Extract - Docs, Extract - Paper":               {Tags: []string{"academic", "code"}},
+		"Extract - Paper":                               {Tags: []string{"academic"}, SkipTags: []string{"slow"}},
+		"Extract - Docs, Crawl - Docs":                  {Types: []string{"extract", "crawl"}, Run: "Docs"},
+		"Extract - Paper, Crawl - Docs":                 {NoSearch: true, SkipTags: []string{"code"}},
+		"Extract - Docs, Extract - Paper, Crawl - Docs": {NoSearch: true},
+	} {
+		if got := names(sel); got != want {
+			t.Errorf("SelectTests(%+v) = %q, want %q", sel, got, want)
+		}
+	}
+
+	if _, err := SelectTests(cfg.Tests, benchmetrics.Selection{Run: "("}); err == nil {
+		t.Error("expected an invalid -run pattern error")
+	}
+	if _, err := SelectTests(cfg.Tests, benchmetrics.Selection{Types: []string{"scrape"}}); err == nil || !strings.Contains(err.Error(), "invalid test type") {
+		t.Errorf("expected an invalid type error, got %v", err)
+	}
+	if _, err := Parse([]byte("[[tests]]\nname = \"S\"\ntype = \"search\"\nquery = \"q\"\ntags = [\" \"]\n"), ""); err == nil {
+		t.Error("expected an empty tag error")
+	}
+}
+
+func TestLoad_IncludesSuites(t *testing.T) {
+	tmpDir := t.TempDir()
+	suitesDir := filepath.Join(tmpDir, "suites")
+	if err := os.MkdirAll(suitesDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"config.toml": `
+include = ["suites/*.toml"]
+
+[[tests]]
+name = "Search - Own"
+type = "search"
+query = "own query"
+`,
+		"suites/academic.toml": `
+[[tests]]
+name = "Paper"
+type = "extract"
+url = "https://arxiv.org/abs/1706.03762"
+reference_file = "paper.txt"
+`,
+		"suites/code.toml": `
+suite = "code-team"
+
+[[tests]]
+name = "Docs"
+type = "crawl"
+url = "https://go.dev/doc"
+`,
+		"suites/paper.txt": "Attention is all you need",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// paper.txt matches no *.toml pattern, so it is not read as a suite.
+
+	cfg, err := Load(filepath.Join(tmpDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	var names []string
+	for _, test := range cfg.Tests {
+		names = append(names, test.Name)
+	}
+	if got := strings.Join(names, ", "); got != "Search - Own, academic/Paper, code-team/Docs" {
+		t.Errorf("tests = %q, want the config's own tests followed by prefixed suite tests", got)
+	}
+	if cfg.Tests[1].ReferenceText != "Attention is all you need" || cfg.Tests[1].ReferenceFile != "" {
+		t.Errorf("expected the suite's reference file read relative to the suite, got %+v", cfg.Tests[1])
+	}
+	if cfg.Include != nil {
+		t.Errorf("expected includes cleared after resolving, got %q", cfg.Include)
+	}
+
+	for name, content := range map[string]string{
+		"missing.toml": "include = [\"nothing/*.toml\"]\n",
+		"general.toml": "include = [\"bad/*.toml\"]\n",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Load(filepath.Join(tmpDir, "missing.toml")); err == nil || !strings.Contains(err.Error(), "matches no files") {
+		t.Errorf("expected an unmatched include error, got %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "bad"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "bad", "suite.toml"), []byte("[general]\nconcurrency = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filepath.Join(tmpDir, "general.toml")); err == nil || !strings.Contains(err.Error(), "may only set suite") {
+		t.Errorf("expected a suite settings error, got %v", err)
+	}
+}
+
+func TestParse_ExpandsEnvironment(t *testing.T) {
+	t.Setenv("BENCH_TEST_TOPIC", "rust")
+	t.Setenv("BENCH_TEST_EMPTY", "")
+
+	cfg, err := Parse([]byte(`
+[general]
+output_dir = "${BENCH_TEST_UNSET:-./results}/${BENCH_TEST_TOPIC}"
+
+[[tests]]
+name = "Search - ${BENCH_TEST_TOPIC}"
+type = "search"
+query = "${BENCH_TEST_TOPIC} ownership${BENCH_TEST_EMPTY}"
+expected_url_patterns = ["^https://doc\\.rust-lang\\.org/.*$"]
+
+[[tests]]
+name = "Structured"
+type = "structured_extract"
+url = "https://example.com"
+schema = '{"type": "object", "properties": {"lang": {"type": "string"}}}'
+expected = { lang = "${BENCH_TEST_EMPTY:-en}", tags = ["${BENCH_TEST_TOPIC}"] }
+`), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.General.OutputDir != "./results/rust" {
+		t.Errorf("output_dir = %q, want the default and variable expanded", cfg.General.OutputDir)
+	}
+	if test := cfg.Tests[0]; test.Name != "Search - rust" || test.Query != "rust ownership" || test.ExpectedURLPatterns[0] != `^https://doc\.rust-lang\.org/.*$` {
+		t.Errorf("unexpected expansion %+v", test)
+	}
+	expected := cfg.Tests[1].Expected
+	if tags, ok := expected["tags"].([]interface{}); expected["lang"] != "en" || !ok || tags[0] != "rust" {
+		t.Errorf("expected = %v, want nested strings expanded", expected)
+	}
+
+	_, err = Parse([]byte("[[tests]]\nname = \"S\"\ntype = \"search\"\nquery = \"${BENCH_TEST_UNSET}\"\n"), "")
+	if err == nil || !strings.Contains(err.Error(), "BENCH_TEST_UNSET is not set") || !strings.Contains(err.Error(), "query") {
+		t.Errorf("expected an unset variable error naming the field, got %v", err)
+	}
+}
+
+func TestParse_EscapedEnvironmentReferencesStayLiteral(t *testing.T) {
+	t.Setenv("BENCH_TEST_TOPIC", "rust")
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	content := `
+[[tests]]
+name = "Template"
+type = "extract"
+url = "https://example.com/templates"
+expected_snippets = ["$${HOME}", "$${BENCH_TEST_UNSET:-x} ${BENCH_TEST_TOPIC}", "$$${BENCH_TEST_TOPIC}"]
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"${HOME}", "${BENCH_TEST_UNSET:-x} rust", "$${BENCH_TEST_TOPIC}"}
+	check := func(cfg *Config, what string) {
+		t.Helper()
+		got := cfg.Tests[0].ExpectedSnippets
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected_snippets = %q, want %q", what, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected_snippets[%d] = %q, want %q", what, i, got[i], want[i])
+			}
+		}
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	check(cfg, "loaded")
+
+	// The saved config and a saved suite load with the same literals.
+	savedPath := filepath.Join(tmpDir, "saved.toml")
+	if err := cfg.Save(savedPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	t.Setenv("BENCH_TEST_TOPIC", "go")
+	saved, err := Load(savedPath)
+	if err != nil {
+		t.Fatalf("Load of the saved config failed: %v", err)
+	}
+	check(saved, "saved")
+
+	if err := SaveSuite(filepath.Join(tmpDir, "suite.toml"), "templates", cfg.Tests); err != nil {
+		t.Fatalf("SaveSuite failed: %v", err)
+	}
+	includePath := filepath.Join(tmpDir, "include.toml")
+	if err := os.WriteFile(includePath, []byte("include = [\"suite.toml\"]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	included, err := Load(includePath)
+	if err != nil {
+		t.Fatalf("Load of the saved suite failed: %v", err)
+	}
+	check(included, "included")
+}
+
+func TestLoadProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.toml")
+	content := `
+[general]
+concurrency = 8
+timeout = "30s"
+provider_concurrency = { firecrawl = 1, exa = 3 }
+
+[profiles.ci]
+concurrency = 2
+timeout = "90s"
+provider_concurrency = { firecrawl = 2 }
+
+[profiles.nightly]
+output_dir = "nightly"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "q"
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProfile(configPath, "ci")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+	g := cfg.General
+	if g.Concurrency != 2 || g.Timeout != "90s" || g.ProviderConcurrency["firecrawl"] != 2 || g.ProviderConcurrency["exa"] != 3 {
+		t.Errorf("expected the ci profile applied over general, got %+v", g)
+	}
+	if cfg.Profiles != nil {
+		t.Errorf("expected profiles cleared after applying, got %v", cfg.Profiles)
+	}
+
+	cfg, err = Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.General.Concurrency != 8 || cfg.General.Timeout != "30s" {
+		t.Errorf("expected no profile applied by default, got %+v", cfg.General)
+	}
+
+	if _, err := LoadProfile(configPath, "staging"); err == nil || !strings.Contains(err.Error(), "defined: ci, nightly") {
+		t.Errorf("expected an unknown profile error listing the profiles, got %v", err)
+	}
+}
+
+func TestSave_KeepsSecretReferences(t *testing.T) {
+	t.Setenv("BENCH_TEST_TOKEN", "s3cret-token")
+	t.Setenv("BENCH_TEST_HOOK", "https://hooks.example/s3cret-hook")
+	tmpDir := t.TempDir()
+	content := `
+[providers.firecrawl]
+base_url = "https://firecrawl.internal"
+headers = { Authorization = "Bearer ${BENCH_TEST_TOKEN}" }
+
+[[notifications.webhooks]]
+url = "${BENCH_TEST_HOOK}"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "q"
+`
+	configPath := filepath.Join(tmpDir, "config.toml")
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Providers["firecrawl"].Headers["Authorization"] != "Bearer s3cret-token" ||
+		cfg.Notifications.Webhooks[0].URL != "https://hooks.example/s3cret-hook" {
+		t.Fatalf("expected the loaded config to be expanded, got %+v %+v", cfg.Providers, cfg.Notifications.Webhooks)
+	}
+
+	savedPath := filepath.Join(tmpDir, "saved.toml")
+	if err := cfg.Save(savedPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved, err := os.ReadFile(savedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(saved), "s3cret") {
+		t.Errorf("saved config contains an expanded secret:\n%s", saved)
+	}
+	for _, ref := range []string{"${BENCH_TEST_TOKEN}", "${BENCH_TEST_HOOK}", "https://firecrawl.internal"} {
+		if !strings.Contains(string(saved), ref) {
+			t.Errorf("saved config is missing %q:\n%s", ref, saved)
+		}
+	}
+	if info, err := os.Stat(savedPath); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected the saved config to be 0600, got %v %v", info.Mode().Perm(), err)
+	}
+
+	loaded, err := Load(savedPath)
+	if err != nil {
+		t.Fatalf("Load of the saved config failed: %v", err)
+	}
+	if loaded.Providers["firecrawl"].Headers["Authorization"] != "Bearer s3cret-token" {
+		t.Errorf("expected the reloaded config to expand the header again, got %+v", loaded.Providers)
+	}
+}
+
+func TestSave_ResolvedConfigLoadsAgain(t *testing.T) {
+	t.Setenv("BENCH_TEST_QUERY", "resolved query")
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.toml": `
+include = ["suite.toml"]
+
+[general]
+fixtures_dir = "fixtures"
+
+[profiles.ci]
+concurrency = 1
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "${BENCH_TEST_QUERY}"
+`,
+		"suite.toml": `
+[[tests]]
+name = "Doc"
+type = "extract"
+url = "fixture://doc.txt"
+reference_file = "reference.txt"
+`,
+		"reference.txt": "reference text",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := LoadProfile(filepath.Join(tmpDir, "config.toml"), "ci")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+	outDir := filepath.Join(tmpDir, "results")
+	if err := os.MkdirAll(outDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	savedPath := filepath.Join(outDir, "config.toml")
+	if err := cfg.Save(savedPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved, err := os.ReadFile(savedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unresolved := range []string{"include", "profiles", "${", "reference_file"} {
+		if strings.Contains(string(saved), unresolved) {
+			t.Errorf("saved config still contains %q:\n%s", unresolved, saved)
+		}
+	}
+
+	// Unset the variable so loading the saved config cannot depend on it.
+	t.Setenv("BENCH_TEST_QUERY", "")
+	loaded, err := Load(savedPath)
+	if err != nil {
+		t.Fatalf("Load of the saved config failed: %v", err)
+	}
+	if loaded.General.Concurrency != 1 || loaded.General.FixturesDir != filepath.Join(tmpDir, "fixtures") {
+		t.Errorf("expected the profile and absolute fixtures_dir kept, got %+v", loaded.General)
+	}
+	if len(loaded.Tests) != 2 || loaded.Tests[0].Query != "resolved query" ||
+		loaded.Tests[1].Name != "suite/Doc" || loaded.Tests[1].ReferenceText != "reference text" {
+		t.Errorf("unexpected tests after reload: %+v", loaded.Tests)
+	}
+}
+
+func TestSaveSuite_LoadsAsInclude(t *testing.T) {
+	tmpDir := t.TempDir()
+	tests := []TestConfig{{
+		Name:         "1: what is bm25",
+		Type:         "search",
+		Query:        "what is bm25",
+		Tags:         []string{"msmarco"},
+		ExpectedURLs: []string{"https://en.wikipedia.org/wiki/Okapi_BM25", "D7"},
+		Relevance:    map[string]int{"https://en.wikipedia.org/wiki/Okapi_BM25": 2, "D7": 1},
+	}}
+	if err := SaveSuite(filepath.Join(tmpDir, "dev.toml"), "msmarco", tests); err != nil {
+		t.Fatalf("SaveSuite failed: %v", err)
+	}
+	config := "include = [\"dev.toml\"]\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.toml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(filepath.Join(tmpDir, "config.toml"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Tests) != 1 {
+		t.Fatalf("expected 1 test, got %d", len(cfg.Tests))
+	}
+	want := tests[0]
+	want.Name = "msmarco/" + want.Name
+	if !reflect.DeepEqual(cfg.Tests[0], want) {
+		t.Errorf("loaded test = %+v, want %+v", cfg.Tests[0], want)
+	}
+
+	if _, err := Parse([]byte("[[tests]]\nname = \"x\"\ntype = \"search\"\nquery = \"q\"\nrelevance = { D7 = -1 }\n"), tmpDir); err == nil {
+		t.Error("expected an error for a negative relevance grade")
+	}
+}
+
+func TestParseInline(t *testing.T) {
+	t.Setenv("BENCH_TEST_SECRET", "s3cret")
+	cfg, err := ParseInline([]byte("[[tests]]\nname = \"${BENCH_TEST_SECRET}\"\ntype = \"search\"\nquery = \"q ${BENCH_TEST_SECRET:-x}\"\n"))
+	if err != nil {
+		t.Fatalf("ParseInline failed: %v", err)
+	}
+	if cfg.Tests[0].Name != "${BENCH_TEST_SECRET}" || cfg.Tests[0].Query != "q ${BENCH_TEST_SECRET:-x}" {
+		t.Errorf("expected environment references to stay unexpanded, got %+v", cfg.Tests[0])
+	}
+
+	_, err = ParseInline([]byte("include = [\"*.toml\"]\n[providers.leak]\ntype = \"exa\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "include, providers") {
+		t.Errorf("expected include and providers to be rejected, got %v", err)
+	}
+}
+
+func TestValidate_ReportsIssuesWithLines(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.toml": `include = ["suite.toml"]
+
+[general]
+provider_concurrency = { firecrawl = 1, firecrawll = 2 }
+
+[[tests]]
+name = "Search"
+type = "search"
+url = "https://example.com"
+
+[[tests]]
+name = "Extract"
+type = "extract"
+url = "https://example.com"
+querry = "typo"
+`,
+		"suite.toml": `
+[[tests]]
+name = "Crawl"
+type = "crawl"
+url = "https://example.com"
+schema = """
+{"type": "object"}
+"""
+
+[[tests]]
+name = "Crawl"
+type = "crawl"
+url = "https://example.com/docs"
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(tmpDir, "config.toml")
+	suitePath := filepath.Join(tmpDir, "suite.toml")
+
+	cfg, issues := Validate(configPath, "")
+	if cfg != nil || !HasErrors(issues) {
+		t.Fatalf("expected errors and no config, got %v", issues)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		configPath + ":4: error: provider_concurrency names unknown provider 'firecrawll'",
+		configPath + ":6: error: test 'Search' of type 'search' requires a query",
+		configPath + ":9: warning: test 'Search' sets url, which search tests ignore",
+		configPath + ":15: error: unknown key tests.querry",
+		suitePath + ":6: warning: test 'suite/Crawl' sets schema, which crawl tests ignore",
+		suitePath + ":11: error: duplicate test name 'suite/Crawl' (first defined at " + suitePath + ":3)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got issues:\n%s\nwant %d", strings.Join(got, "\n"), len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("issue %d = %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+
+	if err := os.WriteFile(configPath, []byte("[[tests]]\nname = \"S\"\ntype = \"search\"\nquery = 3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, issues := Validate(configPath, ""); len(issues) != 1 || issues[0].Line != 4 {
+		t.Errorf("expected a type error on line 4, got %v", issues)
+	}
+
+	if err := os.WriteFile(configPath, []byte("[[tests]]\nname = \"S\"\ntype = \"search\"\nquery = \"q\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, issues = Validate(configPath, "")
+	if cfg == nil || len(issues) != 0 || cfg.General.Concurrency != 5 {
+		t.Errorf("expected a clean config loaded with defaults, got %v", issues)
+	}
+}
+
+func TestValidate_ReportsConflictingExpectations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		wantErr string // empty when the test is valid
+	}{
+		{
+			name:    "term required and forbidden",
+			fields:  "must_include_terms = [\"Rust\"]\nmust_not_include_terms = [\"rust \"]",
+			wantErr: `:6: error: test 'S' lists "Rust" in both must_include_terms and must_not_include_terms`,
+		},
+		{
+			name:    "snippet expected and forbidden",
+			fields:  "expected_snippets = [\"memory safety\"]\nforbidden_snippets = [\"Memory Safety\"]",
+			wantErr: `:6: error: test 'S' lists "memory safety" in both expected_snippets and forbidden_snippets`,
+		},
+		{
+			name:    "expected url on excluded domain",
+			fields:  "expected_urls = [\"https://docs.rust-lang.org/book\"]\nexclude_domains = [\"rust-lang.org\"]",
+			wantErr: ":5: error: test 'S' expects https://docs.rust-lang.org/book, which exclude_domains filters out (rust-lang.org)",
+		},
+		{
+			name:    "expected url outside include_domains",
+			fields:  "expected_urls = [\"https://www.rust-lang.org/learn\"]\ninclude_domains = [\"github.com\"]",
+			wantErr: ":5: error: test 'S' expects https://www.rust-lang.org/learn, which is outside include_domains",
+		},
+		{
+			name:   "expected url inside include_domains",
+			fields: "expected_urls = [\"https://www.rust-lang.org/learn\"]\ninclude_domains = [\"rust-lang.org\"]\nexclude_domains = [\"blog.rust-lang.org\"]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.toml")
+			content := "[[tests]]\nname = \"S\"\ntype = \"search\"\nquery = \"rust\"\n" + tt.fields + "\n"
+			if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, issues := Validate(configPath, "")
+			if tt.wantErr == "" {
+				if len(issues) != 0 {
+					t.Fatalf("expected no issues, got %v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0].String() != configPath+tt.wantErr {
+				t.Fatalf("expected %q, got %v", configPath+tt.wantErr, issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Notification events a webhook can subscribe to.
+const (
+	NotifyRunCompleted = "run_completed"
+	NotifyRegression   = "regression"
+	NotifySLOViolation = "slo_violation"
+)
+
+// NotificationsConfig configures the webhooks posted to when a run finishes,
+// when results regress against a golden baseline and when assertions fail.
+type NotificationsConfig struct {
+	Webhooks []WebhookConfig `toml:"webhooks"`
+	Timeout  string          `toml:"timeout"` // per attempt; default 10s
+	Retries  *int            `toml:"retries"` // retries after a failed attempt; default 2
+	Backoff  string          `toml:"backoff"` // wait before the first retry, doubled after each; default 1s
+	// Baseline is a golden baseline JSON file (see internal/evaluation) the
+	// results are checked against for regressions. Relative paths are
+	// resolved against the config file's directory.
+	Baseline            string  `toml:"baseline"`
+	RegressionThreshold float64 `toml:"regression_threshold"` // relative drop counted as a regression; default 0.1
+	// ReportBaseURL, when set, links report files under this URL instead of
+	// by their local path, e.g. where CI publishes the output directory.
+	ReportBaseURL string `toml:"report_base_url"`
+}
+
+// WebhookConfig is one webhook endpoint.
+type WebhookConfig struct {
+	URL    string `toml:"url"`
+	URLEnv string `toml:"url_env"` // env var holding the URL, for URLs that embed a secret
+	// Events lists the notifications sent to this webhook; default all.
+	Events []string `toml:"events"`
+	// Template is a Go text/template rendering the request body from the
+	// notification. The default body is the notification as JSON.
+	Template string            `toml:"template"`
+	Headers  map[string]string `toml:"headers"`
+}
+
+// Endpoint returns the webhook URL, reading it from URLEnv when URL is unset.
+func (w WebhookConfig) Endpoint() string {
+	if w.URL == "" && w.URLEnv != "" {
+		return os.Getenv(w.URLEnv)
+	}
+	return w.URL
+}
+
+// Wants reports whether the webhook subscribes to event.
+func (w WebhookConfig) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether any webhook is configured.
+func (n NotificationsConfig) Enabled() bool {
+	return len(n.Webhooks) > 0
+}
+
+// Wants reports whether any webhook subscribes to event.
+func (n NotificationsConfig) Wants(event string) bool {
+	for _, w := range n.Webhooks {
+		if w.Wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutDuration returns the per-attempt timeout.
+func (n NotificationsConfig) TimeoutDuration() time.Duration {
+	if d, err := time.ParseDuration(n.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return 10 * time.Second
+}
+
+// BackoffDuration returns the wait before the first retry.
+func (n NotificationsConfig) BackoffDuration() time.Duration {
+	if d, err := time.ParseDuration(n.Backoff); err == nil && d > 0 {
+		return d
+	}
+	return time.Second
+}
+
+// RetryCount returns the number of retries after a failed attempt.
+func (n NotificationsConfig) RetryCount() int {
+	if n.Retries == nil {
+		return 2
+	}
+	return *n.Retries
+}
+
+// Threshold returns the relative drop counted as a regression.
+func (n NotificationsConfig) Threshold() float64 {
+	if n.RegressionThreshold <= 0 {
+		return 0.1
+	}
+	return n.RegressionThreshold
+}
+
+func (n *NotificationsConfig) normalize(baseDir string) error {
+	for name, value := range map[string]string{"timeout": n.Timeout, "backoff": n.Backoff} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("invalid notifications.%s: %q", name, value)
+		}
+	}
+	if n.Retries != nil && *n.Retries < 0 {
+		return fmt.Errorf("invalid notifications.retries: %d", *n.Retries)
+	}
+	if n.RegressionThreshold < 0 {
+		return fmt.Errorf("invalid notifications.regression_threshold: %g", n.RegressionThreshold)
+	}
+	if n.Baseline != "" && !filepath.IsAbs(n.Baseline) {
+		n.Baseline = filepath.Join(baseDir, n.Baseline)
+	}
+	for i, w := range n.Webhooks {
+		if w.URL == "" && w.URLEnv == "" {
+			return fmt.Errorf("notifications webhook %d requires url or url_env", i+1)
+		}
+		for _, event := range w.Events {
+			switch event {
+			case NotifyRunCompleted, NotifyRegression, NotifySLOViolation:
+			default:
+				return fmt.Errorf("notifications webhook %d has invalid event %q (want %s, %s or %s)",
+					i+1, event, NotifyRunCompleted, NotifyRegression, NotifySLOViolation)
+			}
+		}
+	}
+	return nil
+}
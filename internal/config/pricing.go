@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// PricingConfig selects the pricing profile used for cost figures. Prices
+// come from the built-in pay-as-you-go rates, then the optional pricing file,
+// then providers listed inline; each layer replaces whole provider entries.
+type PricingConfig struct {
+	// File is a separate pricing profile in TOML with the same name and
+	// [providers.<name>] layout. Relative paths are resolved against the
+	// config file's directory.
+	File      string                                  `toml:"file,omitempty"`
+	Name      string                                  `toml:"name,omitempty"`
+	Providers map[string]benchmetrics.ProviderPricing `toml:"providers,omitempty"`
+}
+
+// Profile builds the pricing profile described by the section.
+func (p PricingConfig) Profile() (benchmetrics.PricingProfile, error) {
+	profile := benchmetrics.DefaultPricingProfile()
+	if p.File != "" {
+		fromFile, err := LoadPricingProfile(p.File)
+		if err != nil {
+			return benchmetrics.PricingProfile{}, err
+		}
+		profile = profile.Merge(fromFile)
+	}
+	name := p.Name
+	if name == "" && len(p.Providers) > 0 {
+		// Inline prices change the profile, so don't report it under the base name.
+		name = "custom"
+	}
+	profile = profile.Merge(benchmetrics.PricingProfile{Name: name, Providers: p.Providers})
+	if err := profile.Validate(); err != nil {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("invalid pricing: %w", err)
+	}
+	return profile, nil
+}
+
+// LoadPricingProfile reads a pricing profile from a TOML file. Providers it
+// does not list keep the default rates when it is merged.
+func LoadPricingProfile(path string) (benchmetrics.PricingProfile, error) {
+	if err := validatePath(path); err != nil {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("invalid pricing path: %w", err)
+	}
+
+	// #nosec G304 - Path validated above, this is intentional file inclusion
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("failed to read pricing file: %w", err)
+	}
+
+	var profile benchmetrics.PricingProfile
+	if err := toml.Unmarshal(data, &profile); err != nil {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("failed to parse pricing file: %w", err)
+	}
+	if profile.Name == "" {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("pricing file %s must set a name", path)
+	}
+	if err := profile.Validate(); err != nil {
+		return benchmetrics.PricingProfile{}, fmt.Errorf("invalid pricing file %s: %w", path, err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// ProviderTypes lists the built-in provider clients.
+var ProviderTypes = []string{"firecrawl", "tavily", "local", "brave", "exa", "mixedbread", "jina"}
+
+// ProviderConfig configures how a provider client reaches its API. A
+// [providers.<name>] section named after a built-in provider adjusts that
+// provider; any other name defines an additional instance, such as
+// "firecrawl-selfhosted", of the provider given by type.
+type ProviderConfig struct {
+	Type    string `toml:"type,omitempty"`
+	BaseURL string `toml:"base_url,omitempty"`
+	// BaseURLs sets base URLs per endpoint for providers with several, such
+	// as Jina's reader and search APIs.
+	BaseURLs  map[string]string `toml:"base_urls,omitempty"`
+	Timeout   string            `toml:"timeout,omitempty"`
+	Headers   map[string]string `toml:"headers,omitempty"`
+	Proxy     string            `toml:"proxy,omitempty"`
+	APIKeyEnv string            `toml:"api_key_env,omitempty"` // env var holding the API key
+}
+
+func isProviderType(name string) bool {
+	for _, t := range ProviderTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeProviders lowercases section names and types and validates each
+// section. Instances inherit their type's provider_concurrency unless they
+// set their own.
+func (c *Config) normalizeProviders() error {
+	if len(c.Providers) == 0 {
+		return nil
+	}
+	normalized := make(map[string]ProviderConfig, len(c.Providers))
+	for rawName, p := range c.Providers {
+		name := strings.ToLower(strings.TrimSpace(rawName))
+		if name == "" || name == "all" || strings.ContainsAny(name, ", ") {
+			return fmt.Errorf("invalid provider instance name %q", rawName)
+		}
+		if _, exists := normalized[name]; exists {
+			return fmt.Errorf("provider '%s' is configured more than once", name)
+		}
+
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+		switch {
+		case isProviderType(name) && p.Type != "" && p.Type != name:
+			return fmt.Errorf("provider '%s' cannot change its type to '%s'", name, p.Type)
+		case isProviderType(name):
+			p.Type = name
+		case p.Type == "":
+			return fmt.Errorf("provider instance '%s' requires a type (%s)", name, strings.Join(ProviderTypes, ", "))
+		case !isProviderType(p.Type):
+			return fmt.Errorf("provider instance '%s' has invalid type: %s", name, p.Type)
+		}
+
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("provider '%s': %w", name, err)
+		}
+		normalized[name] = p
+
+		if limit, ok := c.General.ProviderConcurrency[p.Type]; ok && name != p.Type {
+			if _, set := c.General.ProviderConcurrency[name]; !set {
+				c.General.ProviderConcurrency[name] = limit
+			}
+		}
+	}
+	c.Providers = normalized
+	return nil
+}
+
+func (p ProviderConfig) validate() error {
+	if p.Timeout != "" {
+		d, err := time.ParseDuration(p.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout: %s", p.Timeout)
+		}
+	}
+	urls := map[string]string{"base_url": p.BaseURL, "proxy": p.Proxy}
+	for endpoint, baseURL := range p.BaseURLs {
+		urls["base_urls."+endpoint] = baseURL
+	}
+	for field, raw := range urls {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s: %q must be an http(s) URL", field, raw)
+		}
+	}
+	for key := range p.Headers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("header names must not be empty")
+		}
+	}
+	return nil
+}
+
+// ProviderType returns the provider type for a built-in provider or a
+// configured instance name, or "" when the name is unknown.
+func (c *Config) ProviderType(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if p, ok := c.Providers[name]; ok {
+		return p.Type
+	}
+	if isProviderType(name) {
+		return name
+	}
+	return ""
+}
+
+// Instances returns the configured instance names that are not built-in
+// providers, sorted.
+func (c *Config) Instances() []string {
+	var names []string
+	for name := range c.Providers {
+		if !isProviderType(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderSettings returns the client settings for a provider or instance.
+// Providers without a section get empty settings, which keep the defaults.
+func (c *Config) ProviderSettings(name string) providers.Settings {
+	name = strings.ToLower(strings.TrimSpace(name))
+	settings := providers.Settings{Name: name}
+	p, ok := c.Providers[name]
+	if !ok {
+		return settings
+	}
+
+	if p.BaseURL != "" || len(p.BaseURLs) > 0 {
+		settings.BaseURLs = make(map[string]string, len(p.BaseURLs)+1)
+		for endpoint, baseURL := range p.BaseURLs {
+			settings.BaseURLs[endpoint] = baseURL
+		}
+		if p.BaseURL != "" {
+			settings.BaseURLs[providers.EndpointAPI] = p.BaseURL
+		}
+	}
+	if p.Timeout != "" {
+		// Validated on load.
+		settings.Timeout, _ = time.ParseDuration(p.Timeout)
+	}
+	settings.Headers = p.Headers
+	settings.Proxy = p.Proxy
+	settings.APIKeyEnv = p.APIKeyEnv
+	return settings
+}
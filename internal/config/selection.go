@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// testTypes are the valid test types.
+var testTypes = []string{"search", "extract", "crawl", "structured_extract"}
+
+// HasTag reports whether the test carries tag, ignoring case.
+func (t TestConfig) HasTag(tag string) bool {
+	for _, own := range t.Tags {
+		if strings.EqualFold(own, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectTests returns the tests chosen by sel, in config order. A test is
+// selected when its name matches sel.Run (unanchored, like go test -run), it
+// has any of sel.Tags and none of sel.SkipTags, its type is one of sel.Types
+// and it is not a search test when sel.NoSearch is set. Empty criteria match
+// every test. Quick mode is applied separately.
+func SelectTests(tests []TestConfig, sel benchmetrics.Selection) ([]TestConfig, error) {
+	var run *regexp.Regexp
+	if sel.Run != "" {
+		var err error
+		if run, err = regexp.Compile(sel.Run); err != nil {
+			return nil, fmt.Errorf("invalid -run pattern: %w", err)
+		}
+	}
+	for _, typ := range sel.Types {
+		if !containsString(testTypes, typ) {
+			return nil, fmt.Errorf("invalid test type %q (valid: %s)", typ, strings.Join(testTypes, ", "))
+		}
+	}
+
+	var selected []TestConfig
+	for _, test := range tests {
+		if run != nil && !run.MatchString(test.Name) {
+			continue
+		}
+		if len(sel.Tags) > 0 && !test.hasAnyTag(sel.Tags) {
+			continue
+		}
+		if test.hasAnyTag(sel.SkipTags) {
+			continue
+		}
+		if len(sel.Types) > 0 && !containsString(sel.Types, test.Type) {
+			continue
+		}
+		if sel.NoSearch && test.Type == "search" {
+			continue
+		}
+		selected = append(selected, test)
+	}
+	return selected, nil
+}
+
+func (t TestConfig) hasAnyTag(tags []string) bool {
+	for _, tag := range tags {
+		if t.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTags trims the test's tags and rejects empty ones.
+func (t *TestConfig) normalizeTags() error {
+	for i, tag := range t.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return fmt.Errorf("test '%s' has an empty tag", t.Name)
+		}
+		t.Tags[i] = tag
+	}
+	return nil
+}
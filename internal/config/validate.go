@@ -0,0 +1,557 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// Issue severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Issue is a problem Validate found in a config or suite file.
+type Issue struct {
+	File     string
+	Line     int // 0 when the issue has no single line
+	Severity string
+	Message  string
+}
+
+// String formats the issue like a compiler diagnostic, e.g.
+// "config.toml:12: error: test 'Docs' of type 'search' requires a query".
+func (i Issue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location += ":" + strconv.Itoa(i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Severity, i.Message)
+}
+
+// HasErrors reports whether any of issues is an error.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the config file at path and the suite files it includes
+// against the config schema, applying the named profile, and reports every
+// problem it finds with its file and line instead of stopping at the first:
+// TOML syntax and type errors, unknown keys, tests missing required fields or
+// setting fields their type ignores, duplicate test names and unknown
+// providers in provider_concurrency. When none of these is an error it loads
+// the config as LoadProfile does and returns it, reporting any remaining
+// error.
+func Validate(path, profile string) (*Config, []Issue) {
+	v := &validator{}
+	var cfg Config
+	index, ok := v.decode(path, &cfg)
+	if !ok {
+		return nil, v.issues
+	}
+
+	tests := make([]locatedTest, 0, len(cfg.Tests))
+	for i, test := range cfg.Tests {
+		tests = append(tests, locatedTest{TestConfig: test, file: path, index: index, i: i})
+	}
+	baseDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		v.add(SeverityError, path, 0, "failed to resolve config directory: %v", err)
+		return nil, v.issues
+	}
+	paths, err := includePaths(cfg.Include, baseDir)
+	if err != nil {
+		v.add(SeverityError, path, index.line("include", 0), "%v", err)
+	}
+	for _, suitePath := range paths {
+		tests = append(tests, v.suiteTests(suitePath)...)
+	}
+
+	if err := cfg.applyProfile(profile); err != nil {
+		v.add(SeverityError, path, 0, "%v", err)
+	}
+	v.checkGeneral(&cfg, path, index, profile)
+	v.checkTests(tests)
+	if len(tests) == 0 {
+		v.add(SeverityError, path, 0, "no tests defined in configuration")
+	}
+	if HasErrors(v.issues) {
+		return nil, v.sorted()
+	}
+
+	loaded, err := LoadProfile(path, profile)
+	if err != nil {
+		v.add(SeverityError, path, 0, "%v", err)
+		return nil, v.sorted()
+	}
+	return loaded, v.sorted()
+}
+
+type validator struct {
+	issues []Issue
+}
+
+// sorted returns the issues in file and line order.
+func (v *validator) sorted() []Issue {
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		a, b := v.issues[i], v.issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return v.issues
+}
+
+func (v *validator) add(severity, file string, line int, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{File: file, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// locatedTest is a test with the file and [[tests]] entry it came from.
+type locatedTest struct {
+	TestConfig
+	file  string
+	index *lineIndex
+	i     int // position among the file's [[tests]]
+}
+
+// line returns the line setting key in the test, or its [[tests]] header
+// when key is empty or not set.
+func (t locatedTest) line(key string) int {
+	if key != "" {
+		if line := t.index.lineIn("tests."+key, "tests", t.i); line > 0 {
+			return line
+		}
+	}
+	return t.index.line("tests", t.i)
+}
+
+// tomlLinePattern extracts the line from decode errors without a position.
+var tomlLinePattern = regexp.MustCompile(`^toml: line (\d+)(?: \(last key "[^"]*"\))?: `)
+
+// decode decodes the TOML file at path into dst, reporting syntax and type
+// errors, unknown keys and unset environment variables. It returns the
+// file's line index and whether dst can be checked further.
+func (v *validator) decode(path string, dst interface{}) (*lineIndex, bool) {
+	// #nosec G304 - Config and suite files are named by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		v.add(SeverityError, path, 0, "%v", err)
+		return nil, false
+	}
+	index := indexLines(data)
+	meta, err := toml.Decode(string(data), dst)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			v.add(SeverityError, path, parseErr.Position.Line, "%s", parseErr.Message)
+			return nil, false
+		}
+		line := 0
+		message := err.Error()
+		if m := tomlLinePattern.FindStringSubmatch(message); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			message = message[len(m[0]):]
+		}
+		v.add(SeverityError, path, line, "%s", message)
+		return nil, false
+	}
+
+	seen := make(map[string]int)
+	var unknown []string
+	for _, key := range meta.Undecoded() {
+		name := strings.Join(key, ".")
+		n := seen[name]
+		seen[name]++
+		if hasKeyPrefix(name, unknown) {
+			continue // inside a table already reported
+		}
+		unknown = append(unknown, name)
+		v.add(SeverityError, path, index.line(name, n), "unknown key %s", name)
+	}
+	if err := interpolate(dst); err != nil {
+		v.add(SeverityError, path, 0, "%v", err)
+		return index, false
+	}
+	return index, true
+}
+
+func hasKeyPrefix(name string, tables []string) bool {
+	for _, table := range tables {
+		if strings.HasPrefix(name, table+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// suiteTests decodes the suite file at path and returns its tests named with
+// the suite prefix.
+func (v *validator) suiteTests(path string) []locatedTest {
+	var suite suiteFile
+	index, ok := v.decode(path, &suite)
+	if !ok {
+		return nil
+	}
+	raw := make([]TestConfig, len(suite.Tests))
+	copy(raw, suite.Tests)
+	resolved, err := suite.resolve(path)
+	if err != nil {
+		// A test without a name; checkTests reports it with its line.
+		resolved = raw
+	}
+	tests := make([]locatedTest, len(resolved))
+	for i, test := range resolved {
+		tests[i] = locatedTest{TestConfig: test, file: path, index: index, i: i}
+	}
+	return tests
+}
+
+// checkGeneral checks the general settings and the profile applied to them.
+func (v *validator) checkGeneral(cfg *Config, path string, index *lineIndex, profile string) {
+	g := cfg.General
+	line := func(key string) int {
+		if profile != "" {
+			if l := index.line("profiles."+profile+"."+key, 0); l > 0 {
+				return l
+			}
+		}
+		return index.line("general."+key, 0)
+	}
+	if g.Concurrency < 0 {
+		v.add(SeverityError, path, line("concurrency"), "general.concurrency must not be negative: %d", g.Concurrency)
+	}
+	if g.Timeout != "" {
+		if d, err := time.ParseDuration(g.Timeout); err != nil {
+			v.add(SeverityWarning, path, line("timeout"), "invalid general.timeout %q; tests run with the 30s default", g.Timeout)
+		} else if d <= 0 {
+			v.add(SeverityError, path, line("timeout"), "general.timeout must be positive: %q", g.Timeout)
+		}
+	}
+	providers := make([]string, 0, len(g.ProviderConcurrency))
+	for provider := range g.ProviderConcurrency {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		limit := g.ProviderConcurrency[provider]
+		name := strings.ToLower(strings.TrimSpace(provider))
+		if !isProviderType(name) && !cfg.hasInstance(name) {
+			v.add(SeverityError, path, line("provider_concurrency"),
+				"provider_concurrency names unknown provider '%s' (built-in: %s; or a [providers.<name>] instance)",
+				provider, strings.Join(ProviderTypes, ", "))
+		}
+		if limit <= 0 {
+			v.add(SeverityError, path, line("provider_concurrency"), "provider_concurrency for '%s' must be > 0", provider)
+		}
+	}
+}
+
+// hasInstance reports whether a [providers.<name>] section names the
+// instance, ignoring case.
+func (c *Config) hasInstance(name string) bool {
+	for raw := range c.Providers {
+		if strings.EqualFold(strings.TrimSpace(raw), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeFields lists the test fields that only some test types use, with the
+// types that use them.
+var typeFields = []struct {
+	key   string
+	types []string
+	set   func(TestConfig) bool
+}{
+	{"query", []string{"search"}, func(t TestConfig) bool { return t.Query != "" }},
+	{"url", []string{"extract", "crawl", "structured_extract"}, func(t TestConfig) bool { return t.URL != "" }},
+	{"max_pages", []string{"crawl"}, func(t TestConfig) bool { return t.MaxPages != nil }},
+	{"max_depth", []string{"crawl"}, func(t TestConfig) bool { return t.MaxDepth != nil }},
+	{"expected_max_depth", []string{"crawl"}, func(t TestConfig) bool { return t.ExpectedMaxDepth != nil }},
+	{"expected_urls", []string{"search", "crawl"}, func(t TestConfig) bool { return len(t.ExpectedURLs) > 0 }},
+	{"relevance", []string{"search"}, func(t TestConfig) bool { return len(t.Relevance) > 0 }},
+	{"time_range", []string{"search"}, func(t TestConfig) bool { return t.TimeRange != "" }},
+	{"include_domains", []string{"search"}, func(t TestConfig) bool { return len(t.IncludeDomains) > 0 }},
+	{"exclude_domains", []string{"search"}, func(t TestConfig) bool { return len(t.ExcludeDomains) > 0 }},
+	{"country", []string{"search"}, func(t TestConfig) bool { return t.Country != "" }},
+	{"safe_search", []string{"search"}, func(t TestConfig) bool { return t.SafeSearch != "" }},
+	{"schema", []string{"structured_extract"}, func(t TestConfig) bool { return t.Schema != "" }},
+	{"expected", []string{"structured_extract"}, func(t TestConfig) bool { return len(t.Expected) > 0 }},
+	{"extraction_prompt", []string{"structured_extract"}, func(t TestConfig) bool { return t.ExtractionPrompt != "" }},
+	{"document_type", []string{"extract"}, func(t TestConfig) bool { return t.DocumentType != "" }},
+	{"reference_text", []string{"extract"}, func(t TestConfig) bool { return t.ReferenceText != "" }},
+	{"reference_file", []string{"extract"}, func(t TestConfig) bool { return t.ReferenceFile != "" }},
+	{"expected_pages", []string{"extract"}, func(t TestConfig) bool { return t.ExpectedPages != nil }},
+	{"expected_tables", []string{"extract"}, func(t TestConfig) bool { return t.ExpectedTables != nil }},
+}
+
+// checkTests checks each test and reports duplicate names.
+func (v *validator) checkTests(tests []locatedTest) {
+	names := make(map[string]locatedTest, len(tests))
+	for _, test := range tests {
+		if err := validateTest(test.i, test.TestConfig); err != nil {
+			v.add(SeverityError, test.file, test.line(""), "%v", err)
+		}
+		if test.ReferenceText != "" && test.ReferenceFile != "" {
+			v.add(SeverityError, test.file, test.line("reference_file"), "test '%s' sets both reference_text and reference_file", test.Name)
+		}
+		v.checkConflicts(test)
+		if !containsString(testTypes, test.Type) {
+			continue
+		}
+		for _, field := range typeFields {
+			if field.set(test.TestConfig) && !containsString(field.types, test.Type) {
+				v.add(SeverityWarning, test.file, test.line(field.key),
+					"test '%s' sets %s, which %s tests ignore", test.Name, field.key, test.Type)
+			}
+		}
+		if test.Name == "" {
+			continue
+		}
+		if first, ok := names[test.Name]; ok {
+			v.add(SeverityError, test.file, test.line("name"), "duplicate test name '%s' (first defined at %s:%d)",
+				test.Name, first.file, first.line("name"))
+			continue
+		}
+		names[test.Name] = test
+	}
+}
+
+// checkConflicts reports expectations of a test that contradict each other,
+// so no result could pass it.
+func (v *validator) checkConflicts(test locatedTest) {
+	if term, ok := sharedValue(test.MustIncludeTerms, test.MustNotIncludeTerms); ok {
+		v.add(SeverityError, test.file, test.line("must_not_include_terms"),
+			"test '%s' lists %q in both must_include_terms and must_not_include_terms", test.Name, term)
+	}
+	if snippet, ok := sharedValue(test.ExpectedSnippets, test.ForbiddenSnippets); ok {
+		v.add(SeverityError, test.file, test.line("forbidden_snippets"),
+			"test '%s' lists %q in both expected_snippets and forbidden_snippets", test.Name, snippet)
+	}
+	if test.Type != "search" {
+		return
+	}
+	for _, expected := range test.ExpectedURLs {
+		for _, domain := range test.ExcludeDomains {
+			if providers.HostMatchesDomain(expected, domain) {
+				v.add(SeverityError, test.file, test.line("expected_urls"),
+					"test '%s' expects %s, which exclude_domains filters out (%s)", test.Name, expected, domain)
+			}
+		}
+		if len(test.IncludeDomains) == 0 {
+			continue
+		}
+		included := false
+		for _, domain := range test.IncludeDomains {
+			included = included || providers.HostMatchesDomain(expected, domain)
+		}
+		if !included {
+			v.add(SeverityError, test.file, test.line("expected_urls"),
+				"test '%s' expects %s, which is outside include_domains", test.Name, expected)
+		}
+	}
+}
+
+// sharedValue returns the first value of a that b also lists, ignoring case
+// and surrounding whitespace as scoring does.
+func sharedValue(a, b []string) (string, bool) {
+	for _, x := range a {
+		for _, y := range b {
+			if strings.TrimSpace(x) != "" && strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y)) {
+				return x, true
+			}
+		}
+	}
+	return "", false
+}
+
+// lineIndex maps the keys and table headers of a TOML file to their lines.
+// Keys are dotted paths without array indexes, e.g. "tests.query"; for keys
+// inside an array of tables, the element they belong to is recorded too.
+type lineIndex struct {
+	keys map[string][]keyLine
+}
+
+type keyLine struct {
+	array   string // enclosing array of tables, e.g. "tests"
+	element int    // position in that array
+	line    int
+}
+
+// line returns the line of the nth occurrence of key, or 0.
+func (x *lineIndex) line(key string, n int) int {
+	if x == nil {
+		return 0
+	}
+	if occurrences := x.keys[key]; n < len(occurrences) {
+		return occurrences[n].line
+	}
+	return 0
+}
+
+// lineIn returns the line of key within the given element of an array of
+// tables, or 0.
+func (x *lineIndex) lineIn(key, array string, element int) int {
+	if x == nil {
+		return 0
+	}
+	for _, occurrence := range x.keys[key] {
+		if occurrence.array == array && occurrence.element == element {
+			return occurrence.line
+		}
+	}
+	return 0
+}
+
+// indexLines scans TOML source for table headers and key assignments. It
+// skips multi-line strings and arrays; it is meant for locating diagnostics
+// in files that already parse, not for validating them.
+func indexLines(data []byte) *lineIndex {
+	s := &lineScanner{
+		index:   &lineIndex{keys: make(map[string][]keyLine)},
+		arrays:  make(map[string]int),
+		element: -1,
+	}
+	for n, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if s.skip(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			s.header(line, n+1)
+		} else {
+			s.assignment(line, n+1)
+		}
+	}
+	return s.index
+}
+
+// lineScanner tracks the table and multi-line value indexLines is in.
+type lineScanner struct {
+	index        *lineIndex
+	arrays       map[string]int // elements seen per array of tables
+	table, array string
+	element      int
+	openString   string // delimiter of the multi-line string being skipped
+	depth        int    // bracket depth of the multi-line array being skipped
+}
+
+// skip reports whether line holds no key or header: a comment, a blank line
+// or the continuation of a multi-line value.
+func (s *lineScanner) skip(line string) bool {
+	switch {
+	case s.openString != "":
+		if strings.Count(line, s.openString)%2 == 1 {
+			s.openString = ""
+		}
+		return true
+	case s.depth > 0:
+		s.depth += bracketDepth(line)
+		return true
+	default:
+		return line == "" || strings.HasPrefix(line, "#")
+	}
+}
+
+// header records a [table] or [[array]] header.
+func (s *lineScanner) header(line string, lineNo int) {
+	if strings.HasPrefix(line, "[[") {
+		end := strings.Index(line, "]]")
+		if end < 0 {
+			return
+		}
+		s.table = normalizeKey(line[2:end])
+		s.array = s.table
+		s.element = s.arrays[s.table]
+		s.arrays[s.table]++
+	} else {
+		end := strings.Index(line, "]")
+		if end < 0 {
+			return
+		}
+		s.table = normalizeKey(line[1:end])
+		if s.array == "" || !strings.HasPrefix(s.table, s.array+".") {
+			s.array, s.element = "", -1
+		}
+	}
+	s.index.add(s.table, s.array, s.element, lineNo)
+}
+
+// assignment records a key = value line and notes a value that continues
+// on the following lines.
+func (s *lineScanner) assignment(line string, lineNo int) {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return
+	}
+	key := normalizeKey(line[:eq])
+	if s.table != "" {
+		key = s.table + "." + key
+	}
+	s.index.add(key, s.array, s.element, lineNo)
+
+	value := line[eq+1:]
+	for _, delimiter := range []string{`"""`, `'''`} {
+		if strings.Count(value, delimiter)%2 == 1 {
+			s.openString = delimiter
+		}
+	}
+	if s.openString == "" {
+		s.depth = bracketDepth(value)
+	}
+}
+
+func (x *lineIndex) add(key, array string, element, line int) {
+	x.keys[key] = append(x.keys[key], keyLine{array: array, element: element, line: line})
+}
+
+// normalizeKey trims a TOML key and the spaces and quotes around its dotted
+// parts, e.g. `general . "timeout"` becomes "general.timeout".
+func normalizeKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// bracketDepth returns how many more arrays a line of TOML opens than it
+// closes, ignoring brackets in strings and comments.
+func bracketDepth(line string) int {
+	depth := 0
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return depth
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth
+}
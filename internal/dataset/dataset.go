@@ -0,0 +1,369 @@
+// Package dataset imports search tests from IR evaluation datasets: query
+// sets and relevance judgments (qrels) in the TSV and JSONL layouts BEIR,
+// MS MARCO and TREC publish.
+package dataset
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+)
+
+// Query is one query of a query set.
+type Query struct {
+	ID   string
+	Text string
+}
+
+// Judgment grades one document's relevance to a query; higher grades are more
+// relevant, and 0 usually marks a judged non-relevant document.
+type Judgment struct {
+	QueryID string
+	DocID   string
+	Grade   int
+}
+
+// maxLineSize bounds a line of a dataset file; corpus documents can be long.
+const maxLineSize = 16 << 20
+
+// queryIDKeys and queryTextKeys are the JSONL keys a query's ID and text are
+// read from, in order of preference.
+var (
+	queryIDKeys   = []string{"_id", "id", "query_id", "query-id", "qid"}
+	queryTextKeys = []string{"text", "query", "title"}
+)
+
+// LoadQueries reads a query set: JSONL objects with "_id" and "text" as in
+// BEIR, or TSV lines of ID and text as in MS MARCO and TREC DL, with an
+// optional header line. Files ending in .gz are decompressed.
+func LoadQueries(path string) ([]Query, error) {
+	var queries []Query
+	err := eachLine(path, func(lineNo int, line string) error {
+		var q Query
+		if strings.HasPrefix(line, "{") {
+			obj, err := decodeObject(line)
+			if err != nil {
+				return err
+			}
+			q = Query{ID: field(obj, queryIDKeys...), Text: field(obj, queryTextKeys...)}
+		} else {
+			id, text, ok := strings.Cut(line, "\t")
+			if !ok {
+				return fmt.Errorf("expected a tab between query ID and text")
+			}
+			if lineNo == 1 && containsFold(queryIDKeys, id) {
+				return nil
+			}
+			q = Query{ID: strings.TrimSpace(id), Text: text}
+		}
+		q.Text = strings.TrimSpace(q.Text)
+		if q.ID == "" || q.Text == "" {
+			return fmt.Errorf("query is missing an ID or text")
+		}
+		queries = append(queries, q)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load queries: %w", err)
+	}
+	return queries, nil
+}
+
+// LoadQrels reads relevance judgments: BEIR TSV lines of query ID, document
+// ID and score with an optional header, TREC and MS MARCO lines of query ID,
+// iteration, document ID and grade, or JSONL objects with "query-id",
+// "corpus-id" and "score". Columns may be separated by tabs or spaces. Files
+// ending in .gz are decompressed.
+func LoadQrels(path string) ([]Judgment, error) {
+	var qrels []Judgment
+	err := eachLine(path, func(lineNo int, line string) error {
+		if strings.HasPrefix(line, "{") {
+			j, err := jsonJudgment(line)
+			if err != nil {
+				return err
+			}
+			qrels = append(qrels, j)
+			return nil
+		}
+		fields := strings.Fields(line)
+		var j Judgment
+		var grade string
+		switch len(fields) {
+		case 3:
+			j.QueryID, j.DocID, grade = fields[0], fields[1], fields[2]
+		case 4:
+			j.QueryID, j.DocID, grade = fields[0], fields[2], fields[3]
+		default:
+			return fmt.Errorf("expected 3 or 4 columns, found %d", len(fields))
+		}
+		n, err := strconv.Atoi(grade)
+		if err != nil {
+			if lineNo == 1 {
+				return nil // header
+			}
+			return fmt.Errorf("invalid relevance grade %q", grade)
+		}
+		j.Grade = n
+		qrels = append(qrels, j)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load qrels: %w", err)
+	}
+	return qrels, nil
+}
+
+func jsonJudgment(line string) (Judgment, error) {
+	obj, err := decodeObject(line)
+	if err != nil {
+		return Judgment{}, err
+	}
+	j := Judgment{
+		QueryID: field(obj, "query-id", "query_id", "qid"),
+		DocID:   field(obj, "corpus-id", "corpus_id", "doc_id", "docid"),
+	}
+	if j.QueryID == "" || j.DocID == "" {
+		return Judgment{}, fmt.Errorf("judgment is missing a query or document ID")
+	}
+	score := field(obj, "score", "relevance", "grade")
+	if j.Grade, err = strconv.Atoi(score); err != nil {
+		return Judgment{}, fmt.Errorf("invalid relevance grade %q", score)
+	}
+	return j, nil
+}
+
+// LoadCorpusURLs reads the URLs of the documents in ids from a JSONL corpus,
+// taken from a document's "url" key or its "metadata" object's. Documents
+// without a URL are left out. A nil ids reads every document.
+func LoadCorpusURLs(path string, ids map[string]bool) (map[string]string, error) {
+	urls := make(map[string]string)
+	err := eachLine(path, func(_ int, line string) error {
+		obj, err := decodeObject(line)
+		if err != nil {
+			return err
+		}
+		id := field(obj, "_id", "id", "doc_id", "docid")
+		if ids != nil && !ids[id] {
+			return nil
+		}
+		url := field(obj, "url")
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok && url == "" {
+			url = field(metadata, "url")
+		}
+		if id != "" && url != "" {
+			urls[id] = url
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load corpus: %w", err)
+	}
+	return urls, nil
+}
+
+// eachLine calls fn with each non-blank line of the file at path, numbered
+// from 1, and prefixes fn's errors with the file and line.
+func eachLine(path string, fn func(lineNo int, line string) error) error {
+	// #nosec G304 - Dataset files are named by the user
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" {
+			continue
+		}
+		if err := fn(lineNo, line); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func decodeObject(line string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return obj, nil
+}
+
+// field returns the first of keys obj holds a string or number for, as a
+// trimmed string.
+func field(obj map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		switch value := obj[key].(type) {
+		case string:
+			return strings.TrimSpace(value)
+		case json.Number:
+			return value.String()
+		}
+	}
+	return ""
+}
+
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Options control how queries and judgments become tests.
+type Options struct {
+	// Sample keeps this many queries, drawn with Seed; 0 keeps all.
+	Sample int
+	Seed   int64
+	// MinGrade is the lowest grade that counts as relevant; it defaults to 1.
+	MinGrade int
+	// URLs maps document IDs to the URLs tests expect.
+	URLs URLMapper
+	// KeepUnmapped keeps document IDs URLs does not map. Results match them
+	// when their URLs contain the ID, so short numeric IDs match unrelated
+	// results; by default they are dropped.
+	KeepUnmapped bool
+	// Tags are added to every test.
+	Tags []string
+}
+
+// Import turns queries into search tests that expect the documents judged
+// relevant to them, as the URLs opts.URLs maps them to, most relevant first,
+// with their grades as the tests' relevance. Unmapped documents are dropped
+// unless opts.KeepUnmapped is set, and queries left without a relevant
+// document are left out. Tests keep the order of
+// queries, and are named by query ID and the start of the query.
+func Import(queries []Query, qrels []Judgment, opts Options) ([]config.TestConfig, error) {
+	if opts.MinGrade <= 0 {
+		opts.MinGrade = 1
+	}
+	relevant := make(map[string]map[string]int)
+	unmapped := 0
+	for _, j := range qrels {
+		if j.Grade < opts.MinGrade {
+			continue
+		}
+		target, ok := opts.URLs.Map(j.DocID)
+		if !ok && !opts.KeepUnmapped {
+			unmapped++
+			continue
+		}
+		if relevant[j.QueryID] == nil {
+			relevant[j.QueryID] = make(map[string]int)
+		}
+		if j.Grade > relevant[j.QueryID][target] {
+			relevant[j.QueryID][target] = j.Grade
+		}
+	}
+
+	var eligible []Query
+	seen := make(map[string]bool, len(queries))
+	for _, q := range queries {
+		if seen[q.ID] {
+			return nil, fmt.Errorf("duplicate query ID %s", q.ID)
+		}
+		seen[q.ID] = true
+		if len(relevant[q.ID]) > 0 {
+			eligible = append(eligible, q)
+		}
+	}
+	if len(eligible) == 0 && unmapped > 0 {
+		return nil, fmt.Errorf("no query has a document graded %d or higher that maps to a URL (%d unmapped)", opts.MinGrade, unmapped)
+	}
+	if len(eligible) == 0 {
+		return nil, fmt.Errorf("no query has a document graded %d or higher", opts.MinGrade)
+	}
+
+	selected := sample(eligible, opts.Sample, opts.Seed)
+	tests := make([]config.TestConfig, 0, len(selected))
+	for _, q := range selected {
+		tests = append(tests, config.TestConfig{
+			Name:         testName(q),
+			Type:         "search",
+			Query:        q.Text,
+			Tags:         append([]string(nil), opts.Tags...),
+			ExpectedURLs: byGrade(relevant[q.ID]),
+			Relevance:    relevant[q.ID],
+		})
+	}
+	return tests, nil
+}
+
+// sample returns n of queries drawn with seed, in their original order. The
+// same queries and seed always draw the same sample.
+func sample(queries []Query, n int, seed int64) []Query {
+	if n <= 0 || n >= len(queries) {
+		return queries
+	}
+	// #nosec G404 - Sampling must be reproducible, not unpredictable
+	picked := rand.New(rand.NewSource(seed)).Perm(len(queries))[:n]
+	sort.Ints(picked)
+	out := make([]Query, 0, n)
+	for _, i := range picked {
+		out = append(out, queries[i])
+	}
+	return out
+}
+
+// byGrade returns the graded targets, highest grade first and then by name.
+func byGrade(grades map[string]int) []string {
+	targets := make([]string, 0, len(grades))
+	for target := range grades {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if grades[targets[i]] != grades[targets[j]] {
+			return grades[targets[i]] > grades[targets[j]]
+		}
+		return targets[i] < targets[j]
+	})
+	return targets
+}
+
+// maxNameQueryLen bounds the query text in test names.
+const maxNameQueryLen = 60
+
+func testName(q Query) string {
+	text := strings.Join(strings.Fields(q.Text), " ")
+	if utf8.RuneCountInString(text) > maxNameQueryLen {
+		text = string([]rune(text)[:maxNameQueryLen]) + "…"
+	}
+	return q.ID + ": " + text
+}
@@ -0,0 +1,245 @@
+package dataset
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if strings.HasSuffix(name, ".gz") {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gz := gzip.NewWriter(f)
+		if _, err := gz.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadQueriesAndQrels_Formats(t *testing.T) {
+	dir := t.TempDir()
+	want := []Query{{ID: "1", Text: "what is bm25"}, {ID: "2", Text: "dense retrieval"}}
+
+	for name, content := range map[string]string{
+		"queries.jsonl":  "{\"_id\": \"1\", \"text\": \"what is bm25\"}\n\n{\"_id\": 2, \"text\": \"dense retrieval\", \"metadata\": {}}\n",
+		"queries.tsv":    "1\twhat is bm25\n2\tdense retrieval\n",
+		"header.tsv":     "query_id\ttext\n1\twhat is bm25\n2\t dense retrieval \n",
+		"queries.tsv.gz": "1\twhat is bm25\n2\tdense retrieval\n",
+	} {
+		got, err := LoadQueries(writeFile(t, dir, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: queries = %+v, want %+v", name, got, want)
+		}
+	}
+
+	wantQrels := []Judgment{{QueryID: "1", DocID: "d1", Grade: 2}, {QueryID: "1", DocID: "d2", Grade: 0}}
+	for name, content := range map[string]string{
+		"beir.tsv":    "query-id\tcorpus-id\tscore\n1\td1\t2\n1\td2\t0\n",
+		"trec.txt":    "1 0 d1 2\n1 0 d2 0\n",
+		"qrels.jsonl": "{\"query-id\": \"1\", \"corpus-id\": \"d1\", \"score\": 2}\n{\"query-id\": 1, \"corpus-id\": \"d2\", \"score\": 0}\n",
+	} {
+		got, err := LoadQrels(writeFile(t, dir, name, content))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, wantQrels) {
+			t.Errorf("%s: qrels = %+v, want %+v", name, got, wantQrels)
+		}
+	}
+
+	_, err := LoadQrels(writeFile(t, dir, "bad.tsv", "1 d1 2\n1 d2 high\n"))
+	if err == nil || !strings.Contains(err.Error(), "bad.tsv:2") {
+		t.Errorf("expected an error locating line 2, got %v", err)
+	}
+}
+
+func TestLoadCorpusURLs(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "corpus.jsonl", strings.Join([]string{
+		`{"_id": "d1", "title": "BM25", "url": "https://en.wikipedia.org/wiki/Okapi_BM25"}`,
+		`{"_id": "d2", "text": "no url"}`,
+		`{"_id": "d3", "metadata": {"url": "https://example.com/d3"}}`,
+		`{"_id": "d4", "url": "https://example.com/unjudged"}`,
+	}, "\n"))
+
+	got, err := LoadCorpusURLs(path, map[string]bool{"d1": true, "d2": true, "d3": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"d1": "https://en.wikipedia.org/wiki/Okapi_BM25", "d3": "https://example.com/d3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("urls = %v, want %v", got, want)
+	}
+}
+
+func TestURLMapper(t *testing.T) {
+	wiki, err := ParseURLRule(`^wiki:(.+)$=>https://en.wikipedia.org/wiki/$1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	rules, err := LoadURLRules(writeFile(t, dir, "rules.toml", `
+[[url_rules]]
+match = '^D(?P<id>\d+)$'
+url = "https://msmarco.example/doc/${id}"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := URLMapper{
+		Corpus: map[string]string{"D1": "https://corpus.example/1"},
+		Rules:  append([]URLRule{wiki}, rules...),
+	}
+	for id, want := range map[string]string{
+		"wiki:Okapi_BM25":  "https://en.wikipedia.org/wiki/Okapi_BM25",
+		"D42":              "https://msmarco.example/doc/42",
+		"D1":               "https://corpus.example/1",
+		"arxiv-1706.03762": "arxiv-1706.03762",
+	} {
+		if got, ok := m.Map(id); got != want || ok != (got != id) {
+			t.Errorf("Map(%q) = %q, %v, want %q", id, got, ok, want)
+		}
+	}
+
+	if _, err := ParseURLRule("^x$"); err == nil {
+		t.Error("expected an error for a rule without =>")
+	}
+	if _, err := LoadURLRules(writeFile(t, dir, "typo.toml", "[[url_rules]]\nmatch = 'x'\ntemplate = 'y'\n")); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
+
+func TestImport_GradesSamplesAndSkipsUnjudgedQueries(t *testing.T) {
+	queries := []Query{
+		{ID: "q1", Text: "what is   bm25"},
+		{ID: "q2", Text: "no relevant documents"},
+		{ID: "q3", Text: strings.Repeat("long query ", 10)},
+		{ID: "q4", Text: "dense retrieval"},
+		{ID: "q5", Text: "learned sparse retrieval"},
+	}
+	qrels := []Judgment{
+		{QueryID: "q1", DocID: "D1", Grade: 1},
+		{QueryID: "q1", DocID: "D2", Grade: 3},
+		{QueryID: "q1", DocID: "D3", Grade: 0},
+		{QueryID: "q2", DocID: "D9", Grade: 0},
+		{QueryID: "q3", DocID: "D4", Grade: 1},
+		{QueryID: "q4", DocID: "D5", Grade: 2},
+		{QueryID: "q5", DocID: "D6", Grade: 1},
+		{QueryID: "unknown", DocID: "D7", Grade: 1},
+	}
+	rule, err := ParseURLRule(`^D(\d)$=>https://example.com/$1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{URLs: URLMapper{Rules: []URLRule{rule}}, Tags: []string{"msmarco"}}
+
+	tests, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 4 {
+		t.Fatalf("expected 4 tests (q2 has no relevant document), got %d", len(tests))
+	}
+	first := tests[0]
+	if first.Name != "q1: what is bm25" || first.Type != "search" || first.Query != "what is   bm25" {
+		t.Errorf("unexpected test %+v", first)
+	}
+	if want := []string{"https://example.com/2", "https://example.com/1"}; !reflect.DeepEqual(first.ExpectedURLs, want) {
+		t.Errorf("expected_urls = %v, want %v (highest grade first)", first.ExpectedURLs, want)
+	}
+	if want := map[string]int{"https://example.com/2": 3, "https://example.com/1": 1}; !reflect.DeepEqual(first.Relevance, want) {
+		t.Errorf("relevance = %v, want %v", first.Relevance, want)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"msmarco"}) {
+		t.Errorf("tags = %v", first.Tags)
+	}
+	if name := tests[1].Name; !strings.HasPrefix(name, "q3: long query") || !strings.HasSuffix(name, "…") {
+		t.Errorf("expected a truncated name, got %q", name)
+	}
+
+	opts.MinGrade = 2
+	graded, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graded) != 2 || graded[0].Relevance["https://example.com/1"] != 0 {
+		t.Errorf("min grade 2: unexpected tests %+v", graded)
+	}
+
+	opts.MinGrade, opts.Sample, opts.Seed = 0, 2, 7
+	sampled, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sampled) != 2 || !reflect.DeepEqual(sampled, again) {
+		t.Fatalf("expected the same 2 tests for the same seed, got %v and %v", sampled, again)
+	}
+	if sampled[0].Name > sampled[1].Name {
+		t.Errorf("expected sampled tests in query order, got %s before %s", sampled[0].Name, sampled[1].Name)
+	}
+
+	if _, err := Import(queries, []Judgment{{QueryID: "q1", DocID: "D1"}}, Options{}); err == nil {
+		t.Error("expected an error when no query has a relevant document")
+	}
+}
+
+func TestImport_DropsUnmappedDocumentsUnlessKept(t *testing.T) {
+	queries := []Query{{ID: "q1", Text: "bm25"}, {ID: "q2", Text: "dense retrieval"}}
+	qrels := []Judgment{
+		{QueryID: "q1", DocID: "D1", Grade: 1},
+		{QueryID: "q1", DocID: "7", Grade: 2},
+		{QueryID: "q2", DocID: "8", Grade: 1},
+	}
+	rule, err := ParseURLRule(`^D(\d)$=>https://example.com/$1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{URLs: URLMapper{Rules: []URLRule{rule}}}
+
+	tests, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tests) != 1 || !reflect.DeepEqual(tests[0].ExpectedURLs, []string{"https://example.com/1"}) {
+		t.Errorf("expected unmapped IDs and q2 to be dropped, got %+v", tests)
+	}
+
+	opts.KeepUnmapped = true
+	kept, err := Import(queries, qrels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept) != 2 || !reflect.DeepEqual(kept[0].ExpectedURLs, []string{"7", "https://example.com/1"}) {
+		t.Errorf("expected unmapped IDs to be kept, got %+v", kept)
+	}
+
+	_, err = Import(queries, qrels[1:], Options{})
+	if err == nil || !strings.Contains(err.Error(), "maps to a URL (2 unmapped)") {
+		t.Errorf("expected an error naming the unmapped documents, got %v", err)
+	}
+}
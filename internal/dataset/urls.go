@@ -0,0 +1,99 @@
+package dataset
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// URLRule rewrites the document IDs Match matches to a URL; the template
+// may refer to submatches as $1 or ${name}, as in regexp.Expand.
+type URLRule struct {
+	Match    *regexp.Regexp
+	Template string
+}
+
+// URLMapper maps a dataset's document IDs to URLs. URLs from a corpus take
+// precedence over the rules, which apply in order; the first that matches
+// wins.
+type URLMapper struct {
+	Corpus map[string]string
+	Rules  []URLRule
+}
+
+// Map returns the URL for a document ID, and false with the ID itself when
+// nothing maps it.
+func (m URLMapper) Map(docID string) (string, bool) {
+	if url, ok := m.Corpus[docID]; ok {
+		return url, true
+	}
+	for _, rule := range m.Rules {
+		if match := rule.Match.FindStringSubmatchIndex(docID); match != nil {
+			return string(rule.Match.ExpandString(nil, rule.Template, docID, match)), true
+		}
+	}
+	return docID, false
+}
+
+// ParseURLRule parses a rule written as "regexp=>template", e.g.
+// `^(\d+)$=>https://example.com/doc/$1`. The regexp is not anchored; use ^
+// and $ to match whole document IDs.
+func ParseURLRule(s string) (URLRule, error) {
+	match, template, ok := strings.Cut(s, "=>")
+	if !ok {
+		return URLRule{}, fmt.Errorf("invalid URL rule %q: expected regexp=>template", s)
+	}
+	return newURLRule(match, template)
+}
+
+func newURLRule(match, template string) (URLRule, error) {
+	if strings.TrimSpace(template) == "" {
+		return URLRule{}, fmt.Errorf("URL rule for %q has an empty template", match)
+	}
+	re, err := regexp.Compile(match)
+	if err != nil {
+		return URLRule{}, fmt.Errorf("invalid URL rule regexp %q: %w", match, err)
+	}
+	return URLRule{Match: re, Template: strings.TrimSpace(template)}, nil
+}
+
+// rulesFile is a TOML file of URL rules for a dataset:
+//
+//	[[url_rules]]
+//	match = '^msmarco_doc_(\d+)$'
+//	url = "https://example.com/doc/$1"
+type rulesFile struct {
+	URLRules []struct {
+		Match string `toml:"match"`
+		URL   string `toml:"url"`
+	} `toml:"url_rules"`
+}
+
+// LoadURLRules reads the [[url_rules]] of a TOML rules file, in order.
+func LoadURLRules(path string) ([]URLRule, error) {
+	// #nosec G304 - Rules files are named by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read URL rules: %w", err)
+	}
+	var file rulesFile
+	meta, err := toml.Decode(string(data), &file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL rules %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("URL rules %s may only set [[url_rules]] match and url, found %s", path, undecoded[0])
+	}
+	rules := make([]URLRule, 0, len(file.URLRules))
+	for _, r := range file.URLRules {
+		rule, err := newURLRule(r.Match, r.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,420 @@
+package debug
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Debug output formats.
+const (
+	FormatJSON = "json"
+	FormatHAR  = "har"
+	FormatAll  = "all"
+)
+
+// ParseFormat validates a debug output format; empty selects FormatJSON.
+func ParseFormat(format string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "":
+		return FormatJSON, nil
+	case FormatJSON, FormatHAR, FormatAll:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid debug format: %s (valid: json, har, all)", format)
+	}
+}
+
+// HAR is an HTTP Archive 1.2 document.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR document.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Pages   []HARPage  `json:"pages"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator names the application that wrote the archive.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HARPage groups the entries of one test.
+type HARPage struct {
+	StartedDateTime time.Time      `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     HARPageTimings `json:"pageTimings"`
+}
+
+// HARPageTimings are page load timings, unknown (-1) for API traffic.
+type HARPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// HAREntry is one request/response exchange.
+type HAREntry struct {
+	PageRef         string      `json:"pageref,omitempty"`
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// HARRequest describes a request.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse describes a response. Status 0 marks a request without a
+// logged response, such as a failed attempt that was retried.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header, cookie or query parameter.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is a request body.
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent is a response body. Text is only set for full-capture logs.
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings are the phases of an exchange in milliseconds; -1 means the
+// phase does not apply or was not measured. Connect includes SSL.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+const harUnknown = -1
+
+// BuildHAR converts provider logs to a HAR 1.2 archive with one page per
+// test. Each request pairs with the response logged for it, see
+// testResponses.
+// Headers are sanitized again so archives from older logs stay redacted.
+func BuildHAR(providerLogs ...*ProviderLog) *HAR {
+	har := &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "SanityWebEval", Version: fmt.Sprintf("debug-schema-%d", debugSchemaVersion)},
+		Pages:   []HARPage{},
+		Entries: []HAREntry{},
+	}}
+	for _, providerLog := range providerLogs {
+		if providerLog == nil {
+			continue
+		}
+		for _, testLog := range providerLog.Tests {
+			har.Log.Pages = append(har.Log.Pages, HARPage{
+				StartedDateTime: testLog.StartTime,
+				ID:              testLog.ID,
+				Title:           fmt.Sprintf("%s: %s (%s)", providerLog.Name, testLog.TestName, testLog.TestType),
+				PageTimings:     HARPageTimings{OnContentLoad: harUnknown, OnLoad: harUnknown},
+			})
+			responses := testResponses(testLog)
+			for i, req := range testLog.Requests {
+				har.Log.Entries = append(har.Log.Entries, harEntry(testLog.ID, req, responses[i]))
+			}
+		}
+	}
+	sort.SliceStable(har.Log.Entries, func(a, b int) bool {
+		return har.Log.Entries[a].StartedDateTime.Before(har.Log.Entries[b].StartedDateTime)
+	})
+	return har
+}
+
+// testResponses returns the response logged for each request, nil where none
+// was. Responses pair with requests by request ID; logs written before IDs
+// pair by index, and logs written before every response was kept give the
+// last request the last response.
+func testResponses(testLog *TestLog) []*ResponseLog {
+	out := make([]*ResponseLog, len(testLog.Requests))
+	if len(testLog.Responses) == 0 {
+		if testLog.Response != nil && len(out) > 0 {
+			out[len(out)-1] = testLog.Response
+		}
+		return out
+	}
+	byID := make(map[int]int, len(testLog.Requests))
+	for i, req := range testLog.Requests {
+		if req.ID > 0 {
+			byID[req.ID] = i
+		}
+	}
+	for i := range testLog.Responses {
+		resp := &testLog.Responses[i]
+		if len(byID) == 0 {
+			if i < len(out) {
+				out[i] = resp
+			}
+			continue
+		}
+		if j, ok := byID[resp.RequestID]; ok {
+			out[j] = resp
+		}
+	}
+	return out
+}
+
+func harEntry(pageRef string, req RequestLog, resp *ResponseLog) HAREntry {
+	entry := HAREntry{
+		PageRef:         pageRef,
+		StartedDateTime: req.Timestamp,
+		Request:         harRequest(req),
+		Response: HARResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     []HARNameValue{},
+			Content:     HARContent{MimeType: "x-unknown", Comment: "no response logged"},
+			HeadersSize: harUnknown,
+			BodySize:    harUnknown,
+		},
+	}
+	if req.RetryAttempt > 0 {
+		entry.Comment = fmt.Sprintf("retry attempt %d", req.RetryAttempt)
+	}
+
+	timing := req.Timing
+	var duration time.Duration
+	if resp != nil {
+		entry.Response = harResponse(*resp)
+		duration = resp.Duration
+		if timing == nil {
+			timing = resp.Timing
+		}
+	}
+	entry.Timings = harTimings(timing, duration)
+	entry.Time = harTotal(entry.Timings)
+	return entry
+}
+
+func harRequest(req RequestLog) HARRequest {
+	out := HARRequest{
+		Method:      req.Method,
+		URL:         sanitizeURL(req.URL),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(req.Headers),
+		QueryString: []HARNameValue{},
+		HeadersSize: harUnknown,
+		BodySize:    0,
+	}
+	if u, err := url.Parse(out.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, value := range values {
+				out.QueryString = append(out.QueryString, HARNameValue{Name: name, Value: value})
+			}
+		}
+		sort.SliceStable(out.QueryString, func(a, b int) bool { return out.QueryString[a].Name < out.QueryString[b].Name })
+	}
+	if req.BodyFull != "" {
+		out.PostData = &HARPostData{MimeType: headerValue(req.Headers, "Content-Type", "application/json"), Text: req.BodyFull}
+		out.BodySize = len(req.BodyFull)
+	} else if req.BodyPreview != "" {
+		// Only a preview was captured, so the size is unknown.
+		out.BodySize = harUnknown
+	}
+	return out
+}
+
+func harResponse(resp ResponseLog) HARResponse {
+	out := HARResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(resp.Headers),
+		Content: HARContent{
+			Size:     resp.BodySize,
+			MimeType: headerValue(resp.Headers, "Content-Type", "x-unknown"),
+			Text:     resp.BodyFull,
+		},
+		RedirectURL: headerValue(resp.Headers, "Location", ""),
+		HeadersSize: harUnknown,
+		BodySize:    resp.BodySize,
+	}
+	if resp.BodyFull == "" && resp.BodySize > 0 {
+		out.Content.Comment = "body not captured; run with -debug-full"
+	}
+	return out
+}
+
+// harTimings maps an httptrace breakdown to HAR phases. Without a breakdown
+// the whole duration counts as wait.
+func harTimings(timing *TimingBreakdown, duration time.Duration) HARTimings {
+	t := HARTimings{Blocked: harUnknown, DNS: harUnknown, Connect: harUnknown, SSL: harUnknown}
+	if timing == nil {
+		t.Wait = durationMillis(duration)
+		return t
+	}
+	if timing.TotalDuration > 0 {
+		duration = timing.TotalDuration
+	}
+	if timing.DNSLookup > 0 {
+		t.DNS = durationMillis(timing.DNSLookup)
+	}
+	if timing.TCPConnection > 0 || timing.TLSHandshake > 0 {
+		t.Connect = durationMillis(timing.TCPConnection + timing.TLSHandshake)
+	}
+	if timing.TLSHandshake > 0 {
+		t.SSL = durationMillis(timing.TLSHandshake)
+	}
+	setup := max(t.DNS, 0) + max(t.Connect, 0)
+	if timing.TimeToFirstByte > 0 {
+		t.Wait = max(durationMillis(timing.TimeToFirstByte)-setup, 0)
+		t.Receive = max(durationMillis(duration)-durationMillis(timing.TimeToFirstByte), 0)
+	} else {
+		t.Wait = max(durationMillis(duration)-setup, 0)
+	}
+	return t
+}
+
+// harTotal sums the measured phases; SSL is already part of connect.
+func harTotal(t HARTimings) float64 {
+	total := 0.0
+	for _, phase := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if phase > 0 {
+			total += phase
+		}
+	}
+	return total
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func harHeaders(headers map[string]string) []HARNameValue {
+	sanitized := sanitizeHeaders(headers)
+	out := make([]HARNameValue, 0, len(sanitized))
+	for name, value := range sanitized {
+		out = append(out, HARNameValue{Name: name, Value: value})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+// sanitizeURL masks query parameters that may carry credentials.
+func sanitizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if isSensitiveName(name) {
+			query[name] = []string{"[REDACTED]"}
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func headerValue(headers map[string]string, name, fallback string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) && v != "" {
+			return v
+		}
+	}
+	return fallback
+}
+
+// WriteHAR writes provider logs as a HAR file.
+func WriteHAR(path string, providerLogs ...*ProviderLog) error {
+	data, err := json.MarshalIndent(BuildHAR(providerLogs...), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal HAR: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write HAR file: %w", err)
+	}
+	return nil
+}
+
+// ConvertDirToHAR writes a <provider>.har next to each <provider>.json in an
+// existing debug directory and returns the files written.
+func ConvertDirToHAR(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list debug logs: %w", err)
+	}
+	sort.Strings(matches)
+
+	var written []string
+	for _, path := range matches {
+		if filepath.Base(path) == "session.json" {
+			continue
+		}
+		// #nosec G304 - Path comes from listing the user-provided debug directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return written, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var providerLog ProviderLog
+		if err := json.Unmarshal(data, &providerLog); err != nil {
+			return written, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		harPath := strings.TrimSuffix(path, ".json") + ".har"
+		if err := WriteHAR(harPath, &providerLog); err != nil {
+			return written, err
+		}
+		written = append(written, harPath)
+	}
+	if len(written) == 0 {
+		return nil, fmt.Errorf("no provider debug logs found in %s", dir)
+	}
+	return written, nil
+}
@@ -0,0 +1,144 @@
+package debug
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildHAR_PairsRequestsAndMapsTimings(t *testing.T) {
+	logger := NewLogger(true, true, t.TempDir())
+	testLog := logger.StartTest("brave", "Search - Rust", "search")
+	logger.LogRequestWithTiming(testLog, "GET", "https://api.example.com/search?q=rust&api_key=abc", map[string]string{"X-Subscription-Token": "secret"}, "", nil, 0)
+	logger.LogResponse(testLog, 503, map[string]string{"Content-Type": "text/plain"}, "unavailable", 11, 40*time.Millisecond)
+	timing := &TimingBreakdown{
+		DNSLookup:       5 * time.Millisecond,
+		TCPConnection:   10 * time.Millisecond,
+		TLSHandshake:    20 * time.Millisecond,
+		TimeToFirstByte: 80 * time.Millisecond,
+	}
+	logger.LogRequestWithTiming(testLog, "POST", "https://api.example.com/search", map[string]string{"Content-Type": "application/json"}, `{"q":"rust"}`, timing, 1)
+	logger.LogResponseWithTiming(testLog, 200, map[string]string{"Content-Type": "application/json"}, `{"results":[]}`, 14, 100*time.Millisecond, timing)
+	logger.EndTest(testLog)
+
+	har := BuildHAR(logger.session.Providers["brave"])
+	if har.Log.Version != "1.2" || len(har.Log.Pages) != 1 || len(har.Log.Entries) != 2 {
+		t.Fatalf("unexpected HAR shape: version %s, %d pages, %d entries", har.Log.Version, len(har.Log.Pages), len(har.Log.Entries))
+	}
+
+	first := har.Log.Entries[0]
+	if first.PageRef != testLog.ID || first.Response.Status != 503 || first.Response.StatusText != "Service Unavailable" {
+		t.Errorf("unexpected first entry: pageref %s, status %d %q", first.PageRef, first.Response.Status, first.Response.StatusText)
+	}
+	if strings.Contains(first.Request.URL, "abc") {
+		t.Errorf("expected api_key to be redacted from URL, got %s", first.Request.URL)
+	}
+	for _, h := range first.Request.Headers {
+		if h.Name == "X-Subscription-Token" && h.Value != "[REDACTED]" {
+			t.Errorf("expected token header to be redacted, got %q", h.Value)
+		}
+	}
+	if first.Timings.Wait != 40 || first.Timings.DNS != -1 || first.Time != 40 {
+		t.Errorf("expected untimed entry to count its duration as wait, got %+v (time %v)", first.Timings, first.Time)
+	}
+
+	second := har.Log.Entries[1]
+	want := HARTimings{Blocked: -1, DNS: 5, Connect: 30, SSL: 20, Wait: 45, Receive: 20}
+	if second.Timings != want {
+		t.Errorf("timings = %+v, want %+v", second.Timings, want)
+	}
+	if second.Time != 100 {
+		t.Errorf("expected total time 100ms, got %v", second.Time)
+	}
+	if second.Request.PostData == nil || second.Request.PostData.Text != `{"q":"rust"}` {
+		t.Errorf("expected full request body, got %+v", second.Request.PostData)
+	}
+	if second.Response.Content.Text != `{"results":[]}` || second.Response.Content.MimeType != "application/json" {
+		t.Errorf("unexpected response content: %+v", second.Response.Content)
+	}
+	if second.Comment != "retry attempt 1" {
+		t.Errorf("expected retry comment, got %q", second.Comment)
+	}
+}
+
+func TestBuildHAR_OmitsBodiesWithoutFullCapture(t *testing.T) {
+	logger := NewLogger(true, false, t.TempDir())
+	testLog := logger.StartTest("tavily", "Extract", "extract")
+	logger.LogRequest(testLog, "POST", "https://api.example.com/extract", nil, `{"urls":["https://example.com"]}`)
+	logger.LogRequest(testLog, "POST", "https://api.example.com/extract", nil, `{"urls":["https://example.com"]}`)
+	logger.LogResponse(testLog, 200, nil, `{"results":[]}`, 14, 10*time.Millisecond)
+
+	// Older logs kept only the last response.
+	testLog.Responses = nil
+	har := BuildHAR(logger.session.Providers["tavily"])
+	if har.Log.Entries[0].Response.Status != 0 {
+		t.Errorf("expected first request without response, got status %d", har.Log.Entries[0].Response.Status)
+	}
+	last := har.Log.Entries[1]
+	if last.Response.Status != 200 || last.Request.PostData != nil || last.Response.Content.Text != "" {
+		t.Errorf("expected status 200 without bodies, got %+v", last)
+	}
+	if last.Response.Content.Size != 14 || last.Response.Content.Comment == "" {
+		t.Errorf("expected body size and capture note, got %+v", last.Response.Content)
+	}
+}
+
+func TestLoggerFinalize_HARFormatAndConvertDir(t *testing.T) {
+	outputDir := t.TempDir()
+	logger := NewLogger(true, false, outputDir)
+	if err := logger.SetFormat("yaml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if err := logger.SetFormat(FormatHAR); err != nil {
+		t.Fatalf("SetFormat failed: %v", err)
+	}
+	testLog := logger.StartTest("exa", "Search", "search")
+	logger.LogRequest(testLog, "POST", "https://api.exa.ai/search", nil, "")
+	logger.LogResponse(testLog, 200, nil, "{}", 2, 5*time.Millisecond)
+	logger.EndTest(testLog)
+	if err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	debugDir := logger.GetOutputPath()
+	if _, err := os.Stat(filepath.Join(debugDir, "exa.har")); err != nil {
+		t.Fatalf("expected exa.har: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(debugDir, "exa.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected no exa.json in har format, got %v", err)
+	}
+
+	// Convert a JSON-format debug directory.
+	jsonLogger := NewLogger(true, false, t.TempDir())
+	testLog = jsonLogger.StartTest("exa", "Search", "search")
+	jsonLogger.LogRequest(testLog, "POST", "https://api.exa.ai/search", nil, "")
+	jsonLogger.LogResponse(testLog, 200, nil, "{}", 2, 5*time.Millisecond)
+	if err := jsonLogger.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+	written, err := ConvertDirToHAR(jsonLogger.GetOutputPath())
+	if err != nil {
+		t.Fatalf("ConvertDirToHAR failed: %v", err)
+	}
+	if len(written) != 1 || filepath.Base(written[0]) != "exa.har" {
+		t.Fatalf("expected exa.har to be written, got %v", written)
+	}
+	data, err := os.ReadFile(written[0])
+	if err != nil {
+		t.Fatalf("failed reading HAR: %v", err)
+	}
+	var har HAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("failed parsing HAR: %v", err)
+	}
+	if len(har.Log.Entries) != 1 || har.Log.Entries[0].Response.Status != 200 {
+		t.Errorf("unexpected converted HAR: %+v", har.Log.Entries)
+	}
+
+	if _, err := ConvertDirToHAR(t.TempDir()); err == nil {
+		t.Error("expected error for a directory without debug logs")
+	}
+}
@@ -14,9 +14,10 @@ import (
 	"time"
 )
 
-const debugSchemaVersion = 2
+const debugSchemaVersion = 4
 
-// TimingBreakdown captures detailed HTTP timing using httptrace
+// TimingBreakdown captures detailed HTTP timing using httptrace.
+// TimeToFirstByte and TotalDuration are measured from the attempt's start.
 type TimingBreakdown struct {
 	DNSLookup       time.Duration `json:"dns_lookup"`
 	TCPConnection   time.Duration `json:"tcp_connection"`
@@ -30,6 +31,7 @@ type Logger struct {
 	mu          sync.RWMutex
 	enabled     bool
 	fullCapture bool
+	format      string
 	startTime   time.Time
 	session     *Session
 	outputDir   string
@@ -63,16 +65,15 @@ type TestLog struct {
 	EndTime   *time.Time             `json:"end_time,omitempty"`
 	Duration  time.Duration          `json:"duration"`
 	Requests  []RequestLog           `json:"requests"`
-	Response  *ResponseLog           `json:"response,omitempty"`
+	Response  *ResponseLog           `json:"response,omitempty"`  // last response
+	Responses []ResponseLog          `json:"responses,omitempty"` // every response, paired with Requests by request_id
 	Errors    []ErrorLog             `json:"errors"`
 	Metadata  map[string]interface{} `json:"metadata"`
-
-	// internal metadata storage for non-serializable objects (not exported to JSON)
-	internalMeta map[string]interface{}
 }
 
 // RequestLog captures HTTP request details
 type RequestLog struct {
+	ID           int               `json:"id,omitempty"` // 1-based position within the test
 	Timestamp    time.Time         `json:"timestamp"`
 	Method       string            `json:"method"`
 	URL          string            `json:"url"`
@@ -85,6 +86,7 @@ type RequestLog struct {
 
 // ResponseLog captures HTTP response details
 type ResponseLog struct {
+	RequestID   int               `json:"request_id,omitempty"` // ID of the request it answers, 0 if none was logged
 	Timestamp   time.Time         `json:"timestamp"`
 	StatusCode  int               `json:"status_code"`
 	Headers     map[string]string `json:"headers,omitempty"`
@@ -111,6 +113,7 @@ func NewLogger(enabled bool, fullCapture bool, outputDir string) *Logger {
 	logger := &Logger{
 		enabled:     enabled,
 		fullCapture: fullCapture,
+		format:      FormatJSON,
 		startTime:   time.Now(),
 		outputDir:   outputDir,
 		session: &Session{
@@ -136,81 +139,111 @@ func (l *Logger) IsEnabled() bool {
 	return l.enabled
 }
 
+// SetFormat selects the files Finalize writes: FormatJSON for the
+// per-provider JSON logs, FormatHAR for per-provider HAR 1.2 archives, or
+// FormatAll for both. session.json is always written.
+func (l *Logger) SetFormat(format string) error {
+	parsed, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.format = parsed
+	return nil
+}
+
 // IsFullCapture returns whether full body capture is enabled
 func (l *Logger) IsFullCapture() bool {
 	return l.fullCapture
 }
 
-// NewTraceContext creates an httptrace.ClientTrace that populates timing data
-// Returns a context with the trace attached and a *TimingBreakdown to be filled
-func (l *Logger) NewTraceContext(testLog *TestLog) (*TimingBreakdown, func()) {
-	if !l.enabled || testLog == nil {
-		return nil, func() {}
+// TraceTiming returns an httptrace.ClientTrace that measures one HTTP
+// attempt from now, and a function that returns the breakdown once the
+// response has been read. Phases the attempt skipped, such as DNS for an IP
+// address or connect on a reused connection, stay zero.
+func TraceTiming() (*httptrace.ClientTrace, func() *TimingBreakdown) {
+	start := time.Now()
+	var mu sync.Mutex
+	var timing TimingBreakdown
+	var dnsStart, connectStart, tlsStart time.Time
+	since := func(t time.Time) time.Duration {
+		if t.IsZero() {
+			return 0
+		}
+		return time.Since(t)
 	}
 
-	timing := &TimingBreakdown{}
-	var dnsStart, tcpStart, tlsStart, firstByteTime time.Time
-
 	trace := &httptrace.ClientTrace{
 		DNSStart: func(_ httptrace.DNSStartInfo) {
+			mu.Lock()
+			defer mu.Unlock()
 			dnsStart = time.Now()
 		},
 		DNSDone: func(_ httptrace.DNSDoneInfo) {
-			timing.DNSLookup = time.Since(dnsStart)
+			mu.Lock()
+			defer mu.Unlock()
+			timing.DNSLookup = since(dnsStart)
 		},
 		ConnectStart: func(_, _ string) {
-			tcpStart = time.Now()
+			mu.Lock()
+			defer mu.Unlock()
+			if connectStart.IsZero() {
+				connectStart = time.Now()
+			}
 		},
-		ConnectDone: func(_, _ string, _ error) {
-			timing.TCPConnection = time.Since(tcpStart)
+		ConnectDone: func(_, _ string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			// With several dials in flight, the first that connects counts.
+			if err == nil && timing.TCPConnection == 0 {
+				timing.TCPConnection = since(connectStart)
+			}
 		},
 		TLSHandshakeStart: func() {
+			mu.Lock()
+			defer mu.Unlock()
 			tlsStart = time.Now()
 		},
 		TLSHandshakeDone: func(_ tls.ConnectionState, _ error) {
-			timing.TLSHandshake = time.Since(tlsStart)
+			mu.Lock()
+			defer mu.Unlock()
+			timing.TLSHandshake = since(tlsStart)
 		},
 		GotFirstResponseByte: func() {
-			firstByteTime = time.Now()
+			mu.Lock()
+			defer mu.Unlock()
+			timing.TimeToFirstByte = time.Since(start)
 		},
 	}
 
-	// Return timing struct and a function to finalize total duration
-	finalize := func() {
-		if !firstByteTime.IsZero() {
-			timing.TimeToFirstByte = firstByteTime.Sub(dnsStart)
-		}
-		// TotalDuration should be set by the caller after request completes
-	}
-
-	// Store trace in internal metadata (not serialized to JSON)
-	l.mu.Lock()
-	if testLog.internalMeta == nil {
-		testLog.internalMeta = make(map[string]interface{})
+	done := func() *TimingBreakdown {
+		mu.Lock()
+		defer mu.Unlock()
+		out := timing
+		out.TotalDuration = time.Since(start)
+		return &out
 	}
-	testLog.internalMeta["_trace"] = trace
-	testLog.internalMeta["_timing"] = timing
-	l.mu.Unlock()
-
-	return timing, finalize
+	return trace, done
 }
 
-// GetTraceFromTest retrieves the httptrace.ClientTrace associated with a test log
-func (l *Logger) GetTraceFromTest(testLog *TestLog) *httptrace.ClientTrace {
-	if !l.enabled || testLog == nil {
-		return nil
+// RecordTiming attaches the timing of an HTTP attempt to the test's latest
+// logged request. With retries the last attempt wins, and retryAttempt
+// records which one it was.
+func (l *Logger) RecordTiming(testLog *TestLog, timing *TimingBreakdown, retryAttempt int) {
+	if !l.enabled || testLog == nil || timing == nil {
+		return
 	}
 
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	if testLog.internalMeta == nil {
-		return nil
-	}
-	if trace, ok := testLog.internalMeta["_trace"].(*httptrace.ClientTrace); ok {
-		return trace
+	if len(testLog.Requests) == 0 {
+		return
 	}
-	return nil
+	req := &testLog.Requests[len(testLog.Requests)-1]
+	req.Timing = timing
+	req.RetryAttempt = retryAttempt
 }
 
 // LogProviderInit logs provider initialization
@@ -282,6 +315,7 @@ func (l *Logger) LogRequest(testLog *TestLog, method, url string, headers map[st
 	defer l.mu.Unlock()
 
 	reqLog := RequestLog{
+		ID:          len(testLog.Requests) + 1,
 		Timestamp:   time.Now(),
 		Method:      method,
 		URL:         url,
@@ -306,6 +340,7 @@ func (l *Logger) LogRequestWithTiming(testLog *TestLog, method, url string, head
 	defer l.mu.Unlock()
 
 	reqLog := RequestLog{
+		ID:           len(testLog.Requests) + 1,
 		Timestamp:    time.Now(),
 		Method:       method,
 		URL:          url,
@@ -332,6 +367,7 @@ func (l *Logger) LogResponse(testLog *TestLog, statusCode int, headers map[strin
 	defer l.mu.Unlock()
 
 	testLog.Response = &ResponseLog{
+		RequestID:   lastRequestID(testLog),
 		Timestamp:   time.Now(),
 		StatusCode:  statusCode,
 		Headers:     sanitizeHeaders(headers),
@@ -343,6 +379,7 @@ func (l *Logger) LogResponse(testLog *TestLog, statusCode int, headers map[strin
 	if l.fullCapture {
 		testLog.Response.BodyFull = bodyPreview
 	}
+	testLog.Responses = append(testLog.Responses, *testLog.Response)
 }
 
 // LogResponseWithTiming logs an HTTP response with detailed timing
@@ -355,6 +392,7 @@ func (l *Logger) LogResponseWithTiming(testLog *TestLog, statusCode int, headers
 	defer l.mu.Unlock()
 
 	testLog.Response = &ResponseLog{
+		RequestID:   lastRequestID(testLog),
 		Timestamp:   time.Now(),
 		StatusCode:  statusCode,
 		Headers:     sanitizeHeaders(headers),
@@ -372,6 +410,16 @@ func (l *Logger) LogResponseWithTiming(testLog *TestLog, statusCode int, headers
 	if timing != nil {
 		timing.TotalDuration = duration
 	}
+	testLog.Responses = append(testLog.Responses, *testLog.Response)
+}
+
+// lastRequestID returns the ID of the test's latest logged request, which is
+// the one a response logged now answers.
+func lastRequestID(testLog *TestLog) int {
+	if len(testLog.Requests) == 0 {
+		return 0
+	}
+	return testLog.Requests[len(testLog.Requests)-1].ID
 }
 
 // LogError logs an error with context
@@ -457,6 +505,7 @@ func (l *Logger) Finalize() error {
 		"schema_version": debugSchemaVersion,
 		"start_time":     l.session.StartTime,
 		"end_time":       l.session.EndTime,
+		"format":         l.format,
 		"system_info":    l.session.SystemInfo,
 		"providers":      make([]string, 0, len(l.session.Providers)),
 	}
@@ -480,6 +529,14 @@ func (l *Logger) Finalize() error {
 	for _, providerName := range providerNames {
 		providerLog := l.session.Providers[providerName]
 		providerLog.SchemaVersion = debugSchemaVersion
+		if l.format == FormatHAR || l.format == FormatAll {
+			if err := WriteHAR(filepath.Join(debugDir, providerName+".har"), providerLog); err != nil {
+				return fmt.Errorf("failed to write HAR for %s: %w", providerName, err)
+			}
+		}
+		if l.format == FormatHAR {
+			continue
+		}
 		providerPath := filepath.Join(debugDir, providerName+".json")
 		data, err := json.MarshalIndent(providerLog, "", "  ")
 		if err != nil {
@@ -533,17 +590,7 @@ func sanitizeHeaders(headers map[string]string) map[string]string {
 
 	sanitized := make(map[string]string, len(headers))
 	for k, v := range headers {
-		lowerKey := strings.ToLower(k)
-		// Check for sensitive header names
-		if lowerKey == "authorization" ||
-			lowerKey == "x-subscription-token" ||
-			lowerKey == "x-api-token" ||
-			strings.Contains(lowerKey, "api-key") ||
-			strings.Contains(lowerKey, "api_key") ||
-			strings.Contains(lowerKey, "secret") ||
-			strings.Contains(lowerKey, "token") ||
-			strings.Contains(lowerKey, "password") ||
-			strings.Contains(lowerKey, "key") {
+		if isSensitiveName(k) {
 			sanitized[k] = "[REDACTED]"
 		} else {
 			sanitized[k] = v
@@ -551,3 +598,18 @@ func sanitizeHeaders(headers map[string]string) map[string]string {
 	}
 	return sanitized
 }
+
+// isSensitiveName reports whether a header or query parameter name may carry
+// credentials.
+func isSensitiveName(name string) bool {
+	lowerKey := strings.ToLower(name)
+	return lowerKey == "authorization" ||
+		lowerKey == "x-subscription-token" ||
+		lowerKey == "x-api-token" ||
+		strings.Contains(lowerKey, "api-key") ||
+		strings.Contains(lowerKey, "api_key") ||
+		strings.Contains(lowerKey, "secret") ||
+		strings.Contains(lowerKey, "token") ||
+		strings.Contains(lowerKey, "password") ||
+		strings.Contains(lowerKey, "key")
+}
@@ -0,0 +1,183 @@
+// Package document extracts text from non-HTML documents (PDF, DOCX and plain
+// text) using only the standard library, so the local provider can benchmark
+// document extraction without external services.
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format identifies a document format.
+type Format string
+
+const (
+	// FormatHTML is a regular web page.
+	FormatHTML Format = "html"
+	// FormatPDF is a Portable Document Format file.
+	FormatPDF Format = "pdf"
+	// FormatDOCX is an Office Open XML word processing document.
+	FormatDOCX Format = "docx"
+	// FormatText is plain text, Markdown or CSV.
+	FormatText Format = "text"
+)
+
+// ParseFormat normalizes a configured document type. It returns "" for unknown values.
+func ParseFormat(s string) Format {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "html", "htm":
+		return FormatHTML
+	case "pdf":
+		return FormatPDF
+	case "docx":
+		return FormatDOCX
+	case "text", "txt", "plain", "markdown", "md", "csv":
+		return FormatText
+	default:
+		return ""
+	}
+}
+
+// Document is the text extracted from a document.
+type Document struct {
+	Format Format
+	Title  string
+	Text   string
+	Pages  int // 0 when the format has no page concept or it is unknown
+	Tables int // Markdown tables in Text
+}
+
+// FormatFromURL infers a document format from the URL path extension.
+// It returns "" when the extension is missing or not a known document type.
+func FormatFromURL(rawURL string) Format {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".pdf":
+		return FormatPDF
+	case ".docx":
+		return FormatDOCX
+	case ".txt", ".md", ".markdown", ".csv":
+		return FormatText
+	case ".html", ".htm":
+		return FormatHTML
+	default:
+		return ""
+	}
+}
+
+// DetectFormat identifies a response body from its Content-Type header,
+// falling back to content sniffing. It returns "" for unsupported binaries.
+func DetectFormat(contentType string, body []byte) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	switch {
+	case mediaType == "application/pdf" || bytes.HasPrefix(body, []byte("%PDF-")):
+		return FormatPDF
+	case mediaType == "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return FormatDOCX
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return FormatHTML
+	case strings.HasPrefix(mediaType, "text/"):
+		return FormatText
+	case bytes.HasPrefix(body, []byte("PK\x03\x04")) && isDOCX(body):
+		return FormatDOCX
+	}
+
+	sniffed := http.DetectContentType(body)
+	switch {
+	case strings.HasPrefix(sniffed, "text/html"):
+		return FormatHTML
+	case strings.HasPrefix(sniffed, "text/plain"):
+		return FormatText
+	default:
+		return ""
+	}
+}
+
+// Extract parses body as the given format.
+func Extract(body []byte, format Format) (*Document, error) {
+	var (
+		doc *Document
+		err error
+	)
+	switch format {
+	case FormatPDF:
+		doc, err = extractPDF(body)
+	case FormatDOCX:
+		doc, err = extractDOCX(body)
+	case FormatText:
+		doc, err = extractText(body)
+	default:
+		return nil, fmt.Errorf("unsupported document format: %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.Format = format
+	doc.Tables = CountMarkdownTables(doc.Text)
+	return doc, nil
+}
+
+func extractText(body []byte) (*Document, error) {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(body) {
+		return nil, fmt.Errorf("text document is not valid UTF-8")
+	}
+	text := strings.ReplaceAll(string(body), "\r\n", "\n")
+	return &Document{Text: strings.TrimSpace(text)}, nil
+}
+
+// CountMarkdownTables counts pipe tables: a row followed by a |---| separator row.
+func CountMarkdownTables(text string) int {
+	lines := strings.Split(text, "\n")
+	count := 0
+	for i := 1; i < len(lines); i++ {
+		if isTableSeparator(lines[i]) && strings.Contains(lines[i-1], "|") {
+			count++
+		}
+	}
+	return count
+}
+
+func isTableSeparator(line string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.Contains(line, "|") || !strings.Contains(line, "-") {
+		return false
+	}
+	return strings.Trim(line, "|-: ") == ""
+}
+
+// normalizeLines trims each line, collapses runs of spaces and limits blank lines to one.
+func normalizeLines(text string) string {
+	var sb strings.Builder
+	blank := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank++
+			continue
+		}
+		if sb.Len() > 0 {
+			if blank > 0 {
+				sb.WriteString("\n\n")
+			} else {
+				sb.WriteString("\n")
+			}
+		}
+		blank = 0
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
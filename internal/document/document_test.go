@@ -0,0 +1,132 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func buildDOCX(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Pricing</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Plans are billed </w:t></w:r><w:r><w:t>monthly.</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Plan</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Price</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>Pro</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>$20</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:t>Contact sales for more.</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func TestExtractDOCX(t *testing.T) {
+	body := buildDOCX(t, map[string]string{
+		"word/document.xml": testDocumentXML,
+		"docProps/app.xml":  `<Properties><Pages>3</Pages></Properties>`,
+		"docProps/core.xml": `<cp:coreProperties xmlns:cp="x" xmlns:dc="y"><dc:title>Price List</dc:title></cp:coreProperties>`,
+	})
+
+	doc, err := Extract(body, FormatDOCX)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	want := "# Pricing\n\nPlans are billed monthly.\n\n| Plan | Price |\n|---|---|\n| Pro | $20 |\n\nContact sales for more."
+	if doc.Text != want {
+		t.Errorf("Text = %q, want %q", doc.Text, want)
+	}
+	if doc.Pages != 3 || doc.Title != "Price List" || doc.Tables != 1 {
+		t.Errorf("got pages=%d title=%q tables=%d", doc.Pages, doc.Title, doc.Tables)
+	}
+}
+
+func TestExtractDOCX_MissingBody(t *testing.T) {
+	body := buildDOCX(t, map[string]string{"other.xml": "<x/>"})
+	if _, err := Extract(body, FormatDOCX); err == nil {
+		t.Error("expected error for archive without word/document.xml")
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	doc, err := Extract([]byte("\xef\xbb\xbfline one\r\nline two\n"), FormatText)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "line one\nline two" || doc.Format != FormatText {
+		t.Errorf("got %+v", doc)
+	}
+	if _, err := Extract([]byte{0xff, 0xfe, 0x00}, FormatText); err == nil {
+		t.Error("expected error for invalid UTF-8")
+	}
+	if _, err := Extract([]byte("<p>x</p>"), FormatHTML); err == nil {
+		t.Error("expected error for HTML, which is not handled by this package")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	docx := buildDOCX(t, map[string]string{"word/document.xml": testDocumentXML})
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        Format
+	}{
+		{"pdf header", "application/pdf", []byte("%PDF-1.7"), FormatPDF},
+		{"pdf sniffed", "application/octet-stream", []byte("%PDF-1.7"), FormatPDF},
+		{"docx header", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", docx, FormatDOCX},
+		{"docx sniffed", "application/octet-stream", docx, FormatDOCX},
+		{"html", "text/html; charset=utf-8", []byte("<html></html>"), FormatHTML},
+		{"plain", "text/plain; charset=utf-8", []byte("hello"), FormatText},
+		{"markdown", "text/markdown", []byte("# hi"), FormatText},
+		{"no header text", "", []byte("just words"), FormatText},
+		{"no header html", "", []byte("<!DOCTYPE html><html></html>"), FormatHTML},
+		{"binary", "application/octet-stream", []byte{0x00, 0x01, 0x02, 0x89}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.contentType, tt.body); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromURLAndParseFormat(t *testing.T) {
+	if got := FormatFromURL("https://example.com/files/Report.PDF?x=1"); got != FormatPDF {
+		t.Errorf("FormatFromURL(pdf) = %q", got)
+	}
+	if got := FormatFromURL("https://example.com/docs/"); got != "" {
+		t.Errorf("FormatFromURL(no ext) = %q", got)
+	}
+	if got := ParseFormat(" TXT "); got != FormatText {
+		t.Errorf("ParseFormat(TXT) = %q", got)
+	}
+	if got := ParseFormat("xlsx"); got != "" {
+		t.Errorf("ParseFormat(xlsx) = %q", got)
+	}
+}
+
+func TestCountMarkdownTables(t *testing.T) {
+	text := "| a | b |\n|---|:--:|\n| 1 | 2 |\n\ntext | more\n\n| c |\n| --- |\n"
+	if got := CountMarkdownTables(text); got != 2 {
+		t.Errorf("CountMarkdownTables() = %d, want 2", got)
+	}
+}
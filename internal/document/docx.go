@@ -0,0 +1,238 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxDOCXPartSize bounds decompressed XML parts to guard against zip bombs.
+const maxDOCXPartSize = 64 << 20
+
+func isDOCX(body []byte) bool {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// extractDOCX converts word/document.xml to Markdown-like text: headings get
+// # prefixes and tables become pipe tables. Page count and title come from
+// docProps when present.
+func extractDOCX(body []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open docx archive: %w", err)
+	}
+
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+	main, ok := parts["word/document.xml"]
+	if !ok {
+		return nil, fmt.Errorf("docx archive has no word/document.xml")
+	}
+
+	data, err := readZipPart(main)
+	if err != nil {
+		return nil, err
+	}
+	text, err := docxBodyText(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse docx body: %w", err)
+	}
+
+	doc := &Document{Text: text}
+	if f, ok := parts["docProps/app.xml"]; ok {
+		if data, err := readZipPart(f); err == nil {
+			doc.Pages, _ = strconv.Atoi(xmlElementText(data, "Pages"))
+		}
+	}
+	if f, ok := parts["docProps/core.xml"]; ok {
+		if data, err := readZipPart(f); err == nil {
+			doc.Title = xmlElementText(data, "title")
+		}
+	}
+	return doc, nil
+}
+
+func readZipPart(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+	data, err := io.ReadAll(io.LimitReader(rc, maxDOCXPartSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return data, nil
+}
+
+// xmlElementText returns the text of the first element with the given local name.
+func xmlElementText(data []byte, local string) string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == local {
+			var s string
+			if err := dec.DecodeElement(&s, &start); err != nil {
+				return ""
+			}
+			return strings.TrimSpace(s)
+		}
+	}
+}
+
+// docxWriter accumulates paragraphs and tables while walking document.xml.
+type docxWriter struct {
+	out        strings.Builder
+	para       strings.Builder
+	heading    int
+	inText     bool
+	tableDepth int
+	rows       [][]string
+	cell       []string
+}
+
+func docxBodyText(data []byte) (string, error) {
+	w := &docxWriter{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			w.start(t)
+		case xml.EndElement:
+			w.end(t.Name.Local)
+		case xml.CharData:
+			if w.inText {
+				w.para.Write(t)
+			}
+		}
+	}
+	return normalizeLines(w.out.String()), nil
+}
+
+func (w *docxWriter) start(t xml.StartElement) {
+	switch t.Name.Local {
+	case "t":
+		w.inText = true
+	case "tab":
+		w.para.WriteString("\t")
+	case "br", "cr":
+		w.para.WriteString(" ")
+	case "pStyle":
+		w.heading = headingLevel(xmlAttr(t, "val"))
+	case "tbl":
+		w.tableDepth++
+		if w.tableDepth == 1 {
+			w.rows = nil
+		}
+	case "tr":
+		if w.tableDepth == 1 {
+			w.rows = append(w.rows, nil)
+		}
+	case "tc":
+		if w.tableDepth == 1 {
+			w.cell = nil
+		}
+	}
+}
+
+func (w *docxWriter) end(local string) {
+	switch local {
+	case "t":
+		w.inText = false
+	case "p":
+		w.endParagraph()
+	case "tc":
+		if w.tableDepth == 1 && len(w.rows) > 0 {
+			last := len(w.rows) - 1
+			w.rows[last] = append(w.rows[last], strings.Join(w.cell, " "))
+		}
+	case "tbl":
+		if w.tableDepth == 1 {
+			w.writeTable()
+		}
+		w.tableDepth--
+	}
+}
+
+func (w *docxWriter) endParagraph() {
+	text := strings.TrimSpace(w.para.String())
+	w.para.Reset()
+	heading := w.heading
+	w.heading = 0
+	if text == "" {
+		return
+	}
+	if w.tableDepth > 0 {
+		w.cell = append(w.cell, text)
+		return
+	}
+	if heading > 0 {
+		w.out.WriteString(strings.Repeat("#", heading) + " ")
+	}
+	w.out.WriteString(text)
+	w.out.WriteString("\n\n")
+}
+
+func (w *docxWriter) writeTable() {
+	if len(w.rows) == 0 {
+		return
+	}
+	for i, row := range w.rows {
+		w.out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			w.out.WriteString(strings.Repeat("|---", len(row)) + "|\n")
+		}
+	}
+	w.out.WriteString("\n")
+	w.rows = nil
+}
+
+func xmlAttr(t xml.StartElement, local string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// headingLevel maps Word paragraph styles (Title, Heading1..Heading6) to Markdown levels.
+func headingLevel(style string) int {
+	lower := strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	if lower == "title" {
+		return 1
+	}
+	if rest, ok := strings.CutPrefix(lower, "heading"); ok {
+		if n, err := strconv.Atoi(rest); err == nil && n >= 1 && n <= 6 {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,349 @@
+package document
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPDFStreamSize bounds decompressed streams to guard against deflate bombs.
+const maxPDFStreamSize = 64 << 20
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// pdfObject is an indirect object: its value and, for streams, the decoded data.
+type pdfObject struct {
+	value  pdfValue
+	stream []byte
+}
+
+type pdfFile struct {
+	objects map[int]*pdfObject
+}
+
+// extractPDF pulls text from every page's content streams. Objects are found
+// by scanning for "N G obj" headers rather than reading the xref table, which
+// also tolerates slightly damaged files. Only Flate-compressed and
+// uncompressed streams are decoded; encrypted files are rejected.
+func extractPDF(body []byte) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(body, " \t\r\n"), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF document")
+	}
+	if bytes.Contains(body, []byte("/Encrypt")) {
+		return nil, fmt.Errorf("encrypted PDF documents are not supported")
+	}
+
+	objects, err := scanPDFObjects(body)
+	if err != nil {
+		return nil, err
+	}
+	f := &pdfFile{objects: objects}
+	f.expandObjectStreams()
+
+	pages := f.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found in PDF document")
+	}
+
+	texts := make([]string, 0, len(pages))
+	for _, page := range pages {
+		fonts := f.pageFonts(page.resources)
+		if text := normalizeLines(pdfContentText(f.pageContent(page.dict), fonts)); text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return &Document{
+		Title: f.infoTitle(body),
+		Text:  strings.Join(texts, "\n\n"),
+		Pages: len(pages),
+	}, nil
+}
+
+// scanPDFObjects finds every indirect object in file order. Later definitions
+// of the same object number (incremental updates) replace earlier ones.
+func scanPDFObjects(body []byte) (map[int]*pdfObject, error) {
+	objects := make(map[int]*pdfObject)
+	matches := pdfObjectHeader.FindAllSubmatchIndex(body, -1)
+	skipUntil := 0
+	for _, m := range matches {
+		if m[0] < skipUntil {
+			// Header-like bytes inside the previous object's stream data.
+			continue
+		}
+		num, err := strconv.Atoi(string(body[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		rest := body[m[1]:]
+		lex := &pdfLexer{data: rest}
+		value := lex.parseValue()
+
+		obj := &pdfObject{value: value}
+		lex.skipSpace()
+		if lex.pos > len(rest) {
+			return nil, fmt.Errorf("malformed PDF object %d at offset %d", num, m[0])
+		}
+		if dict, ok := value.(pdfDict); ok && bytes.HasPrefix(rest[lex.pos:], []byte("stream")) {
+			streamStart := lex.pos + len("stream")
+			var consumed int
+			obj.stream, consumed = readPDFStream(dict, rest[streamStart:])
+			skipUntil = m[1] + streamStart + consumed
+		}
+		objects[num] = obj
+	}
+	return objects, nil
+}
+
+// readPDFStream returns the decoded stream data and the number of bytes
+// consumed up to the endstream keyword.
+func readPDFStream(dict pdfDict, data []byte) ([]byte, int) {
+	// The keyword is followed by CRLF or LF before the data.
+	offset := 0
+	if bytes.HasPrefix(data, []byte("\r\n")) {
+		offset = 2
+	} else if len(data) > 0 && (data[0] == '\n' || data[0] == '\r') {
+		offset = 1
+	}
+	data = data[offset:]
+
+	var raw []byte
+	consumed := 0
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && length <= float64(len(data)) {
+		if bytes.HasPrefix(bytes.TrimLeft(data[int(length):], " \t\r\n"), []byte("endstream")) {
+			raw = data[:int(length)]
+			consumed = int(length)
+		}
+	}
+	if raw == nil {
+		end := bytes.Index(data, []byte("endstream"))
+		if end < 0 {
+			return nil, len(data) + offset
+		}
+		raw = bytes.TrimRight(data[:end], "\r\n")
+		consumed = end
+	}
+	return decodePDFStream(dict, raw), consumed + offset
+}
+
+// decodePDFStream applies the stream filters. Unsupported filters yield nil.
+func decodePDFStream(dict pdfDict, raw []byte) []byte {
+	var filters []string
+	switch f := dict["Filter"].(type) {
+	case pdfName:
+		filters = []string{string(f)}
+	case pdfArray:
+		for _, v := range f {
+			if name, ok := v.(pdfName); ok {
+				filters = append(filters, string(name))
+			}
+		}
+	}
+
+	data := raw
+	for _, filter := range filters {
+		if filter != "FlateDecode" {
+			return nil
+		}
+		decoded, err := inflate(data)
+		if err != nil {
+			return nil
+		}
+		data = decoded
+	}
+	return data
+}
+
+func inflate(data []byte) ([]byte, error) {
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		out, err := io.ReadAll(io.LimitReader(zr, maxPDFStreamSize))
+		if err == nil || len(out) > 0 {
+			return out, nil
+		}
+	}
+	// Some producers write raw deflate data without the zlib header.
+	out, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(data)), maxPDFStreamSize))
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+// expandObjectStreams adds objects stored in /Type /ObjStm streams (PDF 1.5+).
+func (f *pdfFile) expandObjectStreams() {
+	for _, obj := range f.objects {
+		dict, ok := obj.value.(pdfDict)
+		if !ok || dict["Type"] != pdfName("ObjStm") || obj.stream == nil {
+			continue
+		}
+		n, _ := dict["N"].(float64)
+		first, _ := dict["First"].(float64)
+		if first < 0 || first > float64(len(obj.stream)) || n < 0 {
+			continue
+		}
+
+		header := &pdfLexer{data: obj.stream[:int(first)]}
+		type entry struct{ num, offset int }
+		var entries []entry
+		for i := 0; float64(i) < n; i++ {
+			num, ok1 := header.parseValue().(float64)
+			offset, ok2 := header.parseValue().(float64)
+			if !ok1 || !ok2 || offset < 0 || offset > float64(len(obj.stream)) {
+				break
+			}
+			entries = append(entries, entry{int(num), int(offset)})
+		}
+
+		body := obj.stream[int(first):]
+		for _, e := range entries {
+			if _, exists := f.objects[e.num]; exists || e.offset > len(body) {
+				continue
+			}
+			lex := &pdfLexer{data: body[e.offset:]}
+			f.objects[e.num] = &pdfObject{value: lex.parseValue()}
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (f *pdfFile) resolve(v pdfValue) pdfValue {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj, ok := f.objects[int(ref)]
+		if !ok {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(v pdfValue) pdfDict {
+	d, _ := f.resolve(v).(pdfDict)
+	return d
+}
+
+func (f *pdfFile) streamOf(v pdfValue) []byte {
+	if ref, ok := v.(pdfRef); ok {
+		if obj, ok := f.objects[int(ref)]; ok {
+			return obj.stream
+		}
+	}
+	return nil
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree from the catalog, inheriting resources. When no
+// catalog is found it falls back to every /Type /Page object in number order.
+func (f *pdfFile) pages() []pdfPage {
+	for _, obj := range f.objects {
+		if d, ok := obj.value.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+			var out []pdfPage
+			f.walkPages(d["Pages"], nil, &out, 0)
+			if len(out) > 0 {
+				return out
+			}
+		}
+	}
+
+	nums := make([]int, 0, len(f.objects))
+	for num, obj := range f.objects {
+		if d, ok := obj.value.(pdfDict); ok && d["Type"] == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	out := make([]pdfPage, 0, len(nums))
+	for _, num := range nums {
+		d := f.objects[num].value.(pdfDict)
+		out = append(out, pdfPage{dict: d, resources: f.dict(d["Resources"])})
+	}
+	return out
+}
+
+func (f *pdfFile) walkPages(node pdfValue, inherited pdfDict, out *[]pdfPage, depth int) {
+	d := f.dict(node)
+	if d == nil || depth > 64 {
+		return
+	}
+	resources := inherited
+	if r := f.dict(d["Resources"]); r != nil {
+		resources = r
+	}
+	if d["Type"] == pdfName("Page") {
+		*out = append(*out, pdfPage{dict: d, resources: resources})
+		return
+	}
+	kids, _ := f.resolve(d["Kids"]).(pdfArray)
+	for _, kid := range kids {
+		f.walkPages(kid, resources, out, depth+1)
+	}
+}
+
+func (f *pdfFile) pageContent(page pdfDict) []byte {
+	var parts [][]byte
+	switch c := page["Contents"].(type) {
+	case pdfRef:
+		if arr, ok := f.resolve(c).(pdfArray); ok {
+			for _, v := range arr {
+				parts = append(parts, f.streamOf(v))
+			}
+		} else {
+			parts = append(parts, f.streamOf(c))
+		}
+	case pdfArray:
+		for _, v := range c {
+			parts = append(parts, f.streamOf(v))
+		}
+	}
+	return bytes.Join(parts, []byte("\n"))
+}
+
+func (f *pdfFile) pageFonts(resources pdfDict) map[string]*pdfFont {
+	fonts := make(map[string]*pdfFont)
+	for name, ref := range f.dict(resources["Font"]) {
+		fonts[name] = f.loadFont(f.dict(ref))
+	}
+	return fonts
+}
+
+func (f *pdfFile) loadFont(d pdfDict) *pdfFont {
+	font := &pdfFont{codeLen: 1}
+	if d == nil {
+		return font
+	}
+	if d["Subtype"] == pdfName("Type0") {
+		font.codeLen = 2
+	}
+	if cmap := f.streamOf(d["ToUnicode"]); cmap != nil {
+		font.toUnicode, font.codeLen = parseToUnicode(cmap, font.codeLen)
+	}
+	return font
+}
+
+// infoTitle reads /Title from the document information dictionary.
+func (f *pdfFile) infoTitle(body []byte) string {
+	idx := bytes.LastIndex(body, []byte("/Info"))
+	if idx < 0 {
+		return ""
+	}
+	lex := &pdfLexer{data: body[idx+len("/Info"):]}
+	info := f.dict(lex.parseValue())
+	if title, ok := info["Title"].(pdfString); ok {
+		return strings.TrimSpace(decodePDFTextString(title))
+	}
+	return ""
+}
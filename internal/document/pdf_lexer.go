@@ -0,0 +1,295 @@
+package document
+
+import (
+	"strconv"
+)
+
+// PDF object model used by the lexer. Dictionary keys and names are stored
+// without the leading slash.
+type (
+	pdfValue   interface{}
+	pdfName    string
+	pdfString  []byte
+	pdfArray   []pdfValue
+	pdfDict    map[string]pdfValue
+	pdfRef     int
+	pdfKeyword string
+)
+
+// pdfLexer tokenizes PDF object syntax and content streams. pos never
+// passes the end of data, so data[pos:] is always valid.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (l *pdfLexer) eof() bool {
+	return l.pos >= len(l.data)
+}
+
+// skipSpace skips whitespace and comments.
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFWhitespace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// parseValue reads the next object or operator. It returns nil at end of input.
+func (l *pdfLexer) parseValue() pdfValue {
+	l.skipSpace()
+	if l.eof() {
+		return nil
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfName(l.readRegular())
+	case c == '(':
+		l.pos++
+		return l.readLiteralString()
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return l.readDict()
+	case c == '<':
+		l.pos++
+		return l.readHexString()
+	case c == '[':
+		l.pos++
+		return l.readArray()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.readNumberOrRef()
+	case isPDFDelimiter(c):
+		// Stray closing delimiter; consume it so callers always make progress.
+		l.pos++
+		return pdfKeyword(string(c))
+	}
+
+	word := l.readRegular()
+	switch word {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return pdfKeyword(word)
+}
+
+func (l *pdfLexer) readRegular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	raw := string(l.data[start:l.pos])
+	if l.pos == start && !l.eof() {
+		// Never return an empty token without advancing.
+		l.pos++
+	}
+	return unescapeName(raw)
+}
+
+// unescapeName decodes #xx escapes in names.
+func unescapeName(s string) string {
+	if len(s) < 3 {
+		return s
+	}
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && i+2 < len(s) {
+			if b, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				out = append(out, byte(b))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, s[i])
+	}
+	return string(out)
+}
+
+func (l *pdfLexer) readNumberOrRef() pdfValue {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) && (l.data[l.pos] == '.' || (l.data[l.pos] >= '0' && l.data[l.pos] <= '9')) {
+		l.pos++
+	}
+	n, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+	if err != nil {
+		return pdfKeyword(string(l.data[start:l.pos]))
+	}
+
+	// "num gen R" is an indirect reference.
+	save := l.pos
+	l.skipSpace()
+	genStart := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if l.pos > genStart {
+		l.skipSpace()
+		if l.pos < len(l.data) && l.data[l.pos] == 'R' &&
+			(l.pos+1 == len(l.data) || isPDFWhitespace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+			l.pos++
+			return pdfRef(int(n))
+		}
+	}
+	l.pos = save
+	return n
+}
+
+func (l *pdfLexer) readLiteralString() pdfString {
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+		case '\\':
+			out = l.readEscape(out)
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) readEscape(out []byte) []byte {
+	if l.eof() {
+		return out
+	}
+	c := l.data[l.pos]
+	l.pos++
+	switch c {
+	case 'n':
+		return append(out, '\n')
+	case 'r':
+		return append(out, '\r')
+	case 't':
+		return append(out, '\t')
+	case 'b':
+		return append(out, '\b')
+	case 'f':
+		return append(out, '\f')
+	case '\r':
+		// Line continuation.
+		if !l.eof() && l.data[l.pos] == '\n' {
+			l.pos++
+		}
+		return out
+	case '\n':
+		return out
+	}
+	if c >= '0' && c <= '7' {
+		v := int(c - '0')
+		for i := 0; i < 2 && !l.eof() && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+			v = v*8 + int(l.data[l.pos]-'0')
+			l.pos++
+		}
+		return append(out, byte(v))
+	}
+	return append(out, c)
+}
+
+func (l *pdfLexer) readHexString() pdfString {
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		c := l.data[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if !l.eof() {
+		l.pos++ // closing '>'
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		b, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(b)
+	}
+	return out
+}
+
+func (l *pdfLexer) readArray() pdfArray {
+	var arr pdfArray
+	for {
+		l.skipSpace()
+		if l.eof() {
+			return arr
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return arr
+		}
+		arr = append(arr, l.parseValue())
+	}
+}
+
+func (l *pdfLexer) readDict() pdfDict {
+	dict := make(pdfDict)
+	for {
+		l.skipSpace()
+		if l.eof() {
+			return dict
+		}
+		if l.data[l.pos] == '>' {
+			l.pos++
+			if !l.eof() && l.data[l.pos] == '>' {
+				l.pos++
+			}
+			return dict
+		}
+		key, ok := l.parseValue().(pdfName)
+		if !ok {
+			continue
+		}
+		dict[string(key)] = l.parseValue()
+	}
+}
+
+// skipInlineImage advances past inline image data up to the EI operator.
+func (l *pdfLexer) skipInlineImage() {
+	for l.pos+2 < len(l.data) {
+		if isPDFWhitespace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 == len(l.data) || isPDFWhitespace(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,163 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a minimal PDF with one page per content stream. Content
+// streams are Flate-compressed. When cidFont is true the page font is a Type0
+// font whose two-byte codes are mapped through a ToUnicode CMap.
+func buildPDF(t testing.TB, title string, pages []string, cidFont bool) []byte {
+	t.Helper()
+
+	var objects []string
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+	stream := func(dict string, data []byte, compress bool) string {
+		if compress {
+			var buf bytes.Buffer
+			zw := zlib.NewWriter(&buf)
+			if _, err := zw.Write(data); err != nil {
+				t.Fatalf("compress stream: %v", err)
+			}
+			if err := zw.Close(); err != nil {
+				t.Fatalf("compress stream: %v", err)
+			}
+			data = buf.Bytes()
+			dict += " /Filter /FlateDecode"
+		}
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+
+	catalog := add("") // placeholder, filled once the page tree exists
+	pagesObj := add("")
+
+	var font int
+	if cidFont {
+		cmap := "/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+			"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" +
+			"2 beginbfchar\n<0001> <0048>\n<0002> <0069>\nendbfchar\n" +
+			"1 beginbfrange\n<0010> <0012> <0061>\nendbfrange\n" +
+			"endcmap\nend\nend"
+		toUnicode := add(stream("", []byte(cmap), true))
+		font = add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /Test /Encoding /Identity-H /ToUnicode %d 0 R >>", toUnicode))
+	} else {
+		font = add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	}
+
+	var kids []string
+	for _, content := range pages {
+		contents := add(stream("", []byte(content), true))
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Contents %d 0 R >>", pagesObj, contents))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objects[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 %d 0 R >> >> >>",
+		strings.Join(kids, " "), len(kids), font)
+	info := add(fmt.Sprintf("<< /Title (%s) >>", title))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	for i, body := range objects {
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\n%%%%EOF\n", len(objects)+1, catalog, info)
+	return buf.Bytes()
+}
+
+func TestExtractPDF_TextAndPages(t *testing.T) {
+	body := buildPDF(t, "Quarterly Report", []string{
+		"BT /F1 12 Tf 72 720 Td (Quarterly Report) Tj 0 -14 Td (Revenue grew by 12%) Tj ET",
+		"BT /F1 12 Tf 72 720 Td [(Second) -250 (page)] TJ T* (caf\\351 \\(draft\\)) Tj ET",
+	}, false)
+
+	doc, err := Extract(body, FormatPDF)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Pages != 2 {
+		t.Errorf("Pages = %d, want 2", doc.Pages)
+	}
+	if doc.Title != "Quarterly Report" {
+		t.Errorf("Title = %q, want %q", doc.Title, "Quarterly Report")
+	}
+	want := "Quarterly Report\nRevenue grew by 12%\n\nSecond page\ncafé (draft)"
+	if doc.Text != want {
+		t.Errorf("Text = %q, want %q", doc.Text, want)
+	}
+}
+
+func TestExtractPDF_ToUnicodeCompositeFont(t *testing.T) {
+	body := buildPDF(t, "CID", []string{
+		"BT /F1 12 Tf 72 720 Td <00010002> Tj 0 -14 Td <001000110012> Tj ET",
+	}, true)
+
+	doc, err := Extract(body, FormatPDF)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Text != "Hi\nabc" {
+		t.Errorf("Text = %q, want %q", doc.Text, "Hi\nabc")
+	}
+}
+
+func TestExtractPDF_IgnoresHeadersInsideStreams(t *testing.T) {
+	// An uncompressed stream whose data looks like an object header must not
+	// be parsed as a separate object.
+	content := "BT /F1 12 Tf (Fake 3 0 obj header) Tj ET"
+	body := []byte("%PDF-1.4\n" +
+		"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n" +
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n" +
+		fmt.Sprintf("4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content) +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	doc, err := Extract(body, FormatPDF)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if doc.Pages != 1 || doc.Text != "Fake 3 0 obj header" {
+		t.Errorf("got pages=%d text=%q", doc.Pages, doc.Text)
+	}
+}
+
+func TestExtractPDF_Errors(t *testing.T) {
+	if _, err := Extract([]byte("not a pdf"), FormatPDF); err == nil {
+		t.Error("expected error for non-PDF input")
+	}
+	encrypted := []byte("%PDF-1.4\ntrailer\n<< /Encrypt 5 0 R >>\n")
+	if _, err := Extract(encrypted, FormatPDF); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("expected encrypted error, got %v", err)
+	}
+	if _, err := Extract([]byte("%PDF-1.4\n%%EOF\n"), FormatPDF); err == nil {
+		t.Error("expected error for PDF without pages")
+	}
+}
+
+func TestExtractPDF_MalformedObject(t *testing.T) {
+	if _, err := Extract([]byte("%PDF-0 0 obj<<<"), FormatPDF); err == nil {
+		t.Error("expected an error for a truncated object")
+	}
+}
+
+func FuzzExtractPDF(f *testing.F) {
+	f.Add([]byte("%PDF-0 0 obj<<<"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Type /ObjStm /N -1 /First -5 /Length 3 >>\nstream\nabc\nendstream\n"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj\n<< /Length 100000000000000000000000000000 >>\nstream\nabc\nendstream\n"))
+	valid := buildPDF(f, "Fuzz", []string{"BT /F1 12 Tf (Hello) Tj ET"}, true)
+	f.Add(valid)
+	f.Add(valid[:len(valid)/2])
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		doc, err := Extract(body, FormatPDF)
+		if err == nil && doc == nil {
+			t.Error("Extract returned neither a document nor an error")
+		}
+	})
+}
@@ -0,0 +1,281 @@
+package document
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// tjSpaceThreshold is the TJ displacement (thousandths of an em) treated as a word gap.
+const tjSpaceThreshold = -180
+
+// pdfFont decodes string operands shown with a font.
+type pdfFont struct {
+	codeLen   int
+	toUnicode map[uint32]string
+}
+
+var defaultPDFFont = &pdfFont{codeLen: 1}
+
+// cp1252High maps WinAnsiEncoding bytes 0x80-0x9F that differ from Latin-1.
+var cp1252High = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+func decodeCP1252(b byte) rune {
+	if r, ok := cp1252High[b]; ok {
+		return r
+	}
+	return rune(b)
+}
+
+func (f *pdfFont) decode(s pdfString) string {
+	var sb strings.Builder
+	if len(f.toUnicode) == 0 {
+		if f.codeLen != 1 {
+			// Composite font without a ToUnicode map: glyph IDs cannot be mapped to text.
+			return ""
+		}
+		for _, b := range s {
+			sb.WriteRune(decodeCP1252(b))
+		}
+		return sb.String()
+	}
+
+	for i := 0; i+f.codeLen <= len(s); i += f.codeLen {
+		var code uint32
+		for _, b := range s[i : i+f.codeLen] {
+			code = code<<8 | uint32(b)
+		}
+		if text, ok := f.toUnicode[code]; ok {
+			sb.WriteString(text)
+		} else if f.codeLen == 1 {
+			sb.WriteRune(decodeCP1252(byte(code)))
+		}
+	}
+	return sb.String()
+}
+
+// pdfTextWriter assembles shown text into lines.
+type pdfTextWriter struct {
+	sb    strings.Builder
+	last  byte
+	lastY float64
+	haveY bool
+}
+
+func (w *pdfTextWriter) newline() {
+	if w.sb.Len() > 0 && w.last != '\n' {
+		w.sb.WriteByte('\n')
+		w.last = '\n'
+	}
+}
+
+func (w *pdfTextWriter) space() {
+	if w.sb.Len() > 0 && w.last != ' ' && w.last != '\n' {
+		w.sb.WriteByte(' ')
+		w.last = ' '
+	}
+}
+
+func (w *pdfTextWriter) show(font *pdfFont, v pdfValue) {
+	switch s := v.(type) {
+	case pdfString:
+		if text := font.decode(s); text != "" {
+			w.sb.WriteString(text)
+			w.last = text[len(text)-1]
+		}
+	case pdfArray:
+		for _, item := range s {
+			if n, ok := item.(float64); ok {
+				if n < tjSpaceThreshold {
+					w.space()
+				}
+				continue
+			}
+			w.show(font, item)
+		}
+	}
+}
+
+// moveTo starts a new line when the vertical position changes and otherwise
+// separates the text runs with a space.
+func (w *pdfTextWriter) moveTo(y float64, relative bool) {
+	switch {
+	case relative && y != 0:
+		w.newline()
+	case !relative && w.haveY && y != w.lastY:
+		w.newline()
+	default:
+		w.space()
+	}
+	if !relative {
+		w.lastY = y
+		w.haveY = true
+	}
+}
+
+// pdfContentText interprets the text operators of a content stream.
+func pdfContentText(content []byte, fonts map[string]*pdfFont) string {
+	lex := &pdfLexer{data: content}
+	w := &pdfTextWriter{}
+	font := defaultPDFFont
+	var operands []pdfValue
+
+	operand := func(fromEnd int) pdfValue {
+		if len(operands) < fromEnd {
+			return nil
+		}
+		return operands[len(operands)-fromEnd]
+	}
+	number := func(fromEnd int) float64 {
+		n, _ := operand(fromEnd).(float64)
+		return n
+	}
+
+	for {
+		lex.skipSpace()
+		if lex.eof() {
+			break
+		}
+		v := lex.parseValue()
+		op, isOp := v.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, v)
+			continue
+		}
+
+		switch op {
+		case "BI":
+			lex.skipInlineImage()
+		case "Tf":
+			font = defaultPDFFont
+			if name, ok := operand(2).(pdfName); ok && fonts[string(name)] != nil {
+				font = fonts[string(name)]
+			}
+		case "Td", "TD":
+			w.moveTo(number(1), true)
+		case "Tm":
+			w.moveTo(number(1), false)
+		case "T*", "ET":
+			w.newline()
+		case "Tj", "TJ":
+			w.show(font, operand(1))
+		case "'", "\"":
+			w.newline()
+			w.show(font, operand(1))
+		}
+		operands = operands[:0]
+	}
+	return w.sb.String()
+}
+
+// parseToUnicode reads bfchar and bfrange mappings from a ToUnicode CMap.
+// It returns the mapping and the code length in bytes.
+func parseToUnicode(cmap []byte, defaultLen int) (map[uint32]string, int) {
+	lex := &pdfLexer{data: cmap}
+	mapping := make(map[uint32]string)
+	codeLen := 0
+
+	for {
+		lex.skipSpace()
+		if lex.eof() {
+			break
+		}
+		op, ok := lex.parseValue().(pdfKeyword)
+		if !ok {
+			continue
+		}
+		switch op {
+		case "begincodespacerange":
+			if lo, ok := lex.parseValue().(pdfString); ok && len(lo) > 0 {
+				codeLen = len(lo)
+			}
+		case "beginbfchar":
+			for {
+				src, ok := lex.parseValue().(pdfString)
+				if !ok {
+					break
+				}
+				if dst, ok := lex.parseValue().(pdfString); ok {
+					mapping[codeValue(src)] = decodeUTF16BE(dst)
+				}
+				if codeLen == 0 {
+					codeLen = len(src)
+				}
+			}
+		case "beginbfrange":
+			for {
+				lo, ok := lex.parseValue().(pdfString)
+				if !ok {
+					break
+				}
+				hi, _ := lex.parseValue().(pdfString)
+				addBFRange(mapping, codeValue(lo), codeValue(hi), lex.parseValue())
+				if codeLen == 0 {
+					codeLen = len(lo)
+				}
+			}
+		}
+	}
+
+	if codeLen == 0 {
+		codeLen = defaultLen
+	}
+	return mapping, codeLen
+}
+
+func addBFRange(mapping map[uint32]string, lo, hi uint32, dst pdfValue) {
+	if hi < lo || hi-lo > 0xFFFF {
+		return
+	}
+	switch d := dst.(type) {
+	case pdfString:
+		runes := []rune(decodeUTF16BE(d))
+		if len(runes) == 0 {
+			return
+		}
+		for code := lo; code <= hi; code++ {
+			out := append([]rune(nil), runes...)
+			out[len(out)-1] += rune(code - lo)
+			mapping[code] = string(out)
+		}
+	case pdfArray:
+		for i, item := range d {
+			if s, ok := item.(pdfString); ok && lo+uint32(i) <= hi {
+				mapping[lo+uint32(i)] = decodeUTF16BE(s)
+			}
+		}
+	}
+}
+
+func codeValue(s pdfString) uint32 {
+	var v uint32
+	for _, b := range s {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodePDFTextString decodes text strings outside content streams (UTF-16BE
+// with a byte order mark, otherwise PDFDocEncoding approximated as CP1252).
+func decodePDFTextString(s pdfString) string {
+	if len(s) >= 2 && s[0] == 0xFE && s[1] == 0xFF {
+		return decodeUTF16BE(s[2:])
+	}
+	var sb strings.Builder
+	for _, b := range s {
+		sb.WriteRune(decodeCP1252(b))
+	}
+	return sb.String()
+}
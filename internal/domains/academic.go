@@ -8,15 +8,28 @@ import (
 // AcademicValidator validates academic/research content extraction quality
 type AcademicValidator struct {
 	expectedCitationFormat string
+	language               string
 }
 
 // NewAcademicValidator creates a new academic validator
 func NewAcademicValidator(citationFormat string) *AcademicValidator {
 	return &AcademicValidator{
 		expectedCitationFormat: citationFormat,
+		language:               "en",
 	}
 }
 
+// SetLanguage selects the content language (ISO 639-1, e.g. "de", "ja").
+// Languages without a lexicon skip the section, terminology and tone checks.
+func (v *AcademicValidator) SetLanguage(language string) {
+	v.language = normalizeLanguage(language)
+}
+
+func (v *AcademicValidator) lexicon() (academicLexicon, bool) {
+	lexicon, ok := academicLexicons[v.language]
+	return lexicon, ok
+}
+
 // AcademicValidationResult contains academic extraction metrics
 type AcademicValidationResult struct {
 	HasAbstract         bool            `json:"has_abstract"`
@@ -31,6 +44,8 @@ type AcademicValidationResult struct {
 	CitationFormatScore float64         `json:"citation_format_score"` // 0-100
 	AcademicToneScore   float64         `json:"academic_tone_score"`   // 0-100
 	Score               float64         `json:"score"`                 // 0-100
+	Language            string          `json:"language"`
+	HeuristicsSkipped   bool            `json:"heuristics_skipped,omitempty"` // no lexicon for Language
 	Issues              []AcademicIssue `json:"issues"`
 }
 
@@ -45,11 +60,15 @@ type AcademicIssue struct {
 func (v *AcademicValidator) ValidateExtract(content string) AcademicValidationResult {
 	result := AcademicValidationResult{
 		DetectedSections: make([]string, 0),
+		Language:         v.language,
 		Issues:           make([]AcademicIssue, 0),
 	}
 
+	lexicon, ok := v.lexicon()
+	result.HeuristicsSkipped = !ok
+
 	// Check for abstract
-	result.HasAbstract = v.hasAbstract(content)
+	result.HasAbstract = ok && v.hasAbstract(content, lexicon)
 	if result.HasAbstract {
 		result.DetectedSections = append(result.DetectedSections, "abstract")
 	}
@@ -59,25 +78,25 @@ func (v *AcademicValidator) ValidateExtract(content string) AcademicValidationRe
 	result.HasCitations = result.CitationCount > 0
 
 	// Check for references section
-	result.HasReferences = v.hasReferences(content)
+	result.HasReferences = ok && v.hasReferences(content, lexicon)
 	if result.HasReferences {
 		result.DetectedSections = append(result.DetectedSections, "references")
 	}
 
 	// Check for methodology section
-	result.HasMethodology = v.hasMethodology(content)
+	result.HasMethodology = ok && v.hasMethodology(content, lexicon)
 	if result.HasMethodology {
 		result.DetectedSections = append(result.DetectedSections, "methodology")
 	}
 
 	// Check for results section
-	result.HasResults = v.hasResults(content)
+	result.HasResults = ok && v.hasResults(content, lexicon)
 	if result.HasResults {
 		result.DetectedSections = append(result.DetectedSections, "results")
 	}
 
 	// Count academic terminology
-	result.AcademicTermCount = v.countAcademicTerms(content)
+	result.AcademicTermCount = v.countAcademicTerms(content, lexicon)
 
 	// Calculate paper length score
 	result.PaperLengthScore = v.calculatePaperLengthScore(content)
@@ -86,7 +105,7 @@ func (v *AcademicValidator) ValidateExtract(content string) AcademicValidationRe
 	result.CitationFormatScore = v.assessCitationFormat(content)
 
 	// Calculate academic tone score
-	result.AcademicToneScore = v.assessAcademicTone(content)
+	result.AcademicToneScore = v.assessAcademicTone(content, lexicon)
 
 	// Calculate overall score
 	result.Score = v.calculateScore(result)
@@ -98,21 +117,8 @@ func (v *AcademicValidator) ValidateExtract(content string) AcademicValidationRe
 }
 
 // hasAbstract checks for abstract section
-func (v *AcademicValidator) hasAbstract(content string) bool {
-	abstractPatterns := []string{
-		`(?i)^\s*abstract\s*\n`,
-		`(?i)\n\s*abstract\s*\n`,
-		`(?i)^\s*summary\s*\n`,
-	}
-
-	for _, pattern := range abstractPatterns {
-		re := regexp.MustCompile(pattern)
-		if re.FindString(content) != "" {
-			return true
-		}
-	}
-
-	return false
+func (v *AcademicValidator) hasAbstract(content string, lexicon academicLexicon) bool {
+	return headingPattern(lexicon.abstract).MatchString(content)
 }
 
 // countCitations counts academic citations
@@ -135,75 +141,25 @@ func (v *AcademicValidator) countCitations(content string) int {
 }
 
 // hasReferences checks for references/bibliography section
-func (v *AcademicValidator) hasReferences(content string) bool {
-	referencePatterns := []string{
-		`(?i)\n\s*references?\s*\n`,
-		`(?i)\n\s*bibliography\s*\n`,
-		`(?i)\n\s*works?\s+cited\s*\n`,
-	}
-
-	for _, pattern := range referencePatterns {
-		re := regexp.MustCompile(pattern)
-		if re.FindString(content) != "" {
-			return true
-		}
-	}
-
-	return false
+func (v *AcademicValidator) hasReferences(content string, lexicon academicLexicon) bool {
+	return headingPattern(lexicon.references).MatchString(content)
 }
 
 // hasMethodology checks for methodology section
-func (v *AcademicValidator) hasMethodology(content string) bool {
-	methodPatterns := []string{
-		`(?i)\n\s*methodology\s*\n`,
-		`(?i)\n\s*methods?\s*\n`,
-		`(?i)\n\s*experimental\s+setup\s*\n`,
-		`(?i)\n\s*materials?\s+and\s+methods?\s*\n`,
-	}
-
-	for _, pattern := range methodPatterns {
-		re := regexp.MustCompile(pattern)
-		if re.FindString(content) != "" {
-			return true
-		}
-	}
-
-	return false
+func (v *AcademicValidator) hasMethodology(content string, lexicon academicLexicon) bool {
+	return headingPattern(lexicon.methodology).MatchString(content)
 }
 
 // hasResults checks for results section
-func (v *AcademicValidator) hasResults(content string) bool {
-	resultPatterns := []string{
-		`(?i)\n\s*results?\s*\n`,
-		`(?i)\n\s*findings?\s*\n`,
-		`(?i)\n\s*outcomes?\s*\n`,
-	}
-
-	for _, pattern := range resultPatterns {
-		re := regexp.MustCompile(pattern)
-		if re.FindString(content) != "" {
-			return true
-		}
-	}
-
-	return false
+func (v *AcademicValidator) hasResults(content string, lexicon academicLexicon) bool {
+	return headingPattern(lexicon.results).MatchString(content)
 }
 
 // countAcademicTerms counts academic terminology
-func (v *AcademicValidator) countAcademicTerms(content string) int {
-	academicTerms := []string{
-		"hypothesis", "methodology", "significant", "correlation",
-		"analysis", "conclusion", "framework", "literature review",
-		"empirical", "theoretical", "quantitative", "qualitative",
-		"statistical", "evidence", "demonstrates", "indicates",
-		"participants", "sample", "data", "findings",
-		"peer-reviewed", "doi", "journal", "conference",
-		"abstract", "introduction", "discussion", "implications",
-	}
-
+func (v *AcademicValidator) countAcademicTerms(content string, lexicon academicLexicon) int {
 	contentLower := strings.ToLower(content)
 	count := 0
-	for _, term := range academicTerms {
+	for _, term := range lexicon.terms {
 		count += strings.Count(contentLower, term)
 	}
 
@@ -338,30 +294,19 @@ func (v *AcademicValidator) detectAnyCitationFormat(content string) float64 {
 }
 
 // assessAcademicTone checks for academic writing characteristics
-func (v *AcademicValidator) assessAcademicTone(content string) float64 {
+func (v *AcademicValidator) assessAcademicTone(content string, lexicon academicLexicon) float64 {
 	score := 50.0 // Start neutral
 
 	// Positive indicators
-	academicPhrases := []string{
-		"this study", "the results indicate", "we found that",
-		"our findings", "the data suggest", "in conclusion",
-		"further research", "limitations", "implications",
-	}
-
 	contentLower := strings.ToLower(content)
-	for _, phrase := range academicPhrases {
+	for _, phrase := range lexicon.phrases {
 		if strings.Contains(contentLower, phrase) {
 			score += 5
 		}
 	}
 
 	// Negative indicators (too informal)
-	informalPatterns := []string{
-		"i think", "you should", "a lot of", "really",
-		"very", "just", "basically", "actually",
-	}
-
-	for _, pattern := range informalPatterns {
+	for _, pattern := range lexicon.informal {
 		if strings.Count(contentLower, pattern) > 3 {
 			score -= 5
 		}
@@ -383,6 +328,12 @@ func (v *AcademicValidator) calculateScore(result AcademicValidationResult) floa
 		0.10, // Citation format
 		0.10, // Academic tone
 	}
+	if result.HeuristicsSkipped {
+		// Sections, terminology and tone depend on the language lexicon.
+		for _, i := range []int{0, 2, 3, 4, 5, 8} {
+			weights[i] = 0
+		}
+	}
 
 	// Normalize academic term count (cap at 50 for 100%)
 	termScore := float64(result.AcademicTermCount) / 50.0 * 100
@@ -402,9 +353,13 @@ func (v *AcademicValidator) calculateScore(result AcademicValidationResult) floa
 		result.AcademicToneScore,
 	}
 
-	var total float64
+	var total, weightSum float64
 	for i, score := range scores {
 		total += score * weights[i]
+		weightSum += weights[i]
+	}
+	if weightSum > 0 {
+		total /= weightSum
 	}
 
 	return clamp(total, 0, 100)
@@ -414,7 +369,15 @@ func (v *AcademicValidator) calculateScore(result AcademicValidationResult) floa
 func (v *AcademicValidator) collectIssues(result AcademicValidationResult) []AcademicIssue {
 	issues := make([]AcademicIssue, 0)
 
-	if !result.HasAbstract {
+	if result.HeuristicsSkipped {
+		issues = append(issues, AcademicIssue{
+			Type:        "locale_unsupported",
+			Description: "Section, terminology and tone checks skipped: no lexicon for language " + result.Language,
+			Severity:    "info",
+		})
+	}
+
+	if !result.HasAbstract && !result.HeuristicsSkipped {
 		issues = append(issues, AcademicIssue{
 			Type:        "missing_abstract",
 			Description: "No abstract section detected",
@@ -436,7 +399,7 @@ func (v *AcademicValidator) collectIssues(result AcademicValidationResult) []Aca
 		})
 	}
 
-	if !result.HasReferences {
+	if !result.HasReferences && !result.HeuristicsSkipped {
 		issues = append(issues, AcademicIssue{
 			Type:        "missing_references",
 			Description: "No references/bibliography section",
@@ -444,7 +407,7 @@ func (v *AcademicValidator) collectIssues(result AcademicValidationResult) []Aca
 		})
 	}
 
-	if result.AcademicToneScore < 50 {
+	if result.AcademicToneScore < 50 && !result.HeuristicsSkipped {
 		issues = append(issues, AcademicIssue{
 			Type:        "informal_tone",
 			Description: "Content appears to have informal tone",
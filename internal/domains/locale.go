@@ -0,0 +1,193 @@
+package domains
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// academicLexicon holds the section headings and phrases the academic
+// heuristics look for in one language.
+type academicLexicon struct {
+	abstract    []string
+	references  []string
+	methodology []string
+	results     []string
+	terms       []string
+	phrases     []string
+	informal    []string
+}
+
+var academicLexicons = map[string]academicLexicon{
+	"en": {
+		abstract:    []string{"abstract", "summary"},
+		references:  []string{"reference", "references", "bibliography", "work cited", "works cited"},
+		methodology: []string{"methodology", "method", "methods", "experimental setup", "material and method", "materials and methods"},
+		results:     []string{"result", "results", "finding", "findings", "outcome", "outcomes"},
+		terms: []string{
+			"hypothesis", "methodology", "significant", "correlation",
+			"analysis", "conclusion", "framework", "literature review",
+			"empirical", "theoretical", "quantitative", "qualitative",
+			"statistical", "evidence", "demonstrates", "indicates",
+			"participants", "sample", "data", "findings",
+			"peer-reviewed", "doi", "journal", "conference",
+			"abstract", "introduction", "discussion", "implications",
+		},
+		phrases: []string{
+			"this study", "the results indicate", "we found that",
+			"our findings", "the data suggest", "in conclusion",
+			"further research", "limitations", "implications",
+		},
+		informal: []string{
+			"i think", "you should", "a lot of", "really",
+			"very", "just", "basically", "actually",
+		},
+	},
+	"de": {
+		abstract:    []string{"zusammenfassung", "kurzfassung", "abstract"},
+		references:  []string{"literatur", "literaturverzeichnis", "quellen", "quellenverzeichnis", "referenzen"},
+		methodology: []string{"methodik", "methode", "methoden", "material und methoden", "versuchsaufbau"},
+		results:     []string{"ergebnisse", "resultate", "befunde"},
+		terms: []string{
+			"hypothese", "methodik", "signifikant", "korrelation",
+			"analyse", "schlussfolgerung", "empirisch", "theoretisch",
+			"quantitativ", "qualitativ", "statistisch", "evidenz",
+			"teilnehmer", "stichprobe", "daten", "ergebnisse",
+			"fachzeitschrift", "konferenz", "einleitung", "diskussion", "doi",
+		},
+		phrases: []string{
+			"diese studie", "die ergebnisse zeigen", "wir fanden",
+			"unsere ergebnisse", "die daten deuten", "zusammenfassend",
+			"weitere forschung", "einschränkungen", "implikationen",
+		},
+		informal: []string{
+			"ich denke", "ihr solltet", "echt", "total",
+			"halt", "eigentlich", "einfach mal", "voll",
+		},
+	},
+	"ja": {
+		abstract:    []string{"要旨", "概要", "要約", "抄録"},
+		references:  []string{"参考文献", "引用文献", "文献"},
+		methodology: []string{"方法", "手法", "研究方法", "実験方法"},
+		results:     []string{"結果", "実験結果", "研究結果"},
+		terms: []string{
+			"仮説", "方法論", "有意", "相関", "分析", "結論",
+			"実証", "理論", "定量", "定性", "統計", "被験者",
+			"標本", "データ", "論文", "学会", "序論", "考察", "先行研究", "doi",
+		},
+		phrases: []string{
+			"本研究", "結果は", "明らかになった", "示唆される",
+			"今後の課題", "結論として", "本稿", "限界",
+		},
+		informal: []string{
+			"と思う", "めっちゃ", "すごく", "マジ",
+			"ですよね", "だよね", "とりあえず", "ちょっと",
+		},
+	},
+}
+
+// newsLexicon holds the author bylines, localized date formats and headline
+// rules the news heuristics use for one language.
+type newsLexicon struct {
+	authorPatterns   []*regexp.Regexp
+	datePatterns     []localDatePattern
+	headlineMinRunes int
+}
+
+// localDatePattern matches a localized date; parse builds the time from the submatches.
+type localDatePattern struct {
+	re    *regexp.Regexp
+	parse func(m []string) time.Time
+}
+
+var germanMonths = map[string]time.Month{
+	"januar": time.January, "jänner": time.January, "februar": time.February, "märz": time.March,
+	"april": time.April, "mai": time.May, "juni": time.June, "juli": time.July,
+	"august": time.August, "september": time.September, "oktober": time.October,
+	"november": time.November, "dezember": time.December,
+}
+
+var newsLexicons = map[string]newsLexicon{
+	"en": {
+		authorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)by\s+[A-Z][a-z]+\s+[A-Z][a-z]+`), // By John Smith
+			regexp.MustCompile(`(?i)author[s]?:?\s*[A-Z]`),           // Author: John
+			regexp.MustCompile(`(?i)written\s+by\s+[A-Z]`),           // Written by John
+			regexp.MustCompile(`(?i)reporting\s+by\s+[A-Z]`),         // Reporting by John
+		},
+		headlineMinRunes: 10,
+	},
+	"de": {
+		authorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`\b[Vv]on\s+\p{Lu}\p{Ll}+\s+\p{Lu}\p{Ll}+`), // Von Max Mustermann
+			regexp.MustCompile(`\b[Aa]utor(?:in|en)?:\s*\p{Lu}`),           // Autorin: Erika
+		},
+		datePatterns: []localDatePattern{
+			{
+				// 2. Januar 2024
+				re: regexp.MustCompile(`(?i)\b(\d{1,2})\.\s*(Januar|Jänner|Februar|März|April|Mai|Juni|Juli|August|September|Oktober|November|Dezember)\s+(\d{4})\b`),
+				parse: func(m []string) time.Time {
+					return civilDate(m[3], strconv.Itoa(int(germanMonths[strings.ToLower(m[2])])), m[1])
+				},
+			},
+			{
+				// 02.01.2024
+				re:    regexp.MustCompile(`\b(\d{1,2})\.(\d{1,2})\.(\d{4})\b`),
+				parse: func(m []string) time.Time { return civilDate(m[3], m[2], m[1]) },
+			},
+		},
+		headlineMinRunes: 10,
+	},
+	"ja": {
+		authorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`記者|筆者|著者|執筆者?|文[:：]`),
+		},
+		datePatterns: []localDatePattern{
+			{
+				// 2024年1月2日
+				re:    regexp.MustCompile(`(\d{4})\s*年\s*(\d{1,2})\s*月\s*(\d{1,2})\s*日`),
+				parse: func(m []string) time.Time { return civilDate(m[1], m[2], m[3]) },
+			},
+		},
+		headlineMinRunes: 5,
+	},
+}
+
+// civilDate builds a UTC date from numeric strings, returning the zero time
+// for out-of-range values.
+func civilDate(year, month, day string) time.Time {
+	y, errY := strconv.Atoi(year)
+	m, errM := strconv.Atoi(month)
+	d, errD := strconv.Atoi(day)
+	if errY != nil || errM != nil || errD != nil || m < 1 || m > 12 || d < 1 || d > 31 {
+		return time.Time{}
+	}
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if t.Day() != d {
+		return time.Time{}
+	}
+	return t
+}
+
+// normalizeLanguage reduces a locale such as "de-DE" to its lowercase
+// language code. An empty value is English.
+func normalizeLanguage(language string) string {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if code == "" {
+		return "en"
+	}
+	return code
+}
+
+// headingPattern matches any of the words alone on a line.
+func headingPattern(words []string) *regexp.Regexp {
+	alternatives := make([]string, 0, len(words))
+	for _, w := range words {
+		alternatives = append(alternatives, strings.ReplaceAll(regexp.QuoteMeta(w), " ", `\s+`))
+	}
+	return regexp.MustCompile(`(?i)(?:^|\n)\s*(?:` + strings.Join(alternatives, "|") + `)\s*\n`)
+}
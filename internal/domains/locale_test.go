@@ -0,0 +1,88 @@
+package domains
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewsValidator_LocalizedDatesAndBylines(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		content  string
+		wantDate time.Time
+	}{
+		{
+			name:     "german long date",
+			language: "de-DE",
+			content:  "Neue Regeln für Elektroautos\n\nVon Erika Mustermann, 2. März 2024\n\nDie Bundesregierung hat am Montag neue Förderregeln für Elektroautos beschlossen, die ab dem Sommer gelten sollen.",
+			wantDate: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "german numeric date",
+			language: "de",
+			content:  "Neue Regeln für Elektroautos\n\nAutorin: Erika Mustermann, 02.03.2024\n\nDie Bundesregierung hat am Montag neue Förderregeln für Elektroautos beschlossen, die ab dem Sommer gelten sollen.",
+			wantDate: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "japanese",
+			language: "ja",
+			content:  "電気自動車の新制度\n\n2024年3月2日 記者 山田太郎\n\n政府は月曜日、電気自動車の購入を支援する新しい補助金制度を決定した。制度は夏から適用される予定で、対象車種も拡大される。",
+			wantDate: time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewNewsValidator(24)
+			v.SetLanguage(tt.language)
+			got := v.ValidateExtract(tt.content, "https://example.com/news")
+			if !got.HasHeadline || !got.HasAuthor || got.HeuristicsSkipped {
+				t.Errorf("got headline=%v author=%v skipped=%v", got.HasHeadline, got.HasAuthor, got.HeuristicsSkipped)
+			}
+			if !got.DetectedDate.Equal(tt.wantDate) {
+				t.Errorf("DetectedDate = %v, want %v", got.DetectedDate, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestAcademicValidator_LocalizedSections(t *testing.T) {
+	content := "要旨\n本研究では統計的な分析を行った。\n\n研究方法\n被験者のデータを収集した。\n\n結果\n有意な相関が明らかになった。\n\n参考文献\n[1] 山田 (2020)\n"
+	v := NewAcademicValidator("")
+	v.SetLanguage("ja")
+	got := v.ValidateExtract(content)
+	if !got.HasAbstract || !got.HasMethodology || !got.HasResults || !got.HasReferences {
+		t.Errorf("expected Japanese sections, got %+v", got)
+	}
+	if got.AcademicTermCount == 0 {
+		t.Error("expected Japanese academic terms to be counted")
+	}
+}
+
+func TestValidators_UnsupportedLanguageSkipsHeuristics(t *testing.T) {
+	academic := NewAcademicValidator("")
+	academic.SetLanguage("ru")
+	result := academic.ValidateExtract("Аннотация\nВладение гарантирует безопасность памяти.\n")
+	if !result.HeuristicsSkipped || !hasAcademicIssue(result.Issues, "locale_unsupported") {
+		t.Errorf("expected skipped heuristics note, got %+v", result.Issues)
+	}
+	if hasAcademicIssue(result.Issues, "missing_abstract") {
+		t.Error("missing abstract should not be reported when heuristics are skipped")
+	}
+
+	news := NewNewsValidator(24)
+	news.SetLanguage("ru")
+	newsResult := news.ValidateExtract("Новости\n\nВладение гарантирует безопасность памяти.", "")
+	if !newsResult.HeuristicsSkipped {
+		t.Error("expected news heuristics to be skipped for Russian")
+	}
+}
+
+func hasAcademicIssue(issues []AcademicIssue, issueType string) bool {
+	for _, issue := range issues {
+		if issue.Type == issueType {
+			return true
+		}
+	}
+	return false
+}
@@ -4,11 +4,13 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // NewsValidator validates news/current affairs extraction quality
 type NewsValidator struct {
 	maxAgeHours int
+	language    string
 }
 
 // NewNewsValidator creates a new news validator
@@ -18,9 +20,22 @@ func NewNewsValidator(maxAgeHours int) *NewsValidator {
 	}
 	return &NewsValidator{
 		maxAgeHours: maxAgeHours,
+		language:    "en",
 	}
 }
 
+// SetLanguage selects the content language (ISO 639-1, e.g. "de", "ja").
+// Languages without a lexicon skip the byline check and only recognize
+// numeric dates.
+func (v *NewsValidator) SetLanguage(language string) {
+	v.language = normalizeLanguage(language)
+}
+
+func (v *NewsValidator) lexicon() (newsLexicon, bool) {
+	lexicon, ok := newsLexicons[v.language]
+	return lexicon, ok
+}
+
 // NewsValidationResult contains news extraction metrics
 type NewsValidationResult struct {
 	HasHeadline        bool        `json:"has_headline"`
@@ -33,6 +48,8 @@ type NewsValidationResult struct {
 	DomainAuthority    float64     `json:"domain_authority"`    // 0-100
 	Score              float64     `json:"score"`               // 0-100
 	DetectedDate       time.Time   `json:"detected_date,omitempty"`
+	Language           string      `json:"language"`
+	HeuristicsSkipped  bool        `json:"heuristics_skipped,omitempty"` // no lexicon for Language
 	Issues             []NewsIssue `json:"issues"`
 }
 
@@ -46,26 +63,30 @@ type NewsIssue struct {
 // ValidateExtract validates extracted news content
 func (v *NewsValidator) ValidateExtract(content string, sourceURL string) NewsValidationResult {
 	result := NewsValidationResult{
-		Issues: make([]NewsIssue, 0),
+		Language: v.language,
+		Issues:   make([]NewsIssue, 0),
 	}
 
+	lexicon, ok := v.lexicon()
+	result.HeuristicsSkipped = !ok
+
 	// Check for headline (usually first line or in content)
-	result.HasHeadline = v.hasHeadline(content)
+	result.HasHeadline = v.hasHeadline(content, lexicon.headlineMinRunes)
 
 	// Try to extract publication date
-	date := v.extractDate(content)
+	date := v.extractDate(content, lexicon)
 	result.HasPublicationDate = !date.IsZero()
 	result.DetectedDate = date
 
 	// Check for author
-	result.HasAuthor = v.hasAuthor(content)
+	result.HasAuthor = v.hasAuthor(content, lexicon)
 
 	// Check for lead paragraph (first substantial paragraph)
 	result.HasLeadParagraph = v.hasLeadParagraph(content)
 
-	// Calculate content metrics
-	result.ContentLength = len(content)
-	result.EstimatedReadTime = estimateReadTime(content)
+	// Calculate content metrics (in characters, so multi-byte scripts compare fairly)
+	result.ContentLength = utf8.RuneCountInString(content)
+	result.EstimatedReadTime = estimateReadTime(content, v.language)
 
 	// Calculate freshness score
 	result.FreshnessScore = v.calculateFreshness(date)
@@ -83,7 +104,7 @@ func (v *NewsValidator) ValidateExtract(content string, sourceURL string) NewsVa
 }
 
 // hasHeadline checks if content has a headline structure
-func (v *NewsValidator) hasHeadline(content string) bool {
+func (v *NewsValidator) hasHeadline(content string, minRunes int) bool {
 	lines := strings.Split(content, "\n")
 	if len(lines) == 0 {
 		return false
@@ -96,9 +117,13 @@ func (v *NewsValidator) hasHeadline(content string) bool {
 	}
 
 	// Check if it looks like a headline (not too long, no punctuation at end)
-	if len(firstLine) < 150 && len(firstLine) > 10 {
-		lastChar := firstLine[len(firstLine)-1]
-		if lastChar != '.' && lastChar != ',' && lastChar != ';' {
+	if minRunes <= 0 {
+		minRunes = 10
+	}
+	length := utf8.RuneCountInString(firstLine)
+	if length < 150 && length > minRunes {
+		lastChar, _ := utf8.DecodeLastRuneInString(firstLine)
+		if !strings.ContainsRune(".,;。、．", lastChar) {
 			return true
 		}
 	}
@@ -107,7 +132,15 @@ func (v *NewsValidator) hasHeadline(content string) bool {
 }
 
 // extractDate attempts to find and parse a date in the content
-func (v *NewsValidator) extractDate(content string) time.Time {
+func (v *NewsValidator) extractDate(content string, lexicon newsLexicon) time.Time {
+	for _, pattern := range lexicon.datePatterns {
+		if m := pattern.re.FindStringSubmatch(content); m != nil {
+			if parsed := pattern.parse(m); !parsed.IsZero() {
+				return parsed
+			}
+		}
+	}
+
 	// Common date patterns
 	datePatterns := []string{
 		`\b(\d{1,2})[/-](\d{1,2})[/-](\d{2,4})\b`, // 01/02/2024 or 01-02-24
@@ -156,16 +189,8 @@ func tryParseDate(dateStr string) time.Time {
 }
 
 // hasAuthor checks if content has author information
-func (v *NewsValidator) hasAuthor(content string) bool {
-	authorPatterns := []string{
-		`(?i)by\s+[A-Z][a-z]+\s+[A-Z][a-z]+`, // By John Smith
-		`(?i)author[s]?:?\s*[A-Z]`,           // Author: John
-		`(?i)written\s+by\s+[A-Z]`,           // Written by John
-		`(?i)reporting\s+by\s+[A-Z]`,         // Reporting by John
-	}
-
-	for _, pattern := range authorPatterns {
-		re := regexp.MustCompile(pattern)
+func (v *NewsValidator) hasAuthor(content string, lexicon newsLexicon) bool {
+	for _, re := range lexicon.authorPatterns {
 		if re.FindString(content) != "" {
 			return true
 		}
@@ -198,10 +223,13 @@ func (v *NewsValidator) hasLeadParagraph(content string) bool {
 }
 
 // estimateReadTime estimates reading time in minutes
-func estimateReadTime(content string) int {
-	words := len(strings.Fields(content))
-	// Average reading speed: 200 words per minute
-	minutes := words / 200
+func estimateReadTime(content string, language string) int {
+	// Average reading speed: 200 words per minute; Japanese is not
+	// space-separated, so use about 500 characters per minute instead.
+	minutes := len(strings.Fields(content)) / 200
+	if language == "ja" {
+		minutes = utf8.RuneCountInString(content) / 500
+	}
 	if minutes < 1 {
 		return 1
 	}
@@ -284,6 +312,9 @@ func (v *NewsValidator) calculateScore(result NewsValidationResult) float64 {
 		0.10, // Freshness
 		0.10, // Domain authority
 	}
+	if result.HeuristicsSkipped {
+		weights[2] = 0 // Bylines are language-specific
+	}
 
 	scores := []float64{
 		boolToScore(result.HasHeadline),
@@ -295,9 +326,13 @@ func (v *NewsValidator) calculateScore(result NewsValidationResult) float64 {
 		result.DomainAuthority,
 	}
 
-	var total float64
+	var total, weightSum float64
 	for i, score := range scores {
 		total += score * weights[i]
+		weightSum += weights[i]
+	}
+	if weightSum > 0 {
+		total /= weightSum
 	}
 
 	return clamp(total, 0, 100)
@@ -307,6 +342,14 @@ func (v *NewsValidator) calculateScore(result NewsValidationResult) float64 {
 func (v *NewsValidator) collectIssues(result NewsValidationResult) []NewsIssue {
 	issues := make([]NewsIssue, 0)
 
+	if result.HeuristicsSkipped {
+		issues = append(issues, NewsIssue{
+			Type:        "locale_unsupported",
+			Description: "Byline and localized date checks skipped: no lexicon for language " + result.Language,
+			Severity:    "info",
+		})
+	}
+
 	if !result.HasHeadline {
 		issues = append(issues, NewsIssue{
 			Type:        "missing_headline",
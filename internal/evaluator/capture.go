@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// outputCapture builds a result's Output within the [capture] limits.
+type outputCapture struct {
+	test     config.TestConfig
+	maxItems int
+	maxChars int
+	output   *benchmetrics.Output
+}
+
+// newOutputCapture returns nil when capture is disabled.
+func (r *Runner) newOutputCapture(test config.TestConfig, total int) *outputCapture {
+	if r.config.Capture.Disabled {
+		return nil
+	}
+	maxItems, maxChars := r.config.Capture.Limits()
+	return &outputCapture{
+		test:     test,
+		maxItems: maxItems,
+		maxChars: maxChars,
+		output: &benchmetrics.Output{
+			Query:        test.Query,
+			URL:          test.URL,
+			ExpectedURLs: uniqueNonEmptyStrings(test.ExpectedURLs),
+			Terms:        highlightTerms(test),
+			Items:        []benchmetrics.OutputItem{},
+			TotalItems:   total,
+			Truncated:    total > maxItems,
+		},
+	}
+}
+
+// add appends an item unless the item limit is reached.
+func (c *outputCapture) add(item benchmetrics.OutputItem, content string) {
+	if len(c.output.Items) >= c.maxItems {
+		return
+	}
+	runes := []rune(content)
+	item.ContentLength = len(runes)
+	if len(runes) > c.maxChars {
+		content = string(runes[:c.maxChars])
+		c.output.Truncated = true
+	}
+	item.Content = content
+	item.Rank = len(c.output.Items) + 1
+	item.Matched = matchesExpectedURL(item.URL, c.output.ExpectedURLs)
+	c.output.Items = append(c.output.Items, item)
+}
+
+func (r *Runner) captureSearch(test config.TestConfig, results []providers.SearchItem) *benchmetrics.Output {
+	c := r.newOutputCapture(test, len(results))
+	if c == nil {
+		return nil
+	}
+	for _, item := range results {
+		c.add(benchmetrics.OutputItem{
+			Title:       item.Title,
+			URL:         item.URL,
+			Score:       item.Score,
+			PublishedAt: item.PublishedAt,
+		}, item.Content)
+	}
+	return c.output
+}
+
+func (r *Runner) captureContent(test config.TestConfig, url, title, content string) *benchmetrics.Output {
+	c := r.newOutputCapture(test, 1)
+	if c == nil {
+		return nil
+	}
+	if url == "" {
+		url = test.URL
+	}
+	c.add(benchmetrics.OutputItem{Title: title, URL: url}, content)
+	return c.output
+}
+
+func (r *Runner) captureCrawl(test config.TestConfig, pages []providers.CrawledPage) *benchmetrics.Output {
+	c := r.newOutputCapture(test, len(pages))
+	if c == nil {
+		return nil
+	}
+	for _, page := range pages {
+		c.add(benchmetrics.OutputItem{Title: page.Title, URL: page.URL}, page.Content)
+	}
+	return c.output
+}
+
+// highlightTerms returns the query words and the test's expected topics,
+// content and terms.
+func highlightTerms(test config.TestConfig) []string {
+	var terms []string
+	for _, word := range strings.Fields(test.Query) {
+		word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len([]rune(word)) >= 3 {
+			terms = append(terms, word)
+		}
+	}
+	terms = append(terms, test.ExpectedTopics...)
+	terms = append(terms, test.ExpectedContent...)
+	terms = append(terms, test.MustIncludeTerms...)
+	return uniqueNonEmptyStrings(terms)
+}
+
+// matchesExpectedURL matches URLs the way search ground truth does: equal
+// after normalization or one containing the other.
+func matchesExpectedURL(url string, expected []string) bool {
+	norm := normalizeURLForMatch(url)
+	if norm == "" {
+		return false
+	}
+	for _, raw := range expected {
+		want := normalizeURLForMatch(raw)
+		if want != "" && (strings.Contains(norm, want) || strings.Contains(want, norm)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package evaluator
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/document"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/quality"
+)
+
+// fixtureServer serves general.fixtures_dir on a loopback port for fixture:// test URLs.
+type fixtureServer struct {
+	server  *http.Server
+	baseURL string
+}
+
+func startFixtureServer(dir string) (*fixtureServer, error) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start fixture server: %w", err)
+	}
+	server := &http.Server{
+		Handler:           http.FileServer(http.Dir(dir)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return &fixtureServer{server: server, baseURL: "http://" + listener.Addr().String()}, nil
+}
+
+func (f *fixtureServer) Close() {
+	_ = f.server.Close()
+}
+
+// URL maps fixture://name to the server's address.
+func (f *fixtureServer) URL(raw string) string {
+	return f.baseURL + "/" + strings.TrimLeft(strings.TrimPrefix(raw, config.FixtureScheme), "/")
+}
+
+func needsFixtureServer(cfg *config.Config) bool {
+	for _, test := range cfg.Tests {
+		if test.IsFixtureURL() {
+			return true
+		}
+	}
+	return false
+}
+
+// reachesFixtures reports whether a provider fetches URLs from this machine.
+// Hosted APIs fetch from their own infrastructure and cannot see a loopback
+// server. Named instances are matched by their type, not their name.
+func reachesFixtures(prov providers.Provider) bool {
+	return providers.TypeOf(prov) == "local"
+}
+
+// documentFormat returns the document type a test targets: the configured
+// document_type, else the URL extension, else HTML.
+func documentFormat(test config.TestConfig) document.Format {
+	if format := document.ParseFormat(test.DocumentType); format != "" {
+		return format
+	}
+	if format := document.FormatFromURL(test.URL); format != "" {
+		return format
+	}
+	return document.FormatHTML
+}
+
+// isDocumentTest reports whether an extract test should record document stats:
+// it targets a non-HTML document or sets document expectations.
+func isDocumentTest(test config.TestConfig) bool {
+	return documentFormat(test) != document.FormatHTML ||
+		test.ReferenceText != "" || test.ExpectedPages != nil || test.ExpectedTables != nil
+}
+
+// buildDocumentStats records how a provider handled a document. extracted is
+// nil when the extraction failed.
+func buildDocumentStats(test config.TestConfig, extracted *providers.ExtractResult) *benchmetrics.DocumentStats {
+	stats := &benchmetrics.DocumentStats{
+		DocumentType:       string(documentFormat(test)),
+		ReferenceAvailable: strings.TrimSpace(test.ReferenceText) != "",
+		ExpectedPages:      test.ExpectedPages,
+		ExpectedTables:     test.ExpectedTables,
+	}
+	if extracted == nil {
+		return stats
+	}
+
+	content := extracted.Markdown
+	if content == "" {
+		content = extracted.Content
+	}
+	stats.ReportedType = string(reportedDocumentType(extracted.Metadata))
+	stats.Handled = strings.TrimSpace(content) != "" &&
+		(stats.ReportedType == "" || stats.ReportedType == stats.DocumentType)
+	stats.Pages = metadataInt(extracted.Metadata, "pages", "numPages", "num_pages", "pageCount", "page_count")
+	stats.Tables = document.CountMarkdownTables(content)
+	if stats.ReferenceAvailable {
+		stats.TextFidelity = quality.TextFidelity(test.ReferenceText, content)
+	}
+	return stats
+}
+
+// reportedDocumentType reads the document type a provider reported, if any.
+func reportedDocumentType(metadata map[string]interface{}) document.Format {
+	if v, ok := metadata["document_type"].(string); ok {
+		return document.ParseFormat(v)
+	}
+	for _, key := range []string{"contentType", "content_type", "mimeType", "mime_type"} {
+		if v, ok := metadata[key].(string); ok && strings.Contains(v, "/") {
+			return document.DetectFormat(v, nil)
+		}
+	}
+	return ""
+}
+
+func metadataInt(metadata map[string]interface{}, keys ...string) int {
+	for _, key := range keys {
+		switch v := metadata[key].(type) {
+		case int:
+			return v
+		case int64:
+			return int(v)
+		case float64:
+			return int(v)
+		}
+	}
+	return 0
+}
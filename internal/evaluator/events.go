@@ -0,0 +1,253 @@
+package evaluator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// EventSchemaVersion is the version of the Event JSON schema. It changes only
+// when a field is renamed, removed or changes meaning; new fields and event
+// types may be added within a version.
+const EventSchemaVersion = "v1"
+
+// EventType identifies a runner event.
+type EventType string
+
+// Runner event types. A run emits run_started, then for each test × provider
+// × repeat test_started, any http_attempt and retry events, quality_scored
+// when the result was scored and test_completed, and finally run_finished.
+// Events of concurrent tests interleave; skipped tests only produce
+// test_completed.
+const (
+	EventRunStarted    EventType = "run_started"
+	EventTestStarted   EventType = "test_started"
+	EventHTTPAttempt   EventType = "http_attempt"
+	EventRetry         EventType = "retry"
+	EventQualityScored EventType = "quality_scored"
+	EventTestCompleted EventType = "test_completed"
+	EventRunFinished   EventType = "run_finished"
+)
+
+// Event reports progress of a run. Test events name the test × provider ×
+// repeat they belong to; run events leave those fields empty. Completed and
+// Total count finished and planned tests at the time of the event. At most
+// one of the pointer fields is set, as noted on each.
+type Event struct {
+	Type      EventType `json:"type"`
+	Time      time.Time `json:"time"`
+	Provider  string    `json:"provider,omitempty"`
+	Test      string    `json:"test,omitempty"`
+	TestType  string    `json:"test_type,omitempty"`
+	Repeat    int       `json:"repeat,omitempty"`
+	Completed int       `json:"completed"`
+	Total     int       `json:"total"`
+
+	Run     *RunInfo               `json:"run,omitempty"`     // run_started and run_finished
+	HTTP    *providers.HTTPAttempt `json:"http,omitempty"`    // http_attempt
+	Retry   *providers.HTTPRetry   `json:"retry,omitempty"`   // retry
+	Quality *QualityInfo           `json:"quality,omitempty"` // quality_scored
+	Result  *benchmetrics.Result   `json:"result,omitempty"`  // test_completed
+}
+
+// RunInfo describes the run in run_started and run_finished events. The
+// outcome counts and duration are only set on run_finished.
+type RunInfo struct {
+	SchemaVersion string        `json:"schema_version"`
+	Mode          string        `json:"mode"`
+	Repeats       int           `json:"repeats"`
+	Tests         []string      `json:"tests"`
+	Providers     []string      `json:"providers"`
+	Succeeded     int           `json:"succeeded,omitempty"`
+	Failed        int           `json:"failed,omitempty"`
+	Skipped       int           `json:"skipped,omitempty"`
+	Duration      time.Duration `json:"duration,omitempty"` // nanoseconds
+}
+
+// QualityInfo holds the quality scores of a finished test (0-100).
+type QualityInfo struct {
+	Score         float64                `json:"score"`
+	SemanticScore float64                `json:"semantic_score,omitempty"`
+	RerankerScore float64                `json:"reranker_score,omitempty"`
+	DomainScores  map[string]float64     `json:"domain_scores,omitempty"`
+	Metrics       map[string]interface{} `json:"metrics,omitempty"`
+}
+
+// totalTests is the number of test × provider × repeat combinations in a run.
+func (r *Runner) totalTests() int {
+	return len(r.config.Tests) * len(r.providers) * r.options.Repeats
+}
+
+// runInfo describes the run for run_started and run_finished events.
+func (r *Runner) runInfo() *RunInfo {
+	info := &RunInfo{
+		SchemaVersion: EventSchemaVersion,
+		Mode:          string(r.options.Mode),
+		Repeats:       r.options.Repeats,
+		Tests:         make([]string, 0, len(r.config.Tests)),
+		Providers:     make([]string, 0, len(r.providers)),
+	}
+	for _, test := range r.config.Tests {
+		info.Tests = append(info.Tests, test.Name)
+	}
+	for _, prov := range r.providers {
+		info.Providers = append(info.Providers, prov.Name())
+	}
+	return info
+}
+
+func (r *Runner) emitRunStarted() {
+	if r.options.OnEvent == nil {
+		return
+	}
+	r.options.OnEvent(Event{
+		Type:  EventRunStarted,
+		Time:  time.Now(),
+		Total: r.totalTests(),
+		Run:   r.runInfo(),
+	})
+}
+
+// emitRunFinished reports the outcome of the run started at start.
+func (r *Runner) emitRunFinished(start time.Time) {
+	if r.options.OnEvent == nil {
+		return
+	}
+	info := r.runInfo()
+	for _, result := range r.collector.GetResults() {
+		switch {
+		case result.Skipped:
+			info.Skipped++
+		case result.Success:
+			info.Succeeded++
+		default:
+			info.Failed++
+		}
+	}
+	info.Duration = time.Since(start)
+	r.options.OnEvent(Event{
+		Type:      EventRunFinished,
+		Time:      time.Now(),
+		Completed: int(atomic.LoadInt64(&r.completed)),
+		Total:     r.totalTests(),
+		Run:       info,
+	})
+}
+
+// testEvent returns an event of type typ for a test × provider × repeat.
+func (r *Runner) testEvent(typ EventType, repeat int, test config.TestConfig, prov providers.Provider) Event {
+	return Event{
+		Type:      typ,
+		Time:      time.Now(),
+		Provider:  prov.Name(),
+		Test:      test.Name,
+		TestType:  test.Type,
+		Repeat:    repeat,
+		Completed: int(atomic.LoadInt64(&r.completed)),
+		Total:     r.totalTests(),
+	}
+}
+
+func (r *Runner) emitStarted(repeat int, test config.TestConfig, prov providers.Provider) {
+	if r.options.OnEvent == nil {
+		return
+	}
+	r.options.OnEvent(r.testEvent(EventTestStarted, repeat, test, prov))
+}
+
+// observeHTTP returns ctx with an observer that reports the test's provider
+// HTTP attempts and retries as events.
+func (r *Runner) observeHTTP(ctx context.Context, repeat int, test config.TestConfig, prov providers.Provider) context.Context {
+	if r.options.OnEvent == nil {
+		return ctx
+	}
+	return providers.WithHTTPObserver(ctx, &providers.HTTPObserver{
+		Attempt: func(attempt providers.HTTPAttempt) {
+			e := r.testEvent(EventHTTPAttempt, repeat, test, prov)
+			e.HTTP = &attempt
+			r.options.OnEvent(e)
+		},
+		Retry: func(retry providers.HTTPRetry) {
+			e := r.testEvent(EventRetry, repeat, test, prov)
+			e.Retry = &retry
+			r.options.OnEvent(e)
+		},
+	})
+}
+
+// emitQualityScored reports the scores of a scored result.
+func (r *Runner) emitQualityScored(test config.TestConfig, prov providers.Provider, result *benchmetrics.Result) {
+	if r.options.OnEvent == nil || !result.QualityScored {
+		return
+	}
+	e := r.testEvent(EventQualityScored, result.Repeat, test, prov)
+	e.Quality = &QualityInfo{
+		Score:         result.QualityScore,
+		SemanticScore: result.SemanticScore,
+		RerankerScore: result.RerankerScore,
+		DomainScores:  result.DomainScores,
+		Metrics:       result.RawQualityMetrics,
+	}
+	r.options.OnEvent(e)
+}
+
+// addResult records a finished test and reports it to OnEvent.
+func (r *Runner) addResult(result benchmetrics.Result) {
+	r.collector.AddResult(result)
+	completed := atomic.AddInt64(&r.completed, 1)
+	if r.options.OnEvent == nil {
+		return
+	}
+	r.options.OnEvent(Event{
+		Type:      EventTestCompleted,
+		Time:      time.Now(),
+		Provider:  result.Provider,
+		Test:      result.TestName,
+		TestType:  result.TestType,
+		Repeat:    result.Repeat,
+		Completed: int(completed),
+		Total:     r.totalTests(),
+		Result:    &result,
+	})
+}
+
+// EventLog writes runner events as JSON Lines, one Event per line. It is safe
+// for concurrent use as RunnerOptions.OnEvent.
+type EventLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewEventLog returns an EventLog writing to w.
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{enc: json.NewEncoder(w)}
+}
+
+// Write writes e as one line. After a write error further events are dropped;
+// Err reports the error.
+func (l *EventLog) Write(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return
+	}
+	if err := l.enc.Encode(e); err != nil {
+		l.err = fmt.Errorf("failed to write event: %w", err)
+	}
+}
+
+// Err returns the first write error, if any.
+func (l *EventLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
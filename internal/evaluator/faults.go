@@ -0,0 +1,124 @@
+package evaluator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/faultproxy"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// faultProxies routes each provider's API endpoints through its own
+// fault-injection proxies.
+type faultProxies struct {
+	byProvider map[string][]*faultproxy.Proxy
+	restore    []func()
+}
+
+// startFaultProxies starts one proxy per endpoint of every provider that
+// implements providers.BaseURLOverrider and points the provider at it.
+// Providers without overridable endpoints (such as local) run unproxied.
+func startFaultProxies(cfg faultproxy.Config, provs []providers.Provider) (*faultProxies, error) {
+	f := &faultProxies{byProvider: make(map[string][]*faultproxy.Proxy)}
+	for _, prov := range provs {
+		overrider, ok := prov.(providers.BaseURLOverrider)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(prov.Name()))
+
+		endpoints := overrider.BaseURLs()
+		names := make([]string, 0, len(endpoints))
+		for endpoint := range endpoints {
+			names = append(names, endpoint)
+		}
+		sort.Strings(names)
+
+		for _, endpoint := range names {
+			upstream := endpoints[endpoint]
+			proxy, err := faultproxy.Start(upstream, cfg)
+			if err != nil {
+				f.Close()
+				return nil, fmt.Errorf("failed to proxy %s %s endpoint: %w", prov.Name(), endpoint, err)
+			}
+			if err := overrider.SetBaseURL(endpoint, proxy.URL()); err != nil {
+				_ = proxy.Close()
+				f.Close()
+				return nil, err
+			}
+			f.byProvider[name] = append(f.byProvider[name], proxy)
+			f.restore = append(f.restore, func() {
+				_ = overrider.SetBaseURL(endpoint, upstream)
+				_ = proxy.Close()
+			})
+		}
+	}
+	return f, nil
+}
+
+// Close stops the proxies and restores the providers' original base URLs.
+func (f *faultProxies) Close() {
+	for i := len(f.restore) - 1; i >= 0; i-- {
+		f.restore[i]()
+	}
+	f.restore = nil
+}
+
+// snapshot sums the provider's proxy counters. It returns nil for providers
+// that are not proxied.
+func (f *faultProxies) snapshot(prov providers.Provider) *faultproxy.Stats {
+	if f == nil {
+		return nil
+	}
+	proxies := f.byProvider[strings.ToLower(strings.TrimSpace(prov.Name()))]
+	if len(proxies) == 0 {
+		return nil
+	}
+	total := faultproxy.Stats{Faults: make(map[faultproxy.Fault]int)}
+	for _, proxy := range proxies {
+		stats := proxy.Stats()
+		total.Requests += stats.Requests
+		for fault, n := range stats.Faults {
+			total.Faults[fault] += n
+		}
+	}
+	return &total
+}
+
+// recordFaults attributes the proxy traffic since before to the result.
+// Provider concurrency is 1 in fault mode, so the delta belongs to this test.
+func (r *Runner) recordFaults(prov providers.Provider, before *faultproxy.Stats, result *benchmetrics.Result) {
+	if before == nil {
+		return
+	}
+	after := r.faults.snapshot(prov)
+	if after == nil {
+		return
+	}
+	result.Faults = buildFaultStats(after.Sub(*before), result)
+}
+
+func buildFaultStats(delta faultproxy.Stats, result *benchmetrics.Result) *benchmetrics.FaultStats {
+	stats := &benchmetrics.FaultStats{
+		Attempts: delta.Requests,
+		Injected: make(map[string]int, len(delta.Faults)),
+		Faulted:  delta.Total(),
+	}
+	for fault, n := range delta.Faults {
+		stats.Injected[string(fault)] = n
+	}
+
+	needed := result.RequestCount
+	if needed <= 0 {
+		needed = 1
+	}
+	if delta.Requests > needed {
+		stats.Retries = delta.Requests - needed
+	}
+	if !result.Success {
+		stats.WastedRetries = stats.Retries
+	}
+	return stats
+}
@@ -0,0 +1,56 @@
+package evaluator
+
+import (
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/quality"
+)
+
+// measureLanguage counts texts with a detected language and those matching language.
+func measureLanguage(language string, texts []string) (detected, matched int) {
+	for _, text := range texts {
+		lang, _ := quality.DetectLanguage(text)
+		if lang == "" {
+			continue
+		}
+		detected++
+		if lang == language {
+			matched++
+		}
+	}
+	return detected, matched
+}
+
+// buildLanguageStats detects the language of each returned item for tests
+// tagged with a language. It returns nil for untagged tests.
+func buildLanguageStats(test config.TestConfig, texts []string) *benchmetrics.LanguageStats {
+	language := providers.LanguageCode(test.Language)
+	if language == "" {
+		return nil
+	}
+	detected, matched := measureLanguage(language, texts)
+	return &benchmetrics.LanguageStats{
+		Expected:          language,
+		Items:             len(texts),
+		Detected:          detected,
+		Matched:           matched,
+		HeuristicsSkipped: !quality.SupportsLocale(language),
+	}
+}
+
+func searchItemTexts(results []providers.SearchItem) []string {
+	texts := make([]string, 0, len(results))
+	for _, item := range results {
+		texts = append(texts, item.Title+" "+item.Content)
+	}
+	return texts
+}
+
+func crawledPageTexts(pages []providers.CrawledPage) []string {
+	texts := make([]string, 0, len(pages))
+	for _, page := range pages {
+		texts = append(texts, page.Title+" "+page.Content)
+	}
+	return texts
+}
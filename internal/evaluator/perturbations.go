@@ -0,0 +1,124 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/quality"
+	"github.com/lamim/SanityWebEval/internal/robustness"
+)
+
+var perturbationGenerator = robustness.NewEdgeCaseGenerator()
+
+// runQueryPerturbations runs the configured query variants of a successful
+// search test against the same provider and compares them with the original
+// results. Each variant gets its own timeout.
+func (r *Runner) runQueryPerturbations(ctx context.Context, test config.TestConfig, prov providers.Provider, original []providers.SearchItem, result *benchmetrics.Result) []benchmetrics.PerturbationResult {
+	if test.Type != "search" || len(r.options.Perturbations) == 0 || !result.Success {
+		return nil
+	}
+	variants := perturbationGenerator.GenerateQueryPerturbations(test.Query, r.options.Perturbations...)
+	if len(variants) == 0 {
+		return nil
+	}
+
+	originalURLs := make([]string, 0, len(original))
+	for _, item := range original {
+		originalURLs = append(originalURLs, item.URL)
+	}
+	opts := r.searchOptionsForTest(test)
+
+	out := make([]benchmetrics.PerturbationResult, 0, len(variants))
+	for _, variant := range variants {
+		p := benchmetrics.PerturbationResult{Kind: variant.Name, Query: variant.Query}
+
+		variantCtx, cancel := context.WithTimeout(ctx, r.config.General.TimeoutDuration())
+		startTime := time.Now()
+		searchResult, err := prov.Search(variantCtx, variant.Query, opts)
+		p.Latency = time.Since(startTime)
+		if err != nil {
+			cancel()
+			p.Error = err.Error()
+			out = append(out, p)
+			continue
+		}
+
+		p.Success = true
+		p.ResultsCount = searchResult.TotalResults
+		p.CreditsUsed = searchResult.CreditsUsed
+		p.CostUSD = r.charge(prov, searchResult.CreditsUsed, "search")
+		p.URLOverlap = urlOverlapPct(originalURLs, searchResult.Results)
+		p.QualityScore, p.QualityScored = r.searchQuality(variantCtx, test, searchResult.Results)
+		cancel()
+		if p.QualityScored && result.QualityScored {
+			p.QualityDelta = p.QualityScore - result.QualityScore
+		}
+		out = append(out, p)
+	}
+
+	if r.progress == nil || !r.progress.IsEnabled() {
+		fmt.Printf("  ↻ %s: %d query variants, %.0f%% mean URL overlap\n",
+			prov.Name(), len(out), meanURLOverlap(out))
+	}
+	return out
+}
+
+// searchQuality scores results against the original test so variants are
+// judged by the intent of the original query.
+func (r *Runner) searchQuality(ctx context.Context, test config.TestConfig, results []providers.SearchItem) (float64, bool) {
+	groundTruthScore, groundTruthMetrics := evaluateSearchGroundTruth(test, results)
+	hasGroundTruth := groundTruthMetrics["ground_truth_available"] == float64(1)
+	var modelScore float64
+	hasModelScore := false
+	if r.scorer != nil && len(results) > 0 {
+		qualityScore, err := r.scorer.ScoreSearch(ctx, test.Query, results, quality.SearchScoreOptions{
+			TimeRange: test.TimeRange,
+			Reference: freshnessReference(test),
+		})
+		if err == nil {
+			modelScore = qualityScore.OverallScore
+			hasModelScore = true
+		}
+	}
+	return combineQualityScores(groundTruthScore, hasGroundTruth, modelScore, hasModelScore)
+}
+
+// urlOverlapPct returns the share of original URLs that also appear in results.
+func urlOverlapPct(originalURLs []string, results []providers.SearchItem) float64 {
+	original := make(map[string]struct{}, len(originalURLs))
+	for _, u := range originalURLs {
+		if normalized := normalizeURLForMatch(u); normalized != "" {
+			original[normalized] = struct{}{}
+		}
+	}
+	if len(original) == 0 {
+		return 0
+	}
+	matched := 0
+	for _, item := range results {
+		normalized := normalizeURLForMatch(item.URL)
+		if _, ok := original[normalized]; ok {
+			matched++
+			delete(original, normalized)
+		}
+	}
+	return ratioPct(matched, matched+len(original))
+}
+
+func meanURLOverlap(results []benchmetrics.PerturbationResult) float64 {
+	total, n := 0.0, 0
+	for _, p := range results {
+		if p.Success {
+			total += p.URLOverlap
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / float64(n)
+}
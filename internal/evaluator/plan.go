@@ -0,0 +1,117 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// PlannedProvider describes a provider to plan a run for without creating
+// its client.
+type PlannedProvider struct {
+	Name         string
+	Type         string // provider type; defaults to Name
+	Capabilities providers.CapabilitySet
+}
+
+// PlanStep is one test and provider pairing of a run.
+type PlanStep struct {
+	Test     string
+	TestType string
+	Provider string
+	Support  providers.SupportLevel
+	// Runs is how often the pairing executes: the repeat count, or 0 when it
+	// is skipped for SkipReason.
+	Runs       int
+	SkipReason string
+	// ExclusionReason is set when results are kept out of primary
+	// comparisons, as in benchmetrics.Result.
+	ExclusionReason string
+	// EstimatedUnits and EstimatedCostUSD cover all runs of the pairing; see
+	// benchmetrics.EstimateUnits.
+	EstimatedUnits   int
+	EstimatedCostUSD float64
+}
+
+// Plan returns the test × provider pairings a run of cfg would execute under
+// opts, in config order, with the pairings the capability policy skips and an
+// estimated cost. It calls no provider. Fixture tests a provider cannot reach
+// and query perturbations are not accounted for.
+func Plan(cfg *config.Config, provs []PlannedProvider, opts RunnerOptions) []PlanStep {
+	opts = withDefaults(opts)
+	costs := benchmetrics.NewCostCalculator()
+	if opts.Pricing != nil {
+		costs = benchmetrics.NewCostCalculatorWithProfile(*opts.Pricing)
+	}
+
+	steps := make([]PlanStep, 0, len(cfg.Tests)*len(provs))
+	for _, test := range cfg.Tests {
+		for _, prov := range provs {
+			providerType := prov.Type
+			if providerType == "" {
+				providerType = prov.Name
+			}
+			support := prov.Capabilities.ForOperation(test.Type)
+			step := PlanStep{
+				Test:     test.Name,
+				TestType: test.Type,
+				Provider: prov.Name,
+				Support:  support,
+			}
+			step.ExclusionReason, step.SkipReason = opts.capabilityGate(prov.Name, test.Type, support)
+			if step.SkipReason == "" {
+				step.Runs = opts.Repeats
+				units := benchmetrics.EstimateUnits(providerType, test.Type, crawlPages(test))
+				for run := 0; run < step.Runs; run++ {
+					step.EstimatedUnits += units
+					step.EstimatedCostUSD += costs.Charge(prov.Name, providerType, units, test.Type)
+				}
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// withDefaults fills in the options NewRunner defaults.
+func withDefaults(opts RunnerOptions) RunnerOptions {
+	if opts.Repeats <= 0 {
+		opts.Repeats = 1
+	}
+	if opts.Mode == "" {
+		opts.Mode = providers.ModeNormalized
+	}
+	if opts.CapabilityPolicy == "" {
+		opts.CapabilityPolicy = CapabilityPolicyStrict
+	}
+	return opts
+}
+
+// capabilityGate applies the run mode and capability policy to a provider's
+// support level for a test type. It returns why results are excluded from
+// primary comparisons, if they are, and why the pairing is skipped, if it is.
+func (o RunnerOptions) capabilityGate(provider, testType string, support providers.SupportLevel) (exclusion, skip string) {
+	switch {
+	case support == providers.SupportUnsupported:
+		return "unsupported", fmt.Sprintf("%s provider does not support %s operations", provider, testType)
+	case o.Mode == providers.ModeNormalized && support == providers.SupportEmulated:
+		if o.CapabilityPolicy == CapabilityPolicyStrict {
+			skip = fmt.Sprintf("%s %s operation is emulated and skipped in normalized strict mode", provider, testType)
+		}
+		return "emulated_in_normalized_mode", skip
+	case support != providers.SupportNative:
+		return "not_primary_comparable", ""
+	default:
+		return "", ""
+	}
+}
+
+// crawlPages returns the page limit a crawl test runs with.
+func crawlPages(test config.TestConfig) int {
+	if test.MaxPages != nil {
+		return *test.MaxPages
+	}
+	return providers.DefaultCrawlOptions().MaxPages
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,14 +16,14 @@ import (
 	"github.com/lamim/SanityWebEval/internal/benchmetrics"
 	"github.com/lamim/SanityWebEval/internal/config"
 	"github.com/lamim/SanityWebEval/internal/debug"
+	"github.com/lamim/SanityWebEval/internal/faultproxy"
 	"github.com/lamim/SanityWebEval/internal/progress"
 	"github.com/lamim/SanityWebEval/internal/providers"
 	"github.com/lamim/SanityWebEval/internal/quality"
+	"github.com/lamim/SanityWebEval/internal/robustness"
+	"github.com/lamim/SanityWebEval/internal/tracing"
 )
 
-// costCalculator is used for USD cost calculations
-var costCalculator = benchmetrics.NewCostCalculator()
-
 // Runner executes benchmark tests
 type Runner struct {
 	providers   []providers.Provider
@@ -33,6 +34,10 @@ type Runner struct {
 	debugLogger *debug.Logger
 	scorer      *quality.Scorer
 	options     RunnerOptions
+	fixtures    *fixtureServer
+	faults      *faultProxies
+	costs       *benchmetrics.CostCalculator
+	completed   int64
 }
 
 // CapabilityPolicy defines normalized-mode handling for emulated operations.
@@ -50,6 +55,19 @@ type RunnerOptions struct {
 	Mode             providers.RunMode
 	Repeats          int
 	CapabilityPolicy CapabilityPolicy
+	// Perturbations lists the query variants run after each successful search test.
+	Perturbations []robustness.PerturbationKind
+	// Faults routes provider APIs through fault-injection proxies when set.
+	Faults *faultproxy.Config
+	// Pricing prices provider usage; nil uses the default pay-as-you-go rates.
+	Pricing *benchmetrics.PricingProfile
+	// Tracer records run, test and HTTP attempt spans when set.
+	Tracer *tracing.Tracer
+	// OnEvent, when set, is called with each run event (see EventType). It
+	// may be called from several goroutines at once.
+	OnEvent func(Event)
+	// Slots, when set, limits provider concurrency across runners sharing it.
+	Slots *ProviderSlots
 }
 
 // DefaultRunnerOptions returns production defaults.
@@ -65,16 +83,7 @@ func DefaultRunnerOptions() RunnerOptions {
 func NewRunner(cfg *config.Config, provs []providers.Provider, prog *progress.Manager, debugLog *debug.Logger, scorer *quality.Scorer, opts ...RunnerOptions) *Runner {
 	runnerOptions := DefaultRunnerOptions()
 	if len(opts) > 0 {
-		runnerOptions = opts[0]
-	}
-	if runnerOptions.Repeats <= 0 {
-		runnerOptions.Repeats = 1
-	}
-	if runnerOptions.Mode == "" {
-		runnerOptions.Mode = providers.ModeNormalized
-	}
-	if runnerOptions.CapabilityPolicy == "" {
-		runnerOptions.CapabilityPolicy = CapabilityPolicyStrict
+		runnerOptions = withDefaults(opts[0])
 	}
 	providerSem := make(map[string]chan struct{}, len(provs))
 	for _, prov := range provs {
@@ -87,15 +96,23 @@ func NewRunner(cfg *config.Config, provs []providers.Provider, prog *progress.Ma
 		}
 		providerSem[name] = make(chan struct{}, cfg.General.ConcurrencyForProvider(name))
 	}
+	costs := benchmetrics.NewCostCalculator()
+	if runnerOptions.Pricing != nil {
+		costs = benchmetrics.NewCostCalculatorWithProfile(*runnerOptions.Pricing)
+	}
+	collector := benchmetrics.NewCollector()
+	collector.SetPricingProfile(costs.Profile())
+	collector.SetOutputSidecar(cfg.Capture.Sidecar)
 	return &Runner{
 		providers:   provs,
 		config:      cfg,
 		providerSem: providerSem,
-		collector:   benchmetrics.NewCollector(),
+		collector:   collector,
 		progress:    prog,
 		debugLogger: debugLog,
 		scorer:      scorer,
 		options:     runnerOptions,
+		costs:       costs,
 	}
 }
 
@@ -116,6 +133,11 @@ func (r *Runner) Run(ctx context.Context) error {
 		}
 	}
 
+	ctx, runSpan := r.startRunSpan(ctx)
+	defer runSpan.End()
+	start := time.Now()
+	r.emitRunStarted()
+
 	// Create semaphore for concurrency control.
 	globalLimit := r.config.General.Concurrency
 	if globalLimit <= 0 {
@@ -124,6 +146,28 @@ func (r *Runner) Run(ctx context.Context) error {
 	globalSem := make(chan struct{}, globalLimit)
 	var wg sync.WaitGroup
 
+	if r.config.General.FixturesDir != "" && needsFixtureServer(r.config) {
+		fixtures, err := startFixtureServer(r.config.General.FixturesDir)
+		if err != nil {
+			return err
+		}
+		r.fixtures = fixtures
+		defer func() {
+			fixtures.Close()
+			r.fixtures = nil
+		}()
+	}
+
+	if r.options.Faults != nil {
+		if err := r.startFaults(); err != nil {
+			return err
+		}
+		defer func() {
+			r.faults.Close()
+			r.faults = nil
+		}()
+	}
+
 	// Run tests
 	for repeat := 1; repeat <= r.options.Repeats; repeat++ {
 		for _, test := range r.config.Tests {
@@ -139,7 +183,9 @@ func (r *Runner) Run(ctx context.Context) error {
 
 					globalSem <- struct{}{}
 					providerSem <- struct{}{}
+					release := r.options.Slots.acquire(p.Name())
 					defer func() {
+						release()
 						<-providerSem
 						<-globalSem
 					}()
@@ -151,6 +197,7 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 
 	wg.Wait()
+	r.emitRunFinished(start)
 
 	if r.progress != nil {
 		r.progress.Finish()
@@ -161,13 +208,30 @@ func (r *Runner) Run(ctx context.Context) error {
 	return nil
 }
 
+// charge prices usage against the run's pricing profile.
+func (r *Runner) charge(prov providers.Provider, creditsUsed int, testType string) float64 {
+	return r.costs.Charge(prov.Name(), providers.TypeOf(prov), creditsUsed, testType)
+}
+
+// instanceType returns the provider type of a named instance such as
+// "firecrawl-selfhosted", or "" when the provider runs under its type name.
+func instanceType(prov providers.Provider) string {
+	if typ := providers.TypeOf(prov); typ != prov.Name() {
+		return typ
+	}
+	return ""
+}
+
 func (r *Runner) runTest(ctx context.Context, repeat int, test config.TestConfig, prov providers.Provider) {
+	ctx, span := startTestSpan(ctx, repeat, test, prov)
+	ctx = r.observeHTTP(ctx, repeat, test, prov)
 	capabilities := prov.Capabilities()
 	supportLevel := capabilities.ForOperation(test.Type)
 
 	result := benchmetrics.Result{
 		TestName:            test.Name,
 		Provider:            prov.Name(),
+		ProviderType:        instanceType(prov),
 		TestType:            test.Type,
 		RunMode:             string(r.options.Mode),
 		Repeat:              repeat,
@@ -175,30 +239,36 @@ func (r *Runner) runTest(ctx context.Context, repeat int, test config.TestConfig
 		ExcludedFromPrimary: supportLevel != providers.SupportNative,
 		Timestamp:           time.Now(),
 	}
+	defer func() { endTestSpan(span, &result) }()
 
 	// Check if provider supports this operation type
-	if !capabilities.SupportsOperation(test.Type) {
+	exclusion, skipReason := r.options.capabilityGate(prov.Name(), test.Type, supportLevel)
+	if supportLevel == providers.SupportUnsupported {
 		result.Skipped = true
-		result.SkipReason = fmt.Sprintf("%s provider does not support %s operations", prov.Name(), test.Type)
+		result.SkipReason = skipReason
 		result.ExcludedFromPrimary = true
-		result.ExclusionReason = "unsupported"
+		result.ExclusionReason = exclusion
 		r.completeSkippedResult(prov, test, result)
 		return
 	}
 
-	if r.options.Mode == providers.ModeNormalized && supportLevel == providers.SupportEmulated {
-		result.ExcludedFromPrimary = true
-		result.ExclusionReason = "emulated_in_normalized_mode"
-		if r.options.CapabilityPolicy == CapabilityPolicyStrict {
+	if test.IsFixtureURL() {
+		if skipReason := r.resolveFixture(&test, prov); skipReason != "" {
 			result.Skipped = true
-			result.SkipReason = fmt.Sprintf("%s %s operation is emulated and skipped in normalized strict mode", prov.Name(), test.Type)
+			result.SkipReason = skipReason
+			result.ExcludedFromPrimary = true
+			result.ExclusionReason = "fixture_unreachable"
 			r.completeSkippedResult(prov, test, result)
 			return
 		}
 	}
 
-	if result.ExcludedFromPrimary && result.ExclusionReason == "" {
-		result.ExclusionReason = "not_primary_comparable"
+	result.ExclusionReason = exclusion
+	if skipReason != "" {
+		result.Skipped = true
+		result.SkipReason = skipReason
+		r.completeSkippedResult(prov, test, result)
+		return
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, r.config.General.TimeoutDuration())
@@ -217,21 +287,28 @@ func (r *Runner) runTest(ctx context.Context, repeat int, test config.TestConfig
 
 	// Report test start to progress manager
 	if r.progress != nil {
-		r.progress.StartTest(prov.Name(), test.Name)
+		r.progress.StartTest(prov.Name(), test.Name, repeat)
 	}
+	r.emitStarted(repeat, test, prov)
 
 	if r.progress == nil || !r.progress.IsEnabled() {
 		fmt.Printf("[%s][%s][run %d] Running '%s'...\n", prov.Name(), r.options.Mode, repeat, test.Name)
 	}
 
+	faultsBefore := r.faults.snapshot(prov)
+	var original []providers.SearchItem
 	switch test.Type {
 	case "search":
-		r.runSearchTest(timeoutCtx, test, prov, &result, testLog)
+		original = r.runSearchTest(timeoutCtx, test, prov, &result, testLog)
 	case "extract":
 		r.runExtractTest(timeoutCtx, test, prov, &result, testLog)
 	case "crawl":
 		r.runCrawlTest(timeoutCtx, test, prov, &result, testLog)
+	case "structured_extract":
+		r.runStructuredExtractTest(timeoutCtx, test, prov, supportLevel, &result, testLog)
 	}
+	r.recordFaults(prov, faultsBefore, &result)
+	result.Perturbations = r.runQueryPerturbations(ctx, test, prov, original, &result)
 
 	if testLog != nil && r.debugLogger != nil && r.debugLogger.IsEnabled() {
 		if result.Success {
@@ -243,32 +320,61 @@ func (r *Runner) runTest(ctx context.Context, repeat int, test config.TestConfig
 
 	// Report test completion to progress manager
 	if r.progress != nil {
-		var testErr error
-		if !result.Success {
-			testErr = fmt.Errorf("%s", result.Error)
-		}
-		r.progress.CompleteTest(prov.Name(), test.Name, result.Success, testErr)
+		r.progress.CompleteTest(result)
+	}
+
+	r.emitQualityScored(test, prov, &result)
+	r.addResult(result)
+}
+
+// startFaults puts the fault-injection proxies in front of the providers and
+// limits each provider to one test at a time so proxy traffic can be
+// attributed to the running test.
+func (r *Runner) startFaults() error {
+	faults, err := startFaultProxies(*r.options.Faults, r.providers)
+	if err != nil {
+		return err
 	}
+	r.faults = faults
+	for name := range r.providerSem {
+		r.providerSem[name] = make(chan struct{}, 1)
+	}
+	return nil
+}
 
-	r.collector.AddResult(result)
+// resolveFixture rewrites a fixture:// URL to the local fixture server. It
+// returns a skip reason when the provider cannot reach the server.
+func (r *Runner) resolveFixture(test *config.TestConfig, prov providers.Provider) string {
+	if r.fixtures == nil {
+		return "fixture URL requires general.fixtures_dir"
+	}
+	if !reachesFixtures(prov) {
+		return fmt.Sprintf("%s provider fetches URLs remotely and cannot reach local fixtures", prov.Name())
+	}
+	test.URL = r.fixtures.URL(test.URL)
+	return ""
 }
 
 func (r *Runner) completeSkippedResult(prov providers.Provider, test config.TestConfig, result benchmetrics.Result) {
-	// Report to progress manager (skipped counts as success for progress)
 	if r.progress != nil {
-		r.progress.StartTest(prov.Name(), test.Name)
-		r.progress.CompleteTest(prov.Name(), test.Name, true, nil)
+		r.progress.CompleteTest(result)
 	}
 
 	if r.progress == nil || !r.progress.IsEnabled() {
 		fmt.Printf("[%s] Skipping '%s': %s\n", prov.Name(), test.Name, result.SkipReason)
 	}
 
-	r.collector.AddResult(result)
+	r.addResult(result)
 }
 
-func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov providers.Provider, result *benchmetrics.Result, testLog *debug.TestLog) {
-	opts := r.searchOptionsForMode()
+// runSearchTest runs a search test and returns the results for follow-up
+// comparisons, or nil when the search failed.
+func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov providers.Provider, result *benchmetrics.Result, testLog *debug.TestLog) []providers.SearchItem {
+	opts := r.searchOptionsForTest(test)
+	optionSupport := searchOptionSupportMap(prov, opts)
+	if len(optionSupport) > 0 && r.debugLogger != nil && r.debugLogger.IsEnabled() {
+		r.debugLogger.SetMetadata(testLog, "search_option_support", optionSupport)
+	}
 
 	startTime := time.Now()
 	searchResult, err := prov.Search(ctx, test.Query, opts)
@@ -286,7 +392,7 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 		if r.progress == nil || !r.progress.IsEnabled() {
 			fmt.Printf("  ✗ %s failed: %v\n", prov.Name(), err)
 		}
-		return
+		return nil
 	}
 
 	result.Success = true
@@ -299,7 +405,7 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 		result.RequestCount = 1
 	}
 	result.ResultsCount = searchResult.TotalResults
-	result.CostUSD = costCalculator.CalculateProviderCost(prov.Name(), searchResult.CreditsUsed, "search")
+	result.CostUSD = r.charge(prov, searchResult.CreditsUsed, "search")
 
 	// Log debug info
 	if r.debugLogger != nil && r.debugLogger.IsEnabled() {
@@ -315,6 +421,7 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 		contentLength += len(item.Content)
 	}
 	result.ContentLength = contentLength
+	result.Output = r.captureSearch(test, searchResult.Results)
 
 	// Check expected topics
 	if r.progress == nil || !r.progress.IsEnabled() {
@@ -339,6 +446,9 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 		}
 	}
 
+	result.Freshness = buildFreshnessStats(test, searchResult.Results)
+	result.Language = buildLanguageStats(test, searchItemTexts(searchResult.Results))
+
 	groundTruthScore, groundTruthMetrics := evaluateSearchGroundTruth(test, searchResult.Results)
 	hasGroundTruth := groundTruthMetrics["ground_truth_available"] == float64(1)
 	var modelScore float64
@@ -346,7 +456,10 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 
 	// Perform model-assisted quality scoring if scorer is available
 	if r.scorer != nil && len(searchResult.Results) > 0 {
-		qualityScore, err := r.scorer.ScoreSearch(ctx, test.Query, searchResult.Results)
+		qualityScore, err := r.scorer.ScoreSearch(ctx, test.Query, searchResult.Results, quality.SearchScoreOptions{
+			TimeRange: test.TimeRange,
+			Reference: freshnessReference(test),
+		})
 		if err != nil {
 			if r.debugLogger != nil && r.debugLogger.IsEnabled() {
 				r.debugLogger.LogError(testLog, fmt.Sprintf("quality scoring failed: %v", err), "quality_error", "search quality scoring")
@@ -370,11 +483,16 @@ func (r *Runner) runSearchTest(ctx context.Context, test config.TestConfig, prov
 	result.QualityScore = combined
 	result.QualityScored = scored
 	result.RawQualityMetrics = buildSearchQualityMetricsMap(groundTruthMetrics, hasModelScore, modelScore)
+	if len(optionSupport) > 0 {
+		result.RawQualityMetrics["search_option_support"] = optionSupport
+	}
 
 	if r.debugLogger != nil && r.debugLogger.IsEnabled() {
 		r.debugLogger.SetMetadata(testLog, "quality_score", result.QualityScore)
 		r.debugLogger.SetMetadata(testLog, "quality_scored", result.QualityScored)
 	}
+
+	return searchResult.Results
 }
 
 func (r *Runner) runExtractTest(ctx context.Context, test config.TestConfig, prov providers.Provider, result *benchmetrics.Result, testLog *debug.TestLog) {
@@ -389,6 +507,9 @@ func (r *Runner) runExtractTest(ctx context.Context, test config.TestConfig, pro
 		result.Latency = wallClockLatency
 		result.Error = err.Error()
 		result.ErrorCategory = categorizeError(err)
+		if isDocumentTest(test) {
+			result.Document = buildDocumentStats(test, nil)
+		}
 		if r.debugLogger != nil && r.debugLogger.IsEnabled() {
 			r.debugLogger.LogError(testLog, err.Error(), result.ErrorCategory, "extract execution")
 			r.debugLogger.SetMetadata(testLog, "latency_ms", wallClockLatency.Milliseconds())
@@ -409,7 +530,12 @@ func (r *Runner) runExtractTest(ctx context.Context, test config.TestConfig, pro
 		result.RequestCount = 1
 	}
 	result.ContentLength = len(extractResult.Content)
-	result.CostUSD = costCalculator.CalculateProviderCost(prov.Name(), extractResult.CreditsUsed, "extract")
+	result.CostUSD = r.charge(prov, extractResult.CreditsUsed, "extract")
+	result.Output = r.captureContent(test, extractResult.URL, extractResult.Title, extractResult.Content)
+	if isDocumentTest(test) {
+		result.Document = buildDocumentStats(test, extractResult)
+	}
+	result.Language = buildLanguageStats(test, []string{extractResult.Content})
 
 	// Log debug info
 	if r.debugLogger != nil && r.debugLogger.IsEnabled() {
@@ -444,9 +570,13 @@ func (r *Runner) runExtractTest(ctx context.Context, test config.TestConfig, pro
 
 	// Perform heuristic/model quality scoring if scorer is available
 	if r.scorer != nil {
-		qualityScore := r.scorer.ScoreExtract(extractResult.Content, extractResult.URL, test.ExpectedContent)
+		qualityScore := r.scorer.ScoreExtract(extractResult.Content, extractResult.URL, test.ExpectedContent,
+			quality.ExtractScoreOptions{Language: providers.LanguageCode(test.Language)})
 		modelScore = qualityScore.OverallScore
 		hasModelScore = true
+		if qualityScore.LocaleHeuristicsSkipped {
+			groundTruthMetrics["locale_heuristics_skipped"] = float64(1)
+		}
 		if r.debugLogger != nil && r.debugLogger.IsEnabled() {
 			r.debugLogger.SetMetadata(testLog, "quality_model_score", qualityScore.OverallScore)
 			r.debugLogger.SetMetadata(testLog, "completeness_score", qualityScore.ContentCompleteness)
@@ -503,7 +633,7 @@ func (r *Runner) runCrawlTest(ctx context.Context, test config.TestConfig, prov
 		result.RequestCount = 1
 	}
 	result.ResultsCount = crawlResult.TotalPages
-	result.CostUSD = costCalculator.CalculateProviderCost(prov.Name(), crawlResult.CreditsUsed, "crawl")
+	result.CostUSD = r.charge(prov, crawlResult.CreditsUsed, "crawl")
 
 	// Log debug info
 	if r.debugLogger != nil && r.debugLogger.IsEnabled() {
@@ -521,6 +651,8 @@ func (r *Runner) runCrawlTest(ctx context.Context, test config.TestConfig, prov
 		contentLength += len(page.Content)
 	}
 	result.ContentLength = contentLength
+	result.Language = buildLanguageStats(test, crawledPageTexts(crawlResult.Pages))
+	result.Output = r.captureCrawl(test, crawlResult.Pages)
 
 	if r.progress == nil || !r.progress.IsEnabled() {
 		fmt.Printf("  ✓ %s: %d pages, %d chars, %v latency, $%.4f cost\n",
@@ -555,9 +687,103 @@ func (r *Runner) runCrawlTest(ctx context.Context, test config.TestConfig, prov
 	}
 }
 
+// runStructuredExtractTest calls native schema extraction when the provider has
+// it and otherwise emulates it with a plain extract plus the local field parser.
+func (r *Runner) runStructuredExtractTest(ctx context.Context, test config.TestConfig, prov providers.Provider, supportLevel providers.SupportLevel, result *benchmetrics.Result, testLog *debug.TestLog) {
+	// Schema was validated when the config was loaded.
+	schema, _ := test.JSONSchema()
+
+	startTime := time.Now()
+	extracted, err := structuredExtract(ctx, prov, supportLevel, test.URL, providers.StructuredExtractOptions{
+		Schema: schema,
+		Prompt: test.ExtractionPrompt,
+	})
+	wallClockLatency := time.Since(startTime)
+
+	if err != nil {
+		result.Success = false
+		result.Latency = wallClockLatency
+		result.Error = err.Error()
+		result.ErrorCategory = categorizeError(err)
+		if r.debugLogger != nil && r.debugLogger.IsEnabled() {
+			r.debugLogger.LogError(testLog, err.Error(), result.ErrorCategory, "structured extract execution")
+			r.debugLogger.SetMetadata(testLog, "latency_ms", wallClockLatency.Milliseconds())
+		}
+		if r.progress == nil || !r.progress.IsEnabled() {
+			fmt.Printf("  ✗ %s failed: %v\n", prov.Name(), err)
+		}
+		return
+	}
+
+	result.Success = true
+	result.Latency = wallClockLatency
+	result.ProviderLatency = extracted.Latency
+	result.CreditsUsed = extracted.CreditsUsed
+	result.RequestCount = extracted.RequestCount
+	result.UsageReported = extracted.UsageReported
+	if result.RequestCount <= 0 {
+		result.RequestCount = 1
+	}
+	result.ContentLength = len(extracted.Content)
+	result.ResultsCount = len(extracted.Data)
+	result.CostUSD = r.charge(prov, extracted.CreditsUsed, "structured_extract")
+	result.Output = r.captureContent(test, extracted.URL, "", extracted.Content)
+
+	groundTruthScore, groundTruthMetrics, stats := evaluateStructuredGroundTruth(test, schema, extracted.Data)
+	result.Structured = stats
+	result.QualityScore = groundTruthScore
+	result.QualityScored = true
+	result.RawQualityMetrics = buildExtractQualityMetricsMap(groundTruthMetrics, false, 0)
+	result.RawQualityMetrics["extracted"] = extracted.Data
+
+	if r.debugLogger != nil && r.debugLogger.IsEnabled() {
+		r.debugLogger.SetMetadata(testLog, "url", test.URL)
+		r.debugLogger.SetMetadata(testLog, "latency_ms", wallClockLatency.Milliseconds())
+		r.debugLogger.SetMetadata(testLog, "provider_latency_ms", extracted.Latency.Milliseconds())
+		r.debugLogger.SetMetadata(testLog, "schema_valid", stats.SchemaValid)
+		r.debugLogger.SetMetadata(testLog, "field_accuracy", stats.FieldAccuracy)
+		r.debugLogger.SetMetadata(testLog, "quality_score", result.QualityScore)
+	}
+
+	if r.progress == nil || !r.progress.IsEnabled() {
+		fmt.Printf("  ✓ %s: %d/%d fields matched, schema valid: %t, %v latency\n",
+			prov.Name(), stats.MatchedFields, stats.ExpectedFields, stats.SchemaValid, wallClockLatency.Round(time.Millisecond))
+	}
+}
+
+// structuredExtract dispatches to native schema extraction or the emulated
+// extract-and-parse fallback.
+func structuredExtract(ctx context.Context, prov providers.Provider, supportLevel providers.SupportLevel, url string, opts providers.StructuredExtractOptions) (*providers.StructuredExtractResult, error) {
+	if native, ok := prov.(providers.StructuredExtractor); ok && supportLevel == providers.SupportNative {
+		return native.ExtractStructured(ctx, url, opts)
+	}
+
+	extractResult, err := prov.Extract(ctx, url, providers.DefaultExtractOptions())
+	if err != nil {
+		return nil, err
+	}
+	content := extractResult.Markdown
+	if content == "" {
+		content = extractResult.Content
+	}
+	return &providers.StructuredExtractResult{
+		URL:           url,
+		Data:          providers.ExtractFieldsFromContent(content, opts.Schema),
+		Content:       content,
+		Latency:       extractResult.Latency,
+		CreditsUsed:   extractResult.CreditsUsed,
+		RequestCount:  extractResult.RequestCount,
+		UsageReported: extractResult.UsageReported,
+	}, nil
+}
+
+// searchMaxResults is how many results searches request, and the depth nDCG
+// is computed at.
+const searchMaxResults = 5
+
 func (r *Runner) searchOptionsForMode() providers.SearchOptions {
 	opts := providers.DefaultSearchOptions()
-	opts.MaxResults = 5
+	opts.MaxResults = searchMaxResults
 	switch r.options.Mode {
 	case providers.ModeNative:
 		opts.SearchDepth = "advanced"
@@ -573,6 +799,36 @@ func (r *Runner) searchOptionsForMode() providers.SearchOptions {
 	return opts
 }
 
+// searchOptionsForTest layers a test's search filters over the mode defaults.
+func (r *Runner) searchOptionsForTest(test config.TestConfig) providers.SearchOptions {
+	opts := r.searchOptionsForMode()
+	opts.TimeRange = test.TimeRange
+	if domains := uniqueNonEmptyStrings(test.IncludeDomains); len(domains) > 0 {
+		opts.IncludeDomains = domains
+	}
+	if domains := uniqueNonEmptyStrings(test.ExcludeDomains); len(domains) > 0 {
+		opts.ExcludeDomains = domains
+	}
+	opts.Country = strings.ToLower(strings.TrimSpace(test.Country))
+	opts.Language = strings.TrimSpace(test.Language)
+	opts.SafeSearch = strings.ToLower(strings.TrimSpace(test.SafeSearch))
+	return opts
+}
+
+// searchOptionSupportMap reports the provider's support level for each requested option.
+func searchOptionSupportMap(prov providers.Provider, opts providers.SearchOptions) map[string]string {
+	requested := opts.RequestedOptions()
+	if len(requested) == 0 {
+		return nil
+	}
+	support := providers.SearchRequestOptionSupport(prov, opts)
+	out := make(map[string]string, len(requested))
+	for _, name := range requested {
+		out[name] = string(support.ForOption(name))
+	}
+	return out
+}
+
 func combineQualityScores(groundTruthScore float64, hasGroundTruth bool, modelScore float64, hasModelScore bool) (float64, bool) {
 	switch {
 	case hasGroundTruth && hasModelScore:
@@ -594,11 +850,16 @@ func evaluateSearchGroundTruth(test config.TestConfig, results []providers.Searc
 	expectedTerms := uniqueNonEmptyStrings(append(append([]string{}, test.ExpectedTopics...), test.MustIncludeTerms...))
 	expectedURLs := uniqueNonEmptyStrings(test.ExpectedURLs)
 	forbiddenTerms := uniqueNonEmptyStrings(test.MustNotIncludeTerms)
+	filterScore, filterMetrics, hasFilters := evaluateSearchFilterCompliance(test, results)
+	ndcg, graded := searchNDCG(test.Relevance, results)
 
-	if len(expectedTerms) == 0 && len(expectedURLs) == 0 && len(forbiddenTerms) == 0 {
+	if len(expectedTerms) == 0 && len(expectedURLs) == 0 && len(forbiddenTerms) == 0 && !hasFilters && !graded {
 		return 0, metrics
 	}
 	metrics["ground_truth_available"] = 1
+	for k, v := range filterMetrics {
+		metrics[k] = v
+	}
 
 	allContent := ""
 	for _, item := range results {
@@ -654,9 +915,20 @@ func evaluateSearchGroundTruth(test config.TestConfig, results []providers.Searc
 		scoreComponents = append(scoreComponents, termRecall)
 		weights = append(weights, 0.6)
 	}
-	if len(expectedURLs) > 0 {
+	switch {
+	case len(expectedURLs) > 0 && graded:
+		scoreComponents = append(scoreComponents, (urlRecall+urlPrecision+ndcg)/3)
+		weights = append(weights, 0.4)
+	case len(expectedURLs) > 0:
 		scoreComponents = append(scoreComponents, (urlRecall+urlPrecision)/2)
 		weights = append(weights, 0.4)
+	case graded:
+		scoreComponents = append(scoreComponents, ndcg)
+		weights = append(weights, 0.4)
+	}
+	if hasFilters {
+		scoreComponents = append(scoreComponents, filterScore)
+		weights = append(weights, 0.3)
 	}
 	if len(scoreComponents) == 0 {
 		scoreComponents = append(scoreComponents, 100)
@@ -674,10 +946,181 @@ func evaluateSearchGroundTruth(test config.TestConfig, results []providers.Searc
 	metrics["url_recall"] = urlRecall
 	metrics["url_precision"] = urlPrecision
 	metrics["forbidden_hits"] = float64(forbiddenHits)
+	if graded {
+		metrics["ndcg"] = ndcg
+	}
 
 	return score, metrics
 }
 
+// searchNDCG returns the nDCG of results against graded relevance in percent,
+// at a depth of searchMaxResults, with a gain of 2^grade-1 per result. Results
+// match graded URLs or document IDs as in URL recall, and each is credited
+// once. It reports false when no grade is positive.
+func searchNDCG(relevance map[string]int, results []providers.SearchItem) (float64, bool) {
+	graded := make(map[string]int, len(relevance))
+	for id, grade := range relevance {
+		key := normalizeURLForMatch(id)
+		if grade > 0 && key != "" && grade > graded[key] {
+			graded[key] = grade
+		}
+	}
+	if len(graded) == 0 {
+		return 0, false
+	}
+	keys := make([]string, 0, len(graded))
+	ideal := make([]int, 0, len(graded))
+	for key, grade := range graded {
+		keys = append(keys, key)
+		ideal = append(ideal, grade)
+	}
+	sort.Strings(keys)
+	sort.Sort(sort.Reverse(sort.IntSlice(ideal)))
+
+	gain := func(grade, rank int) float64 {
+		return (math.Pow(2, float64(grade)) - 1) / math.Log2(float64(rank+2))
+	}
+	var dcg, idcg float64
+	for rank := 0; rank < searchMaxResults && rank < len(ideal); rank++ {
+		idcg += gain(ideal[rank], rank)
+	}
+	for rank, item := range results {
+		if rank >= searchMaxResults {
+			break
+		}
+		norm := normalizeURLForMatch(item.URL)
+		if norm == "" {
+			continue
+		}
+		match := norm
+		if _, ok := graded[norm]; !ok {
+			match = ""
+			for _, key := range keys {
+				if _, ok := graded[key]; ok && (strings.Contains(norm, key) || strings.Contains(key, norm)) {
+					match = key
+					break
+				}
+			}
+		}
+		if match != "" {
+			dcg += gain(graded[match], rank)
+			delete(graded, match)
+		}
+	}
+	return dcg / idcg * 100, true
+}
+
+// evaluateSearchFilterCompliance checks results against the test's domain,
+// language and time-range filters. Country and safe search cannot be verified
+// from results; undated results are excluded from the time-range check.
+func evaluateSearchFilterCompliance(test config.TestConfig, results []providers.SearchItem) (float64, map[string]float64, bool) {
+	includeDomains := uniqueNonEmptyStrings(test.IncludeDomains)
+	excludeDomains := uniqueNonEmptyStrings(test.ExcludeDomains)
+	language := providers.LanguageCode(test.Language)
+	window, hasTimeRange := quality.TimeRangeWindow(test.TimeRange)
+	metrics := make(map[string]float64)
+	if len(includeDomains) == 0 && len(excludeDomains) == 0 && language == "" && !hasTimeRange {
+		return 0, metrics, false
+	}
+
+	matchesAny := func(rawURL string, domains []string) bool {
+		for _, domain := range domains {
+			if providers.HostMatchesDomain(rawURL, domain) {
+				return true
+			}
+		}
+		return false
+	}
+
+	parts := make([]float64, 0, 3)
+	if len(excludeDomains) > 0 {
+		hits := 0
+		for _, item := range results {
+			if matchesAny(item.URL, excludeDomains) {
+				hits++
+			}
+		}
+		compliance := 100 - ratioPct(hits, len(results))
+		metrics["excluded_domain_hits"] = float64(hits)
+		metrics["exclude_domain_compliance"] = compliance
+		parts = append(parts, compliance)
+	}
+	if len(includeDomains) > 0 {
+		inside := 0
+		for _, item := range results {
+			if matchesAny(item.URL, includeDomains) {
+				inside++
+			}
+		}
+		compliance := 100.0
+		if len(results) > 0 {
+			compliance = ratioPct(inside, len(results))
+		}
+		metrics["include_domain_compliance"] = compliance
+		parts = append(parts, compliance)
+	}
+	if language != "" {
+		detected, matched := measureLanguage(language, searchItemTexts(results))
+		metrics["language_detected_results"] = float64(detected)
+		metrics["language_undetected_results"] = float64(len(results) - detected)
+		if detected > 0 {
+			matchRate := ratioPct(matched, detected)
+			metrics["language_match_rate"] = matchRate
+			parts = append(parts, matchRate)
+		}
+	}
+	if hasTimeRange {
+		freshness := quality.MeasureFreshness(results, window, freshnessReference(test))
+		metrics["freshness_undated_share"] = freshness.UndatedShare
+		metrics["freshness_dated_results"] = float64(freshness.Dated)
+		if freshness.Dated > 0 {
+			metrics["freshness_compliance"] = freshness.ComplianceRate
+			metrics["freshness_median_age_hours"] = freshness.MedianAge.Hours()
+			parts = append(parts, freshness.ComplianceRate)
+		}
+	}
+	if len(parts) == 0 {
+		return 0, metrics, false
+	}
+
+	total := 0.0
+	for _, part := range parts {
+		total += part
+	}
+	score := clampScore(total / float64(len(parts)))
+	metrics["filter_compliance"] = score
+	return score, metrics, true
+}
+
+// freshnessReference returns the configured reference date or the current time.
+func freshnessReference(test config.TestConfig) time.Time {
+	if ref, ok := test.FreshnessReference(); ok {
+		return ref
+	}
+	return time.Now()
+}
+
+// buildFreshnessStats measures PublishedAt compliance for tests with a time_range.
+func buildFreshnessStats(test config.TestConfig, results []providers.SearchItem) *benchmetrics.FreshnessStats {
+	window, ok := quality.TimeRangeWindow(test.TimeRange)
+	if !ok {
+		return nil
+	}
+	m := quality.MeasureFreshness(results, window, freshnessReference(test))
+	stats := &benchmetrics.FreshnessStats{
+		TimeRange:       test.TimeRange,
+		WindowHours:     window.Hours(),
+		TotalResults:    m.Total,
+		DatedResults:    m.Dated,
+		UndatedResults:  m.Undated,
+		InWindowResults: m.InWindow,
+	}
+	for _, age := range m.Ages {
+		stats.AgesHours = append(stats.AgesHours, math.Round(age.Hours()*10)/10)
+	}
+	return stats
+}
+
 func evaluateExtractGroundTruth(test config.TestConfig, content string) (float64, map[string]float64) {
 	metrics := map[string]float64{
 		"ground_truth_available": 0,
@@ -685,12 +1128,22 @@ func evaluateExtractGroundTruth(test config.TestConfig, content string) (float64
 
 	expectedSnippets := uniqueNonEmptyStrings(append(append([]string{}, test.ExpectedContent...), test.ExpectedSnippets...))
 	forbiddenSnippets := uniqueNonEmptyStrings(append(append([]string{}, test.ForbiddenSnippets...), test.MustNotIncludeTerms...))
+	hasReference := strings.TrimSpace(test.ReferenceText) != ""
 
-	if len(expectedSnippets) == 0 && len(forbiddenSnippets) == 0 {
+	if len(expectedSnippets) == 0 && len(forbiddenSnippets) == 0 && !hasReference {
 		return 0, metrics
 	}
 	metrics["ground_truth_available"] = 1
 
+	var fidelity float64
+	if hasReference {
+		fidelity = quality.TextFidelity(test.ReferenceText, content)
+		metrics["text_fidelity"] = fidelity
+		if len(expectedSnippets) == 0 && len(forbiddenSnippets) == 0 {
+			return clampScore(fidelity), metrics
+		}
+	}
+
 	contentLower := strings.ToLower(content)
 	matched := 0
 	for _, snippet := range expectedSnippets {
@@ -721,6 +1174,11 @@ func evaluateExtractGroundTruth(test config.TestConfig, content string) (float64
 	metrics["forbidden_hits"] = float64(forbiddenHits)
 	metrics["safety_score"] = safety
 
+	if hasReference {
+		// Snippets check key facts; the reference covers the whole text.
+		score = clampScore(weightedAverage([]float64{score, fidelity}, []float64{0.6, 0.4}))
+	}
+
 	return score, metrics
 }
 
@@ -795,6 +1253,42 @@ func evaluateCrawlGroundTruth(test config.TestConfig, crawlResult *providers.Cra
 	return score, metrics
 }
 
+// evaluateStructuredGroundTruth validates extracted data against the schema and
+// scores field-level accuracy against the expected object. Validity weighs 30%
+// and accuracy 70%; without expected values the score is validity alone.
+func evaluateStructuredGroundTruth(test config.TestConfig, schema map[string]interface{}, data map[string]interface{}) (float64, map[string]float64, *benchmetrics.StructuredStats) {
+	schemaErrors := quality.ValidateJSONSchema(data, schema)
+	stats := &benchmetrics.StructuredStats{
+		SchemaValid:  len(schemaErrors) == 0,
+		SchemaErrors: schemaErrors,
+	}
+	validity := 0.0
+	if stats.SchemaValid {
+		validity = 100
+	}
+	metrics := map[string]float64{
+		"ground_truth_available": 1,
+		"schema_valid":           validity / 100,
+		"schema_errors":          float64(len(schemaErrors)),
+		"extracted_fields":       float64(len(data)),
+	}
+
+	if len(test.Expected) == 0 {
+		return validity, metrics, stats
+	}
+
+	fields := quality.ScoreStructuredFields(test.Expected, data)
+	stats.ExpectedFields = fields.ExpectedFields
+	stats.MatchedFields = fields.MatchedFields
+	stats.FieldAccuracy = fields.Accuracy
+	metrics["expected_fields"] = float64(fields.ExpectedFields)
+	metrics["matched_fields"] = float64(fields.MatchedFields)
+	metrics["field_accuracy"] = fields.Accuracy
+
+	score := clampScore(weightedAverage([]float64{validity, fields.Accuracy}, []float64{0.3, 0.7}))
+	return score, metrics, stats
+}
+
 func buildSearchQualityMetricsMap(groundTruthMetrics map[string]float64, hasModelScore bool, modelScore float64) map[string]interface{} {
 	m := make(map[string]interface{}, len(groundTruthMetrics)+2)
 	for k, v := range groundTruthMetrics {
@@ -2,16 +2,27 @@ package evaluator
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/faultproxy"
 	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+	"github.com/lamim/SanityWebEval/internal/quality"
+	"github.com/lamim/SanityWebEval/internal/robustness"
+	"github.com/lamim/SanityWebEval/internal/tracing"
+	"github.com/lamim/SanityWebEval/internal/tracing/tracingtest"
 )
 
 // mockProvider implements providers.Provider for testing
@@ -375,6 +386,81 @@ func TestRun_SearchTest(t *testing.T) {
 	}
 }
 
+func TestRun_SearchCapturesOutput(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Capture: config.CaptureConfig{MaxItems: 2, MaxChars: 5},
+		Tests: []config.TestConfig{
+			{Name: "search-test", Type: "search", Query: "CRISPR off-target", ExpectedURLs: []string{"https://www.nature.com/crispr"}},
+		},
+	}
+
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			return &providers.SearchResult{
+				Query: query,
+				Results: []providers.SearchItem{
+					{Title: "Nature", URL: "https://nature.com/crispr", Content: "CRISPR effects", Score: 0.9},
+					{Title: "Blog", URL: "https://blog.example.com", Content: "short"},
+					{Title: "Wiki", URL: "https://wiki.example.com", Content: "dropped"},
+				},
+				TotalResults: 3,
+				Latency:      100 * time.Millisecond,
+				CreditsUsed:  1,
+			}, nil
+		},
+	}
+
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil)
+	runner.Run(context.Background())
+
+	results := runner.GetCollector().GetResults()
+	if len(results) != 1 || results[0].Output == nil {
+		t.Fatalf("expected a result with output, got %+v", results)
+	}
+	output := results[0].Output
+	if output.Query != "CRISPR off-target" || output.TotalItems != 3 || len(output.Items) != 2 || !output.Truncated {
+		t.Fatalf("expected 2 of 3 items within the limits, got %+v", output)
+	}
+	first := output.Items[0]
+	if first.Rank != 1 || first.Content != "CRISP" || first.ContentLength != 14 || first.Score != 0.9 || !first.Matched {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+	if second := output.Items[1]; second.Rank != 2 || second.Content != "short" || second.Matched {
+		t.Errorf("unexpected second item: %+v", second)
+	}
+	if strings.Join(output.Terms, ",") != "CRISPR,off-target" {
+		t.Errorf("expected query words as terms, got %v", output.Terms)
+	}
+}
+
+func TestRun_CaptureDisabled(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Capture: config.CaptureConfig{Disabled: true},
+		Tests: []config.TestConfig{
+			{Name: "crawl-test", Type: "crawl", URL: "https://example.com"},
+		},
+	}
+
+	runner := NewRunner(cfg, []providers.Provider{&mockProvider{name: "mock"}}, nil, nil, nil)
+	runner.Run(context.Background())
+
+	results := runner.GetCollector().GetResults()
+	if len(results) != 1 || results[0].Output != nil {
+		t.Fatalf("expected no output with capture disabled, got %+v", results)
+	}
+}
+
 func TestRun_ExtractTest(t *testing.T) {
 	cfg := &config.Config{
 		General: config.GeneralConfig{
@@ -707,6 +793,399 @@ func TestRun_SearchResultMetrics(t *testing.T) {
 	}
 }
 
+func TestRun_SearchFiltersPassedAndChecked(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{
+				Name:           "filtered",
+				Type:           "search",
+				Query:          "rust ownership",
+				ExcludeDomains: []string{"reddit.com"},
+				Country:        "DE",
+				Language:       "de-DE",
+				SafeSearch:     "Strict",
+			},
+		},
+	}
+
+	var captured providers.SearchOptions
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			captured = opts
+			return &providers.SearchResult{
+				Query: query,
+				Results: []providers.SearchItem{
+					{URL: "https://doc.rust-lang.org/book/", Title: "Ownership", Content: "Der Besitz ist ein zentrales Konzept und sorgt für die Sicherheit des Speichers, auch ohne Garbage Collector."},
+					{URL: "https://www.reddit.com/r/rust/", Title: "Ownership", Content: "The ownership model is one of the key features of the language and it is checked by the compiler."},
+				},
+				TotalResults: 2,
+				CreditsUsed:  1,
+			}, nil
+		},
+	}
+
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(captured.ExcludeDomains) != 1 || captured.Country != "de" || captured.Language != "de-DE" || captured.SafeSearch != "strict" {
+		t.Fatalf("search filters not passed through: %+v", captured)
+	}
+
+	r := runner.GetCollector().GetResults()[0]
+	if !r.QualityScored {
+		t.Fatal("expected filter compliance to count as ground truth")
+	}
+	if got := r.RawQualityMetrics["excluded_domain_hits"]; got != float64(1) {
+		t.Errorf("expected 1 excluded domain hit, got %v", got)
+	}
+	if got := r.RawQualityMetrics["language_match_rate"]; got != float64(50) {
+		t.Errorf("expected 50%% language match rate, got %v", got)
+	}
+	support, ok := r.RawQualityMetrics["search_option_support"].(map[string]string)
+	if !ok || support[providers.SearchOptionCountry] != string(providers.SupportUnsupported) {
+		t.Errorf("expected unsupported country option for mock provider, got %v", r.RawQualityMetrics["search_option_support"])
+	}
+}
+
+func TestRun_SearchTimeRangeFreshness(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{
+				Name:                   "recent",
+				Type:                   "search",
+				Query:                  "rust release",
+				TimeRange:              "week",
+				FreshnessReferenceDate: "2026-03-10",
+			},
+		},
+	}
+
+	reference := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	fresh := reference.Add(-2 * 24 * time.Hour)
+	stale := reference.Add(-60 * 24 * time.Hour)
+
+	var captured providers.SearchOptions
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			captured = opts
+			return &providers.SearchResult{
+				Query: query,
+				Results: []providers.SearchItem{
+					{URL: "https://blog.rust-lang.org/a", Title: "Rust release", Content: "Rust release notes", PublishedAt: &fresh},
+					{URL: "https://blog.rust-lang.org/b", Title: "Rust release", Content: "Older Rust release notes", PublishedAt: &stale},
+					{URL: "https://example.com/rust", Title: "Rust", Content: "Undated Rust release page"},
+				},
+				TotalResults: 3,
+				CreditsUsed:  1,
+			}, nil
+		},
+	}
+
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if captured.TimeRange != "week" {
+		t.Fatalf("expected time_range to be passed through, got %q", captured.TimeRange)
+	}
+
+	r := runner.GetCollector().GetResults()[0]
+	if r.Freshness == nil {
+		t.Fatal("expected freshness stats for time-range test")
+	}
+	if r.Freshness.TimeRange != "week" || r.Freshness.DatedResults != 2 || r.Freshness.UndatedResults != 1 || r.Freshness.InWindowResults != 1 {
+		t.Fatalf("unexpected freshness stats: %+v", r.Freshness)
+	}
+	if got := r.RawQualityMetrics["freshness_compliance"]; got != float64(50) {
+		t.Errorf("expected 50%% freshness compliance, got %v", got)
+	}
+	if !r.QualityScored {
+		t.Fatal("expected time-range compliance to count as ground truth")
+	}
+}
+
+// structuredMockProvider adds native schema extraction to mockProvider.
+type structuredMockProvider struct {
+	*mockProvider
+	data map[string]interface{}
+}
+
+func (m *structuredMockProvider) Capabilities() providers.CapabilitySet {
+	return providers.CapabilitySet{
+		Search:            providers.SupportNative,
+		Extract:           providers.SupportNative,
+		Crawl:             providers.SupportNative,
+		StructuredExtract: providers.SupportNative,
+	}
+}
+
+func (m *structuredMockProvider) ExtractStructured(ctx context.Context, url string, opts providers.StructuredExtractOptions) (*providers.StructuredExtractResult, error) {
+	return &providers.StructuredExtractResult{URL: url, Data: m.data, CreditsUsed: 5, RequestCount: 1}, nil
+}
+
+func structuredTestConfig(t *testing.T) *config.Config {
+	return &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{
+				Name:   "product",
+				Type:   "structured_extract",
+				URL:    "https://example.com/product",
+				Schema: `{"type":"object","required":["name","price"],"properties":{"name":{"type":"string"},"price":{"type":"number"}}}`,
+				Expected: map[string]interface{}{
+					"name":  "Widget",
+					"price": int64(12),
+				},
+			},
+		},
+	}
+}
+
+func TestRun_StructuredExtractNative(t *testing.T) {
+	mock := &structuredMockProvider{
+		mockProvider: &mockProvider{name: "native"},
+		data:         map[string]interface{}{"name": "Widget", "price": "twelve"},
+	}
+
+	runner := NewRunner(structuredTestConfig(t), []providers.Provider{mock}, nil, nil, nil)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if calls := atomic.LoadInt32(&mock.extractCalls); calls != 0 {
+		t.Fatalf("expected native structured extraction without plain extract, got %d extract calls", calls)
+	}
+	r := runner.GetCollector().GetResults()[0]
+	if !r.Success || r.ImplementationType != string(providers.SupportNative) {
+		t.Fatalf("expected successful native result, got %+v", r)
+	}
+	if r.Structured == nil || r.Structured.SchemaValid || r.Structured.MatchedFields != 1 || r.Structured.ExpectedFields != 2 {
+		t.Fatalf("unexpected structured stats: %+v", r.Structured)
+	}
+	// 30% validity (0) + 70% field accuracy (50).
+	if r.QualityScore != 35 {
+		t.Fatalf("expected quality score 35, got %.1f", r.QualityScore)
+	}
+}
+
+func TestRun_StructuredExtractEmulated(t *testing.T) {
+	mock := &mockProvider{
+		name: "emulated",
+		extractFn: func(ctx context.Context, url string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
+			return &providers.ExtractResult{
+				URL:         url,
+				Markdown:    "# Widget\n\nName: Widget\nPrice: $12.00\n",
+				CreditsUsed: 1,
+			}, nil
+		},
+	}
+
+	runner := NewRunner(structuredTestConfig(t), []providers.Provider{mock}, nil, nil, nil, RunnerOptions{
+		Mode:             providers.ModeNative,
+		Repeats:          1,
+		CapabilityPolicy: CapabilityPolicyStrict,
+	})
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	r := runner.GetCollector().GetResults()[0]
+	if !r.Success || r.ImplementationType != string(providers.SupportEmulated) {
+		t.Fatalf("expected successful emulated result, got %+v", r)
+	}
+	if r.Structured == nil || !r.Structured.SchemaValid || r.Structured.FieldAccuracy != 100 {
+		t.Fatalf("unexpected structured stats: %+v", r.Structured)
+	}
+	if r.QualityScore != 100 {
+		t.Fatalf("expected quality score 100, got %.1f", r.QualityScore)
+	}
+}
+
+func TestRun_StructuredExtractEmulatedSkippedInNormalizedStrict(t *testing.T) {
+	mock := &mockProvider{name: "emulated"}
+	runner := NewRunner(structuredTestConfig(t), []providers.Provider{mock}, nil, nil, nil)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	r := runner.GetCollector().GetResults()[0]
+	if !r.Skipped {
+		t.Fatalf("expected emulated structured extract to be skipped, got %+v", r)
+	}
+	if calls := atomic.LoadInt32(&mock.extractCalls); calls != 0 {
+		t.Fatalf("expected no extract calls, got %d", calls)
+	}
+}
+
+// instanceProvider is a named provider instance of another provider type.
+type instanceProvider struct {
+	*mockProvider
+	typ string
+}
+
+func (p *instanceProvider) Type() string { return p.typ }
+
+func TestReachesFixtures_UsesProviderType(t *testing.T) {
+	for _, tc := range []struct {
+		prov providers.Provider
+		want bool
+	}{
+		{&mockProvider{name: "local"}, true},
+		{&instanceProvider{mockProvider: &mockProvider{name: "local-crawler"}, typ: "local"}, true},
+		{&instanceProvider{mockProvider: &mockProvider{name: "local"}, typ: "firecrawl"}, false},
+		{&mockProvider{name: "remote"}, false},
+	} {
+		if got := reachesFixtures(tc.prov); got != tc.want {
+			t.Errorf("reachesFixtures(%s, type %s) = %v, want %v", tc.prov.Name(), providers.TypeOf(tc.prov), got, tc.want)
+		}
+	}
+}
+
+func TestRun_DocumentExtractFromFixtures(t *testing.T) {
+	fixturesDir := t.TempDir()
+	body := "Plan pricing\n\n| Plan | Price |\n|---|---|\n| Pro | $20 |\n"
+	if err := os.WriteFile(filepath.Join(fixturesDir, "pricing.txt"), []byte(body), 0600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 2,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+			FixturesDir: fixturesDir,
+		},
+		Tests: []config.TestConfig{
+			{
+				Name:           "pricing-text",
+				Type:           "extract",
+				URL:            "fixture://pricing.txt",
+				ReferenceText:  "Plan pricing Plan Price Pro $20",
+				ExpectedTables: intPtr(1),
+			},
+		},
+	}
+
+	local := &mockProvider{
+		name: "local",
+		extractFn: func(ctx context.Context, url string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return nil, err
+			}
+			return &providers.ExtractResult{
+				URL:      url,
+				Content:  string(data),
+				Markdown: string(data),
+				Metadata: map[string]interface{}{"document_type": "text"},
+			}, nil
+		},
+	}
+	remote := &mockProvider{name: "remote"}
+
+	runner := NewRunner(cfg, []providers.Provider{local, remote}, nil, nil, nil)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if atomic.LoadInt32(&remote.extractCalls) != 0 {
+		t.Error("remote provider should not be sent fixture URLs")
+	}
+	for _, r := range runner.GetCollector().GetResults() {
+		switch r.Provider {
+		case "remote":
+			if !r.Skipped || r.ExclusionReason != "fixture_unreachable" {
+				t.Errorf("expected remote provider to be skipped, got %+v", r)
+			}
+		case "local":
+			if !r.Success {
+				t.Fatalf("local extract failed: %s", r.Error)
+			}
+			if r.Document == nil {
+				t.Fatal("expected document stats")
+			}
+			if r.Document.DocumentType != "text" || !r.Document.Handled || r.Document.Tables != 1 {
+				t.Errorf("unexpected document stats: %+v", r.Document)
+			}
+			if r.Document.TextFidelity != 100 || r.QualityScore != 100 {
+				t.Errorf("expected full fidelity, got fidelity=%.1f score=%.1f", r.Document.TextFidelity, r.QualityScore)
+			}
+		}
+	}
+}
+
+func TestEvaluateExtractGroundTruth_ReferenceText(t *testing.T) {
+	test := config.TestConfig{
+		ExpectedContent: []string{"revenue"},
+		ReferenceText:   "revenue grew quickly",
+	}
+	score, metrics := evaluateExtractGroundTruth(test, "revenue grew")
+	if metrics["text_fidelity"] < 79 || metrics["text_fidelity"] > 81 {
+		t.Errorf("text_fidelity = %.2f, want 80", metrics["text_fidelity"])
+	}
+	// 60% snippet score (100) + 40% fidelity (80).
+	if score < 91.9 || score > 92.1 {
+		t.Errorf("score = %.2f, want 92", score)
+	}
+}
+
+func TestEvaluateSearchGroundTruth_GradedRelevance(t *testing.T) {
+	test := config.TestConfig{
+		ExpectedURLs: []string{"https://a.com/x", "D42", "https://b.com/y"},
+		Relevance:    map[string]int{"https://a.com/x": 3, "D42": 2, "https://b.com/y": 1},
+	}
+	results := []providers.SearchItem{
+		{URL: "https://b.com/y"},
+		{URL: "https://example.com/doc/D42"},
+		{URL: "https://a.com/x/"},
+	}
+	_, metrics := evaluateSearchGroundTruth(test, results)
+	// DCG 1 + 3/log2(3) + 7/2 over the ideal 7 + 3/log2(3) + 1/2.
+	if ndcg := metrics["ndcg"]; ndcg < 68 || ndcg > 68.1 {
+		t.Errorf("ndcg = %.2f, want 68.06", ndcg)
+	}
+
+	results[0], results[2] = results[2], results[0]
+	score, metrics := evaluateSearchGroundTruth(test, results)
+	if metrics["ndcg"] != 100 || score != 100 {
+		t.Errorf("ideal ranking: ndcg = %.2f, score = %.2f, want 100", metrics["ndcg"], score)
+	}
+
+	test.Relevance = map[string]int{"https://a.com/x": 0}
+	_, metrics = evaluateSearchGroundTruth(test, results)
+	if _, ok := metrics["ndcg"]; ok {
+		t.Errorf("expected no ndcg without a positive grade, got %v", metrics)
+	}
+}
+
 func TestEnsureOutputDir_Creates(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputDir := tmpDir + "/nested/output"
@@ -941,3 +1420,465 @@ func TestRun_RepeatsExecuteAllRuns(t *testing.T) {
 		t.Fatalf("expected 3 search calls, got %d", mock.searchCalls)
 	}
 }
+
+func TestRun_LanguageTaggedTests(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{Name: "ja-search", Type: "search", Query: "所有権", Language: "ja"},
+			{Name: "ru-extract", Type: "extract", URL: "https://ru.example.com/", Language: "ru"},
+			{Name: "untagged", Type: "search", Query: "ownership"},
+		},
+	}
+
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			return &providers.SearchResult{
+				Query: query,
+				Results: []providers.SearchItem{
+					{URL: "https://ja.example.com/", Title: "所有権", Content: "所有権はRustの最もユニークな機能であり、ガベージコレクタなしでメモリ安全性を保証します。"},
+					{URL: "https://en.example.com/", Title: "Ownership", Content: "The ownership model is one of the key features of the language and it is checked by the compiler."},
+				},
+				TotalResults: 2,
+			}, nil
+		},
+		extractFn: func(ctx context.Context, url string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
+			return &providers.ExtractResult{
+				URL:     url,
+				Content: "# Владение\n\nВладение является уникальной особенностью языка и гарантирует безопасность памяти.",
+			}, nil
+		},
+	}
+
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, quality.NewScorer(nil, nil))
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	for _, r := range runner.GetCollector().GetResults() {
+		switch r.TestName {
+		case "ja-search":
+			if r.Language == nil || r.Language.Expected != "ja" || r.Language.Detected != 2 || r.Language.Matched != 1 {
+				t.Errorf("unexpected search language stats: %+v", r.Language)
+			}
+		case "ru-extract":
+			if r.Language == nil || r.Language.Matched != 1 || !r.Language.HeuristicsSkipped {
+				t.Errorf("unexpected extract language stats: %+v", r.Language)
+			}
+			if r.RawQualityMetrics["locale_heuristics_skipped"] != float64(1) {
+				t.Errorf("expected locale heuristics note, got %v", r.RawQualityMetrics["locale_heuristics_skipped"])
+			}
+		case "untagged":
+			if r.Language != nil {
+				t.Errorf("untagged test should not record language stats: %+v", r.Language)
+			}
+		}
+	}
+
+	summary := runner.GetCollector().ComputeSummary("mock")
+	if summary.LanguageTests != 2 || summary.LanguageMatchRate < 66 || summary.LanguageMatchRate > 67 {
+		t.Errorf("unexpected language summary: tests=%d rate=%.1f", summary.LanguageTests, summary.LanguageMatchRate)
+	}
+}
+
+func TestRun_SearchQueryPerturbations(t *testing.T) {
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 1,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{Name: "rust", Type: "search", Query: "Rust ownership borrowing", ExpectedTopics: []string{"ownership", "borrowing"}},
+		},
+	}
+
+	var queries []string
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			queries = append(queries, query)
+			if query == "rust ownership borrowing" {
+				return nil, fmt.Errorf("rate limited")
+			}
+			items := []providers.SearchItem{
+				{URL: "https://doc.rust-lang.org/book/ch04-00-understanding-ownership.html", Title: "Ownership", Content: "ownership and borrowing"},
+				{URL: "https://example.com/rust", Title: "Rust", Content: "borrowing rules"},
+			}
+			if query != "Rust ownership borrowing" {
+				items = items[:1]
+				items[0].Content = "ownership"
+			}
+			return &providers.SearchResult{Query: query, Results: items, TotalResults: len(items)}, nil
+		},
+	}
+
+	opts := DefaultRunnerOptions()
+	opts.Perturbations = []robustness.PerturbationKind{robustness.PerturbCase, robustness.PerturbReorder}
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil, opts)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(queries) != 3 || queries[0] != "Rust ownership borrowing" {
+		t.Fatalf("expected original query followed by 2 variants, got %q", queries)
+	}
+	r := runner.GetCollector().GetResults()[0]
+	if len(r.Perturbations) != 2 {
+		t.Fatalf("expected 2 perturbation results, got %+v", r.Perturbations)
+	}
+
+	caseVariant, reorder := r.Perturbations[0], r.Perturbations[1]
+	if caseVariant.Kind != "case" || caseVariant.Success || caseVariant.Error == "" {
+		t.Errorf("expected failed case variant, got %+v", caseVariant)
+	}
+	if reorder.Kind != "reorder" || !reorder.Success || reorder.URLOverlap != 50 {
+		t.Errorf("expected reorder variant with 50%% URL overlap, got %+v", reorder)
+	}
+	if !reorder.QualityScored || reorder.QualityDelta >= 0 {
+		t.Errorf("expected lower quality for the variant, got %+v", reorder)
+	}
+
+	summary := runner.GetCollector().ComputeSummary("mock")
+	if summary.PerturbationVariants != 2 || summary.PerturbationSuccessRate != 50 || summary.PerturbationURLOverlap != 50 {
+		t.Errorf("unexpected perturbation summary: %+v", summary)
+	}
+}
+
+// endpointMockProvider issues its searches against an overridable base URL
+// with the shared retry logic.
+type endpointMockProvider struct {
+	*mockProvider
+	baseURL  string
+	retryCfg providers.RetryConfig
+}
+
+func (m *endpointMockProvider) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: m.baseURL}
+}
+
+func (m *endpointMockProvider) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(m.Name(), endpoint)
+	}
+	m.baseURL = baseURL
+	return nil
+}
+
+func (m *endpointMockProvider) Search(ctx context.Context, query string, _ providers.SearchOptions) (*providers.SearchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.baseURL+"/search", nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.retryCfg.DoHTTPRequest(ctx, http.DefaultClient, req); err != nil {
+		return nil, err
+	}
+	return &providers.SearchResult{Query: query, Results: []providers.SearchItem{{URL: "https://example.com"}}, TotalResults: 1, RequestCount: 1}, nil
+}
+
+func TestRun_FaultInjection(t *testing.T) {
+	upstream := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency: 2,
+			Timeout:     "30s",
+			OutputDir:   t.TempDir(),
+		},
+		Tests: []config.TestConfig{
+			{Name: "search-1", Type: "search", Query: "rust"},
+			{Name: "search-2", Type: "search", Query: "go"},
+		},
+	}
+
+	retryCfg := providers.DefaultRetryConfig()
+	retryCfg.MaxRetries = 2
+	retryCfg.InitialBackoff = time.Millisecond
+	retryCfg.MaxBackoff = 5 * time.Millisecond
+	prov := &endpointMockProvider{mockProvider: &mockProvider{name: "flaky"}, baseURL: upstream.URL, retryCfg: retryCfg}
+	local := &mockProvider{name: "local"}
+
+	opts := DefaultRunnerOptions()
+	opts.Faults = &faultproxy.Config{ServerErrorRate: 1}
+	runner := NewRunner(cfg, []providers.Provider{prov, local}, nil, nil, nil, opts)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if prov.baseURL != upstream.URL {
+		t.Errorf("base URL not restored after run: %s", prov.baseURL)
+	}
+	for _, r := range runner.GetCollector().GetResults() {
+		if r.Provider == "local" {
+			if r.Faults != nil {
+				t.Errorf("unproxied provider should not record faults: %+v", r.Faults)
+			}
+			continue
+		}
+		if r.Success || r.Faults == nil {
+			t.Fatalf("expected failed proxied search with fault stats, got %+v", r)
+		}
+		if r.Faults.Attempts != 3 || r.Faults.Faulted != 3 || r.Faults.Retries != 2 || r.Faults.WastedRetries != 2 {
+			t.Errorf("unexpected fault stats for %s: %+v", r.TestName, r.Faults)
+		}
+	}
+
+	summary := runner.GetCollector().ComputeSummary("flaky")
+	if summary.FaultTests != 2 || summary.FaultWastedRetries != 4 || summary.FaultRecoveryRate != 0 {
+		t.Errorf("unexpected fault summary: %+v", summary)
+	}
+}
+
+func TestRun_TracingSpans(t *testing.T) {
+	var calls int32
+	upstream := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	collector := tracingtest.NewCollector(t)
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{Concurrency: 1, Timeout: "30s", OutputDir: t.TempDir()},
+		Tests: []config.TestConfig{
+			{Name: "traced search", Type: "search", Query: "golang"},
+			{Name: "traced extract", Type: "extract", URL: "https://example.com"},
+		},
+	}
+	retry := providers.DefaultRetryConfig()
+	retry.InitialBackoff = time.Millisecond
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL+"/search?api_key=secret", nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := retry.DoHTTPRequestDetailed(ctx, http.DefaultClient, req); err != nil {
+				return nil, err
+			}
+			return &providers.SearchResult{
+				Query:       query,
+				Results:     []providers.SearchItem{{Title: "Go", URL: "https://go.dev", Content: "Go"}},
+				CreditsUsed: 2,
+			}, nil
+		},
+		extractFn: func(ctx context.Context, url string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
+			return nil, errors.New("rate limit exceeded")
+		},
+	}
+
+	opts := DefaultRunnerOptions()
+	opts.Tracer = tracing.New(tracing.Config{Endpoint: collector.URL})
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil, opts)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := opts.Tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	runs := collector.Named("benchmark run")
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run span, got %d", len(runs))
+	}
+	search, extract := collector.Named("test search"), collector.Named("test extract")
+	if len(search) != 1 || len(extract) != 1 {
+		t.Fatalf("expected one span per test, got %d search and %d extract", len(search), len(extract))
+	}
+	if search[0].ParentSpanID != runs[0].SpanID || search[0].TraceID != runs[0].TraceID {
+		t.Error("expected test span to be a child of the run span")
+	}
+	attrs := search[0].Attributes
+	if attrs["bench.provider"] != "mock" || attrs["bench.operation"] != "search" || attrs["bench.credits"] != int64(2) || attrs["bench.success"] != true {
+		t.Errorf("unexpected search span attributes: %v", attrs)
+	}
+	if extract[0].StatusCode != 2 || extract[0].Attributes["bench.error.category"] != "rate_limit" {
+		t.Errorf("expected failed extract span with error category, got status %d, %v", extract[0].StatusCode, extract[0].Attributes)
+	}
+
+	attempts := collector.Named("GET")
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 HTTP attempt spans, got %d", len(attempts))
+	}
+	for _, attempt := range attempts {
+		if attempt.ParentSpanID != search[0].SpanID {
+			t.Error("expected HTTP attempt spans under the test span")
+		}
+		if url, _ := attempt.Attributes["url.full"].(string); url != upstream.URL+"/search" {
+			t.Errorf("expected query-free url.full, got %q", url)
+		}
+	}
+	if attempts[0].Attributes["http.response.status_code"] != int64(503) || attempts[0].StatusCode != 2 {
+		t.Errorf("unexpected first attempt: %+v", attempts[0])
+	}
+	if attempts[1].Attributes["http.request.resend_count"] != int64(1) {
+		t.Errorf("expected resend count on retry, got %v", attempts[1].Attributes)
+	}
+	backoffs := collector.Named("retry backoff")
+	if len(backoffs) != 1 || backoffs[0].Attributes["retry.reason"] != "status" {
+		t.Errorf("expected one status backoff span, got %+v", backoffs)
+	}
+}
+
+func TestRun_EventStream(t *testing.T) {
+	var calls int32
+	upstream := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{Concurrency: 1, Timeout: "30s", OutputDir: t.TempDir()},
+		Tests: []config.TestConfig{
+			{Name: "search", Type: "search", Query: "golang"},
+			{Name: "extract", Type: "extract", URL: "https://example.com", ExpectedContent: []string{"Content"}},
+		},
+	}
+	retry := providers.DefaultRetryConfig()
+	retry.InitialBackoff = time.Millisecond
+	retry.MaxBackoff = time.Millisecond
+	mock := &mockProvider{
+		name: "mock",
+		searchFn: func(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.URL+"/search?key=secret", nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := retry.DoHTTPRequestDetailed(ctx, http.DefaultClient, req); err != nil {
+				return nil, err
+			}
+			return &providers.SearchResult{Query: query, Results: []providers.SearchItem{{URL: "https://go.dev"}}}, nil
+		},
+	}
+
+	var buf strings.Builder
+	log := NewEventLog(&buf)
+	opts := DefaultRunnerOptions()
+	opts.OnEvent = log.Write
+	runner := NewRunner(cfg, []providers.Provider{mock}, nil, nil, nil, opts)
+	if err := runner.Run(context.Background()); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if err := log.Err(); err != nil {
+		t.Fatalf("event log failed: %v", err)
+	}
+
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	// Tests run concurrently, so check the order within each test.
+	byTest := make(map[string][]Event)
+	for _, e := range events[1 : len(events)-1] {
+		byTest[e.Test] = append(byTest[e.Test], e)
+	}
+	for test, want := range map[string]string{
+		"search":  "test_started http_attempt retry http_attempt test_completed",
+		"extract": "test_started quality_scored test_completed",
+	} {
+		var types []string
+		for _, e := range byTest[test] {
+			types = append(types, string(e.Type))
+		}
+		if got := strings.Join(types, " "); got != want {
+			t.Errorf("unexpected %s events:\n got %s\nwant %s", test, got, want)
+		}
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	started, finished := events[0], events[len(events)-1]
+	if started.Type != EventRunStarted || started.Run == nil || started.Run.SchemaVersion != EventSchemaVersion || len(started.Run.Tests) != 2 || started.Total != 2 {
+		t.Errorf("unexpected first event: %+v", started)
+	}
+	if finished.Type != EventRunFinished || finished.Run == nil || finished.Run.Succeeded != 2 || finished.Run.Failed != 0 || finished.Run.Duration <= 0 || finished.Completed != 2 {
+		t.Errorf("unexpected last event: %+v", finished)
+	}
+
+	search, extract := byTest["search"], byTest["extract"]
+	first, second := search[1].HTTP, search[3].HTTP
+	if first == nil || first.Method != http.MethodPost || first.URL != upstream.URL+"/search" || first.StatusCode != http.StatusTooManyRequests || first.Attempt != 1 {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	if second == nil || second.StatusCode != http.StatusOK || second.Attempt != 2 || second.Error != "" {
+		t.Errorf("unexpected second attempt: %+v", second)
+	}
+	if search[1].Provider != "mock" || search[1].Repeat != 1 || search[1].TestType != "search" {
+		t.Errorf("expected attempt events tied to their test, got %+v", search[1])
+	}
+	if r := search[2].Retry; r == nil || r.Retry != 1 || r.Reason != "rate_limit" {
+		t.Errorf("unexpected retry event: %+v", r)
+	}
+	if result := search[4].Result; result == nil || !result.Success || result.TestName != "search" {
+		t.Errorf("unexpected test_completed event: %+v", search[4])
+	}
+	if q, result := extract[1].Quality, extract[2].Result; q == nil || result == nil || q.Score != result.QualityScore {
+		t.Errorf("expected quality_scored to match the result, got %+v", q)
+	}
+}
+
+func TestPlan_MatchesCapabilityPolicy(t *testing.T) {
+	cfg := &config.Config{
+		Tests: []config.TestConfig{
+			{Name: "search", Type: "search", Query: "q"},
+			{Name: "crawl", Type: "crawl", URL: "https://example.com", MaxPages: intPtr(4)},
+		},
+	}
+	provs := []PlannedProvider{
+		{Name: "firecrawl", Capabilities: providers.CapabilitySet{
+			Search: providers.SupportNative, Extract: providers.SupportNative, Crawl: providers.SupportNative,
+		}},
+		{Name: "tavily-eu", Type: "tavily", Capabilities: providers.CapabilitySet{
+			Search: providers.SupportNative, Extract: providers.SupportNative, Crawl: providers.SupportEmulated,
+		}},
+		{Name: "local", Capabilities: providers.CapabilitySet{Extract: providers.SupportNative, Crawl: providers.SupportNative}},
+	}
+
+	steps := Plan(cfg, provs, RunnerOptions{Repeats: 2, CapabilityPolicy: CapabilityPolicyStrict})
+	if len(steps) != 6 {
+		t.Fatalf("expected 6 pairings, got %d", len(steps))
+	}
+	byPair := make(map[string]PlanStep, len(steps))
+	for _, step := range steps {
+		byPair[step.Test+"/"+step.Provider] = step
+	}
+	if s := byPair["search/local"]; s.Runs != 0 || s.ExclusionReason != "unsupported" || s.SkipReason == "" {
+		t.Errorf("expected unsupported search skipped, got %+v", s)
+	}
+	if s := byPair["crawl/tavily-eu"]; s.Runs != 0 || !strings.Contains(s.SkipReason, "normalized strict mode") {
+		t.Errorf("expected emulated crawl skipped under the strict policy, got %+v", s)
+	}
+	// Firecrawl crawls bill a credit per page: 4 pages × 2 repeats at $0.005.
+	if s := byPair["crawl/firecrawl"]; s.Runs != 2 || s.EstimatedUnits != 8 || s.EstimatedCostUSD < 0.0399 || s.EstimatedCostUSD > 0.0401 {
+		t.Errorf("unexpected firecrawl crawl estimate %+v", s)
+	}
+	if s := byPair["search/tavily-eu"]; s.EstimatedCostUSD <= 0 {
+		t.Errorf("expected the instance priced by its type, got %+v", s)
+	}
+
+	steps = Plan(cfg, provs, RunnerOptions{Repeats: 1, CapabilityPolicy: CapabilityPolicyTagged})
+	for _, s := range steps {
+		if s.Test == "crawl" && s.Provider == "tavily-eu" && (s.Runs != 1 || s.ExclusionReason != "emulated_in_normalized_mode") {
+			t.Errorf("expected emulated crawl run but excluded under the tagged policy, got %+v", s)
+		}
+	}
+}
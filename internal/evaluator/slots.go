@@ -0,0 +1,42 @@
+package evaluator
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+)
+
+// ProviderSlots caps concurrent tests per provider across every runner that
+// shares it, so runs executing side by side together stay within the
+// provider concurrency limits of one config.
+type ProviderSlots struct {
+	general config.GeneralConfig
+
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+// NewProviderSlots creates slots sized by general's provider concurrency.
+func NewProviderSlots(general config.GeneralConfig) *ProviderSlots {
+	return &ProviderSlots{general: general, sems: make(map[string]chan struct{})}
+}
+
+// acquire blocks until the provider has a free slot and returns its release
+// function. A nil ProviderSlots imposes no limit.
+func (s *ProviderSlots) acquire(provider string) func() {
+	if s == nil {
+		return func() {}
+	}
+	name := strings.ToLower(strings.TrimSpace(provider))
+	s.mu.Lock()
+	sem, ok := s.sems[name]
+	if !ok {
+		sem = make(chan struct{}, s.general.ConcurrencyForProvider(name))
+		s.sems[name] = sem
+	}
+	s.mu.Unlock()
+
+	sem <- struct{}{}
+	return func() { <-sem }
+}
@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"context"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/tracing"
+)
+
+// startRunSpan begins the root span covering the whole benchmark run.
+func (r *Runner) startRunSpan(ctx context.Context) (context.Context, *tracing.Span) {
+	return r.options.Tracer.Start(ctx, "benchmark run",
+		tracing.Int("bench.tests", len(r.config.Tests)),
+		tracing.Int("bench.providers", len(r.providers)),
+		tracing.String("bench.mode", string(r.options.Mode)),
+		tracing.Int("bench.repeats", r.options.Repeats),
+	)
+}
+
+// startTestSpan begins the span for one test × provider × repeat. Provider
+// HTTP attempts made under the returned context become its children.
+func startTestSpan(ctx context.Context, repeat int, test config.TestConfig, prov providers.Provider) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, "test "+test.Type,
+		tracing.String("bench.provider", prov.Name()),
+		tracing.String("bench.provider.type", providers.TypeOf(prov)),
+		tracing.String("bench.operation", test.Type),
+		tracing.String("bench.test.name", test.Name),
+		tracing.Int("bench.repeat", repeat),
+	)
+}
+
+// endTestSpan records the test outcome on its span and ends it.
+func endTestSpan(span *tracing.Span, result *benchmetrics.Result) {
+	if span == nil {
+		return
+	}
+	span.SetAttributes(
+		tracing.Bool("bench.success", result.Success),
+		tracing.Bool("bench.skipped", result.Skipped),
+		tracing.Int("bench.credits", result.CreditsUsed),
+		tracing.Float64("bench.cost_usd", result.CostUSD),
+		tracing.Int("bench.results", result.ResultsCount),
+	)
+	if result.QualityScored {
+		span.SetAttributes(tracing.Float64("bench.quality.score", result.QualityScore))
+	}
+	switch {
+	case result.Skipped:
+		span.SetAttributes(tracing.String("bench.skip_reason", result.SkipReason))
+	case result.Success:
+		span.SetOK()
+	default:
+		if result.ErrorCategory != "" {
+			span.SetAttributes(tracing.String("bench.error.category", result.ErrorCategory))
+		}
+		span.SetError(result.Error)
+	}
+	span.End()
+}
@@ -0,0 +1,314 @@
+// Package faultproxy provides a local reverse proxy that injects latency,
+// rate limits, server errors, connection resets, truncated bodies and
+// malformed JSON in front of a provider API, to exercise client retry logic.
+package faultproxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault names an injected failure.
+type Fault string
+
+const (
+	// FaultLatency delays the request before it is handled. It combines with the other faults.
+	FaultLatency Fault = "latency"
+	// FaultRateLimit answers 429 with a Retry-After header.
+	FaultRateLimit Fault = "rate_limit"
+	// FaultServerError answers 503.
+	FaultServerError Fault = "server_error"
+	// FaultConnectionReset closes the connection without a response.
+	FaultConnectionReset Fault = "connection_reset"
+	// FaultTruncatedBody forwards the request but cuts the response body short.
+	FaultTruncatedBody Fault = "truncated_body"
+	// FaultMalformedJSON forwards the request and returns a complete but invalid JSON body.
+	FaultMalformedJSON Fault = "malformed_json"
+)
+
+// Faults lists every fault in the order they are reported.
+var Faults = []Fault{FaultLatency, FaultRateLimit, FaultServerError, FaultConnectionReset, FaultTruncatedBody, FaultMalformedJSON}
+
+// Config sets the probability of each fault per request. Latency is drawn
+// independently; the other faults are mutually exclusive, so their rates
+// must sum to at most 1.
+type Config struct {
+	Seed              uint64 // zero picks a random seed
+	LatencyRate       float64
+	Latency           time.Duration
+	RateLimitRate     float64
+	RetryAfter        time.Duration // Retry-After sent with injected 429s
+	ServerErrorRate   float64
+	ResetRate         float64
+	TruncateRate      float64
+	MalformedJSONRate float64
+}
+
+// DefaultConfig returns moderate fault rates: about a third of requests
+// receive a fault and one in ten is delayed.
+func DefaultConfig() Config {
+	return Config{
+		LatencyRate:       0.1,
+		Latency:           500 * time.Millisecond,
+		RateLimitRate:     0.1,
+		RetryAfter:        time.Second,
+		ServerErrorRate:   0.05,
+		ResetRate:         0.05,
+		TruncateRate:      0.05,
+		MalformedJSONRate: 0.05,
+	}
+}
+
+// Validate checks that the rates are probabilities.
+func (c Config) Validate() error {
+	rates := map[string]float64{
+		"latency":        c.LatencyRate,
+		"rate_limit":     c.RateLimitRate,
+		"server_error":   c.ServerErrorRate,
+		"reset":          c.ResetRate,
+		"truncate":       c.TruncateRate,
+		"malformed_json": c.MalformedJSONRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s rate must be between 0 and 1, got %g", name, rate)
+		}
+	}
+	if sum := c.RateLimitRate + c.ServerErrorRate + c.ResetRate + c.TruncateRate + c.MalformedJSONRate; sum > 1 {
+		return fmt.Errorf("rate_limit, server_error, reset, truncate and malformed_json rates must sum to at most 1, got %g", sum)
+	}
+	if c.Latency < 0 || c.RetryAfter < 0 {
+		return fmt.Errorf("latency and retry_after must not be negative")
+	}
+	return nil
+}
+
+// Stats counts requests seen by a proxy.
+type Stats struct {
+	Requests int           `json:"requests"`
+	Faults   map[Fault]int `json:"faults"`
+}
+
+// Total returns the number of requests that received a fault other than latency.
+func (s Stats) Total() int {
+	total := 0
+	for fault, n := range s.Faults {
+		if fault != FaultLatency {
+			total += n
+		}
+	}
+	return total
+}
+
+// Sub returns the counts accumulated since an earlier snapshot.
+func (s Stats) Sub(earlier Stats) Stats {
+	out := Stats{Requests: s.Requests - earlier.Requests, Faults: make(map[Fault]int)}
+	for fault, n := range s.Faults {
+		if d := n - earlier.Faults[fault]; d > 0 {
+			out.Faults[fault] = d
+		}
+	}
+	return out
+}
+
+// Proxy forwards requests to one upstream base URL and injects faults.
+type Proxy struct {
+	cfg      Config
+	listener net.Listener
+	server   *http.Server
+	forward  *httputil.ReverseProxy
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stats Stats
+}
+
+// Start listens on a loopback port and proxies to upstream.
+func Start(upstream string, cfg Config) (*Proxy, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	target, err := url.Parse(upstream)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid upstream URL %q", upstream)
+	}
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start fault proxy: %w", err)
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64() //nolint:gosec // Fault selection does not need a cryptographic source
+	}
+	p := &Proxy{
+		cfg:      cfg,
+		listener: listener,
+		//nolint:gosec // Fault selection does not need a cryptographic source
+		rng:   rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		stats: Stats{Faults: make(map[Fault]int)},
+	}
+	p.forward = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.Host = target.Host
+			// Let the outbound transport negotiate and decode compression so
+			// body faults corrupt the JSON itself, not a gzip stream.
+			pr.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: p.corruptResponse,
+	}
+	p.server = &http.Server{
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() { _ = p.server.Serve(listener) }()
+	return p, nil
+}
+
+// URL returns the proxy's base URL.
+func (p *Proxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Stats returns a snapshot of the request and fault counts.
+func (p *Proxy) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := Stats{Requests: p.stats.Requests, Faults: make(map[Fault]int, len(p.stats.Faults))}
+	for fault, n := range p.stats.Faults {
+		out.Faults[fault] = n
+	}
+	return out
+}
+
+// Close stops the proxy.
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+// faultContextKey carries the body fault chosen for a forwarded request.
+type faultContextKey struct{}
+
+func contextWithFault(r *http.Request, fault Fault) context.Context {
+	return context.WithValue(r.Context(), faultContextKey{}, fault)
+}
+
+// pick draws the faults for one request and records them.
+func (p *Proxy) pick() (delay bool, fault Fault) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Requests++
+
+	if p.cfg.LatencyRate > 0 && p.rng.Float64() < p.cfg.LatencyRate {
+		delay = true
+		p.stats.Faults[FaultLatency]++
+	}
+
+	draw := p.rng.Float64()
+	for _, candidate := range []struct {
+		fault Fault
+		rate  float64
+	}{
+		{FaultRateLimit, p.cfg.RateLimitRate},
+		{FaultServerError, p.cfg.ServerErrorRate},
+		{FaultConnectionReset, p.cfg.ResetRate},
+		{FaultTruncatedBody, p.cfg.TruncateRate},
+		{FaultMalformedJSON, p.cfg.MalformedJSONRate},
+	} {
+		if draw < candidate.rate {
+			p.stats.Faults[candidate.fault]++
+			return delay, candidate.fault
+		}
+		draw -= candidate.rate
+	}
+	return delay, ""
+}
+
+// ServeHTTP injects the drawn fault or forwards the request upstream.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	delay, fault := p.pick()
+	if delay && p.cfg.Latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(p.cfg.Latency):
+		}
+	}
+
+	switch fault {
+	case FaultRateLimit:
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", strconv.Itoa(int(p.cfg.RetryAfter.Round(time.Second)/time.Second)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":"rate limit exceeded (injected)"}`))
+	case FaultServerError:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"service unavailable (injected)"}`))
+	case FaultConnectionReset:
+		resetConnection(w)
+	default:
+		if fault != "" {
+			r = r.WithContext(contextWithFault(r, fault))
+		}
+		p.forward.ServeHTTP(w, r)
+	}
+}
+
+// resetConnection drops the client connection without writing a response.
+// SO_LINGER 0 makes the close send a TCP RST rather than a FIN.
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// corruptResponse applies body faults to a forwarded response.
+func (p *Proxy) corruptResponse(resp *http.Response) error {
+	fault, _ := resp.Request.Context().Value(faultContextKey{}).(Fault)
+	if fault != FaultTruncatedBody && fault != FaultMalformedJSON {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Header.Del("Content-Encoding")
+
+	if fault == FaultTruncatedBody {
+		// Declare the full length but send half: the client sees an unexpected EOF.
+		resp.ContentLength = int64(len(body))
+		resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		resp.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+		return nil
+	}
+
+	malformed := append(body[:len(body)*2/3:len(body)*2/3], []byte(`,"injected":}`)...)
+	resp.ContentLength = int64(len(malformed))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(malformed)))
+	resp.Body = io.NopCloser(bytes.NewReader(malformed))
+	return nil
+}
@@ -0,0 +1,215 @@
+package faultproxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+)
+
+func newUpstream(t *testing.T) *testutil.Server {
+	t.Helper()
+	return testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"path":  r.URL.Path,
+			"query": r.URL.RawQuery,
+			"auth":  r.Header.Get("Authorization"),
+			"pad":   strings.Repeat("x", 200),
+		})
+	}))
+}
+
+func startProxy(t *testing.T, upstream string, cfg Config) *Proxy {
+	t.Helper()
+	p, err := Start(upstream, cfg)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func get(t *testing.T, url string) (*http.Response, []byte, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestProxy_ForwardsWithoutFaults(t *testing.T) {
+	upstream := newUpstream(t)
+	defer upstream.Close()
+	p := startProxy(t, upstream.URL+"/v1", Config{})
+
+	resp, body, err := get(t, p.URL()+"/search?q=rust")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", body, err)
+	}
+	if resp.StatusCode != http.StatusOK || got["path"] != "/v1/search" || got["query"] != "q=rust" || got["auth"] != "Bearer test" {
+		t.Errorf("unexpected forwarded request: status=%d %v", resp.StatusCode, got)
+	}
+	if stats := p.Stats(); stats.Requests != 1 || stats.Total() != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestProxy_InjectsFaults(t *testing.T) {
+	upstream := newUpstream(t)
+	defer upstream.Close()
+
+	tests := []struct {
+		name  string
+		cfg   Config
+		fault Fault
+		check func(t *testing.T, resp *http.Response, body []byte, err error)
+	}{
+		{
+			name:  "rate limit",
+			cfg:   Config{RateLimitRate: 1, RetryAfter: 2 * time.Second},
+			fault: FaultRateLimit,
+			check: func(t *testing.T, resp *http.Response, _ []byte, err error) {
+				if err != nil || resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "2" {
+					t.Errorf("expected 429 with Retry-After 2, got %v %v", resp, err)
+				}
+			},
+		},
+		{
+			name:  "server error",
+			cfg:   Config{ServerErrorRate: 1},
+			fault: FaultServerError,
+			check: func(t *testing.T, resp *http.Response, _ []byte, err error) {
+				if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("expected 503, got %v %v", resp, err)
+				}
+			},
+		},
+		{
+			name:  "connection reset",
+			cfg:   Config{ResetRate: 1},
+			fault: FaultConnectionReset,
+			check: func(t *testing.T, _ *http.Response, _ []byte, err error) {
+				if err == nil {
+					t.Error("expected a connection error")
+				}
+			},
+		},
+		{
+			name:  "truncated body",
+			cfg:   Config{TruncateRate: 1},
+			fault: FaultTruncatedBody,
+			check: func(t *testing.T, _ *http.Response, _ []byte, err error) {
+				if err == nil || !strings.Contains(err.Error(), "unexpected EOF") {
+					t.Errorf("expected unexpected EOF, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "malformed json",
+			cfg:   Config{MalformedJSONRate: 1},
+			fault: FaultMalformedJSON,
+			check: func(t *testing.T, resp *http.Response, body []byte, err error) {
+				if err != nil || resp.StatusCode != http.StatusOK {
+					t.Fatalf("expected complete 200 response, got %v %v", resp, err)
+				}
+				if json.Valid(body) {
+					t.Errorf("expected invalid JSON, got %q", body)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := startProxy(t, upstream.URL, tt.cfg)
+			resp, body, err := get(t, p.URL()+"/search")
+			tt.check(t, resp, body, err)
+			if stats := p.Stats(); stats.Faults[tt.fault] != 1 || stats.Total() != 1 {
+				t.Errorf("expected one %s fault, got %+v", tt.fault, stats)
+			}
+		})
+	}
+}
+
+func TestProxy_SeedMakesFaultsReproducible(t *testing.T) {
+	upstream := newUpstream(t)
+	defer upstream.Close()
+	cfg := Config{Seed: 7, RateLimitRate: 0.3, ServerErrorRate: 0.3}
+
+	sequence := func() []int {
+		p := startProxy(t, upstream.URL, cfg)
+		codes := make([]int, 0, 10)
+		for i := 0; i < 10; i++ {
+			resp, _, err := get(t, p.URL()+"/")
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			codes = append(codes, resp.StatusCode)
+		}
+		return codes
+	}
+	first, second := sequence(), sequence()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("fault sequences differ: %v vs %v", first, second)
+		}
+	}
+}
+
+func TestProxy_RetryConfigRecovers(t *testing.T) {
+	upstream := newUpstream(t)
+	defer upstream.Close()
+	p := startProxy(t, upstream.URL, Config{Seed: 3, ServerErrorRate: 0.5})
+
+	rc := providers.DefaultRetryConfig()
+	rc.MaxRetries = 10
+	rc.InitialBackoff = time.Millisecond
+	rc.MaxBackoff = 5 * time.Millisecond
+
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, p.URL()+"/search", nil)
+		result, err := rc.DoHTTPRequestDetailed(context.Background(), http.DefaultClient, req)
+		if err != nil {
+			t.Fatalf("expected retries to recover, got %v", err)
+		}
+		if !json.Valid(result.Body) {
+			t.Fatalf("expected valid JSON after recovery, got %q", result.Body)
+		}
+	}
+	if stats := p.Stats(); stats.Requests != 5+stats.Faults[FaultServerError] {
+		t.Errorf("every injected 503 should cost one retry: %+v", stats)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("default config invalid: %v", err)
+	}
+	if err := (Config{RateLimitRate: 1.5}).Validate(); err == nil {
+		t.Error("expected error for rate above 1")
+	}
+	if err := (Config{RateLimitRate: 0.6, ServerErrorRate: 0.6}).Validate(); err == nil {
+		t.Error("expected error for exclusive rates summing above 1")
+	}
+	if _, err := Start("not a url", Config{}); err == nil {
+		t.Error("expected error for invalid upstream")
+	}
+}
@@ -0,0 +1,282 @@
+// Package monitor runs the benchmark continuously as a synthetic monitor and
+// exposes the results as Prometheus/OpenMetrics metrics.
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+const namespace = "sanitywebeval"
+
+// Content types served by the metrics handler.
+const (
+	contentTypePrometheus  = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// DefaultLatencyBuckets are the test latency histogram bounds in seconds.
+var DefaultLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// family is a named metric with a fixed label schema.
+type family struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+type series struct {
+	labels []string
+	value  float64
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newFamily(name, help string, typ metricType, labels ...string) *family {
+	return &family{name: name, help: help, typ: typ, labels: labels, series: make(map[string]*series)}
+}
+
+func (f *family) get(values ...string) *series {
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: values}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) add(delta float64, values ...string) { f.get(values...).value += delta }
+
+func (f *family) set(value float64, values ...string) { f.get(values...).value = value }
+
+func (f *family) observe(value float64, values ...string) {
+	s := f.get(values...)
+	for i, bound := range f.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+// Metrics aggregates monitor results for exposition. It is safe for
+// concurrent use.
+type Metrics struct {
+	mu sync.Mutex
+
+	latency       *family
+	tests         *family
+	errors        *family
+	cost          *family
+	credits       *family
+	testSuccess   *family
+	quality       *family
+	cycles        *family
+	lastCycle     *family
+	cycleDuration *family
+}
+
+// NewMetrics creates an empty metric set. Nil or empty buckets use
+// DefaultLatencyBuckets.
+func NewMetrics(buckets []float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	latency := newFamily(namespace+"_test_duration_seconds", "Latency of executed tests.", typeHistogram, "provider", "operation", "outcome")
+	latency.buckets = append([]float64(nil), buckets...)
+	sort.Float64s(latency.buckets)
+	return &Metrics{
+		latency:       latency,
+		tests:         newFamily(namespace+"_tests", "Tests run, by outcome (success, error or skipped).", typeCounter, "provider", "operation", "outcome"),
+		errors:        newFamily(namespace+"_errors", "Failed tests, by error category.", typeCounter, "provider", "operation", "category"),
+		cost:          newFamily(namespace+"_cost_usd", "Estimated provider spend in USD.", typeCounter, "provider", "operation"),
+		credits:       newFamily(namespace+"_credits", "Provider credits consumed.", typeCounter, "provider", "operation"),
+		testSuccess:   newFamily(namespace+"_test_success", "Whether the latest run of a test succeeded (1) or failed (0).", typeGauge, "provider", "test"),
+		quality:       newFamily(namespace+"_quality_score", "Latest quality score (0-100) of a test.", typeGauge, "provider", "test"),
+		cycles:        newFamily(namespace+"_monitor_cycles", "Monitor cycles, by outcome (success or error).", typeCounter, "outcome"),
+		lastCycle:     newFamily(namespace+"_monitor_last_cycle_timestamp_seconds", "Unix time the latest monitor cycle finished.", typeGauge),
+		cycleDuration: newFamily(namespace+"_monitor_last_cycle_duration_seconds", "Duration of the latest monitor cycle.", typeGauge),
+	}
+}
+
+// Observe records the results of one monitor cycle.
+func (m *Metrics) Observe(results []benchmetrics.Result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range results {
+		m.observeResult(r)
+	}
+}
+
+func (m *Metrics) observeResult(r benchmetrics.Result) {
+	if r.Skipped {
+		m.tests.add(1, r.Provider, r.TestType, "skipped")
+		return
+	}
+	outcome := "success"
+	if !r.Success {
+		outcome = "error"
+		category := r.ErrorCategory
+		if category == "" {
+			category = "other"
+		}
+		m.errors.add(1, r.Provider, r.TestType, category)
+	}
+	m.tests.add(1, r.Provider, r.TestType, outcome)
+	m.latency.observe(r.Latency.Seconds(), r.Provider, r.TestType, outcome)
+	m.cost.add(r.CostUSD, r.Provider, r.TestType)
+	m.credits.add(float64(r.CreditsUsed), r.Provider, r.TestType)
+	m.testSuccess.set(boolValue(r.Success), r.Provider, r.TestName)
+	if r.QualityScored {
+		m.quality.set(r.QualityScore, r.Provider, r.TestName)
+	}
+}
+
+// ObserveCycle records a finished monitor cycle.
+func (m *Metrics) ObserveCycle(finished time.Time, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	m.cycles.add(1, outcome)
+	m.lastCycle.set(float64(finished.UnixNano()) / 1e9)
+	m.cycleDuration.set(duration.Seconds())
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Handler serves the metrics, in OpenMetrics format when the scraper asks
+// for it and in the Prometheus text format otherwise.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+		if openMetrics {
+			w.Header().Set("Content-Type", contentTypeOpenMetrics)
+		} else {
+			w.Header().Set("Content-Type", contentTypePrometheus)
+		}
+		_ = m.Write(w, openMetrics)
+	})
+}
+
+// Write writes the metrics in the Prometheus text format, or in OpenMetrics
+// format when openMetrics is set.
+func (m *Metrics) Write(w io.Writer, openMetrics bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bw := bufio.NewWriter(w)
+	for _, f := range []*family{
+		m.latency, m.tests, m.errors, m.cost, m.credits,
+		m.testSuccess, m.quality, m.cycles, m.lastCycle, m.cycleDuration,
+	} {
+		f.write(bw, openMetrics)
+	}
+	if openMetrics {
+		_, _ = bw.WriteString("# EOF\n")
+	}
+	return bw.Flush()
+}
+
+func (f *family) write(w *bufio.Writer, openMetrics bool) {
+	if len(f.series) == 0 {
+		return
+	}
+	// Prometheus text names the counter family after its sample; OpenMetrics
+	// names it without the _total suffix.
+	name := f.name
+	if f.typ == typeCounter && !openMetrics {
+		name += "_total"
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n", name, f.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, f.typ)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := f.series[key]
+		switch f.typ {
+		case typeCounter:
+			writeSample(w, f.name+"_total", f.labels, s.labels, "", "", s.value)
+		case typeGauge:
+			writeSample(w, f.name, f.labels, s.labels, "", "", s.value)
+		case typeHistogram:
+			var cumulative uint64
+			for i, bound := range f.buckets {
+				cumulative += s.counts[i]
+				writeSample(w, f.name+"_bucket", f.labels, s.labels, "le", formatFloat(bound), float64(cumulative))
+			}
+			writeSample(w, f.name+"_bucket", f.labels, s.labels, "le", "+Inf", float64(s.count))
+			writeSample(w, f.name+"_sum", f.labels, s.labels, "", "", s.sum)
+			writeSample(w, f.name+"_count", f.labels, s.labels, "", "", float64(s.count))
+		}
+	}
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	_, _ = w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		pairs := make([]string, 0, len(labels)+1)
+		for i, label := range labels {
+			pairs = append(pairs, label+`="`+escapeLabelValue(values[i])+`"`)
+		}
+		if extraLabel != "" {
+			pairs = append(pairs, extraLabel+`="`+extraValue+`"`)
+		}
+		_, _ = w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	_, _ = w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string { return labelEscaper.Replace(v) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
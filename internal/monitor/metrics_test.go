@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+func TestMetrics_WritePrometheusText(t *testing.T) {
+	m := NewMetrics([]float64{0.5, 1})
+	m.Observe([]benchmetrics.Result{
+		{Provider: "exa", TestName: "Search - Go", TestType: "search", Success: true, Latency: 300 * time.Millisecond, CreditsUsed: 1, CostUSD: 0.005, QualityScore: 82, QualityScored: true},
+		{Provider: "exa", TestName: "Search - Go", TestType: "search", Success: true, Latency: 800 * time.Millisecond, CreditsUsed: 1, CostUSD: 0.005},
+		{Provider: "exa", TestName: "Extract", TestType: "extract", Error: "HTTP 429", ErrorCategory: "rate_limit", Latency: 2 * time.Second},
+		{Provider: "local", TestName: "Search - Go", TestType: "search", Skipped: true},
+	})
+	m.ObserveCycle(time.Unix(1700000000, 0), 3*time.Second, errors.New("boom"))
+
+	var out strings.Builder
+	if err := m.Write(&out, false); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE sanitywebeval_test_duration_seconds histogram\n",
+		`sanitywebeval_test_duration_seconds_bucket{provider="exa",operation="search",outcome="success",le="0.5"} 1`,
+		`sanitywebeval_test_duration_seconds_bucket{provider="exa",operation="search",outcome="success",le="1"} 2`,
+		`sanitywebeval_test_duration_seconds_bucket{provider="exa",operation="extract",outcome="error",le="+Inf"} 1`,
+		`sanitywebeval_test_duration_seconds_sum{provider="exa",operation="search",outcome="success"} 1.1`,
+		`sanitywebeval_test_duration_seconds_count{provider="exa",operation="search",outcome="success"} 2`,
+		"# TYPE sanitywebeval_tests_total counter\n",
+		`sanitywebeval_tests_total{provider="local",operation="search",outcome="skipped"} 1`,
+		`sanitywebeval_errors_total{provider="exa",operation="extract",category="rate_limit"} 1`,
+		`sanitywebeval_cost_usd_total{provider="exa",operation="search"} 0.01`,
+		`sanitywebeval_credits_total{provider="exa",operation="search"} 2`,
+		`sanitywebeval_test_success{provider="exa",test="Extract"} 0`,
+		`sanitywebeval_quality_score{provider="exa",test="Search - Go"} 82`,
+		`sanitywebeval_monitor_cycles_total{outcome="error"} 1`,
+		"sanitywebeval_monitor_last_cycle_timestamp_seconds 1.7e+09",
+		"sanitywebeval_monitor_last_cycle_duration_seconds 3",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "# EOF") {
+		t.Error("Prometheus text format should not end with # EOF")
+	}
+}
+
+func TestMetrics_HandlerNegotiatesOpenMetrics(t *testing.T) {
+	m := NewMetrics(nil)
+	m.Observe([]benchmetrics.Result{{Provider: `odd"name`, TestName: "t", TestType: "search", Success: true}})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "# TYPE sanitywebeval_tests counter\n") || !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("expected OpenMetrics counter family and EOF marker, got:\n%s", body)
+	}
+	if !strings.Contains(body, `provider="odd\"name"`) {
+		t.Errorf("expected escaped label value, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected default content type %q", ct)
+	}
+}
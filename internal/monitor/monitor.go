@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// DefaultInterval is the time between the starts of monitor cycles.
+const DefaultInterval = 5 * time.Minute
+
+// Cycle runs one benchmark pass and returns its results.
+type Cycle func(ctx context.Context) ([]benchmetrics.Result, error)
+
+// Monitor runs a benchmark cycle on an interval and records each cycle's
+// results in Metrics.
+type Monitor struct {
+	Interval time.Duration
+	Cycles   int // stop after this many cycles; 0 runs until the context ends
+	Run      Cycle
+	Metrics  *Metrics
+	// Logf, when set, reports cycle failures.
+	Logf func(format string, args ...interface{})
+}
+
+// Loop runs cycles until the context is cancelled or Cycles have run. The
+// first cycle starts immediately; a cycle that overruns the interval is
+// followed at once by the next. A failed cycle is recorded and does not stop
+// the loop. Loop returns nil when stopped by the context.
+func (m *Monitor) Loop(ctx context.Context) error {
+	if m.Run == nil {
+		return errors.New("monitor has no cycle to run")
+	}
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	metrics := m.Metrics
+	if metrics == nil {
+		metrics = NewMetrics(nil)
+	}
+
+	for cycle := 1; m.Cycles <= 0 || cycle <= m.Cycles; cycle++ {
+		start := time.Now()
+		results, err := m.Run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		metrics.Observe(results)
+		metrics.ObserveCycle(time.Now(), time.Since(start), err)
+		if err != nil && m.Logf != nil {
+			m.Logf("monitor cycle %d failed: %v", cycle, err)
+		}
+		if m.Cycles > 0 && cycle == m.Cycles {
+			break
+		}
+
+		wait := interval - time.Since(start)
+		if wait <= 0 {
+			continue
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+func TestMonitorLoop_RunsCyclesAndKeepsGoingAfterFailure(t *testing.T) {
+	metrics := NewMetrics(nil)
+	var calls int
+	var logged []string
+	m := &Monitor{
+		Interval: time.Millisecond,
+		Cycles:   3,
+		Metrics:  metrics,
+		Run: func(ctx context.Context) ([]benchmetrics.Result, error) {
+			calls++
+			if calls == 2 {
+				return nil, errors.New("provider down")
+			}
+			return []benchmetrics.Result{{Provider: "exa", TestName: "t", TestType: "search", Success: true}}, nil
+		},
+		Logf: func(format string, args ...interface{}) { logged = append(logged, format) },
+	}
+	if err := m.Loop(context.Background()); err != nil {
+		t.Fatalf("Loop failed: %v", err)
+	}
+	if calls != 3 || len(logged) != 1 {
+		t.Fatalf("expected 3 cycles with 1 logged failure, got %d cycles, %d logs", calls, len(logged))
+	}
+
+	var out strings.Builder
+	_ = metrics.Write(&out, false)
+	for _, want := range []string{
+		`sanitywebeval_tests_total{provider="exa",operation="search",outcome="success"} 2`,
+		`sanitywebeval_monitor_cycles_total{outcome="error"} 1`,
+		`sanitywebeval_monitor_cycles_total{outcome="success"} 2`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q", want)
+		}
+	}
+}
+
+func TestMonitorLoop_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	m := &Monitor{
+		Interval: time.Hour,
+		Run: func(ctx context.Context) ([]benchmetrics.Result, error) {
+			calls++
+			cancel()
+			return nil, nil
+		},
+	}
+	done := make(chan error, 1)
+	go func() { done <- m.Loop(ctx) }()
+	select {
+	case err := <-done:
+		if err != nil || calls != 1 {
+			t.Errorf("expected clean stop after 1 cycle, got err=%v calls=%d", err, calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Loop did not stop after cancellation")
+	}
+
+	if err := (&Monitor{}).Loop(context.Background()); err == nil {
+		t.Error("expected error without a cycle function")
+	}
+}
@@ -0,0 +1,261 @@
+// Package notify posts run summaries, golden baseline regressions and
+// assertion violations to webhooks such as Slack or Teams incoming webhooks.
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluation"
+	"github.com/lamim/SanityWebEval/internal/slo"
+)
+
+// Notification is the JSON body posted to webhooks without a template, and
+// the data templates render.
+type Notification struct {
+	Event string    `json:"event"` // run_completed, regression or slo_violation
+	Time  time.Time `json:"time"`
+	// Text is a plain-text summary. Slack and Teams incoming webhooks show
+	// the "text" key, so the default body works with them as is.
+	Text        string                        `json:"text"`
+	OutputDir   string                        `json:"output_dir"`
+	Reports     []string                      `json:"reports"` // report file paths or URLs
+	Providers   []ProviderSummary             `json:"providers"`
+	Regressions []evaluation.RegressionResult `json:"regressions,omitempty"`
+	Violations  []Violation                   `json:"violations,omitempty"`
+}
+
+// ProviderSummary is one provider's results in a run.
+type ProviderSummary struct {
+	Provider      string  `json:"provider"`
+	Tests         int     `json:"tests"`
+	Executed      int     `json:"executed"`
+	SuccessRate   float64 `json:"success_rate"` // percent of executed tests
+	CostUSD       float64 `json:"cost_usd"`
+	QualityScore  float64 `json:"quality_score"` // mean over scored tests, 0-100
+	QualityScored int     `json:"quality_scored"`
+}
+
+// Violation lists the assertions a test × provider violated.
+type Violation struct {
+	Provider string   `json:"provider"`
+	Test     string   `json:"test"`
+	Failures []string `json:"failures"`
+}
+
+// reportFiles are the files linked from notifications when they exist.
+var reportFiles = []string{"report.html", "report.md", "report.json", "results.csv", "results.parquet", "junit.xml"}
+
+// ForRun returns the notifications for a finished run: the run summary, a
+// regression notification when the results regress against the configured
+// golden baseline and an SLO notification when assertions failed. A baseline
+// that cannot be read is reported as an error next to the other
+// notifications.
+func ForRun(cfg *config.Config, collector *benchmetrics.Collector) ([]Notification, error) {
+	results := collector.GetResults()
+	summary := Notification{
+		Event:     config.NotifyRunCompleted,
+		Time:      time.Now(),
+		OutputDir: cfg.General.OutputDir,
+		Reports:   reportLinks(cfg.General.OutputDir, cfg.Notifications.ReportBaseURL),
+	}
+	for _, provider := range collector.GetAllProviders() {
+		s := collector.ComputeSummary(provider)
+		summary.Providers = append(summary.Providers, ProviderSummary{
+			Provider:      provider,
+			Tests:         s.TotalTests,
+			Executed:      s.ExecutedTests,
+			SuccessRate:   s.SuccessRate,
+			CostUSD:       s.TotalCostUSD,
+			QualityScore:  s.AvgQualityScore,
+			QualityScored: s.ScoredTests,
+		})
+	}
+	summary.Text = runText(summary, len(collector.GetAllTests()))
+	notifications := []Notification{summary}
+
+	var err error
+	if cfg.Notifications.Baseline != "" {
+		var regressions []evaluation.RegressionResult
+		regressions, err = DetectRegressions(cfg.Notifications.Baseline, cfg.Notifications.Threshold(), results)
+		if len(regressions) > 0 {
+			n := summary
+			n.Event = config.NotifyRegression
+			n.Regressions = regressions
+			n.Text = regressionText(regressions, n.Reports)
+			notifications = append(notifications, n)
+		}
+	}
+
+	if cfg.HasAssertions() {
+		var violations []Violation
+		cases := slo.Evaluate(cfg, results)
+		for _, c := range cases {
+			if c.Failed() {
+				violations = append(violations, Violation{Provider: c.Provider, Test: c.Test, Failures: c.Failures})
+			}
+		}
+		if len(violations) > 0 {
+			n := summary
+			n.Event = config.NotifySLOViolation
+			n.Violations = violations
+			n.Text = violationText(violations, len(cases), n.Reports)
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, err
+}
+
+// DetectRegressions compares the results with a golden baseline file and
+// returns the regressions, most severe first within each provider.
+func DetectRegressions(baselinePath string, threshold float64, results []benchmetrics.Result) ([]evaluation.RegressionResult, error) {
+	manager := evaluation.NewGoldenManager("", baselinePath)
+	if err := manager.LoadBaseline(); err != nil {
+		return nil, err
+	}
+	var regressions []evaluation.RegressionResult
+	scores := currentScores(results)
+	for _, provider := range sortedKeys(scores) {
+		found := manager.DetectRegressions(provider, scores[provider], threshold)
+		sortRegressions(found)
+		regressions = append(regressions, found...)
+	}
+	return regressions, nil
+}
+
+// currentScores averages the executed results per provider and test into the
+// baseline's shape. Latency counts successful runs only.
+func currentScores(results []benchmetrics.Result) map[string]map[string]evaluation.TestBaseline {
+	type acc struct {
+		runs, succeeded, scored int
+		quality, latencyMs      float64
+	}
+	accs := make(map[string]map[string]*acc)
+	for _, r := range results {
+		if r.Skipped {
+			continue
+		}
+		if accs[r.Provider] == nil {
+			accs[r.Provider] = make(map[string]*acc)
+		}
+		a := accs[r.Provider][r.TestName]
+		if a == nil {
+			a = &acc{}
+			accs[r.Provider][r.TestName] = a
+		}
+		a.runs++
+		if r.Success {
+			a.succeeded++
+			a.latencyMs += float64(r.Latency.Milliseconds())
+		}
+		if r.QualityScored {
+			a.scored++
+			a.quality += r.QualityScore
+		}
+	}
+
+	scores := make(map[string]map[string]evaluation.TestBaseline, len(accs))
+	for provider, tests := range accs {
+		scores[provider] = make(map[string]evaluation.TestBaseline, len(tests))
+		for name, a := range tests {
+			score := evaluation.TestBaseline{TestName: name, SuccessRate: float64(a.succeeded) / float64(a.runs)}
+			if a.succeeded > 0 {
+				score.LatencyMs = a.latencyMs / float64(a.succeeded)
+			}
+			if a.scored > 0 {
+				score.QualityScore = a.quality / float64(a.scored)
+			}
+			scores[provider][name] = score
+		}
+	}
+	return scores
+}
+
+// reportLinks returns the report files written to outputDir, as URLs under
+// baseURL when it is set. baseURL points at the configured output directory,
+// so links include the run's timestamped directory.
+func reportLinks(outputDir, baseURL string) []string {
+	var links []string
+	for _, name := range reportFiles {
+		file := filepath.Join(outputDir, name)
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		if baseURL == "" {
+			links = append(links, file)
+			continue
+		}
+		links = append(links, strings.TrimRight(baseURL, "/")+"/"+path.Join(filepath.Base(outputDir), name))
+	}
+	return links
+}
+
+func runText(n Notification, tests int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Benchmark run finished: %d tests against %d providers", tests, len(n.Providers))
+	for _, p := range n.Providers {
+		fmt.Fprintf(&sb, "\n• %s: %.1f%% success, %d of %d tests executed, $%.4f", p.Provider, p.SuccessRate, p.Executed, p.Tests, p.CostUSD)
+		if p.QualityScored > 0 {
+			fmt.Fprintf(&sb, ", quality %.1f", p.QualityScore)
+		}
+	}
+	writeReports(&sb, n.Reports)
+	return sb.String()
+}
+
+func regressionText(regressions []evaluation.RegressionResult, reports []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d regressions against the golden baseline", len(regressions))
+	for _, r := range regressions {
+		fmt.Fprintf(&sb, "\n• [%s] %s / %s: %s %.2f → %.2f (%+.1f%%)",
+			r.Severity, r.Provider, r.TestName, r.Metric, r.BaselineValue, r.CurrentValue, r.ChangePercent)
+	}
+	writeReports(&sb, reports)
+	return sb.String()
+}
+
+func violationText(violations []Violation, cases int, reports []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Assertions failed for %d of %d test/provider pairs", len(violations), cases)
+	for _, v := range violations {
+		fmt.Fprintf(&sb, "\n• [%s] %s: %s", v.Provider, v.Test, strings.Join(v.Failures, "; "))
+	}
+	writeReports(&sb, reports)
+	return sb.String()
+}
+
+func writeReports(sb *strings.Builder, reports []string) {
+	if len(reports) > 0 {
+		sb.WriteString("\nReports: " + strings.Join(reports, " "))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortRegressions orders critical regressions first, then by test and metric.
+func sortRegressions(regressions []evaluation.RegressionResult) {
+	sort.SliceStable(regressions, func(i, j int) bool {
+		a, b := regressions[i], regressions[j]
+		if (a.Severity == "critical") != (b.Severity == "critical") {
+			return a.Severity == "critical"
+		}
+		if a.TestName != b.TestName {
+			return a.TestName < b.TestName
+		}
+		return a.Metric < b.Metric
+	})
+}
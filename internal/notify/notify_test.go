@@ -0,0 +1,200 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluation"
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+)
+
+func testRun(t *testing.T) (*config.Config, *benchmetrics.Collector) {
+	t.Helper()
+	dir := t.TempDir()
+	baseline := evaluation.BaselineScores{ProviderScores: map[string]evaluation.ProviderBaseline{
+		"exa": {Provider: "exa", TestScores: map[string]evaluation.TestBaseline{
+			"Search": {TestName: "Search", QualityScore: 80, LatencyMs: 500, SuccessRate: 1},
+		}},
+	}}
+	data, err := json.Marshal(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baselinePath := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(baselinePath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(dir, "2026-10-18_12-00-00")
+	if err := os.MkdirAll(outputDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "report.html"), []byte("<html>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Parse([]byte(`
+[general]
+output_dir = "`+filepath.ToSlash(outputDir)+`"
+
+[notifications]
+baseline = "`+filepath.ToSlash(baselinePath)+`"
+report_base_url = "https://ci.example.com/results/"
+
+[[notifications.webhooks]]
+url = "http://127.0.0.1:1"
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+
+[tests.assertions]
+max_latency_ms = 1000
+`), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	collector := benchmetrics.NewCollector()
+	collector.AddResult(benchmetrics.Result{TestName: "Search", TestType: "search", Provider: "exa", Success: true,
+		Latency: 1500 * time.Millisecond, QualityScore: 60, QualityScored: true, CostUSD: 0.005})
+	collector.AddResult(benchmetrics.Result{TestName: "Search", TestType: "search", Provider: "tavily", Success: true,
+		Latency: 300 * time.Millisecond, CostUSD: 0.008})
+	return cfg, collector
+}
+
+func TestForRun(t *testing.T) {
+	cfg, collector := testRun(t)
+	notifications, err := ForRun(cfg, collector)
+	if err != nil {
+		t.Fatalf("ForRun failed: %v", err)
+	}
+	if len(notifications) != 3 {
+		t.Fatalf("expected summary, regression and SLO notifications, got %d", len(notifications))
+	}
+
+	summary := notifications[0]
+	if summary.Event != config.NotifyRunCompleted || len(summary.Providers) != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if exa := summary.Providers[0]; exa.Provider != "exa" || exa.SuccessRate != 100 || exa.CostUSD != 0.005 || exa.QualityScore != 60 {
+		t.Errorf("unexpected provider summary: %+v", exa)
+	}
+	if len(summary.Reports) != 1 || summary.Reports[0] != "https://ci.example.com/results/2026-10-18_12-00-00/report.html" {
+		t.Errorf("expected report links under the base URL, got %v", summary.Reports)
+	}
+	if !strings.Contains(summary.Text, "• exa: 100.0% success") || !strings.Contains(summary.Text, "quality 60.0") {
+		t.Errorf("unexpected summary text:\n%s", summary.Text)
+	}
+
+	regression := notifications[1]
+	if regression.Event != config.NotifyRegression || len(regression.Regressions) != 2 {
+		t.Fatalf("expected quality and latency regressions, got %+v", regression.Regressions)
+	}
+	if r := regression.Regressions[1]; r.Provider != "exa" || r.Severity != "critical" || r.Metric != "quality" || r.CurrentValue != 60 {
+		t.Errorf("unexpected quality regression: %+v", r)
+	}
+	if !strings.Contains(regression.Text, "[critical] exa / Search: latency 500.00 → 1500.00 (+200.0%)") {
+		t.Errorf("unexpected regression text:\n%s", regression.Text)
+	}
+
+	violation := notifications[2]
+	if violation.Event != config.NotifySLOViolation || len(violation.Violations) != 1 || violation.Violations[0].Provider != "exa" {
+		t.Errorf("unexpected SLO notification: %+v", violation.Violations)
+	}
+	if !strings.HasPrefix(violation.Text, "Assertions failed for 1 of 2 test/provider pairs") {
+		t.Errorf("unexpected SLO text:\n%s", violation.Text)
+	}
+}
+
+func TestSender_RetriesAndTemplates(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		bodies   []string
+		failures int32 = 2
+	)
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/flaky" && atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, r.URL.Path+" "+r.Header.Get("X-Token")+" "+string(body))
+		mu.Unlock()
+	}))
+
+	retries := 2
+	sender, err := NewSender(config.NotificationsConfig{
+		Retries: &retries,
+		Backoff: "1ms",
+		Webhooks: []config.WebhookConfig{
+			{URL: server.URL + "/flaky"},
+			{URL: server.URL + "/slack", Template: `{"text": {{json .Text}}}`, Headers: map[string]string{"X-Token": "t"}},
+			{URL: server.URL + "/regressions", Events: []string{config.NotifyRegression}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSender failed: %v", err)
+	}
+	n := Notification{Event: config.NotifyRunCompleted, Text: "Benchmark \"run\" finished"}
+	if err := sender.Send(context.Background(), n); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected the run_completed subscribers to get one post each, got %q", bodies)
+	}
+	var posted Notification
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(bodies[0], "/flaky  ")), &posted); err != nil || posted.Text != n.Text {
+		t.Errorf("expected the notification as JSON after retries, got %q (%v)", bodies[0], err)
+	}
+	if bodies[1] != `/slack t {"text": "Benchmark \"run\" finished"}` {
+		t.Errorf("unexpected templated body: %q", bodies[1])
+	}
+}
+
+func TestSender_Failures(t *testing.T) {
+	var calls int32
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/bad" {
+			http.Error(w, "no such channel", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	if _, err := NewSender(config.NotificationsConfig{Webhooks: []config.WebhookConfig{{URL: server.URL, Template: "{{.Nope"}}}); err == nil {
+		t.Error("expected a template parse error")
+	}
+
+	retries := 1
+	sender, err := NewSender(config.NotificationsConfig{
+		Retries:  &retries,
+		Backoff:  "1ms",
+		Webhooks: []config.WebhookConfig{{URL: server.URL + "/bad"}, {URL: server.URL + "/down"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSender failed: %v", err)
+	}
+	err = sender.Send(context.Background(), Notification{Event: config.NotifyRunCompleted})
+	if err == nil || !strings.Contains(err.Error(), "webhook 1: webhook returned status 404: no such channel") ||
+		!strings.Contains(err.Error(), "webhook 2: max retries (1) exceeded") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected no retry for 404 and one retry for 502, got %d calls", got)
+	}
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+)
+
+// templateFuncs are available to webhook templates. json renders a value as
+// JSON, e.g. {"text": {{json .Text}}}.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// Sender posts notifications to the configured webhooks.
+type Sender struct {
+	cfg       config.NotificationsConfig
+	templates []*template.Template // per webhook; nil posts the notification as JSON
+	client    *http.Client
+}
+
+// NewSender returns a Sender for cfg. It fails when a webhook template does
+// not parse.
+func NewSender(cfg config.NotificationsConfig) (*Sender, error) {
+	s := &Sender{
+		cfg:       cfg,
+		templates: make([]*template.Template, len(cfg.Webhooks)),
+		client:    &http.Client{Timeout: cfg.TimeoutDuration()},
+	}
+	for i, hook := range cfg.Webhooks {
+		if hook.Template == "" {
+			continue
+		}
+		tmpl, err := template.New(fmt.Sprintf("webhook %d", i+1)).Funcs(templateFuncs).Parse(hook.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifications webhook template: %w", err)
+		}
+		s.templates[i] = tmpl
+	}
+	return s, nil
+}
+
+// Send posts n to every webhook subscribed to its event and returns the
+// errors of the webhooks that could not be notified.
+func (s *Sender) Send(ctx context.Context, n Notification) error {
+	var errs []error
+	for i, hook := range s.cfg.Webhooks {
+		if !hook.Wants(n.Event) {
+			continue
+		}
+		body, err := s.render(i, n)
+		if err == nil {
+			err = s.post(ctx, hook, body)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook %d: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sender) render(i int, n Notification) ([]byte, error) {
+	if s.templates[i] == nil {
+		return json.Marshal(n)
+	}
+	var buf bytes.Buffer
+	if err := s.templates[i].Execute(&buf, n); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// post sends body, retrying network errors, 429 and 5xx responses with
+// exponential backoff.
+func (s *Sender) post(ctx context.Context, hook config.WebhookConfig, body []byte) error {
+	endpoint := hook.Endpoint()
+	if endpoint == "" {
+		return fmt.Errorf("%s is not set", hook.URLEnv)
+	}
+	backoff := s.cfg.BackoffDuration()
+	var lastErr error
+	for attempt := 0; attempt <= s.cfg.RetryCount(); attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("context cancelled during retry: %w", ctx.Err())
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+
+		retryable, err := s.attempt(ctx, endpoint, hook.Headers, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			return err
+		}
+	}
+	return fmt.Errorf("max retries (%d) exceeded: %w", s.cfg.RetryCount(), lastErr)
+}
+
+// attempt posts body once and reports whether a failure is worth retrying.
+func (s *Sender) attempt(ctx context.Context, endpoint string, headers map[string]string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SanityWebEval")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req) //nolint:gosec // URL comes from the user's notifications config
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+}
@@ -1,16 +1,21 @@
-// Package progress provides a terminal progress bar and status display
-// that stays pinned at the bottom of the screen during benchmark execution.
+// Package progress provides the live terminal dashboard shown while a
+// benchmark runs: one row per provider with counts, rolling latency
+// percentiles, spend and errors by category, plus the tests in flight.
 package progress
 
 import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
 	"github.com/schollz/progressbar/v3"
+	"golang.org/x/term"
 )
 
 // TestStatus represents the current state of a test
@@ -31,54 +36,100 @@ const (
 type RunningTest struct {
 	Name      string
 	Provider  string
+	Repeat    int
 	StartTime time.Time
 	Status    TestStatus
 }
 
-// Manager handles the progress display
+const (
+	// refreshInterval is how often the dashboard redraws, so elapsed times
+	// keep moving between test events.
+	refreshInterval = 250 * time.Millisecond
+	// latencyWindow is how many recent latencies the rolling percentiles use.
+	latencyWindow = 100
+	maxInFlight   = 10
+	maxErrors     = 5
+	maxLogLines   = 5
+)
+
+// providerStats holds one provider's dashboard row.
+type providerStats struct {
+	completed int
+	failed    int
+	skipped   int
+	latencies []time.Duration // the last latencyWindow latencies
+	spend     float64
+	errors    map[string]int // by category
+}
+
+type errorEntry struct {
+	at       time.Time
+	provider string
+	test     string
+	category string
+	message  string
+}
+
+// Manager tracks test progress and, when enabled, draws the dashboard on
+// the terminal's alternate screen.
 type Manager struct {
-	enabled       bool
-	totalTests    int
-	completed     int
-	passed        int
-	failed        int
-	runningTests  map[string]*RunningTest // key: "provider:testName"
-	mu            sync.RWMutex
-	bar           *progressbar.ProgressBar
-	startTime     time.Time
-	showProviders []string
+	enabled    bool
+	out        io.Writer
+	width      int
+	height     int
+	totalTests int
+	completed  int
+	passed     int
+	failed     int
+	skipped    int
+	providers  []string
+	stats      map[string]*providerStats
+	running    map[string]*RunningTest // key: provider/test/repeat
+	errors     []errorEntry            // most recent last
+	logs       []string                // most recent last
+	bar        *progressbar.ProgressBar
+	startTime  time.Time
+	onScreen   bool // the alternate screen is active
+	stop       chan struct{}
+	done       chan struct{}
+	finishOnce sync.Once
+	mu         sync.Mutex
 }
 
-// NewManager creates a new progress manager
+// NewManager creates a progress manager. When enabled it redraws the
+// dashboard on stdout until Finish; otherwise it only tracks state and
+// callers print plain lines.
 func NewManager(totalTests int, providers []string, enabled bool) *Manager {
-	m := &Manager{
-		enabled:       enabled,
-		totalTests:    totalTests,
-		runningTests:  make(map[string]*RunningTest),
-		startTime:     time.Now(),
-		showProviders: providers,
-	}
-
+	width, height := terminalSize()
+	m := newManager(totalTests, providers, enabled, os.Stdout, width, height)
 	if enabled {
-		m.setupProgressBar()
+		go m.refresh()
 	}
-
 	return m
 }
 
-// setupProgressBar initializes the progress bar
-func (m *Manager) setupProgressBar() {
-	// Custom template for our progress bar
-	barTemplate := `{{printf "%-30s" .Description}} {{bar . }} {{counters . }} {{percent . }} {{rtime . "ETA: ~%s remaining"}}`
-
-	m.bar = progressbar.NewOptions(m.totalTests,
+func newManager(totalTests int, providers []string, enabled bool, out io.Writer, width, height int) *Manager {
+	m := &Manager{
+		enabled:    enabled,
+		out:        out,
+		width:      width,
+		height:     height,
+		totalTests: totalTests,
+		providers:  providers,
+		stats:      make(map[string]*providerStats, len(providers)),
+		running:    make(map[string]*RunningTest),
+		startTime:  time.Now(),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, provider := range providers {
+		m.stats[provider] = &providerStats{errors: make(map[string]int)}
+	}
+	m.bar = progressbar.NewOptions(totalTests,
 		progressbar.OptionSetDescription("Benchmark Progress"),
-		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetWriter(io.Discard), // rendered into the dashboard via String
 		progressbar.OptionSetWidth(40),
-		progressbar.OptionThrottle(100*time.Millisecond),
 		progressbar.OptionShowCount(),
-		progressbar.OptionShowIts(),
-		progressbar.OptionSetItsString("tests"),
 		progressbar.OptionSetTheme(progressbar.Theme{
 			Saucer:        "█",
 			SaucerHead:    "█",
@@ -87,166 +138,310 @@ func (m *Manager) setupProgressBar() {
 			BarEnd:        "|",
 		}),
 		progressbar.OptionSetRenderBlankState(true),
-		progressbar.OptionFullWidth(),
 		progressbar.OptionSetPredictTime(true),
 		progressbar.OptionSetElapsedTime(true),
-		progressbar.OptionOnCompletion(func() {
-			fmt.Fprintln(os.Stderr)
-		}),
-		progressbar.OptionSetWriter(io.Discard), // We'll handle rendering manually
 	)
+	return m
+}
 
-	// Store template for manual rendering
-	_ = barTemplate
+// DashboardSupported reports whether stdout is an interactive terminal that
+// can show the dashboard. CI logs, pipes and TERM=dumb get plain output.
+func DashboardSupported() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
-// StartTest marks a test as started
-func (m *Manager) StartTest(provider, testName string) {
-	if !m.enabled {
-		return
+// terminalSize returns the size of the terminal on stdout, else COLUMNS and
+// LINES, falling back to 100×40.
+func terminalSize() (width, height int) {
+	// #nosec G115 - file descriptors fit in an int
+	if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		return w, h
 	}
+	width, height = 100, 40
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n >= 60 {
+		width = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n >= 20 {
+		height = n
+	}
+	return width, height
+}
 
+func runningKey(provider, testName string, repeat int) string {
+	return fmt.Sprintf("%s/%s/%d", provider, testName, repeat)
+}
+
+// StartTest marks a test run as in flight.
+func (m *Manager) StartTest(provider, testName string, repeat int) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-
-	key := fmt.Sprintf("%s:%s", provider, testName)
-	m.runningTests[key] = &RunningTest{
+	m.running[runningKey(provider, testName, repeat)] = &RunningTest{
 		Name:      testName,
 		Provider:  provider,
+		Repeat:    repeat,
 		StartTime: time.Now(),
 		Status:    StatusRunning,
 	}
-
-	m.render()
 }
 
-// CompleteTest marks a test as completed
-func (m *Manager) CompleteTest(provider, testName string, success bool, _ error) {
-	if !m.enabled {
-		return
-	}
-
+// CompleteTest records a finished (or skipped) test run.
+func (m *Manager) CompleteTest(r benchmetrics.Result) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	delete(m.running, runningKey(r.Provider, r.TestName, r.Repeat))
 
-	key := fmt.Sprintf("%s:%s", provider, testName)
-	if test, exists := m.runningTests[key]; exists {
-		test.Status = StatusSuccess
-		if !success {
-			test.Status = StatusFailed
-		}
-		// Keep it in running tests briefly for display, will be cleaned on next render
+	stats := m.stats[r.Provider]
+	if stats == nil {
+		stats = &providerStats{errors: make(map[string]int)}
+		m.stats[r.Provider] = stats
+		m.providers = append(m.providers, r.Provider)
 	}
-
 	m.completed++
-	if success {
+	stats.completed++
+	switch {
+	case r.Skipped:
+		m.skipped++
+		stats.skipped++
+	case r.Success:
 		m.passed++
-	} else {
+	default:
 		m.failed++
+		stats.failed++
+		category := r.ErrorCategory
+		if category == "" {
+			category = "unknown"
+		}
+		stats.errors[category]++
+		m.errors = appendCapped(m.errors, errorEntry{
+			at:       time.Now(),
+			provider: r.Provider,
+			test:     r.TestName,
+			category: category,
+			message:  r.Error,
+		}, maxErrors)
+	}
+	if !r.Skipped {
+		stats.latencies = appendCapped(stats.latencies, r.Latency, latencyWindow)
+		stats.spend += r.CostUSD
 	}
+	_ = m.bar.Set(m.completed)
+}
 
-	delete(m.runningTests, key)
-	m.render()
+func appendCapped[T any](items []T, item T, limit int) []T {
+	items = append(items, item)
+	if len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	return items
 }
 
-// render draws the progress display
-func (m *Manager) render() {
+// PrintAbove prints a message. With the dashboard it goes to the
+// dashboard's log pane instead.
+func (m *Manager) PrintAbove(format string, args ...interface{}) {
 	if !m.enabled {
+		fmt.Printf(format+"\n", args...)
 		return
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = appendCapped(m.logs, fmt.Sprintf(format, args...), maxLogLines)
+}
 
-	// Clear screen and move cursor to top
-	fmt.Print("\033[2J\033[H")
+// refresh redraws the dashboard until Finish, and at once when the
+// terminal is resized.
+func (m *Manager) refresh() {
+	defer close(m.done)
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	resized, stopResize := notifyResize()
+	defer stopResize()
+	m.draw()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-resized:
+			m.resize(terminalSize())
+			m.draw()
+		case <-ticker.C:
+			m.draw()
+		}
+	}
+}
 
-	// Print header
-	fmt.Println("╔══════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                    SanityWebEval Tool                             ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+// resize sets the size the dashboard is laid out for.
+func (m *Manager) resize(width, height int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.width, m.height = width, height
+}
 
-	// Calculate progress bar
-	percent := float64(m.completed) / float64(m.totalTests)
-	filled := int(percent * 40)
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", 40-filled)
+// draw repaints the dashboard on the alternate screen in a single write.
+func (m *Manager) draw() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// Overall progress
-	fmt.Printf("  Overall Progress: [%s] %d/%d tests (%.0f%%)\n", bar, m.completed, m.totalTests, percent*100)
+	var sb strings.Builder
+	if !m.onScreen {
+		sb.WriteString("\033[?1049h\033[?25l") // alternate screen, hide cursor
+		m.onScreen = true
+	}
+	sb.WriteString("\033[H")
+	for _, line := range m.view(time.Now()) {
+		sb.WriteString(line)
+		sb.WriteString("\033[K\n")
+	}
+	sb.WriteString("\033[J")
+	_, _ = io.WriteString(m.out, sb.String())
+}
 
-	// ETA calculation
-	if m.completed > 0 && m.completed < m.totalTests {
-		elapsed := time.Since(m.startTime)
-		avgPerTest := elapsed / time.Duration(m.completed)
-		remaining := m.totalTests - m.completed
-		eta := time.Duration(remaining) * avgPerTest
-		fmt.Printf("  ETA: ~%s remaining (avg %.1fs per test)\n", formatDuration(eta), avgPerTest.Seconds())
+// Finish stops the dashboard, restores the screen and prints the final
+// state so it stays in the scrollback.
+func (m *Manager) Finish() {
+	if !m.enabled {
+		return
 	}
-	fmt.Println()
+	m.finishOnce.Do(func() {
+		close(m.stop)
+		<-m.done
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		var sb strings.Builder
+		if m.onScreen {
+			sb.WriteString("\033[?25h\033[?1049l") // show cursor, leave alternate screen
+			m.onScreen = false
+		}
+		for _, line := range m.view(time.Now()) {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		_, _ = io.WriteString(m.out, sb.String())
+	})
+}
 
-	// Results summary
-	fmt.Printf("  Results: ✓ %d passed | ✗ %d failed | ○ %d pending\n",
-		m.passed, m.failed, m.totalTests-m.completed)
-	fmt.Println()
+// IsEnabled returns whether the dashboard is shown
+func (m *Manager) IsEnabled() bool {
+	return m.enabled
+}
 
-	// Currently running tests by provider
-	if len(m.runningTests) > 0 {
-		fmt.Println("  Currently Running:")
+// view renders the dashboard lines. The caller holds m.mu.
+func (m *Manager) view(now time.Time) []string {
+	var lines []string
+	add := func(format string, args ...interface{}) {
+		lines = append(lines, truncate(fmt.Sprintf(format, args...), m.width))
+	}
+
+	add("SanityWebEval · elapsed %s", formatDuration(now.Sub(m.startTime)))
+	add("%s", strings.TrimSpace(strings.Trim(m.bar.String(), "\r")))
+	add("✓ %d passed | ✗ %d failed | ○ %d skipped | %d pending",
+		m.passed, m.failed, m.skipped, m.totalTests-m.completed)
+	add("")
 
-		// Group by provider
-		byProvider := make(map[string][]*RunningTest)
-		for _, test := range m.runningTests {
-			byProvider[test.Provider] = append(byProvider[test.Provider], test)
+	perProvider := 0
+	if len(m.providers) > 0 {
+		perProvider = m.totalTests / len(m.providers)
+	}
+	add("%-14s %9s %6s %6s %8s %8s %10s  %s", "PROVIDER", "DONE", "FAIL", "SKIP", "P50", "P95", "SPEND", "ERRORS")
+	for _, provider := range m.providers {
+		stats := m.stats[provider]
+		p50, p95 := "-", "-"
+		if len(stats.latencies) > 0 {
+			p50 = formatDuration(benchmetrics.PercentileDuration(stats.latencies, 0.50))
+			p95 = formatDuration(benchmetrics.PercentileDuration(stats.latencies, 0.95))
 		}
+		add("%-14s %9s %6d %6d %8s %8s %10s  %s",
+			truncate(provider, 14),
+			fmt.Sprintf("%d/%d", stats.completed, perProvider),
+			stats.failed,
+			stats.skipped,
+			p50,
+			p95,
+			fmt.Sprintf("$%.4f", stats.spend),
+			errorSummary(stats.errors))
+	}
 
-		// Show each provider's activity
-		for _, provider := range m.showProviders {
-			tests, hasRunning := byProvider[provider]
-			if hasRunning && len(tests) > 0 {
-				for _, test := range tests {
-					elapsed := time.Since(test.StartTime).Round(time.Millisecond)
-					fmt.Printf("    • %-12s: %-35s (%s elapsed)\n", provider, truncate(test.Name, 35), formatDuration(elapsed))
-				}
-			}
+	add("")
+	add("IN FLIGHT (%d)", len(m.running))
+	running := make([]*RunningTest, 0, len(m.running))
+	for _, test := range m.running {
+		running = append(running, test)
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].StartTime.Before(running[j].StartTime) })
+	for i, test := range running {
+		if i == m.inFlightRows() {
+			add("  … %d more", len(running)-i)
+			break
 		}
-		fmt.Println()
+		add("  %-14s %-50s %8s", truncate(test.Provider, 14), truncate(fmt.Sprintf("%s #%d", test.Name, test.Repeat), 50),
+			formatDuration(now.Sub(test.StartTime)))
 	}
 
-	// Recent completed (keep last few for context)
-	fmt.Println("  Recent activity (scrolls up):")
+	if len(m.errors) > 0 {
+		add("")
+		add("RECENT ERRORS")
+		for i := len(m.errors) - 1; i >= 0; i-- {
+			e := m.errors[i]
+			add("  %s %-14s %-12s %s: %s", e.at.Format("15:04:05"), truncate(e.provider, 14), e.category, e.test, firstLine(e.message))
+		}
+	}
+	if len(m.logs) > 0 {
+		add("")
+		add("LOG")
+		for _, line := range m.logs {
+			add("  %s", firstLine(line))
+		}
+	}
+	return lines
 }
 
-// PrintAbove prints a message above the progress bar
-func (m *Manager) PrintAbove(format string, args ...interface{}) {
-	if !m.enabled {
-		fmt.Printf(format+"\n", args...)
-		return
+// inFlightRows is how many in-flight tests fit beside the other panes.
+func (m *Manager) inFlightRows() int {
+	used := 8 + len(m.providers) + len(m.errors) + len(m.logs) + 4
+	rows := m.height - used
+	if rows < 3 {
+		rows = 3
 	}
-
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// Print the message
-	fmt.Printf(format+"\n", args...)
-
-	// Re-render progress bar below
-	m.render()
+	if rows > maxInFlight {
+		rows = maxInFlight
+	}
+	return rows
 }
 
-// Finish marks the benchmark as complete
-func (m *Manager) Finish() {
-	if !m.enabled {
-		return
+// errorSummary lists error counts by category, most frequent first.
+func errorSummary(errors map[string]int) string {
+	if len(errors) == 0 {
+		return "-"
 	}
-
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	// Clear screen for final summary
-	fmt.Print("\033[2J\033[H")
+	categories := make([]string, 0, len(errors))
+	for category := range errors {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if errors[categories[i]] != errors[categories[j]] {
+			return errors[categories[i]] > errors[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s %d", category, errors[category])
+	}
+	return strings.Join(parts, ", ")
 }
 
-// IsEnabled returns whether progress display is enabled
-func (m *Manager) IsEnabled() bool {
-	return m.enabled
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
 }
 
 // formatDuration formats a duration in a human-readable way
@@ -260,10 +455,14 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
 }
 
-// truncate truncates a string to max length with ellipsis
+// truncate shortens s to maxLen characters with an ellipsis.
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
-	return s[:maxLen-3] + "..."
+	if maxLen <= 1 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-1]) + "…"
 }
@@ -0,0 +1,90 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+func TestManager_View(t *testing.T) {
+	m := newManager(6, []string{"exa", "tavily"}, true, &bytes.Buffer{}, 120, 40)
+	m.StartTest("exa", "Search - Rust", 1)
+	m.StartTest("tavily", "Search - Rust", 1)
+	m.StartTest("tavily", "Crawl - Docs", 2)
+	m.CompleteTest(benchmetrics.Result{TestName: "Search - Rust", Provider: "exa", Repeat: 1, Success: true, Latency: 800 * time.Millisecond, CostUSD: 0.005})
+	m.CompleteTest(benchmetrics.Result{TestName: "Search - Rust", Provider: "tavily", Repeat: 1, Error: "HTTP 429\nbody", ErrorCategory: "rate_limit", Latency: 2 * time.Second})
+	m.CompleteTest(benchmetrics.Result{TestName: "Extract", Provider: "tavily", Repeat: 1, Skipped: true})
+	m.PrintAbove("note %d", 1)
+
+	out := strings.Join(m.view(time.Now()), "\n")
+	for _, want := range []string{
+		"3/6",
+		"✓ 1 passed | ✗ 1 failed | ○ 1 skipped | 3 pending",
+		"$0.0050",
+		"800ms",
+		"rate_limit 1",
+		"IN FLIGHT (1)",
+		"Crawl - Docs #2",
+		"rate_limit   Search - Rust: HTTP 429",
+		"note 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected dashboard to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "body") {
+		t.Errorf("expected only the first line of error messages:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if n := len([]rune(line)); n > 120 {
+			t.Errorf("line exceeds the terminal width (%d): %q", n, line)
+		}
+	}
+}
+
+func TestManager_DrawAndFinish(t *testing.T) {
+	var buf bytes.Buffer
+	m := newManager(1, []string{"exa"}, true, &buf, 100, 40)
+	m.draw()
+	if !strings.HasPrefix(buf.String(), "\033[?1049h") {
+		t.Errorf("expected the dashboard on the alternate screen, got %q", buf.String())
+	}
+
+	close(m.done) // no refresh goroutine in this test
+	buf.Reset()
+	m.Finish()
+	m.Finish()
+	out := buf.String()
+	if !strings.HasPrefix(out, "\033[?25h\033[?1049l") || !strings.Contains(out, "PROVIDER") {
+		t.Errorf("expected the screen restored and a final snapshot, got %q", out)
+	}
+	if strings.Count(out, "PROVIDER") != 1 {
+		t.Error("expected Finish to run once")
+	}
+}
+
+func TestManager_ResizeRelaysOut(t *testing.T) {
+	m := newManager(2, []string{"exa"}, true, &bytes.Buffer{}, 120, 40)
+	m.StartTest("exa", "Search - "+strings.Repeat("long test name ", 10), 1)
+
+	m.resize(60, 20)
+	for _, line := range m.view(time.Now()) {
+		if n := len([]rune(line)); n > 60 {
+			t.Errorf("expected lines of at most 60 runes after resizing, got %d: %q", n, line)
+		}
+	}
+}
+
+func TestManager_DisabledPrintsNothing(t *testing.T) {
+	var buf bytes.Buffer
+	m := newManager(1, []string{"exa"}, false, &buf, 100, 40)
+	m.StartTest("exa", "Search", 1)
+	m.CompleteTest(benchmetrics.Result{TestName: "Search", Provider: "exa", Repeat: 1, Success: true})
+	m.Finish()
+	if buf.Len() != 0 {
+		t.Errorf("expected no output without the dashboard, got %q", buf.String())
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package progress
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyResize returns a channel that receives SIGWINCH when the terminal
+// is resized, and a function that stops the notifications.
+func notifyResize() (<-chan os.Signal, func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	return ch, func() { signal.Stop(ch) }
+}
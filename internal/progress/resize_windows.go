@@ -0,0 +1,11 @@
+//go:build windows
+
+package progress
+
+import "os"
+
+// notifyResize returns a nil channel: Windows consoles send no resize
+// signal, so the dashboard keeps the size it started with.
+func notifyResize() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
@@ -15,7 +15,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
@@ -30,6 +29,7 @@ const (
 
 // Client represents a Brave Search API client
 type Client struct {
+	name       string
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
@@ -38,23 +38,44 @@ type Client struct {
 
 // NewClient creates a new Brave Search client
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("BRAVE_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Brave Search client for a named instance, for
+// example a Brave-compatible proxy at a custom base URL.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("brave", providers.EndpointAPI)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := settings.APIKey("BRAVE_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("BRAVE_API_KEY environment variable not set")
+		return nil, fmt.Errorf("%s environment variable not set", settings.APIKeyVar("BRAVE_API_KEY"))
+	}
+	httpClient, err := settings.HTTPClient(defaultTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		retryCfg: providers.DefaultRetryConfig(),
+		name:       settings.Name,
+		apiKey:     apiKey,
+		baseURL:    settings.BaseURL(providers.EndpointAPI, defaultBaseURL),
+		httpClient: httpClient,
+		retryCfg:   providers.DefaultRetryConfig(),
 	}, nil
 }
 
-// Name returns the provider name
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "brave"
 }
 
@@ -72,6 +93,33 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// BaseURLs returns the Brave API base URL.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: c.baseURL}
+}
+
+// SetBaseURL redirects the Brave API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	return nil
+}
+
+// SearchOptionSupport reports how Brave honors normalized search options.
+// Domain filters are emulated with site: operators in the query.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportNative,
+		IncludeDomains: providers.SupportEmulated,
+		ExcludeDomains: providers.SupportEmulated,
+		Country:        providers.SupportNative,
+		Language:       providers.SupportNative,
+		SafeSearch:     providers.SupportNative,
+	}
+}
+
 // Search performs a web search using Brave Search API
 // Endpoint: GET /res/v1/web/search
 // Features leveraged:
@@ -84,7 +132,7 @@ func (c *Client) Search(ctx context.Context, query string, opts providers.Search
 
 	// Build query parameters
 	params := url.Values{}
-	params.Set("q", query)
+	params.Set("q", providers.ApplyDomainOperators(query, opts.IncludeDomains, opts.ExcludeDomains))
 	params.Set("count", fmt.Sprintf("%d", min(opts.MaxResults, 20))) // Max 20 per request
 	params.Set("extra_snippets", "true")                             // Get more content per result
 
@@ -99,8 +147,16 @@ func (c *Client) Search(ctx context.Context, query string, opts providers.Search
 	// Add offset for pagination if needed
 	params.Set("offset", "0")
 
+	// Country/language targeting
+	if opts.Country != "" {
+		params.Set("country", strings.ToUpper(opts.Country))
+	}
+	if lang := providers.LanguageCode(opts.Language); lang != "" {
+		params.Set("search_lang", lang)
+	}
+
 	// Safe search (default moderate)
-	params.Set("safesearch", "moderate")
+	params.Set("safesearch", mapSafeSearch(opts.SafeSearch))
 
 	searchURL := fmt.Sprintf("%s/web/search?%s", c.baseURL, params.Encode())
 
@@ -190,8 +246,10 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 
+	providers.LogRequest(ctx, "GET", pageURL, providers.HeadersToMap(req.Header), "")
 	resp, err := c.retryCfg.DoHTTPRequestDetailed(ctx, c.httpClient, req)
 	if err != nil {
+		providers.LogError(ctx, err.Error(), "http", "extract request failed")
 		return nil, err
 	}
 	providers.LogResponse(ctx, resp.StatusCode, providers.HeadersToMap(resp.Headers), string(resp.Body), len(resp.Body), time.Since(start))
@@ -365,6 +423,15 @@ func mapTimeRangeToFreshness(timeRange string) string {
 	}
 }
 
+func mapSafeSearch(level string) string {
+	switch strings.ToLower(level) {
+	case "off", "strict":
+		return strings.ToLower(level)
+	default:
+		return "moderate"
+	}
+}
+
 func parseBraveAge(_ string) *time.Time {
 	// Brave age format examples: "1 day ago", "2 hours ago", "1 week ago"
 	// For simplicity, return nil - we could parse this more precisely if needed
@@ -3,7 +3,10 @@ package providers
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"net/http/httptrace"
+	"sync"
 	"time"
 
 	"github.com/lamim/SanityWebEval/internal/debug"
@@ -42,22 +45,42 @@ func LogError(ctx context.Context, message, category, errContext string) {
 	logger.LogError(testLog, message, category, errContext)
 }
 
-// NewTraceContext creates an httptrace context for timing breakdown if debug is enabled.
-// Returns the enhanced context, timing breakdown pointer, and a finalize function.
-// This is a shared helper that all providers can use for consistent timing capture.
-func NewTraceContext(ctx context.Context) (context.Context, *debug.TimingBreakdown, func()) {
-	testLog := TestLogFromContext(ctx)
-	logger := DebugLoggerFromContext(ctx)
-	if logger == nil || testLog == nil {
-		return ctx, nil, func() {}
+// traceDebugTiming adds an httptrace timing trace to req when its context
+// carries an enabled debug logger and test log. The returned function records
+// the attempt's timing on the test's latest logged request; it is nil when
+// debug logging is off.
+func traceDebugTiming(req *http.Request, resendCount int) (*http.Request, func()) {
+	testLog := TestLogFromContext(req.Context())
+	logger := DebugLoggerFromContext(req.Context())
+	if logger == nil || testLog == nil || !logger.IsEnabled() {
+		return req, nil
 	}
+	trace, timing := debug.TraceTiming()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	return req, func() {
+		logger.RecordTiming(testLog, timing(), resendCount)
+	}
+}
 
-	timing, finalize := logger.NewTraceContext(testLog)
-	trace := logger.GetTraceFromTest(testLog)
-	if trace != nil {
-		return httptrace.WithClientTrace(ctx, trace), timing, finalize
+// timedBody calls done once the response body has been read to the end or
+// closed, so the recorded timing covers receiving the body.
+type timedBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.done)
 	}
-	return ctx, timing, finalize
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
 }
 
 // HeadersToMap converts http.Header to a map for logging.
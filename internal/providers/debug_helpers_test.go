@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/debug"
+)
+
+func TestDebugHAR_FailedThenRetriedRequestsPairWithTheirResponses(t *testing.T) {
+	// The first two requests drop the connection; the third succeeds.
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	defer server.Close()
+
+	logger := debug.NewLogger(true, false, t.TempDir())
+	if err := logger.SetFormat(debug.FormatHAR); err != nil {
+		t.Fatalf("SetFormat failed: %v", err)
+	}
+	testLog := logger.StartTest("example", "Search - retry", "search")
+	ctx := WithTestLog(WithDebugLogger(context.Background(), logger), testLog)
+	client := &http.Client{Transport: &http.Transport{}}
+
+	fetch := func(maxRetries int) error {
+		start := time.Now()
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/search", nil)
+		if err != nil {
+			return err
+		}
+		LogRequest(ctx, "GET", req.URL.String(), nil, "")
+		rc := RetryConfig{MaxRetries: maxRetries, BackoffFactor: 1}
+		resp, err := rc.DoHTTPRequestDetailed(ctx, client, req)
+		if err != nil {
+			LogError(ctx, err.Error(), "http", "search request failed")
+			return err
+		}
+		LogResponse(ctx, resp.StatusCode, HeadersToMap(resp.Headers), string(resp.Body), len(resp.Body), time.Since(start))
+		return nil
+	}
+	if err := fetch(0); err == nil {
+		t.Fatal("expected the first request to fail")
+	}
+	if err := fetch(1); err != nil {
+		t.Fatalf("expected the retried request to succeed: %v", err)
+	}
+	logger.EndTest(testLog)
+	if err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(logger.GetOutputPath(), "example.har"))
+	if err != nil {
+		t.Fatalf("failed reading HAR: %v", err)
+	}
+	var har debug.HAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("failed parsing HAR: %v", err)
+	}
+	if len(har.Log.Entries) != 2 {
+		t.Fatalf("expected 2 HAR entries, got %d", len(har.Log.Entries))
+	}
+	failed, retried := har.Log.Entries[0], har.Log.Entries[1]
+	if failed.Response.Status != 0 {
+		t.Errorf("expected the failed request to have no response, got status %d", failed.Response.Status)
+	}
+	if retried.Response.Status != 200 {
+		t.Errorf("expected the retried request to pair with the 200 response, got status %d", retried.Response.Status)
+	}
+	if retried.Comment != "retry attempt 1" {
+		t.Errorf("expected the retried request to record its retry attempt, got %q", retried.Comment)
+	}
+}
@@ -0,0 +1,19 @@
+package providers
+
+import "fmt"
+
+// EndpointAPI is the endpoint name for providers with a single API base URL.
+const EndpointAPI = "api"
+
+// BaseURLOverrider is implemented by providers whose API base URLs can be
+// redirected, for example through a local fault-injection proxy. Endpoint
+// names identify each base URL; most providers have only EndpointAPI.
+type BaseURLOverrider interface {
+	BaseURLs() map[string]string
+	SetBaseURL(endpoint, baseURL string) error
+}
+
+// UnknownEndpointError reports an endpoint name a provider does not have.
+func UnknownEndpointError(provider, endpoint string) error {
+	return fmt.Errorf("%s provider has no %q endpoint", provider, endpoint)
+}
@@ -16,7 +16,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
@@ -30,6 +29,7 @@ const (
 
 // Client represents an Exa AI API client
 type Client struct {
+	name       string
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
@@ -38,32 +38,72 @@ type Client struct {
 
 // NewClient creates a new Exa AI client
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("EXA_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Exa AI client for a named instance, for
+// example an Exa-compatible endpoint at a custom base URL.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("exa", providers.EndpointAPI)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := settings.APIKey("EXA_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("EXA_API_KEY environment variable not set")
+		return nil, fmt.Errorf("%s environment variable not set", settings.APIKeyVar("EXA_API_KEY"))
+	}
+	httpClient, err := settings.HTTPClient(defaultTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		retryCfg: providers.DefaultRetryConfig(),
+		name:       settings.Name,
+		apiKey:     apiKey,
+		baseURL:    settings.BaseURL(providers.EndpointAPI, defaultBaseURL),
+		httpClient: httpClient,
+		retryCfg:   providers.DefaultRetryConfig(),
 	}, nil
 }
 
-// Name returns the provider name
+// startPublishedDate converts a normalized time range into Exa's ISO 8601 lower bound.
+func startPublishedDate(timeRange string, now time.Time) string {
+	var since time.Time
+	switch timeRange {
+	case "day":
+		since = now.AddDate(0, 0, -1)
+	case "week":
+		since = now.AddDate(0, 0, -7)
+	case "month":
+		since = now.AddDate(0, -1, 0)
+	case "year":
+		since = now.AddDate(-1, 0, 0)
+	default:
+		return ""
+	}
+	return since.UTC().Format(time.RFC3339)
+}
+
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "exa"
 }
 
 // Capabilities returns Exa operation support levels.
 func (c *Client) Capabilities() providers.CapabilitySet {
 	return providers.CapabilitySet{
-		Search:  providers.SupportNative,
-		Extract: providers.SupportNative,
-		Crawl:   providers.SupportEmulated, // Uses search + contents, not native subpages
+		Search:            providers.SupportNative,
+		Extract:           providers.SupportNative,
+		Crawl:             providers.SupportEmulated, // Uses search + contents, not native subpages
+		StructuredExtract: providers.SupportNative,   // Contents summary with a JSON schema
 	}
 }
 
@@ -72,12 +112,41 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// BaseURLs returns the Exa API base URL.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: c.baseURL}
+}
+
+// SetBaseURL redirects the Exa API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	return nil
+}
+
+// SearchOptionSupport reports how Exa honors normalized search options.
+// Time ranges map to startPublishedDate and safe search to Exa's content
+// moderation flag; there is no language filter.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportNative,
+		IncludeDomains: providers.SupportNative,
+		ExcludeDomains: providers.SupportNative,
+		Country:        providers.SupportNative,
+		Language:       providers.SupportUnsupported,
+		SafeSearch:     providers.SupportNative,
+	}
+}
+
 // Search performs a web search using Exa AI API
 // Endpoint: POST /search
 // Native features leveraged:
 //   - Multiple search modes: fast, auto, deep
 //   - Content retrieval via contents object
-//   - Domain filtering via includeDomains
+//   - Domain filtering via includeDomains/excludeDomains
+//   - Country targeting via userLocation
 func (c *Client) Search(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
 	start := time.Now()
 
@@ -102,6 +171,21 @@ func (c *Client) Search(ctx context.Context, query string, opts providers.Search
 			"text": true,
 		},
 	}
+	if len(opts.IncludeDomains) > 0 {
+		payload["includeDomains"] = opts.IncludeDomains
+	}
+	if len(opts.ExcludeDomains) > 0 {
+		payload["excludeDomains"] = opts.ExcludeDomains
+	}
+	if opts.Country != "" {
+		payload["userLocation"] = strings.ToUpper(opts.Country)
+	}
+	if since := startPublishedDate(opts.TimeRange, time.Now()); since != "" {
+		payload["startPublishedDate"] = since
+	}
+	if opts.SafeSearch != "" && !strings.EqualFold(opts.SafeSearch, "off") {
+		payload["moderation"] = true
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -259,6 +343,80 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 	}, nil
 }
 
+// ExtractStructured extracts schema-shaped JSON from a URL using Exa's
+// contents endpoint. The schema is passed as a summary schema and the
+// summary string is decoded as JSON.
+func (c *Client) ExtractStructured(ctx context.Context, pageURL string, opts providers.StructuredExtractOptions) (*providers.StructuredExtractResult, error) {
+	start := time.Now()
+
+	summary := map[string]interface{}{
+		"schema": opts.Schema,
+	}
+	if opts.Prompt != "" {
+		summary["query"] = opts.Prompt
+	}
+	payload := map[string]interface{}{
+		"urls":    []string{pageURL},
+		"text":    true,
+		"summary": summary,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqURL := c.baseURL + "/contents"
+	providers.LogRequest(ctx, "POST", reqURL, map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer [REDACTED]",
+	}, string(body))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.retryCfg.DoHTTPRequestDetailed(ctx, c.httpClient, req)
+	if err != nil {
+		providers.LogError(ctx, err.Error(), "http", "structured extract request failed")
+		return nil, err
+	}
+
+	var result contentsResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		providers.LogError(ctx, err.Error(), "parse", "failed to unmarshal structured extract response")
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	latency := time.Since(start)
+	providers.LogResponse(ctx, resp.StatusCode, providers.HeadersToMap(resp.Headers), string(resp.Body), len(resp.Body), latency)
+
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("no extraction results returned")
+	}
+
+	r := result.Results[0]
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(r.Summary), &data); err != nil {
+		providers.LogError(ctx, err.Error(), "parse", "failed to parse structured summary")
+		return nil, fmt.Errorf("failed to parse structured summary: %w", err)
+	}
+
+	return &providers.StructuredExtractResult{
+		URL:           pageURL,
+		Data:          data,
+		Content:       r.Text,
+		Latency:       latency,
+		CreditsUsed:   2, // text + summary content fetches
+		RequestCount:  1,
+		UsageReported: result.CostDollars.Total > 0,
+	}, nil
+}
+
 // Crawl crawls a website using Exa AI
 // Strategy: Use includeDomains to search for pages on the target domain,
 // then batch extract content via /contents endpoint.
@@ -432,6 +590,7 @@ type contentsResponse struct {
 		URL        string   `json:"url"`
 		Title      string   `json:"title"`
 		Text       string   `json:"text"`
+		Summary    string   `json:"summary,omitempty"`
 		Author     string   `json:"author,omitempty"`
 		Image      string   `json:"image,omitempty"`
 		Highlights []string `json:"highlights,omitempty"`
@@ -70,6 +70,45 @@ func TestSearch_ReturnsInlineTextContent(t *testing.T) {
 	}
 }
 
+func TestSearch_MapsDomainAndLocationFilters(t *testing.T) {
+	var capturedReq map[string]interface{}
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&capturedReq)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(searchResponse{RequestID: "req-2"})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		retryCfg:   providers.DefaultRetryConfig(),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	opts := providers.DefaultSearchOptions()
+	opts.IncludeDomains = []string{"go.dev"}
+	opts.ExcludeDomains = []string{"medium.com"}
+	opts.Country = "jp"
+	opts.SafeSearch = "strict"
+
+	if _, err := client.Search(context.Background(), "generics", opts); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if _, ok := capturedReq["includeDomains"]; !ok {
+		t.Error("expected includeDomains in payload")
+	}
+	if _, ok := capturedReq["excludeDomains"]; !ok {
+		t.Error("expected excludeDomains in payload")
+	}
+	if capturedReq["userLocation"] != "JP" {
+		t.Errorf("expected userLocation JP, got %v", capturedReq["userLocation"])
+	}
+	if capturedReq["moderation"] != true {
+		t.Errorf("expected moderation for strict safe search, got %v", capturedReq["moderation"])
+	}
+}
+
 func TestParseExaPublishedAt_SupportsCommonFormats(t *testing.T) {
 	t.Run("rfc3339", func(t *testing.T) {
 		parsed, ok := parseExaPublishedAt("2025-01-01T00:00:00.000Z")
@@ -30,6 +30,7 @@ const (
 
 // Client represents a Firecrawl API client
 type Client struct {
+	name       string
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
@@ -38,9 +39,23 @@ type Client struct {
 
 // NewClient creates a new Firecrawl client
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("FIRECRAWL_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Firecrawl client for a named instance,
+// for example a self-hosted Firecrawl at a custom base URL.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("firecrawl", providers.EndpointAPI)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := settings.APIKey("FIRECRAWL_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("FIRECRAWL_API_KEY environment variable not set")
+		return nil, fmt.Errorf("%s environment variable not set", settings.APIKeyVar("FIRECRAWL_API_KEY"))
+	}
+	httpClient, err := settings.HTTPClient(defaultFallbackHTTPClientTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	retryCfg := providers.DefaultRetryConfig()
@@ -66,12 +81,11 @@ func NewClient() (*Client, error) {
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: defaultFallbackHTTPClientTimeout,
-		},
-		retryCfg: retryCfg,
+		name:       settings.Name,
+		apiKey:     apiKey,
+		baseURL:    settings.BaseURL(providers.EndpointAPI, defaultBaseURL),
+		httpClient: httpClient,
+		retryCfg:   retryCfg,
 	}, nil
 }
 
@@ -99,17 +113,42 @@ func parsePositiveDurationEnv(key string, fallback time.Duration) time.Duration
 	return d
 }
 
-// Name returns the provider name
+// mapTimeRangeToTBS converts a normalized time range to a Google-style tbs filter.
+func mapTimeRangeToTBS(timeRange string) string {
+	switch timeRange {
+	case "day":
+		return "qdr:d"
+	case "week":
+		return "qdr:w"
+	case "month":
+		return "qdr:m"
+	case "year":
+		return "qdr:y"
+	default:
+		return ""
+	}
+}
+
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "firecrawl"
 }
 
 // Capabilities returns Firecrawl operation support levels.
 func (c *Client) Capabilities() providers.CapabilitySet {
 	return providers.CapabilitySet{
-		Search:  providers.SupportNative,
-		Extract: providers.SupportNative,
-		Crawl:   providers.SupportNative,
+		Search:            providers.SupportNative,
+		Extract:           providers.SupportNative,
+		Crawl:             providers.SupportNative,
+		StructuredExtract: providers.SupportNative,
 	}
 }
 
@@ -118,15 +157,48 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// BaseURLs returns the Firecrawl API base URL.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: c.baseURL}
+}
+
+// SetBaseURL redirects the Firecrawl API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	return nil
+}
+
+// SearchOptionSupport reports how Firecrawl honors normalized search options.
+// Domain filters are emulated with site: operators in the query.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportNative,
+		IncludeDomains: providers.SupportEmulated,
+		ExcludeDomains: providers.SupportEmulated,
+		Country:        providers.SupportNative,
+		Language:       providers.SupportUnsupported,
+		SafeSearch:     providers.SupportUnsupported,
+	}
+}
+
 // Search performs a web search using Firecrawl v2
 // Endpoint: POST /v2/search
 func (c *Client) Search(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
 	start := time.Now()
 
 	payload := map[string]interface{}{
-		"query": query,
+		"query": providers.ApplyDomainOperators(query, opts.IncludeDomains, opts.ExcludeDomains),
 		"limit": opts.MaxResults,
 	}
+	if tbs := mapTimeRangeToTBS(opts.TimeRange); tbs != "" {
+		payload["tbs"] = tbs
+	}
+	if opts.Country != "" {
+		payload["country"] = strings.ToUpper(opts.Country)
+	}
 
 	// Advanced search includes full scraping with markdown format
 	if opts.SearchDepth == "advanced" {
@@ -266,6 +338,75 @@ func (c *Client) Extract(ctx context.Context, url string, opts providers.Extract
 	}, nil
 }
 
+// ExtractStructured extracts schema-shaped JSON from a URL using Firecrawl v2
+// Endpoint: POST /v2/scrape with the json format
+func (c *Client) ExtractStructured(ctx context.Context, url string, opts providers.StructuredExtractOptions) (*providers.StructuredExtractResult, error) {
+	start := time.Now()
+
+	jsonFormat := map[string]interface{}{
+		"type":   "json",
+		"schema": opts.Schema,
+	}
+	if opts.Prompt != "" {
+		jsonFormat["prompt"] = opts.Prompt
+	}
+	payload := map[string]interface{}{
+		"url": url,
+		"formats": []interface{}{
+			jsonFormat,
+			map[string]interface{}{"type": "markdown"},
+		},
+		"onlyMainContent": true,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqURL := c.baseURL + "/scrape"
+	providers.LogRequest(ctx, "POST", reqURL, map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer [REDACTED]",
+	}, string(body))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.retryCfg.DoHTTPRequestDetailed(ctx, c.httpClient, req)
+	if err != nil {
+		providers.LogError(ctx, err.Error(), "http", "structured extract request failed")
+		return nil, err
+	}
+
+	var result structuredScrapeResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		providers.LogError(ctx, err.Error(), "parse", "failed to unmarshal structured extract response")
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	latency := time.Since(start)
+	providers.LogResponse(ctx, resp.StatusCode, providers.HeadersToMap(resp.Headers), string(resp.Body), len(resp.Body), latency)
+
+	if result.Data.JSON == nil {
+		return nil, fmt.Errorf("no structured data returned")
+	}
+
+	return &providers.StructuredExtractResult{
+		URL:          url,
+		Data:         result.Data.JSON,
+		Content:      result.Data.Markdown,
+		Latency:      latency,
+		CreditsUsed:  5, // 1 scrape credit + 4 for the json format
+		RequestCount: 1,
+	}, nil
+}
+
 // Crawl crawls a website using Firecrawl v2
 // Endpoint: POST /v2/crawl (async with polling)
 func (c *Client) Crawl(ctx context.Context, url string, opts providers.CrawlOptions) (*providers.CrawlResult, error) {
@@ -512,6 +653,14 @@ type scrapeResponse struct {
 	} `json:"data"`
 }
 
+type structuredScrapeResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Markdown string                 `json:"markdown"`
+		JSON     map[string]interface{} `json:"json"`
+	} `json:"data"`
+}
+
 type crawlResponse struct {
 	Success bool   `json:"success"`
 	ID      string `json:"id"`
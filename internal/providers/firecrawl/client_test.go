@@ -78,6 +78,50 @@ func TestNewClient_RetryEnvOverridesAndClamp(t *testing.T) {
 	}
 }
 
+func TestNewClientWithSettings_SelfHostedInstance(t *testing.T) {
+	var gotAuth, gotTeam string
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/search" {
+			t.Errorf("expected path /v2/search, got %s", r.URL.Path)
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotTeam = r.Header.Get("X-Team")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success": true, "data": {"web": [{"url": "https://example.com", "title": "Example"}]}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("FIRECRAWL_API_KEY", "")
+	t.Setenv("SELFHOSTED_FIRECRAWL_KEY", "local-key")
+	t.Setenv("FIRECRAWL_SELFHOSTED_BASE_URL", "")
+	client, err := NewClientWithSettings(providers.Settings{
+		Name:      "firecrawl-selfhosted",
+		BaseURLs:  map[string]string{providers.EndpointAPI: server.URL + "/v2/"},
+		Timeout:   5 * time.Second,
+		Headers:   map[string]string{"X-Team": "search"},
+		APIKeyEnv: "SELFHOSTED_FIRECRAWL_KEY",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.Name() != "firecrawl-selfhosted" || client.Type() != "firecrawl" {
+		t.Errorf("unexpected name/type: %s/%s", client.Name(), client.Type())
+	}
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("expected configured timeout, got %s", client.httpClient.Timeout)
+	}
+
+	if _, err := client.Search(context.Background(), "test query", providers.DefaultSearchOptions()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAuth != "Bearer local-key" {
+		t.Errorf("expected key from api_key_env, got %q", gotAuth)
+	}
+	if gotTeam != "search" {
+		t.Errorf("expected configured header, got %q", gotTeam)
+	}
+}
+
 func TestSearch_Success(t *testing.T) {
 	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/search" {
@@ -284,6 +328,49 @@ func TestExtract_NotFound(t *testing.T) {
 	}
 }
 
+func TestExtractStructured_SendsJSONFormat(t *testing.T) {
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		formats, _ := payload["formats"].([]interface{})
+		if len(formats) == 0 {
+			t.Fatalf("expected formats in request, got %v", payload)
+		}
+		jsonFormat, _ := formats[0].(map[string]interface{})
+		if jsonFormat["type"] != "json" || jsonFormat["prompt"] != "product details" || jsonFormat["schema"] == nil {
+			t.Errorf("unexpected json format: %v", jsonFormat)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":{"markdown":"# Widget","json":{"name":"Widget","price":12}}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:  "test-key",
+		baseURL: server.URL,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+
+	result, err := client.ExtractStructured(context.Background(), "https://example.com", providers.StructuredExtractOptions{
+		Schema: map[string]interface{}{"type": "object", "properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}}},
+		Prompt: "product details",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Data["name"] != "Widget" || result.Data["price"] != float64(12) {
+		t.Errorf("unexpected structured data: %v", result.Data)
+	}
+	if result.Content != "# Widget" {
+		t.Errorf("expected markdown content, got %q", result.Content)
+	}
+}
+
 func TestCrawl_SyncSuccess(t *testing.T) {
 	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/crawl" {
@@ -36,6 +36,9 @@ type CapabilitySet struct {
 	Search  SupportLevel
 	Extract SupportLevel
 	Crawl   SupportLevel
+	// StructuredExtract is left empty by providers without native schema
+	// extraction; it is then emulated whenever Extract is available.
+	StructuredExtract SupportLevel
 }
 
 // ForOperation returns the support level for the given operation.
@@ -56,6 +59,14 @@ func (c CapabilitySet) ForOperation(opType string) SupportLevel {
 			return SupportUnsupported
 		}
 		return c.Crawl
+	case "structured_extract":
+		if c.StructuredExtract != "" {
+			return c.StructuredExtract
+		}
+		if c.ForOperation("extract") != SupportUnsupported {
+			return SupportEmulated
+		}
+		return SupportUnsupported
 	default:
 		return SupportUnsupported
 	}
@@ -74,6 +85,8 @@ const (
 	debugLoggerKey contextKey = iota
 	// testLogKey is the context key for the current test log
 	testLogKey
+	// httpObserverKey is the context key for the HTTP attempt observer
+	httpObserverKey
 )
 
 // WithDebugLogger returns a context with the debug logger attached
@@ -163,7 +176,7 @@ type Provider interface {
 	// Capabilities returns support level by operation.
 	Capabilities() CapabilitySet
 	// SupportsOperation returns whether the provider supports the given operation type
-	// Valid operation types: "search", "extract", "crawl"
+	// Valid operation types: "search", "extract", "crawl", "structured_extract"
 	SupportsOperation(opType string) bool
 	Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error)
 	Extract(ctx context.Context, url string, opts ExtractOptions) (*ExtractResult, error)
@@ -177,6 +190,13 @@ type SearchOptions struct {
 	IncludeImages bool
 	IncludeAnswer bool
 	TimeRange     string // day, week, month, year
+	// IncludeDomains restricts results to these registrable domains (e.g. "go.dev").
+	IncludeDomains []string
+	// ExcludeDomains removes results from these domains.
+	ExcludeDomains []string
+	Country        string // ISO 3166-1 alpha-2, e.g. "us", "de"
+	Language       string // ISO 639-1, e.g. "en", "ja"
+	SafeSearch     string // off, moderate, strict
 }
 
 // ExtractOptions contains options for extract operations
@@ -20,7 +20,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/lamim/SanityWebEval/internal/debug"
 	"github.com/lamim/SanityWebEval/internal/providers"
 )
 
@@ -46,6 +45,7 @@ const (
 
 // Client represents a Jina AI API client
 type Client struct {
+	name             string
 	apiKey           string
 	httpClient       *http.Client
 	readerBaseURL    string
@@ -62,7 +62,17 @@ type Client struct {
 
 // NewClient creates a new Jina AI client
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("JINA_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Jina AI client for a named instance, for
+// example one whose reader and search endpoints point at a gateway.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("jina", EndpointReader, EndpointSearch)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := settings.APIKey("JINA_API_KEY")
 	// Jina works without API key but with lower rate limits
 
 	// Allow timeout override via environment variable
@@ -115,13 +125,17 @@ func NewClient() (*Client, error) {
 		},
 	}
 
+	httpClient, err := settings.HTTPClient(timeout)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
-		apiKey: apiKey,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
-		readerBaseURL:    readerBaseURL,
-		searchBaseURL:    searchBaseURL,
+		name:             settings.Name,
+		apiKey:           apiKey,
+		httpClient:       httpClient,
+		readerBaseURL:    settings.BaseURL(EndpointReader, readerBaseURL),
+		searchBaseURL:    settings.BaseURL(EndpointSearch, searchBaseURL),
 		searchRetryCfg:   searchRetryCfg,
 		extractRetryCfg:  extractRetryCfg,
 		searchTimeout:    searchTimeout,
@@ -133,8 +147,16 @@ func NewClient() (*Client, error) {
 	}, nil
 }
 
-// Name returns the provider name
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "jina"
 }
 
@@ -152,6 +174,44 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// Jina endpoint names for BaseURLs and SetBaseURL.
+const (
+	EndpointReader = "reader"
+	EndpointSearch = "search"
+)
+
+// BaseURLs returns the Jina reader and search base URLs.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{EndpointReader: c.readerBaseURL, EndpointSearch: c.searchBaseURL}
+}
+
+// SetBaseURL redirects the reader or search API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	baseURL = strings.TrimRight(baseURL, "/")
+	switch endpoint {
+	case EndpointReader:
+		c.readerBaseURL = baseURL
+	case EndpointSearch:
+		c.searchBaseURL = baseURL
+	default:
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	return nil
+}
+
+// SearchOptionSupport reports how Jina honors normalized search options.
+// Country and language map to gl/hl; domain filters use site: operators.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportUnsupported,
+		IncludeDomains: providers.SupportEmulated,
+		ExcludeDomains: providers.SupportEmulated,
+		Country:        providers.SupportNative,
+		Language:       providers.SupportNative,
+		SafeSearch:     providers.SupportUnsupported,
+	}
+}
+
 // Search performs a web search using Jina AI Search API.
 // Endpoint: POST https://s.jina.ai/
 // Default mode uses X-Respond-With:no-content for lower token spend.
@@ -213,8 +273,10 @@ func (c *Client) searchInternal(ctx context.Context, query string, opts provider
 
 	// Build POST JSON body per official API docs.
 	searchPayload := searchRequest{
-		Query:      query,
+		Query:      providers.ApplyDomainOperators(query, opts.IncludeDomains, opts.ExcludeDomains),
 		NumResults: topN,
+		Country:    strings.ToLower(opts.Country),
+		Language:   providers.LanguageCode(opts.Language),
 	}
 	payloadBytes, err := json.Marshal(searchPayload)
 	if err != nil {
@@ -241,9 +303,7 @@ func (c *Client) searchInternal(ctx context.Context, query string, opts provider
 	// Log the request
 	logRequest(ctx, "POST", searchURL, headers, string(payloadBytes))
 
-	// Create request with trace context for timing
-	traceCtx, timing, finalize := newTraceContext(reqCtx)
-	req, err := http.NewRequestWithContext(traceCtx, "POST", searchURL, bytes.NewReader(payloadBytes))
+	req, err := http.NewRequestWithContext(reqCtx, "POST", searchURL, bytes.NewReader(payloadBytes))
 	if err != nil {
 		providers.LogError(ctx, err.Error(), "request_build", "search request")
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -262,7 +322,7 @@ func (c *Client) searchInternal(ctx context.Context, query string, opts provider
 		req.Header.Set("X-Retain-Images", "none")
 	}
 
-	resp, err := c.httpClient.Do(req) //nolint:gosec // URL is constructed from trusted config
+	resp, err := providers.DoTraced(c.httpClient, req, 0)
 	if err != nil {
 		providers.LogError(ctx, err.Error(), "http", "search request failed")
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -277,12 +337,6 @@ func (c *Client) searchInternal(ctx context.Context, query string, opts provider
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Finalize timing
-	finalize()
-	if timing != nil {
-		timing.TotalDuration = time.Since(start)
-	}
-
 	// Log the response
 	respHeaders := make(map[string]string)
 	respHeaders["Content-Type"] = resp.Header.Get("Content-Type")
@@ -420,9 +474,7 @@ func (c *Client) extractInternal(ctx context.Context, pageURL string, _ provider
 	// Log the request
 	logRequest(ctx, "GET", readerURL, headers, "")
 
-	// Create request with trace context for timing
-	traceCtx, timing, finalize := newTraceContext(ctx)
-	req, err := http.NewRequestWithContext(traceCtx, "GET", readerURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", readerURL, nil)
 	if err != nil {
 		providers.LogError(ctx, err.Error(), "request_build", "extract request")
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -440,7 +492,7 @@ func (c *Client) extractInternal(ctx context.Context, pageURL string, _ provider
 		req.Header.Set("X-Retain-Images", "none")
 	}
 
-	resp, err := c.httpClient.Do(req) //nolint:gosec // URL is constructed from trusted config
+	resp, err := providers.DoTraced(c.httpClient, req, 0)
 	if err != nil {
 		providers.LogError(ctx, err.Error(), "http", "extract request failed")
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -455,12 +507,6 @@ func (c *Client) extractInternal(ctx context.Context, pageURL string, _ provider
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Finalize timing
-	finalize()
-	if timing != nil {
-		timing.TotalDuration = time.Since(start)
-	}
-
 	// Log the response
 	respHeaders := make(map[string]string)
 	respHeaders["Content-Type"] = resp.Header.Get("Content-Type")
@@ -672,11 +718,6 @@ func logResponse(ctx context.Context, statusCode int, headers map[string]string,
 	providers.LogResponse(ctx, statusCode, headers, body, bodySize, duration)
 }
 
-// newTraceContext creates an httptrace context for timing breakdown if debug is enabled
-func newTraceContext(ctx context.Context) (context.Context, *debug.TimingBreakdown, func()) {
-	return providers.NewTraceContext(ctx)
-}
-
 // isJSONContent checks if content type indicates JSON
 func isJSONContent(contentType string) bool {
 	return strings.Contains(contentType, "application/json") || strings.Contains(contentType, "text/json")
@@ -912,6 +953,8 @@ func extractTitleFromMarkdown(content string) string {
 type searchRequest struct {
 	Query      string `json:"q"`
 	NumResults int    `json:"num_results,omitempty"`
+	Country    string `json:"gl,omitempty"`
+	Language   string `json:"hl,omitempty"`
 }
 
 // Response types
@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lamim/SanityWebEval/internal/debug"
 	"github.com/lamim/SanityWebEval/internal/providers"
 )
 
@@ -203,6 +207,59 @@ func TestSearch_PostRequestWithJSONResponse(t *testing.T) {
 	}
 }
 
+func TestSearch_DebugHARRecordsTimings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"code":200,"data":[{"title":"Paris","url":"https://en.wikipedia.org/wiki/Paris","content":"Capital of France"}]}`)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		httpClient:      &http.Client{Transport: &http.Transport{}},
+		searchBaseURL:   server.URL,
+		searchRetryCfg:  providers.RetryConfig{MaxRetries: 0},
+		searchTimeout:   2 * time.Second,
+		searchMaxResult: 3,
+	}
+
+	logger := debug.NewLogger(true, false, t.TempDir())
+	if err := logger.SetFormat(debug.FormatHAR); err != nil {
+		t.Fatalf("SetFormat failed: %v", err)
+	}
+	testLog := logger.StartTest("jina", "Search - Paris", "search")
+	ctx := providers.WithTestLog(providers.WithDebugLogger(context.Background(), logger), testLog)
+	if _, err := client.Search(ctx, "paris", providers.SearchOptions{}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	logger.EndTest(testLog)
+	if err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(logger.GetOutputPath(), "jina.har"))
+	if err != nil {
+		t.Fatalf("failed reading HAR: %v", err)
+	}
+	var har debug.HAR
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatalf("failed parsing HAR: %v", err)
+	}
+	if len(har.Log.Entries) != 1 {
+		t.Fatalf("expected 1 HAR entry, got %d", len(har.Log.Entries))
+	}
+	timings := har.Log.Entries[0].Timings
+	if timings.Connect < 0 {
+		t.Errorf("expected connect time for a fresh connection, got %+v", timings)
+	}
+	if timings.Wait < 20 {
+		t.Errorf("expected wait to cover the server's 20ms delay, got %+v", timings)
+	}
+	if timings.Receive < 0 {
+		t.Errorf("expected receive time, got %+v", timings)
+	}
+}
+
 func TestExtract_JSONResponseWithWrappedData(t *testing.T) {
 	var gotAccept, gotTokenBudget, gotRetainImages string
 	clientHTTP := &http.Client{
@@ -12,34 +12,74 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"sync"
 	"time"
 
 	md "github.com/JohannesKaufmann/html-to-markdown/v2"
 	"github.com/gocolly/colly/v2"
+	"github.com/lamim/SanityWebEval/internal/document"
 	"github.com/lamim/SanityWebEval/internal/providers"
 )
 
 // Client represents a local crawler/scraper using Colly
 type Client struct {
+	name       string
 	httpClient *http.Client
+	// transport and timeout override Colly's defaults when settings set them.
+	transport http.RoundTripper
+	timeout   time.Duration
 }
 
 // NewClient creates a new local crawler client
 func NewClient() (*Client, error) {
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a local crawler for a named instance. The
+// local crawler has no API, so base URLs cannot be set; timeout, headers and
+// proxy apply to the pages it fetches.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("local")
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := settings.HTTPClient(30 * time.Second)
+	if err != nil {
+		return nil, err
+	}
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		name:       settings.Name,
+		httpClient: httpClient,
+		transport:  httpClient.Transport,
+		timeout:    settings.Timeout,
 	}, nil
 }
 
-// Name returns the provider name
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "local"
 }
 
+// configureCollector applies the instance's transport and timeout to a collector.
+func (c *Client) configureCollector(collector *colly.Collector) {
+	if c.transport != nil {
+		collector.WithTransport(c.transport)
+	}
+	if c.timeout > 0 {
+		collector.SetRequestTimeout(c.timeout)
+	}
+}
+
 // Capabilities returns local provider operation support levels.
 func (c *Client) Capabilities() providers.CapabilitySet {
 	return providers.CapabilitySet{
@@ -62,7 +102,8 @@ func (c *Client) Search(_ context.Context, _ string, _ providers.SearchOptions)
 }
 
 // Extract visits a single URL and converts the HTML content to Markdown.
-// It extracts the title and main content from the page.
+// It extracts the title and main content from the page. PDF, DOCX and
+// plain-text responses are converted with the pure-Go document extractor.
 func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
 	start := time.Now()
 
@@ -82,6 +123,8 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 		htmlContent string
 		extractErr  error
 		done        bool
+		doc         *document.Document
+		docType     string
 	)
 
 	// Create collector (synchronous mode for single page)
@@ -89,6 +132,7 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 		colly.UserAgent("Search-API-Bench/1.0 (Local Crawler)"),
 		colly.MaxDepth(1),
 	)
+	c.configureCollector(collector)
 
 	// Set up context cancellation handling
 	collector.OnRequest(func(r *colly.Request) {
@@ -100,6 +144,21 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 		}
 	})
 
+	// Non-HTML documents never reach the OnHTML callbacks.
+	collector.OnResponse(func(r *colly.Response) {
+		docType = r.Headers.Get("Content-Type")
+		format := document.DetectFormat(docType, r.Body)
+		if format == "" || format == document.FormatHTML {
+			return
+		}
+		parsed, err := document.Extract(r.Body, format)
+		if err != nil {
+			extractErr = fmt.Errorf("failed to extract %s document: %w", format, err)
+			return
+		}
+		doc = parsed
+	})
+
 	// Extract title
 	collector.OnHTML("title", func(e *colly.HTMLElement) {
 		title = strings.TrimSpace(e.Text)
@@ -144,6 +203,10 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 		return nil, ctx.Err()
 	}
 
+	if doc != nil {
+		return documentResult(pageURL, doc, docType, time.Since(start)), nil
+	}
+
 	// Convert HTML to Markdown
 	markdown, err := md.ConvertString(htmlContent)
 	if err != nil {
@@ -176,6 +239,41 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 	}, nil
 }
 
+// documentResult converts an extracted document into an ExtractResult. The
+// metadata records the detected document type and structure so the runner
+// can compare it with the test's expectations.
+func documentResult(pageURL string, doc *document.Document, contentType string, latency time.Duration) *providers.ExtractResult {
+	title := doc.Title
+	if title == "" {
+		if u, err := url.Parse(pageURL); err == nil {
+			title = path.Base(u.Path)
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"generator":     "local-document",
+		"source":        "document-" + string(doc.Format),
+		"url":           pageURL,
+		"contentType":   contentType,
+		"document_type": string(doc.Format),
+		"tables":        doc.Tables,
+	}
+	if doc.Pages > 0 {
+		metadata["pages"] = doc.Pages
+	}
+
+	return &providers.ExtractResult{
+		URL:          pageURL,
+		Title:        title,
+		Content:      doc.Text,
+		Markdown:     doc.Text,
+		Metadata:     metadata,
+		Latency:      latency,
+		CreditsUsed:  0,
+		RequestCount: 1,
+	}
+}
+
 // Crawl recursively visits URLs starting from the given URL.
 // It respects MaxPages and MaxDepth options, using async processing
 // with polite rate limiting.
@@ -192,6 +290,7 @@ func (c *Client) Crawl(ctx context.Context, startURL string, opts providers.Craw
 	if err != nil {
 		return nil, err
 	}
+	c.configureCollector(collector)
 
 	collector.OnRequest(func(r *colly.Request) {
 		if err := ctx.Err(); err != nil {
@@ -202,6 +202,57 @@ func TestClientExtractInvalidURL(t *testing.T) {
 	}
 }
 
+func TestClientExtractDocuments(t *testing.T) {
+	content := "BT /F1 12 Tf 72 720 Td (Annual figures) Tj 0 -14 Td (Net income rose) Tj ET"
+	pdf := "%PDF-1.4\n" +
+		"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n" +
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n" +
+		fmt.Sprintf("4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(content), content) +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files/report.pdf", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		fmt.Fprint(w, pdf)
+	})
+	mux.HandleFunc("/files/notes.txt", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, "| a | b |\n|---|---|\n| 1 | 2 |\n")
+	})
+	server := testutil.NewIPv4Server(t, mux)
+	defer server.Close()
+
+	client, _ := NewClient()
+	ctx := context.Background()
+	opts := providers.DefaultExtractOptions()
+
+	result, err := client.Extract(ctx, server.URL+"/files/report.pdf", opts)
+	if err != nil {
+		t.Fatalf("Extract(pdf) error = %v", err)
+	}
+	if result.Content != "Annual figures\nNet income rose" {
+		t.Errorf("Extract(pdf) Content = %q", result.Content)
+	}
+	if result.Title != "report.pdf" {
+		t.Errorf("Extract(pdf) Title = %q, want report.pdf", result.Title)
+	}
+	if result.Metadata["document_type"] != "pdf" || result.Metadata["pages"] != 1 {
+		t.Errorf("Extract(pdf) Metadata = %v", result.Metadata)
+	}
+	if result.CreditsUsed != 0 {
+		t.Errorf("Extract(pdf) CreditsUsed = %d, want 0", result.CreditsUsed)
+	}
+
+	result, err = client.Extract(ctx, server.URL+"/files/notes.txt", opts)
+	if err != nil {
+		t.Fatalf("Extract(text) error = %v", err)
+	}
+	if result.Metadata["document_type"] != "text" || result.Metadata["tables"] != 1 {
+		t.Errorf("Extract(text) Metadata = %v", result.Metadata)
+	}
+}
+
 func TestClientCrawl(t *testing.T) {
 	server := setupTestServer(t)
 	defer server.Close()
@@ -33,6 +33,7 @@ const (
 
 // Client represents a Mixedbread AI API client
 type Client struct {
+	name       string
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
@@ -41,27 +42,57 @@ type Client struct {
 
 // NewClient creates a new Mixedbread AI client
 func NewClient() (*Client, error) {
-	// Support both MXB_API_KEY (short form) and MIXEDBREAD_API_KEY (verbose form)
-	apiKey := os.Getenv("MXB_API_KEY")
-	if apiKey == "" {
-		apiKey = os.Getenv("MIXEDBREAD_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Mixedbread AI client for a named instance,
+// for example a Mixedbread-compatible endpoint at a custom base URL.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("mixedbread", providers.EndpointAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKey string
+	if settings.APIKeyEnv != "" {
+		apiKey = settings.APIKey("")
+		if apiKey == "" {
+			return nil, fmt.Errorf("%s environment variable not set", settings.APIKeyEnv)
+		}
+	} else {
+		// Support both MXB_API_KEY (short form) and MIXEDBREAD_API_KEY (verbose form)
+		apiKey = os.Getenv("MXB_API_KEY")
+		if apiKey == "" {
+			apiKey = os.Getenv("MIXEDBREAD_API_KEY")
+		}
+		if apiKey == "" {
+			return nil, fmt.Errorf("MXB_API_KEY (or MIXEDBREAD_API_KEY) environment variable not set")
+		}
 	}
-	if apiKey == "" {
-		return nil, fmt.Errorf("MXB_API_KEY (or MIXEDBREAD_API_KEY) environment variable not set")
+	httpClient, err := settings.HTTPClient(defaultTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
-		retryCfg: providers.DefaultRetryConfig(),
+		name:       settings.Name,
+		apiKey:     apiKey,
+		baseURL:    settings.BaseURL(providers.EndpointAPI, defaultBaseURL),
+		httpClient: httpClient,
+		retryCfg:   providers.DefaultRetryConfig(),
 	}, nil
 }
 
-// Name returns the provider name
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "mixedbread"
 }
 
@@ -79,6 +110,33 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// BaseURLs returns the Mixedbread API base URL.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: c.baseURL}
+}
+
+// SetBaseURL redirects the Mixedbread API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	return nil
+}
+
+// SearchOptionSupport reports how Mixedbread honors normalized search options.
+// The web store search is semantic and exposes no filters, so none are honored.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportUnsupported,
+		IncludeDomains: providers.SupportUnsupported,
+		ExcludeDomains: providers.SupportUnsupported,
+		Country:        providers.SupportUnsupported,
+		Language:       providers.SupportUnsupported,
+		SafeSearch:     providers.SupportUnsupported,
+	}
+}
+
 // Search performs a web search using Mixedbread AI Search API
 // Endpoint: POST /v1/stores/search
 // Uses the public "mixedbread/web" store for real-time web search
@@ -181,8 +239,10 @@ func (c *Client) Extract(ctx context.Context, pageURL string, opts providers.Ext
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 
+	providers.LogRequest(ctx, "GET", pageURL, providers.HeadersToMap(req.Header), "")
 	resp, err := c.retryCfg.DoHTTPRequestDetailed(ctx, c.httpClient, req)
 	if err != nil {
+		providers.LogError(ctx, err.Error(), "http", "extract request failed")
 		return nil, err
 	}
 	providers.LogResponse(ctx, resp.StatusCode, providers.HeadersToMap(resp.Headers), string(resp.Body), len(resp.Body), time.Since(start))
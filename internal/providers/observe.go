@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"time"
+)
+
+// HTTPAttempt describes one HTTP request sent to a provider API.
+type HTTPAttempt struct {
+	Method     string        `json:"method"`
+	URL        string        `json:"url"`                   // scheme, host and path; the query is left out
+	Attempt    int           `json:"attempt"`               // 1 for the first request, 2 for the first retry, ...
+	StatusCode int           `json:"status_code,omitempty"` // unset when no response arrived
+	Duration   time.Duration `json:"duration"`              // nanoseconds until the response headers or the error
+	Error      string        `json:"error,omitempty"`       // transport error or HTTP status text for 4xx/5xx
+}
+
+// HTTPRetry describes the wait before a provider request is retried.
+type HTTPRetry struct {
+	Retry   int           `json:"retry"`   // 1 for the first retry; the retried request is attempt Retry+1
+	Backoff time.Duration `json:"backoff"` // nanoseconds waited before the retry
+	Reason  string        `json:"reason"`  // error, status or rate_limit
+}
+
+// HTTPObserver receives the HTTP attempts and retries made under a context.
+// Either func may be nil.
+type HTTPObserver struct {
+	Attempt func(HTTPAttempt)
+	Retry   func(HTTPRetry)
+}
+
+// WithHTTPObserver returns a context whose provider HTTP attempts and retries
+// are reported to obs.
+func WithHTTPObserver(ctx context.Context, obs *HTTPObserver) context.Context {
+	return context.WithValue(ctx, httpObserverKey, obs)
+}
+
+func httpObserverFromContext(ctx context.Context) *HTTPObserver {
+	if obs, ok := ctx.Value(httpObserverKey).(*HTTPObserver); ok {
+		return obs
+	}
+	return nil
+}
+
+func observeAttempt(ctx context.Context, attempt HTTPAttempt) {
+	if obs := httpObserverFromContext(ctx); obs != nil && obs.Attempt != nil {
+		obs.Attempt(attempt)
+	}
+}
+
+func observeRetry(ctx context.Context, retry HTTPRetry) {
+	if obs := httpObserverFromContext(ctx); obs != nil && obs.Retry != nil {
+		obs.Retry(retry)
+	}
+}
@@ -126,11 +126,8 @@ func (rc *RetryConfig) DoWithRetry(ctx context.Context, operation func() error)
 		// Calculate and apply backoff
 		backoff := rc.CalculateBackoff(attempt)
 
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context cancelled during retry: %w", ctx.Err())
-		case <-time.After(backoff):
-			// Continue to next attempt
+		if err := sleepBackoff(ctx, backoff, attempt, "error"); err != nil {
+			return fmt.Errorf("context cancelled during retry: %w", err)
 		}
 	}
 
@@ -284,7 +281,7 @@ func (rc *RetryConfig) DoHTTPRequestDetailed(ctx context.Context, client *http.C
 			reqClone.Body = io.NopCloser(bytes.NewReader(requestBody))
 		}
 
-		resp, err := client.Do(reqClone) //nolint:gosec // URL is constructed from trusted config
+		resp, err := DoTraced(client, reqClone, attempt)
 		if err != nil {
 			lastErr = fmt.Errorf("request failed: %w", err)
 			if attempt == rc.MaxRetries {
@@ -294,7 +291,7 @@ func (rc *RetryConfig) DoHTTPRequestDetailed(ctx context.Context, client *http.C
 				return nil, lastErr
 			}
 			backoff := rc.CalculateBackoff(attempt)
-			if err := SleepWithContext(ctx, backoff); err != nil {
+			if err := sleepBackoff(ctx, backoff, attempt, "error"); err != nil {
 				return nil, err
 			}
 			continue
@@ -316,7 +313,9 @@ func (rc *RetryConfig) DoHTTPRequestDetailed(ctx context.Context, client *http.C
 
 			// For 429 responses, prefer the server's retry-after hint
 			backoff := rc.CalculateBackoff(attempt)
+			reason := "status"
 			if resp.StatusCode == http.StatusTooManyRequests {
+				reason = "rate_limit"
 				if ra := parseRetryAfter(headers, body); ra > 0 {
 					// Use server hint with small jitter, capped at MaxBackoff
 					//nolint:gosec // Math/rand/v2 is sufficient for jitter
@@ -328,7 +327,7 @@ func (rc *RetryConfig) DoHTTPRequestDetailed(ctx context.Context, client *http.C
 				}
 			}
 
-			if err := SleepWithContext(ctx, backoff); err != nil {
+			if err := sleepBackoff(ctx, backoff, attempt, reason); err != nil {
 				return nil, err
 			}
 			continue
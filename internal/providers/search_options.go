@@ -0,0 +1,170 @@
+package providers
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Normalized search option names used for capability reporting.
+const (
+	SearchOptionTimeRange      = "time_range"
+	SearchOptionIncludeDomains = "include_domains"
+	SearchOptionExcludeDomains = "exclude_domains"
+	SearchOptionCountry        = "country"
+	SearchOptionLanguage       = "language"
+	SearchOptionSafeSearch     = "safe_search"
+)
+
+// SearchOptionSupport describes how a provider honors each normalized search option.
+// Native means the option maps to an API parameter; emulated means the client
+// approximates it (for example with site: query operators).
+type SearchOptionSupport struct {
+	TimeRange      SupportLevel
+	IncludeDomains SupportLevel
+	ExcludeDomains SupportLevel
+	Country        SupportLevel
+	Language       SupportLevel
+	SafeSearch     SupportLevel
+}
+
+// ForOption returns the support level for the named option.
+func (s SearchOptionSupport) ForOption(name string) SupportLevel {
+	var level SupportLevel
+	switch name {
+	case SearchOptionTimeRange:
+		level = s.TimeRange
+	case SearchOptionIncludeDomains:
+		level = s.IncludeDomains
+	case SearchOptionExcludeDomains:
+		level = s.ExcludeDomains
+	case SearchOptionCountry:
+		level = s.Country
+	case SearchOptionLanguage:
+		level = s.Language
+	case SearchOptionSafeSearch:
+		level = s.SafeSearch
+	}
+	if level == "" {
+		return SupportUnsupported
+	}
+	return level
+}
+
+// SearchOptionReporter is implemented by providers that can describe which
+// normalized search options they honor.
+type SearchOptionReporter interface {
+	SearchOptionSupport() SearchOptionSupport
+}
+
+// SearchOptionSupportFor returns the provider's option support, treating
+// providers that do not report it as supporting none of the options.
+func SearchOptionSupportFor(p Provider) SearchOptionSupport {
+	if reporter, ok := p.(SearchOptionReporter); ok {
+		return reporter.SearchOptionSupport()
+	}
+	return SearchOptionSupport{}
+}
+
+// SearchRequestOptionReporter is implemented by providers that honor an
+// option for some values only, such as the countries an API can name.
+type SearchRequestOptionReporter interface {
+	SearchRequestOptionSupport(opts SearchOptions) SearchOptionSupport
+}
+
+// SearchRequestOptionSupport returns the provider's option support for one
+// request's values, falling back to SearchOptionSupportFor.
+func SearchRequestOptionSupport(p Provider, opts SearchOptions) SearchOptionSupport {
+	if reporter, ok := p.(SearchRequestOptionReporter); ok {
+		return reporter.SearchRequestOptionSupport(opts)
+	}
+	return SearchOptionSupportFor(p)
+}
+
+// RequestedOptions lists the normalized option names set on opts.
+func (o SearchOptions) RequestedOptions() []string {
+	requested := make([]string, 0, 6)
+	if o.TimeRange != "" {
+		requested = append(requested, SearchOptionTimeRange)
+	}
+	if len(o.IncludeDomains) > 0 {
+		requested = append(requested, SearchOptionIncludeDomains)
+	}
+	if len(o.ExcludeDomains) > 0 {
+		requested = append(requested, SearchOptionExcludeDomains)
+	}
+	if o.Country != "" {
+		requested = append(requested, SearchOptionCountry)
+	}
+	if o.Language != "" {
+		requested = append(requested, SearchOptionLanguage)
+	}
+	if o.SafeSearch != "" {
+		requested = append(requested, SearchOptionSafeSearch)
+	}
+	return requested
+}
+
+// ApplyDomainOperators appends site: and -site: operators to query so providers
+// without native domain filters can emulate include/exclude lists.
+func ApplyDomainOperators(query string, include, exclude []string) string {
+	parts := []string{query}
+	sites := make([]string, 0, len(include))
+	for _, domain := range include {
+		if d := NormalizeDomain(domain); d != "" {
+			sites = append(sites, "site:"+d)
+		}
+	}
+	switch len(sites) {
+	case 0:
+	case 1:
+		parts = append(parts, sites[0])
+	default:
+		parts = append(parts, "("+strings.Join(sites, " OR ")+")")
+	}
+	for _, domain := range exclude {
+		if d := NormalizeDomain(domain); d != "" {
+			parts = append(parts, "-site:"+d)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// NormalizeDomain lowercases a domain or URL and strips scheme, "www." and path.
+func NormalizeDomain(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return ""
+	}
+	if strings.Contains(raw, "://") {
+		if parsed, err := url.Parse(raw); err == nil {
+			raw = parsed.Hostname()
+		}
+	}
+	if idx := strings.IndexAny(raw, "/?#"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	raw = strings.TrimPrefix(raw, "www.")
+	return strings.TrimSuffix(raw, ".")
+}
+
+// HostMatchesDomain reports whether rawURL is served from domain or one of its subdomains.
+func HostMatchesDomain(rawURL, domain string) bool {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return false
+	}
+	host := NormalizeDomain(rawURL)
+	if idx := strings.LastIndex(host, ":"); idx >= 0 {
+		host = host[:idx]
+	}
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// LanguageCode reduces a locale such as "de-DE" or "ja_JP" to its ISO 639-1 code.
+func LanguageCode(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if idx := strings.IndexAny(raw, "-_"); idx >= 0 {
+		raw = raw[:idx]
+	}
+	return raw
+}
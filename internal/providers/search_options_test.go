@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyDomainOperators(t *testing.T) {
+	got := ApplyDomainOperators("rust ownership", []string{"https://www.rust-lang.org/learn", "doc.rust-lang.org"}, []string{"reddit.com"})
+	want := "rust ownership (site:rust-lang.org OR site:doc.rust-lang.org) -site:reddit.com"
+	if got != want {
+		t.Fatalf("ApplyDomainOperators() = %q, want %q", got, want)
+	}
+
+	if got := ApplyDomainOperators("q", []string{"go.dev"}, nil); got != "q site:go.dev" {
+		t.Fatalf("single include = %q", got)
+	}
+	if got := ApplyDomainOperators("q", nil, nil); got != "q" {
+		t.Fatalf("no domains = %q", got)
+	}
+}
+
+func TestHostMatchesDomain(t *testing.T) {
+	cases := []struct {
+		url    string
+		domain string
+		want   bool
+	}{
+		{"https://go.dev/doc", "go.dev", true},
+		{"https://pkg.go.dev/net/http", "go.dev", true},
+		{"https://www.example.com:8443/a", "example.com", true},
+		{"https://notgo.dev/", "go.dev", false},
+		{"https://example.com", "", false},
+	}
+	for _, tc := range cases {
+		if got := HostMatchesDomain(tc.url, tc.domain); got != tc.want {
+			t.Errorf("HostMatchesDomain(%q, %q) = %v, want %v", tc.url, tc.domain, got, tc.want)
+		}
+	}
+}
+
+func TestSearchOptionSupportFor_DefaultsToUnsupported(t *testing.T) {
+	support := SearchOptionSupportFor(nil)
+	if support.ForOption(SearchOptionCountry) != SupportUnsupported {
+		t.Fatalf("expected unsupported, got %s", support.ForOption(SearchOptionCountry))
+	}
+	if (SearchOptionSupport{Language: SupportNative}).ForOption(SearchOptionLanguage) != SupportNative {
+		t.Fatal("expected native language support")
+	}
+}
+
+func TestSearchOptions_RequestedOptions(t *testing.T) {
+	opts := DefaultSearchOptions()
+	if got := opts.RequestedOptions(); len(got) != 0 {
+		t.Fatalf("expected no requested options by default, got %v", got)
+	}
+	opts.ExcludeDomains = []string{"pinterest.com"}
+	opts.Language = "de"
+	want := []string{SearchOptionExcludeDomains, SearchOptionLanguage}
+	if got := opts.RequestedOptions(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("RequestedOptions() = %v, want %v", got, want)
+	}
+}
+
+func TestLanguageCode(t *testing.T) {
+	for raw, want := range map[string]string{"de-DE": "de", "ja_JP": "ja", " EN ": "en", "": ""} {
+		if got := LanguageCode(raw); got != want {
+			t.Errorf("LanguageCode(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Settings adjusts how a provider client reaches its API. Zero values keep
+// the client's defaults. The same settings can come from a [providers.<name>]
+// config section or from <NAME>_* environment variables.
+type Settings struct {
+	// Name is the instance name reported by Name(); empty uses the provider type.
+	Name string
+	// BaseURLs maps endpoint names (EndpointAPI for most providers) to base URLs.
+	BaseURLs map[string]string
+	// Timeout is the HTTP client timeout.
+	Timeout time.Duration
+	// Headers are added to every API request.
+	Headers map[string]string
+	// Proxy is an HTTP(S) proxy URL for API requests.
+	Proxy string
+	// APIKeyEnv names the environment variable holding the API key.
+	APIKeyEnv string
+}
+
+// TypeOf returns the provider type of p. Named instances report their
+// instance name from Name() and their type from Type(); other providers use
+// Name() for both.
+func TypeOf(p Provider) string {
+	if typed, ok := p.(interface{ Type() string }); ok {
+		return typed.Type()
+	}
+	return p.Name()
+}
+
+// EnvPrefix returns the environment variable prefix for an instance name,
+// for example "FIRECRAWL_SELFHOSTED" for "firecrawl-selfhosted".
+func EnvPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, strings.TrimSpace(name))
+}
+
+// Resolve fills in the instance name, overlays environment variables and
+// checks the settings against the provider's endpoints. For an instance
+// prefix P it reads P_BASE_URL (EndpointAPI), P_<ENDPOINT>_BASE_URL (other
+// endpoints), P_TIMEOUT, P_PROXY, P_HEADERS ("Key=Value,Key=Value") and
+// P_API_KEY_ENV; environment values take precedence over the config.
+func (s Settings) Resolve(providerType string, endpoints ...string) (Settings, error) {
+	if strings.TrimSpace(s.Name) == "" {
+		s.Name = providerType
+	}
+	s.Name = strings.ToLower(strings.TrimSpace(s.Name))
+	s.BaseURLs = copyStringMap(s.BaseURLs)
+	s.Headers = copyStringMap(s.Headers)
+
+	prefix := EnvPrefix(s.Name)
+	for _, endpoint := range endpoints {
+		key := prefix + "_" + EnvPrefix(endpoint) + "_BASE_URL"
+		if endpoint == EndpointAPI {
+			key = prefix + "_BASE_URL"
+		}
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			s.BaseURLs[endpoint] = v
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "_TIMEOUT")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("invalid %s_TIMEOUT: %w", prefix, err)
+		}
+		s.Timeout = d
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "_PROXY")); v != "" {
+		s.Proxy = v
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "_HEADERS")); v != "" {
+		headers, err := ParseHeaders(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("invalid %s_HEADERS: %w", prefix, err)
+		}
+		for k, hv := range headers {
+			s.Headers[k] = hv
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(prefix + "_API_KEY_ENV")); v != "" {
+		s.APIKeyEnv = v
+	}
+
+	if err := s.validate(providerType, endpoints); err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
+
+func (s Settings) validate(providerType string, endpoints []string) error {
+	for endpoint, baseURL := range s.BaseURLs {
+		if !containsString(endpoints, endpoint) {
+			if len(endpoints) == 0 {
+				return fmt.Errorf("%s: %s provider has no API base URL to override", s.Name, providerType)
+			}
+			return fmt.Errorf("%s: %w (valid: %s)", s.Name, UnknownEndpointError(providerType, endpoint), strings.Join(endpoints, ", "))
+		}
+		if err := validateHTTPURL(baseURL); err != nil {
+			return fmt.Errorf("%s: invalid %s base URL: %w", s.Name, endpoint, err)
+		}
+	}
+	if s.Timeout < 0 {
+		return fmt.Errorf("%s: timeout must not be negative", s.Name)
+	}
+	if s.Proxy != "" {
+		if err := validateHTTPURL(s.Proxy); err != nil {
+			return fmt.Errorf("%s: invalid proxy: %w", s.Name, err)
+		}
+	}
+	for key := range s.Headers {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%s: header names must not be empty", s.Name)
+		}
+	}
+	return nil
+}
+
+// BaseURL returns the configured base URL for endpoint, or fallback.
+func (s Settings) BaseURL(endpoint, fallback string) string {
+	if v := s.BaseURLs[endpoint]; v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return fallback
+}
+
+// APIKey reads the API key from APIKeyEnv, or from defaultEnv when unset.
+func (s Settings) APIKey(defaultEnv string) string {
+	if s.APIKeyEnv != "" {
+		return os.Getenv(s.APIKeyEnv)
+	}
+	return os.Getenv(defaultEnv)
+}
+
+// APIKeyVar returns the environment variable the API key is read from.
+func (s Settings) APIKeyVar(defaultEnv string) string {
+	if s.APIKeyEnv != "" {
+		return s.APIKeyEnv
+	}
+	return defaultEnv
+}
+
+// Transport returns a round tripper applying the proxy and headers, or nil
+// when neither is set.
+func (s Settings) Transport() (http.RoundTripper, error) {
+	if s.Proxy == "" && len(s.Headers) == 0 {
+		return nil, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if s.Proxy != "" {
+		proxyURL, err := url.Parse(s.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if len(s.Headers) == 0 {
+		return transport, nil
+	}
+	return &headerTransport{base: transport, headers: s.Headers}, nil
+}
+
+// HTTPClient builds the client's HTTP client, using defaultTimeout unless
+// Timeout is set.
+func (s Settings) HTTPClient(defaultTimeout time.Duration) (*http.Client, error) {
+	timeout := defaultTimeout
+	if s.Timeout > 0 {
+		timeout = s.Timeout
+	}
+	transport, err := s.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// headerTransport sets configured headers on outgoing requests. Headers the
+// client sets itself, such as Authorization, are overridden.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ParseHeaders parses "Key=Value,Key=Value" into a header map.
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected Key=Value, got %q", part)
+		}
+		headers[key] = strings.TrimSpace(val)
+	}
+	return headers, nil
+}
+
+func validateHTTPURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q must be an http(s) URL", raw)
+	}
+	return nil
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+)
+
+func TestEnvPrefix(t *testing.T) {
+	if got := EnvPrefix("firecrawl-selfhosted"); got != "FIRECRAWL_SELFHOSTED" {
+		t.Errorf("EnvPrefix = %q", got)
+	}
+	if got := EnvPrefix("brave.internal"); got != "BRAVE_INTERNAL" {
+		t.Errorf("EnvPrefix = %q", got)
+	}
+}
+
+func TestSettingsResolve_EnvOverridesConfig(t *testing.T) {
+	t.Setenv("FIRECRAWL_SELFHOSTED_BASE_URL", "http://firecrawl.internal:3002/v2/")
+	t.Setenv("FIRECRAWL_SELFHOSTED_TIMEOUT", "5s")
+	t.Setenv("FIRECRAWL_SELFHOSTED_HEADERS", "X-Team=search, X-Env=staging")
+	t.Setenv("FIRECRAWL_SELFHOSTED_PROXY", "")
+
+	settings, err := Settings{
+		Name:     "Firecrawl-SelfHosted",
+		BaseURLs: map[string]string{EndpointAPI: "http://old.internal"},
+		Timeout:  time.Minute,
+		Headers:  map[string]string{"X-Team": "config", "X-Only-Config": "1"},
+	}.Resolve("firecrawl", EndpointAPI)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if settings.Name != "firecrawl-selfhosted" {
+		t.Errorf("expected lowercased instance name, got %q", settings.Name)
+	}
+	if got := settings.BaseURL(EndpointAPI, "https://api.firecrawl.dev/v2"); got != "http://firecrawl.internal:3002/v2" {
+		t.Errorf("expected env base URL without trailing slash, got %q", got)
+	}
+	if settings.Timeout != 5*time.Second {
+		t.Errorf("expected env timeout, got %s", settings.Timeout)
+	}
+	if settings.Headers["X-Team"] != "search" || settings.Headers["X-Env"] != "staging" || settings.Headers["X-Only-Config"] != "1" {
+		t.Errorf("unexpected merged headers: %v", settings.Headers)
+	}
+}
+
+func TestSettingsResolve_DefaultsAndEndpoints(t *testing.T) {
+	settings, err := Settings{}.Resolve("tavily", EndpointAPI)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if settings.Name != "tavily" {
+		t.Errorf("expected type as default name, got %q", settings.Name)
+	}
+	if got := settings.BaseURL(EndpointAPI, "https://api.tavily.com"); got != "https://api.tavily.com" {
+		t.Errorf("expected fallback base URL, got %q", got)
+	}
+
+	_, err = Settings{BaseURLs: map[string]string{EndpointAPI: "https://gateway"}}.Resolve("jina", "reader", "search")
+	if err == nil || !strings.Contains(err.Error(), `no "api" endpoint`) {
+		t.Errorf("expected unknown endpoint error, got %v", err)
+	}
+	_, err = Settings{BaseURLs: map[string]string{EndpointAPI: "https://gateway"}}.Resolve("local")
+	if err == nil || !strings.Contains(err.Error(), "no API base URL") {
+		t.Errorf("expected error for base URL on local, got %v", err)
+	}
+	_, err = Settings{Proxy: "not a url"}.Resolve("brave", EndpointAPI)
+	if err == nil {
+		t.Error("expected error for invalid proxy")
+	}
+}
+
+func TestSettingsAPIKey(t *testing.T) {
+	t.Setenv("FIRECRAWL_API_KEY", "cloud-key")
+	t.Setenv("SELFHOSTED_FIRECRAWL_KEY", "local-key")
+
+	if got := (Settings{}).APIKey("FIRECRAWL_API_KEY"); got != "cloud-key" {
+		t.Errorf("expected default env key, got %q", got)
+	}
+	s := Settings{APIKeyEnv: "SELFHOSTED_FIRECRAWL_KEY"}
+	if got := s.APIKey("FIRECRAWL_API_KEY"); got != "local-key" {
+		t.Errorf("expected api_key_env key, got %q", got)
+	}
+	if got := s.APIKeyVar("FIRECRAWL_API_KEY"); got != "SELFHOSTED_FIRECRAWL_KEY" {
+		t.Errorf("unexpected key variable %q", got)
+	}
+}
+
+func TestSettingsHTTPClient_HeadersAndProxy(t *testing.T) {
+	var gotHeader, gotTarget string
+	proxy := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute target URL.
+		gotHeader = r.Header.Get("X-Team")
+		gotTarget = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	client, err := Settings{
+		Timeout: 3 * time.Second,
+		Headers: map[string]string{"X-Team": "search"},
+		Proxy:   proxy.URL,
+	}.HTTPClient(time.Minute)
+	if err != nil {
+		t.Fatalf("HTTPClient failed: %v", err)
+	}
+	if client.Timeout != 3*time.Second {
+		t.Errorf("expected configured timeout, got %s", client.Timeout)
+	}
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://api.example.test/search", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if gotHeader != "search" {
+		t.Errorf("expected configured header, got %q", gotHeader)
+	}
+	if gotTarget != "http://api.example.test/search" {
+		t.Errorf("expected request to be proxied, got target %q", gotTarget)
+	}
+
+	plain, err := Settings{}.HTTPClient(time.Minute)
+	if err != nil {
+		t.Fatalf("HTTPClient failed: %v", err)
+	}
+	if plain.Timeout != time.Minute || plain.Transport != nil {
+		t.Errorf("expected default client, got timeout %s transport %v", plain.Timeout, plain.Transport)
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := ParseHeaders("X-A=1, X-B = two ,")
+	if err != nil {
+		t.Fatalf("ParseHeaders failed: %v", err)
+	}
+	if len(headers) != 2 || headers["X-A"] != "1" || headers["X-B"] != "two" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+	if _, err := ParseHeaders("missing-separator"); err == nil {
+		t.Error("expected error for header without '='")
+	}
+}
+
+type namedProvider struct {
+	Provider
+	name, typ string
+}
+
+func (p namedProvider) Name() string { return p.name }
+func (p namedProvider) Type() string { return p.typ }
+
+func TestTypeOf(t *testing.T) {
+	if got := TypeOf(namedProvider{name: "firecrawl-selfhosted", typ: "firecrawl"}); got != "firecrawl" {
+		t.Errorf("TypeOf = %q", got)
+	}
+}
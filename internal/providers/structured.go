@@ -0,0 +1,246 @@
+package providers
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// StructuredExtractOptions contains options for schema-guided extraction.
+type StructuredExtractOptions struct {
+	// Schema is a JSON Schema describing the object to extract. The root must be an object.
+	Schema map[string]interface{}
+	// Prompt is an optional natural-language hint passed to providers that accept one.
+	Prompt string
+}
+
+// StructuredExtractResult represents the result of a schema-guided extraction.
+type StructuredExtractResult struct {
+	URL           string
+	Data          map[string]interface{}
+	Content       string // page text used for extraction, when the provider returns it
+	Latency       time.Duration
+	CreditsUsed   int
+	RequestCount  int
+	UsageReported bool
+}
+
+// StructuredExtractor is implemented by providers with native schema-guided
+// extraction. Providers without it are emulated with Extract plus
+// ExtractFieldsFromContent.
+type StructuredExtractor interface {
+	ExtractStructured(ctx context.Context, url string, opts StructuredExtractOptions) (*StructuredExtractResult, error)
+}
+
+var (
+	numberPattern   = regexp.MustCompile(`-?\d[\d,]*(?:\.\d+)?`)
+	listItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+)
+
+// ExtractFieldsFromContent fills top-level schema properties from markdown or
+// plain text using "Key: value" lines, two-column table rows and list items
+// under a "Key:" line. Properties that cannot be found are omitted.
+func ExtractFieldsFromContent(content string, schema map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return out
+	}
+
+	lines := strings.Split(content, "\n")
+	for name, raw := range properties {
+		propSchema, _ := raw.(map[string]interface{})
+		labels := fieldLabels(name, propSchema)
+		propType := schemaType(propSchema)
+
+		if propType == "object" {
+			nested := ExtractFieldsFromContent(content, propSchema)
+			if len(nested) > 0 {
+				out[name] = nested
+			}
+			continue
+		}
+
+		rawValue, listValues, ok := findLabeledValue(lines, labels)
+		if !ok && name == "title" {
+			rawValue, ok = firstHeading(lines)
+		}
+		if !ok {
+			continue
+		}
+		if value, converted := convertFieldValue(rawValue, listValues, propType); converted {
+			out[name] = value
+		}
+	}
+	return out
+}
+
+// schemaType returns the first non-null type declared by a schema.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return "string"
+}
+
+// fieldLabels returns normalized labels a property may appear under in page text.
+func fieldLabels(name string, schema map[string]interface{}) []string {
+	labels := []string{normalizeLabel(splitIdentifier(name))}
+	if title, ok := schema["title"].(string); ok && title != "" {
+		labels = append(labels, normalizeLabel(title))
+	}
+	return labels
+}
+
+// splitIdentifier turns snake_case, kebab-case and camelCase names into words.
+func splitIdentifier(name string) string {
+	var sb strings.Builder
+	prevLower := false
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-':
+			sb.WriteRune(' ')
+			prevLower = false
+			continue
+		case unicode.IsUpper(r) && prevLower:
+			sb.WriteRune(' ')
+		}
+		sb.WriteRune(r)
+		prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+	}
+	return sb.String()
+}
+
+func normalizeLabel(s string) string {
+	s = strings.ToLower(stripInlineMarkdown(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func stripInlineMarkdown(s string) string {
+	s = strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
+	s = strings.TrimLeft(s, "#> ")
+	return strings.TrimSpace(s)
+}
+
+// findLabeledValue scans lines for a label followed by a value. When the
+// label line has no inline value, following list items are returned instead.
+func findLabeledValue(lines []string, labels []string) (string, []string, bool) {
+	for i, line := range lines {
+		key, value, ok := splitLabeledLine(line)
+		if !ok || !containsString(labels, normalizeLabel(key)) {
+			continue
+		}
+		if value != "" {
+			return value, nil, true
+		}
+		var items []string
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) == "" && len(items) == 0 {
+				continue
+			}
+			if !listItemPattern.MatchString(next) {
+				break
+			}
+			items = append(items, stripInlineMarkdown(listItemPattern.ReplaceAllString(next, "")))
+		}
+		if len(items) > 0 {
+			return "", items, true
+		}
+	}
+	return "", nil, false
+}
+
+// splitLabeledLine splits "Key: value" lines and "| Key | Value |" table rows.
+func splitLabeledLine(line string) (string, string, bool) {
+	trimmed := strings.TrimSpace(listItemPattern.ReplaceAllString(line, ""))
+	if strings.HasPrefix(trimmed, "|") {
+		var cells []string
+		for _, cell := range strings.Split(strings.Trim(trimmed, "|"), "|") {
+			cells = append(cells, strings.TrimSpace(cell))
+		}
+		if len(cells) >= 2 && cells[0] != "" && !strings.HasPrefix(cells[0], "---") {
+			return cells[0], stripInlineMarkdown(cells[1]), true
+		}
+		return "", "", false
+	}
+	trimmed = stripInlineMarkdown(trimmed)
+	idx := strings.Index(trimmed, ":")
+	if idx <= 0 || idx > 60 {
+		return "", "", false
+	}
+	return trimmed[:idx], strings.TrimSpace(trimmed[idx+1:]), true
+}
+
+func firstHeading(lines []string) (string, bool) {
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "# ") {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, "# ")), true
+		}
+	}
+	return "", false
+}
+
+// convertFieldValue coerces raw text into the JSON type declared by the schema.
+func convertFieldValue(raw string, items []string, propType string) (interface{}, bool) {
+	switch propType {
+	case "array":
+		if len(items) == 0 {
+			for _, part := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' }) {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, part)
+				}
+			}
+		}
+		if len(items) == 0 {
+			return nil, false
+		}
+		values := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			values = append(values, item)
+		}
+		return values, true
+	case "number", "integer":
+		match := numberPattern.FindString(raw)
+		if match == "" {
+			return nil, false
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", ""), 64)
+		if err != nil {
+			return nil, false
+		}
+		return n, true
+	case "boolean":
+		switch strings.ToLower(strings.Trim(raw, " .!")) {
+		case "true", "yes", "y", "✓", "✔":
+			return true, true
+		case "false", "no", "n", "✗", "✘":
+			return false, true
+		}
+		return nil, false
+	default:
+		if raw == "" && len(items) > 0 {
+			return strings.Join(items, ", "), true
+		}
+		return raw, raw != ""
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
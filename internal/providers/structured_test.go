@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractFieldsFromContent(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"title":         map[string]interface{}{"type": "string"},
+			"price":         map[string]interface{}{"type": "number"},
+			"in_stock":      map[string]interface{}{"type": "boolean"},
+			"releaseYear":   map[string]interface{}{"type": "integer"},
+			"features":      map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"tags":          map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"missing_field": map[string]interface{}{"type": "string"},
+		},
+	}
+	content := `# Acme Widget
+
+**Price:** $1,299.50
+| In Stock | Yes |
+|---|---|
+| Release Year | 2024 |
+
+Features:
+- Waterproof
+- Bluetooth 5.3
+
+Tags: gadgets, home; outdoor
+`
+
+	got := ExtractFieldsFromContent(content, schema)
+	want := map[string]interface{}{
+		"title":       "Acme Widget",
+		"price":       1299.5,
+		"in_stock":    true,
+		"releaseYear": float64(2024),
+		"features":    []interface{}{"Waterproof", "Bluetooth 5.3"},
+		"tags":        []interface{}{"gadgets", "home", "outdoor"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractFieldsFromContent() = %#v, want %#v", got, want)
+	}
+}
+
+func TestCapabilitySet_StructuredExtractFallsBackToEmulated(t *testing.T) {
+	withExtract := CapabilitySet{Extract: SupportNative}
+	if got := withExtract.ForOperation("structured_extract"); got != SupportEmulated {
+		t.Fatalf("expected emulated structured extract, got %s", got)
+	}
+	native := CapabilitySet{Extract: SupportNative, StructuredExtract: SupportNative}
+	if got := native.ForOperation("structured_extract"); got != SupportNative {
+		t.Fatalf("expected native structured extract, got %s", got)
+	}
+	if (CapabilitySet{Search: SupportNative}).SupportsOperation("structured_extract") {
+		t.Fatal("expected structured extract to be unsupported without extract")
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/lamim/SanityWebEval/internal/providers"
@@ -20,6 +20,7 @@ const (
 
 // Client represents a Tavily API client
 type Client struct {
+	name       string
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
@@ -28,23 +29,44 @@ type Client struct {
 
 // NewClient creates a new Tavily client
 func NewClient() (*Client, error) {
-	apiKey := os.Getenv("TAVILY_API_KEY")
+	return NewClientWithSettings(providers.Settings{})
+}
+
+// NewClientWithSettings creates a Tavily client for a named instance, for
+// example a Tavily-compatible endpoint at a custom base URL.
+func NewClientWithSettings(settings providers.Settings) (*Client, error) {
+	settings, err := settings.Resolve("tavily", providers.EndpointAPI)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := settings.APIKey("TAVILY_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("TAVILY_API_KEY environment variable not set")
+		return nil, fmt.Errorf("%s environment variable not set", settings.APIKeyVar("TAVILY_API_KEY"))
+	}
+	httpClient, err := settings.HTTPClient(60 * time.Second)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: defaultBaseURL,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
-		retryCfg: providers.DefaultRetryConfig(),
+		name:       settings.Name,
+		apiKey:     apiKey,
+		baseURL:    settings.BaseURL(providers.EndpointAPI, defaultBaseURL),
+		httpClient: httpClient,
+		retryCfg:   providers.DefaultRetryConfig(),
 	}, nil
 }
 
-// Name returns the provider name
+// Name returns the provider instance name
 func (c *Client) Name() string {
+	if c.name == "" {
+		return c.Type()
+	}
+	return c.name
+}
+
+// Type returns the provider type
+func (c *Client) Type() string {
 	return "tavily"
 }
 
@@ -63,6 +85,43 @@ func (c *Client) SupportsOperation(opType string) bool {
 	return c.Capabilities().SupportsOperation(opType)
 }
 
+// BaseURLs returns the Tavily API base URL.
+func (c *Client) BaseURLs() map[string]string {
+	return map[string]string{providers.EndpointAPI: c.baseURL}
+}
+
+// SetBaseURL redirects the Tavily API, for example through a local proxy.
+func (c *Client) SetBaseURL(endpoint, baseURL string) error {
+	if endpoint != providers.EndpointAPI {
+		return providers.UnknownEndpointError(c.Name(), endpoint)
+	}
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	return nil
+}
+
+// SearchOptionSupport reports how Tavily honors normalized search options.
+// Tavily has no language or safe-search parameters.
+func (c *Client) SearchOptionSupport() providers.SearchOptionSupport {
+	return providers.SearchOptionSupport{
+		TimeRange:      providers.SupportNative,
+		IncludeDomains: providers.SupportNative,
+		ExcludeDomains: providers.SupportNative,
+		Country:        providers.SupportNative,
+		Language:       providers.SupportUnsupported,
+		SafeSearch:     providers.SupportUnsupported,
+	}
+}
+
+// SearchRequestOptionSupport reports Country as unsupported for codes Tavily
+// cannot name, since Search drops them.
+func (c *Client) SearchRequestOptionSupport(opts providers.SearchOptions) providers.SearchOptionSupport {
+	support := c.SearchOptionSupport()
+	if opts.Country != "" && tavilyCountryName(opts.Country) == "" {
+		support.Country = providers.SupportUnsupported
+	}
+	return support
+}
+
 // Search performs a web search using Tavily
 func (c *Client) Search(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
 	start := time.Now()
@@ -83,6 +142,17 @@ func (c *Client) Search(ctx context.Context, query string, opts providers.Search
 	if opts.TimeRange != "" {
 		payload["time_range"] = opts.TimeRange
 	}
+	if len(opts.IncludeDomains) > 0 {
+		payload["include_domains"] = opts.IncludeDomains
+	}
+	if len(opts.ExcludeDomains) > 0 {
+		payload["exclude_domains"] = opts.ExcludeDomains
+	}
+	// Country boosting only applies to the general topic and expects a full country name.
+	if country := tavilyCountryName(opts.Country); country != "" {
+		payload["topic"] = "general"
+		payload["country"] = country
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -374,3 +444,36 @@ type extractResponse struct {
 type mapResponse struct {
 	Results []string `json:"results"`
 }
+
+// tavilyCountryNames maps ISO 3166-1 alpha-2 codes to the names Tavily accepts.
+var tavilyCountryNames = map[string]string{
+	"au": "australia",
+	"br": "brazil",
+	"ca": "canada",
+	"cn": "china",
+	"de": "germany",
+	"es": "spain",
+	"fr": "france",
+	"gb": "united kingdom",
+	"in": "india",
+	"it": "italy",
+	"jp": "japan",
+	"kr": "south korea",
+	"mx": "mexico",
+	"nl": "netherlands",
+	"uk": "united kingdom",
+	"us": "united states",
+}
+
+// tavilyCountryName resolves a country code; longer values are passed through
+// as names. Unmapped two-letter codes return "" and are not sent.
+func tavilyCountryName(country string) string {
+	country = strings.ToLower(strings.TrimSpace(country))
+	if name, ok := tavilyCountryNames[country]; ok {
+		return name
+	}
+	if len(country) > 2 {
+		return country
+	}
+	return ""
+}
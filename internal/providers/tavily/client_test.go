@@ -216,6 +216,76 @@ func TestSearch_WithTimeRange(t *testing.T) {
 	}
 }
 
+func TestSearch_WithDomainAndCountryFilters(t *testing.T) {
+	var capturedReq map[string]interface{}
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&capturedReq)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+
+	opts := providers.DefaultSearchOptions()
+	opts.IncludeDomains = []string{"go.dev"}
+	opts.ExcludeDomains = []string{"medium.com"}
+	opts.Country = "de"
+	opts.Language = "de"
+
+	_, err := client.Search(context.Background(), "test", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if domains, ok := capturedReq["include_domains"].([]interface{}); !ok || len(domains) != 1 || domains[0] != "go.dev" {
+		t.Errorf("expected include_domains [go.dev], got %v", capturedReq["include_domains"])
+	}
+	if domains, ok := capturedReq["exclude_domains"].([]interface{}); !ok || len(domains) != 1 {
+		t.Errorf("expected exclude_domains, got %v", capturedReq["exclude_domains"])
+	}
+	if capturedReq["country"] != "germany" {
+		t.Errorf("expected country 'germany', got %v", capturedReq["country"])
+	}
+	if client.SearchOptionSupport().ForOption(providers.SearchOptionLanguage) != providers.SupportUnsupported {
+		t.Error("expected language filter to be reported unsupported")
+	}
+}
+
+func TestSearch_UnmappedCountryIsUnsupported(t *testing.T) {
+	var capturedReq map[string]interface{}
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&capturedReq)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(searchResponse{})
+	}))
+	defer server.Close()
+
+	client := &Client{
+		apiKey:     "test-key",
+		baseURL:    server.URL,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+	opts := providers.DefaultSearchOptions()
+	opts.Country = "pt"
+	if _, err := client.Search(context.Background(), "test", opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := capturedReq["country"]; ok {
+		t.Errorf("expected an unmapped country code not to be sent, got %v", capturedReq["country"])
+	}
+	if got := providers.SearchRequestOptionSupport(client, opts).Country; got != providers.SupportUnsupported {
+		t.Errorf("expected an unmapped country to be reported unsupported, got %s", got)
+	}
+	if got := providers.SearchRequestOptionSupport(client, providers.SearchOptions{Country: "DE"}).Country; got != providers.SupportNative {
+		t.Errorf("expected a mapped country to be reported native, got %s", got)
+	}
+}
+
 func TestSearch_WithImages(t *testing.T) {
 	var capturedReq map[string]interface{}
 	server := testutil.NewIPv4Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/tracing"
+)
+
+// DoTraced sends req, recording a client span for the HTTP attempt when the
+// request's context carries a trace and reporting the attempt to the
+// context's HTTPObserver. resendCount is 0 for the first attempt and the
+// retry number after that. The URL's query is left out of the span and the
+// observed attempt, since some APIs take credentials there. When the context
+// carries a debug test log, the attempt's httptrace timing is recorded on its
+// latest logged request.
+func DoTraced(client *http.Client, req *http.Request, resendCount int) (*http.Response, error) {
+	req, recordTiming := traceDebugTiming(req, resendCount)
+	attempt := HTTPAttempt{
+		Method:  req.Method,
+		URL:     req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+		Attempt: resendCount + 1,
+	}
+	observeCtx := req.Context()
+	start := time.Now()
+	defer func() {
+		attempt.Duration = time.Since(start)
+		observeAttempt(observeCtx, attempt)
+	}()
+
+	attrs := []tracing.Attribute{
+		tracing.String("http.request.method", req.Method),
+		tracing.String("url.full", attempt.URL),
+		tracing.String("server.address", req.URL.Hostname()),
+	}
+	if resendCount > 0 {
+		attrs = append(attrs, tracing.Int("http.request.resend_count", resendCount))
+	}
+	ctx, span := tracing.StartClient(req.Context(), req.Method, attrs...)
+	defer span.End()
+	if span != nil {
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := client.Do(req) //nolint:gosec // URL is constructed from trusted config
+	if err != nil {
+		span.SetAttributes(tracing.String("error.type", categorizeTraceError(err)))
+		span.SetError(err.Error())
+		attempt.Error = err.Error()
+		if recordTiming != nil {
+			recordTiming()
+		}
+		return nil, err
+	}
+	if recordTiming != nil {
+		resp.Body = &timedBody{ReadCloser: resp.Body, done: recordTiming}
+	}
+	span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode >= 400 {
+		span.SetAttributes(tracing.String("error.type", http.StatusText(resp.StatusCode)))
+		span.SetError(resp.Status)
+		attempt.Error = resp.Status
+	}
+	return resp, nil
+}
+
+// sleepBackoff waits before a retry, recording the wait as a span and
+// reporting it to the context's HTTPObserver.
+func sleepBackoff(ctx context.Context, backoff time.Duration, attempt int, reason string) error {
+	observeRetry(ctx, HTTPRetry{Retry: attempt + 1, Backoff: backoff, Reason: reason})
+	ctx, span := tracing.Start(ctx, "retry backoff",
+		tracing.Int("retry.attempt", attempt+1),
+		tracing.Float64("retry.backoff_ms", float64(backoff.Milliseconds())),
+		tracing.String("retry.reason", reason),
+	)
+	defer span.End()
+	err := SleepWithContext(ctx, backoff)
+	if err != nil {
+		span.SetError(err.Error())
+	}
+	return err
+}
+
+func categorizeTraceError(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	return "network"
+}
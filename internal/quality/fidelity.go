@@ -0,0 +1,46 @@
+package quality
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextFidelity compares extracted text with a reference text and returns a
+// 0-100 score: the F1 of word tokens (case-insensitive, punctuation and
+// Markdown markup ignored). Word order is not considered, so reflowed lines
+// and table layout changes are not penalized; missing and spurious words are.
+func TextFidelity(reference, extracted string) float64 {
+	refTokens := fidelityTokens(reference)
+	if len(refTokens) == 0 {
+		return 0
+	}
+	extTokens := fidelityTokens(extracted)
+	if len(extTokens) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(refTokens))
+	for _, tok := range refTokens {
+		counts[tok]++
+	}
+	overlap := 0
+	for _, tok := range extTokens {
+		if counts[tok] > 0 {
+			counts[tok]--
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0
+	}
+
+	precision := float64(overlap) / float64(len(extTokens))
+	recall := float64(overlap) / float64(len(refTokens))
+	return clamp(200*precision*recall/(precision+recall), 0, 100)
+}
+
+func fidelityTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
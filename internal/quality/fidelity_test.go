@@ -0,0 +1,31 @@
+package quality
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTextFidelity(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		extracted string
+		want      float64
+	}{
+		{"identical after normalization", "Revenue grew 12%.\nNet income rose.", "# Revenue grew 12%\n\n**Net income** rose", 100},
+		{"half the words missing", "alpha beta gamma delta", "alpha beta", 66.67},
+		{"spurious words", "alpha beta", "alpha beta gamma delta", 66.67},
+		{"no overlap", "alpha beta", "gamma delta", 0},
+		{"empty extraction", "alpha beta", "", 0},
+		{"empty reference", "", "alpha", 0},
+		{"repeated words count once each", "data data table", "data table table", 66.67},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TextFidelity(tt.reference, tt.extracted)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("TextFidelity() = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
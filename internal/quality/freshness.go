@@ -0,0 +1,83 @@
+package quality
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// defaultFreshnessWindow is used when no time range filter was requested.
+const defaultFreshnessWindow = 365 * 24 * time.Hour
+
+// FreshnessMetrics measures how well dated results respect a time-range window.
+type FreshnessMetrics struct {
+	Window         time.Duration
+	Total          int
+	Dated          int
+	Undated        int
+	InWindow       int
+	ComplianceRate float64 // 0-100, share of dated results inside the window
+	UndatedShare   float64 // 0-100, share of results without a published date
+	MedianAge      time.Duration
+	Ages           []time.Duration
+}
+
+// SearchScoreOptions carries request context that affects search scoring.
+type SearchScoreOptions struct {
+	TimeRange string    // day, week, month, year; empty uses a one-year window
+	Reference time.Time // "now" for age calculations; zero uses time.Now()
+}
+
+// TimeRangeWindow returns the lookback window for a normalized time range.
+func TimeRangeWindow(timeRange string) (time.Duration, bool) {
+	switch timeRange {
+	case "day":
+		return 24 * time.Hour, true
+	case "week":
+		return 7 * 24 * time.Hour, true
+	case "month":
+		return 31 * 24 * time.Hour, true
+	case "year":
+		return 366 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
+
+// MeasureFreshness checks each result's PublishedAt against the window ending at now.
+// Dates slightly in the future (timezone skew) count as inside the window.
+func MeasureFreshness(results []providers.SearchItem, window time.Duration, now time.Time) FreshnessMetrics {
+	metrics := FreshnessMetrics{Window: window, Total: len(results)}
+	for _, r := range results {
+		if r.PublishedAt == nil || r.PublishedAt.IsZero() {
+			metrics.Undated++
+			continue
+		}
+		metrics.Dated++
+		age := now.Sub(*r.PublishedAt)
+		if age < 0 {
+			age = 0
+		}
+		metrics.Ages = append(metrics.Ages, age)
+		if age <= window {
+			metrics.InWindow++
+		}
+	}
+
+	if metrics.Dated > 0 {
+		metrics.ComplianceRate = float64(metrics.InWindow) / float64(metrics.Dated) * 100
+		sorted := append([]time.Duration(nil), metrics.Ages...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			metrics.MedianAge = (sorted[mid-1] + sorted[mid]) / 2
+		} else {
+			metrics.MedianAge = sorted[mid]
+		}
+	}
+	if metrics.Total > 0 {
+		metrics.UndatedShare = float64(metrics.Undated) / float64(metrics.Total) * 100
+	}
+	return metrics
+}
@@ -0,0 +1,77 @@
+package quality
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+func TestMeasureFreshness(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+	results := []providers.SearchItem{
+		{URL: "https://a.example", PublishedAt: at(2 * 24 * time.Hour)},
+		{URL: "https://b.example", PublishedAt: at(6 * 24 * time.Hour)},
+		{URL: "https://c.example", PublishedAt: at(40 * 24 * time.Hour)},
+		{URL: "https://d.example", PublishedAt: at(-time.Hour)}, // slight clock skew
+		{URL: "https://e.example"},
+	}
+
+	window, ok := TimeRangeWindow("week")
+	if !ok {
+		t.Fatal("expected week to be a known time range")
+	}
+	got := MeasureFreshness(results, window, now)
+
+	if got.Total != 5 || got.Dated != 4 || got.Undated != 1 || got.InWindow != 3 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+	if got.ComplianceRate != 75 {
+		t.Fatalf("ComplianceRate = %.1f, want 75", got.ComplianceRate)
+	}
+	if got.UndatedShare != 20 {
+		t.Fatalf("UndatedShare = %.1f, want 20", got.UndatedShare)
+	}
+	if want := 4 * 24 * time.Hour; got.MedianAge != want {
+		t.Fatalf("MedianAge = %v, want %v", got.MedianAge, want)
+	}
+}
+
+func TestTimeRangeWindow_Unknown(t *testing.T) {
+	if _, ok := TimeRangeWindow("decade"); ok {
+		t.Fatal("expected unknown time range to be rejected")
+	}
+	if _, ok := TimeRangeWindow(""); ok {
+		t.Fatal("expected empty time range to be rejected")
+	}
+}
+
+func TestScoreSearch_FreshnessUsesTimeRange(t *testing.T) {
+	scorer := NewScorer(nil, nil)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-20 * 24 * time.Hour)
+	results := []providers.SearchItem{
+		{Title: "Rust news", URL: "https://a.example", Content: "Rust release notes", PublishedAt: &old},
+	}
+
+	yearScore, err := scorer.ScoreSearch(context.Background(), "rust news", results, SearchScoreOptions{TimeRange: "year", Reference: now})
+	if err != nil {
+		t.Fatalf("ScoreSearch(year) failed: %v", err)
+	}
+	weekScore, err := scorer.ScoreSearch(context.Background(), "rust news", results, SearchScoreOptions{TimeRange: "week", Reference: now})
+	if err != nil {
+		t.Fatalf("ScoreSearch(week) failed: %v", err)
+	}
+
+	if yearScore.FreshnessScore != 100 {
+		t.Fatalf("year FreshnessScore = %.1f, want 100", yearScore.FreshnessScore)
+	}
+	if weekScore.FreshnessScore != 0 {
+		t.Fatalf("week FreshnessScore = %.1f, want 0", weekScore.FreshnessScore)
+	}
+}
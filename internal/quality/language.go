@@ -0,0 +1,117 @@
+package quality
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minLanguageLetters is the minimum number of letters needed before a language guess is made.
+const minLanguageLetters = 20
+
+// languageStopwords holds high-frequency function words for Latin-script languages.
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "in", "is", "that", "for", "with", "as", "on", "are", "this", "by", "be", "it", "from", "was", "or", "an"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "den", "von", "zu", "ein", "eine", "auf", "für", "sich", "auch", "dem", "des", "im", "wird"},
+	"fr": {"le", "la", "les", "et", "des", "est", "une", "un", "du", "dans", "pour", "que", "qui", "sur", "pas", "avec", "au", "sont", "par", "ce"},
+	"es": {"el", "la", "los", "las", "y", "de", "que", "en", "es", "por", "una", "un", "con", "para", "del", "se", "como", "al", "su", "más"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "una", "sono", "del", "della", "non", "con", "gli", "le", "nel", "alla", "è", "si", "come"},
+	"pt": {"o", "a", "os", "as", "de", "que", "e", "do", "da", "em", "um", "uma", "para", "com", "não", "por", "mais", "dos", "das", "se"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "op", "te", "in", "voor", "met", "niet", "zijn", "er", "ook", "aan", "worden", "bij", "door"},
+}
+
+// DetectLanguage guesses the ISO 639-1 language of text using script ranges and
+// stopword frequencies. It returns "" when the text is too short or ambiguous,
+// along with a 0-1 confidence for the returned code.
+func DetectLanguage(text string) (string, float64) {
+	var letters, latin, kana, han, hangul, cyrillic, arabic, greek int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Greek, r):
+			greek++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if letters < minLanguageLetters {
+		return "", 0
+	}
+
+	share := func(n int) float64 { return float64(n) / float64(letters) }
+
+	// Japanese mixes kana with kanji; any meaningful kana share distinguishes it from Chinese.
+	if cjk := kana + han; share(cjk) >= 0.3 {
+		if share(kana) >= 0.05 {
+			return "ja", share(cjk)
+		}
+		return "zh", share(cjk)
+	}
+	scripts := []struct {
+		code  string
+		count int
+	}{
+		{"ko", hangul},
+		{"ru", cyrillic},
+		{"ar", arabic},
+		{"el", greek},
+	}
+	for _, script := range scripts {
+		if share(script.count) >= 0.5 {
+			return script.code, share(script.count)
+		}
+	}
+	if share(latin) < 0.5 {
+		return "", 0
+	}
+	return detectLatinLanguage(text)
+}
+
+func detectLatinLanguage(text string) (string, float64) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return "", 0
+	}
+
+	counts := make(map[string]int, len(languageStopwords))
+	for lang, stopwords := range languageStopwords {
+		set := make(map[string]struct{}, len(stopwords))
+		for _, w := range stopwords {
+			set[w] = struct{}{}
+		}
+		for _, w := range words {
+			if _, ok := set[w]; ok {
+				counts[lang]++
+			}
+		}
+	}
+
+	best, second := "", 0
+	bestCount := 0
+	for lang, count := range counts {
+		if count > bestCount || (count == bestCount && lang < best) {
+			second = bestCount
+			best, bestCount = lang, count
+		} else if count > second {
+			second = count
+		}
+	}
+	if bestCount < 2 || bestCount == second {
+		return "", 0
+	}
+	return best, float64(bestCount-second) / float64(bestCount)
+}
@@ -0,0 +1,29 @@
+package quality
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "The ownership model is one of the key features of the Rust language and it is enforced by the compiler.", "en"},
+		{"german", "Der Borrow Checker ist ein zentraler Teil des Compilers und sorgt für die Sicherheit des Speichers, auch wenn es nicht immer einfach ist.", "de"},
+		{"japanese", "所有権はRustの最もユニークな機能であり、ガベージコレクタなしでメモリ安全性を保証します。", "ja"},
+		{"chinese", "所有权是Rust最独特的功能，它让Rust无需垃圾回收器即可保证内存安全。", "zh"},
+		{"russian", "Владение является уникальной особенностью языка и гарантирует безопасность памяти.", "ru"},
+		{"too short", "Rust", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, confidence := DetectLanguage(tc.text)
+			if got != tc.want {
+				t.Fatalf("DetectLanguage() = %q (%.2f), want %q", got, confidence, tc.want)
+			}
+			if got != "" && (confidence <= 0 || confidence > 1) {
+				t.Fatalf("confidence out of range: %.2f", confidence)
+			}
+		})
+	}
+}
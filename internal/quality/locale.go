@@ -0,0 +1,52 @@
+package quality
+
+import "strings"
+
+// ExtractScoreOptions carries request context that affects extract scoring.
+type ExtractScoreOptions struct {
+	Language string // ISO 639-1 code of the expected content language; empty means English
+}
+
+// localeLexicon holds the language-specific phrases used by the extract heuristics.
+type localeLexicon struct {
+	truncation []string
+	navigation []string
+	ads        []string
+}
+
+// languageNeutralTruncation markers are checked regardless of language.
+var languageNeutralTruncation = []string{"...", "[…]"}
+
+var localeLexicons = map[string]localeLexicon{
+	"en": {
+		truncation: []string{"(continued)", "read more", "click to read", "[view full article]", "[see more]"},
+		navigation: []string{"home", "about us", "contact", "privacy policy", "terms of service", "cookie policy", "sign up", "login"},
+		ads:        []string{"advertisement", "sponsored", "promoted", "ad choices"},
+	},
+	"de": {
+		truncation: []string{"(fortsetzung)", "weiterlesen", "mehr lesen", "zum vollständigen artikel", "mehr anzeigen"},
+		navigation: []string{"startseite", "über uns", "kontakt", "datenschutz", "impressum", "nutzungsbedingungen", "anmelden", "registrieren"},
+		ads:        []string{"anzeige", "werbung", "gesponsert", "sponsored"},
+	},
+	"ja": {
+		truncation: []string{"続きを読む", "もっと見る", "全文を読む", "(続く)", "（続く）"},
+		navigation: []string{"ホーム", "会社概要", "お問い合わせ", "プライバシーポリシー", "利用規約", "ログイン", "新規登録"},
+		ads:        []string{"広告", "スポンサー"},
+	},
+}
+
+// SupportsLocale reports whether the extract heuristics have phrase lists for
+// a language. An empty code is treated as English.
+func SupportsLocale(language string) bool {
+	_, ok := lexiconFor(language)
+	return ok
+}
+
+func lexiconFor(language string) (localeLexicon, bool) {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if code == "" {
+		code = "en"
+	}
+	lexicon, ok := localeLexicons[code]
+	return lexicon, ok
+}
@@ -0,0 +1,56 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScoreExtract_LocaleAwareNoise(t *testing.T) {
+	scorer := NewScorer(nil, nil)
+	body := "# Artikel\n\n" + strings.Repeat("Der Speicher wird ohne Garbage Collector sicher verwaltet. ", 20)
+	noisy := "Startseite\nÜber uns\nKontakt\nImpressum\nDatenschutz\nAnzeige\nWerbung\n\n" + body
+
+	clean := scorer.ScoreExtract(body, "", nil, ExtractScoreOptions{Language: "de"})
+	dirty := scorer.ScoreExtract(noisy, "", nil, ExtractScoreOptions{Language: "de"})
+	if dirty.SignalToNoise >= clean.SignalToNoise {
+		t.Errorf("German navigation should lower signal-to-noise: clean=%.1f noisy=%.1f", clean.SignalToNoise, dirty.SignalToNoise)
+	}
+	if clean.LocaleHeuristicsSkipped {
+		t.Error("German has a lexicon and should not skip heuristics")
+	}
+}
+
+func TestScoreExtract_JapaneseTruncation(t *testing.T) {
+	scorer := NewScorer(nil, nil)
+	body := "# 所有権\n\n" + strings.Repeat("所有権はメモリ安全性を保証します。", 40)
+
+	full := scorer.ScoreExtract(body, "", nil, ExtractScoreOptions{Language: "ja"})
+	cut := scorer.ScoreExtract(body+"\n続きを読む", "", nil, ExtractScoreOptions{Language: "ja"})
+	if cut.ContentCompleteness >= full.ContentCompleteness {
+		t.Errorf("Japanese truncation marker should lower completeness: full=%.1f cut=%.1f", full.ContentCompleteness, cut.ContentCompleteness)
+	}
+}
+
+func TestScoreExtract_JapaneseLatinWordsAreNotAds(t *testing.T) {
+	scorer := NewScorer(nil, nil)
+	body := "# 所有権\n\n" + strings.Repeat("所有権はメモリ安全性を保証します。", 40)
+
+	plain := scorer.ScoreExtract(body, "", nil, ExtractScoreOptions{Language: "ja"})
+	latin := scorer.ScoreExtract(body+"\nGitHubのPRでprogrammingのprocessを説明します。", "", nil, ExtractScoreOptions{Language: "ja"})
+	if latin.SignalToNoise < plain.SignalToNoise {
+		t.Errorf("Latin words containing \"pr\" should not count as ads: plain=%.1f latin=%.1f", plain.SignalToNoise, latin.SignalToNoise)
+	}
+}
+
+func TestScoreExtract_UnsupportedLocaleSkipsHeuristics(t *testing.T) {
+	scorer := NewScorer(nil, nil)
+	content := "# Статья\n\n" + strings.Repeat("Владение гарантирует безопасность памяти без сборщика мусора. ", 20)
+
+	score := scorer.ScoreExtract(content, "", nil, ExtractScoreOptions{Language: "ru"})
+	if !score.LocaleHeuristicsSkipped {
+		t.Fatal("expected locale heuristics to be skipped for Russian")
+	}
+	if SupportsLocale("ru") || !SupportsLocale("") || !SupportsLocale("ja") {
+		t.Error("unexpected SupportsLocale result")
+	}
+}
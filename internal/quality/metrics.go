@@ -125,7 +125,7 @@ type SearchQualityScore struct {
 	RerankerAvailable bool    `json:"reranker_available,omitempty"`
 	TopKAccuracy      float64 `json:"top_k_accuracy"`   // 0-100, relevance of top N
 	ResultDiversity   float64 `json:"result_diversity"` // 0-100, domain/content variety
-	FreshnessScore    float64 `json:"freshness_score"`  // 0-100, content recency
+	FreshnessScore    float64 `json:"freshness_score"`  // 0-100, time-range compliance
 	OverallScore      float64 `json:"overall_score"`    // 0-100, weighted composite
 }
 
@@ -138,6 +138,9 @@ type ExtractQualityScore struct {
 	SignalToNoise         float64 `json:"signal_to_noise"`        // 0-100, content vs ads
 	CodePreservation      float64 `json:"code_preservation"`      // 0-100, code blocks intact
 	OverallScore          float64 `json:"overall_score"`          // 0-100, weighted composite
+	// LocaleHeuristicsSkipped is set when the content language has no phrase
+	// lists; signal-to-noise is then left out of the overall score.
+	LocaleHeuristicsSkipped bool `json:"locale_heuristics_skipped,omitempty"`
 }
 
 // CrawlQualityScore represents quality metrics for crawl operations
@@ -210,6 +213,9 @@ func CalculateSearchScore(scores SearchQualityScore, weights ScoreWeights) float
 // CalculateExtractScore computes weighted overall score for extraction
 func CalculateExtractScore(scores ExtractQualityScore) float64 {
 	weights := []float64{0.25, 0.20, 0.20, 0.15, 0.10, 0.10}
+	if scores.LocaleHeuristicsSkipped {
+		weights[4] = 0
+	}
 	values := []float64{
 		scores.ContentCompleteness,
 		scores.StructurePreservation,
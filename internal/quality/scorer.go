@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/lamim/SanityWebEval/internal/providers"
 )
@@ -36,7 +37,7 @@ func (s *Scorer) SetWeights(weights ScoreWeights) {
 
 // ScoreSearch performs comprehensive quality scoring for search results
 // Uses concurrent execution for embedding and reranking to minimize latency
-func (s *Scorer) ScoreSearch(ctx context.Context, query string, results []providers.SearchItem) (SearchQualityScore, error) {
+func (s *Scorer) ScoreSearch(ctx context.Context, query string, results []providers.SearchItem, opts ...SearchScoreOptions) (SearchQualityScore, error) {
 	if len(results) == 0 {
 		return SearchQualityScore{}, nil
 	}
@@ -111,7 +112,11 @@ func (s *Scorer) ScoreSearch(ctx context.Context, query string, results []provid
 	score.ResultDiversity = diversity.DomainDiversity
 
 	// 5. Freshness score
-	score.FreshnessScore = s.calculateFreshnessScore(results)
+	var scoreOpts SearchScoreOptions
+	if len(opts) > 0 {
+		scoreOpts = opts[0]
+	}
+	score.FreshnessScore = s.calculateFreshnessScore(results, scoreOpts)
 
 	weights := s.weights
 	if !score.SemanticAvailable {
@@ -127,12 +132,20 @@ func (s *Scorer) ScoreSearch(ctx context.Context, query string, results []provid
 	return score, nil
 }
 
-// ScoreExtract performs quality scoring for extraction results
-func (s *Scorer) ScoreExtract(content string, _ string, _ []string) ExtractQualityScore {
+// ScoreExtract performs quality scoring for extraction results. Phrase-based
+// heuristics use the lexicon for opts.Language; languages without one fall
+// back to language-neutral checks and skip signal-to-noise.
+func (s *Scorer) ScoreExtract(content string, _ string, _ []string, opts ...ExtractScoreOptions) ExtractQualityScore {
 	score := ExtractQualityScore{}
+	var options ExtractScoreOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+	lexicon, supported := lexiconFor(options.Language)
+	score.LocaleHeuristicsSkipped = !supported
 
 	// 1. Content completeness (check for truncation indicators)
-	score.ContentCompleteness = s.assessCompleteness(content)
+	score.ContentCompleteness = s.assessCompleteness(content, lexicon)
 
 	// 2. Structure preservation
 	score.StructurePreservation = s.assessStructure(content)
@@ -144,7 +157,7 @@ func (s *Scorer) ScoreExtract(content string, _ string, _ []string) ExtractQuali
 	score.FreshnessScore = s.assessContentFreshness(content)
 
 	// 5. Signal to noise ratio
-	score.SignalToNoise = s.assessSignalToNoise(content)
+	score.SignalToNoise = s.assessSignalToNoise(content, lexicon)
 
 	// 6. Code preservation
 	score.CodePreservation = s.assessCodePreservation(content)
@@ -329,43 +342,29 @@ func (s *Scorer) calculateDiversity(results []providers.SearchItem) DiversityMet
 	return metrics
 }
 
-// calculateFreshnessScore estimates content freshness
-func (s *Scorer) calculateFreshnessScore(results []providers.SearchItem) float64 {
+// calculateFreshnessScore measures time-range compliance: dated results inside
+// the requested window (one year by default) score 100, dated results outside
+// it score 0 and undated results stay neutral at 50.
+func (s *Scorer) calculateFreshnessScore(results []providers.SearchItem, opts SearchScoreOptions) float64 {
 	if len(results) == 0 {
 		return 50
 	}
 
-	var totalScore float64
-	now := time.Now()
-
-	for _, r := range results {
-		if r.PublishedAt == nil {
-			totalScore += 50 // Neutral for unknown date
-			continue
-		}
-
-		age := now.Sub(*r.PublishedAt).Hours()
-		var score float64
-		switch {
-		case age < 24:
-			score = 100 // Less than 1 day
-		case age < 168:
-			score = 90 // Less than 1 week
-		case age < 720:
-			score = 80 // Less than 1 month
-		case age < 8760:
-			score = 70 // Less than 1 year
-		default:
-			score = 50 // Older
-		}
-		totalScore += score
+	window, ok := TimeRangeWindow(opts.TimeRange)
+	if !ok {
+		window = defaultFreshnessWindow
+	}
+	now := opts.Reference
+	if now.IsZero() {
+		now = time.Now()
 	}
 
-	return totalScore / float64(len(results))
+	m := MeasureFreshness(results, window, now)
+	return (float64(m.InWindow)*100 + float64(m.Undated)*50) / float64(m.Total)
 }
 
 // assessCompleteness checks for truncation indicators
-func (s *Scorer) assessCompleteness(content string) float64 {
+func (s *Scorer) assessCompleteness(content string, lexicon localeLexicon) float64 {
 	if len(content) == 0 {
 		return 0
 	}
@@ -373,23 +372,20 @@ func (s *Scorer) assessCompleteness(content string) float64 {
 	score := 100.0
 
 	// Check for truncation indicators
-	truncationPatterns := []string{
-		"...", "[…]", "(continued)", "read more", "click to read",
-		"[View full article]", "[See more]",
-	}
-
 	contentLower := strings.ToLower(content)
+	truncationPatterns := append(append([]string{}, languageNeutralTruncation...), lexicon.truncation...)
 	for _, pattern := range truncationPatterns {
 		if strings.Contains(contentLower, pattern) {
 			score -= 15
 		}
 	}
 
-	// Penalize very short content
-	if len(content) < 500 {
+	// Penalize very short content (in characters, so multi-byte scripts are not favored)
+	length := utf8.RuneCountInString(content)
+	if length < 500 {
 		score -= 20
 	}
-	if len(content) < 200 {
+	if length < 200 {
 		score -= 30
 	}
 
@@ -480,7 +476,7 @@ func (s *Scorer) assessContentFreshness(_ string) float64 {
 }
 
 // assessSignalToNoise estimates content vs noise ratio
-func (s *Scorer) assessSignalToNoise(content string) float64 {
+func (s *Scorer) assessSignalToNoise(content string, lexicon localeLexicon) float64 {
 	if len(content) == 0 {
 		return 0
 	}
@@ -489,13 +485,8 @@ func (s *Scorer) assessSignalToNoise(content string) float64 {
 	contentLower := strings.ToLower(content)
 
 	// Check for navigation elements
-	navIndicators := []string{
-		"home", "about us", "contact", "privacy policy",
-		"terms of service", "cookie policy", "sign up", "login",
-	}
-
 	navCount := 0
-	for _, indicator := range navIndicators {
+	for _, indicator := range lexicon.navigation {
 		if strings.Contains(contentLower, indicator) {
 			navCount++
 		}
@@ -504,13 +495,9 @@ func (s *Scorer) assessSignalToNoise(content string) float64 {
 	score -= float64(navCount) * 3
 
 	// Check for ad indicators
-	adIndicators := []string{
-		"advertisement", "sponsored", "promoted", "ad choices",
-	}
-
 	adCount := 0
-	for _, indicator := range adIndicators {
-		if strings.Contains(contentLower, indicator) {
+	for _, indicator := range lexicon.ads {
+		if strings.Contains(contentLower, strings.ToLower(indicator)) {
 			adCount++
 		}
 	}
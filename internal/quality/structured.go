@@ -0,0 +1,321 @@
+package quality
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// StructuredFieldMetrics compares extracted JSON against an expected object.
+type StructuredFieldMetrics struct {
+	ExpectedFields int
+	MatchedFields  int                // fields with an exact (normalized) match
+	Accuracy       float64            // 0-100, mean of per-field scores
+	FieldScores    map[string]float64 // 0-1 per dotted field path
+}
+
+// ValidateJSONSchema checks value against a JSON Schema subset: type, enum,
+// properties, required, additionalProperties, items, min/maxItems,
+// min/maxLength, pattern and minimum/maximum. It returns one message per
+// violation, prefixed with a JSONPath-style location.
+func ValidateJSONSchema(value interface{}, schema map[string]interface{}) []string {
+	var errs []string
+	validateSchemaNode(value, schema, "$", &errs)
+	return errs
+}
+
+func validateSchemaNode(value interface{}, schema map[string]interface{}, path string, errs *[]string) {
+	if len(schema) == 0 {
+		return
+	}
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(value, types) {
+		*errs = append(*errs, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonTypeName(value)))
+		return
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !enumContains(enum, value) {
+		*errs = append(*errs, fmt.Sprintf("%s: value %v not in enum", path, value))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateSchemaObject(v, schema, path, errs)
+	case []interface{}:
+		validateSchemaArray(v, schema, path, errs)
+	case string:
+		validateSchemaString(v, schema, path, errs)
+	default:
+		if n, ok := toFloat(value); ok {
+			validateSchemaNumber(n, schema, path, errs)
+		}
+	}
+}
+
+func validateSchemaObject(obj map[string]interface{}, schema map[string]interface{}, path string, errs *[]string) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; name != "" && !present {
+				*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	additional, hasAdditional := schema["additionalProperties"].(bool)
+	for _, key := range keys {
+		propSchema, known := properties[key].(map[string]interface{})
+		if !known {
+			if hasAdditional && !additional {
+				*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", path, key))
+			}
+			continue
+		}
+		validateSchemaNode(obj[key], propSchema, path+"."+key, errs)
+	}
+}
+
+func validateSchemaArray(arr []interface{}, schema map[string]interface{}, path string, errs *[]string) {
+	if minItems, ok := toFloat(schema["minItems"]); ok && float64(len(arr)) < minItems {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at least %.0f items, got %d", path, minItems, len(arr)))
+	}
+	if maxItems, ok := toFloat(schema["maxItems"]); ok && float64(len(arr)) > maxItems {
+		*errs = append(*errs, fmt.Sprintf("%s: expected at most %.0f items, got %d", path, maxItems, len(arr)))
+	}
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		validateSchemaNode(item, items, fmt.Sprintf("%s[%d]", path, i), errs)
+	}
+}
+
+func validateSchemaString(s string, schema map[string]interface{}, path string, errs *[]string) {
+	length := len([]rune(s))
+	if minLength, ok := toFloat(schema["minLength"]); ok && float64(length) < minLength {
+		*errs = append(*errs, fmt.Sprintf("%s: shorter than minLength %.0f", path, minLength))
+	}
+	if maxLength, ok := toFloat(schema["maxLength"]); ok && float64(length) > maxLength {
+		*errs = append(*errs, fmt.Sprintf("%s: longer than maxLength %.0f", path, maxLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(s) {
+			*errs = append(*errs, fmt.Sprintf("%s: does not match pattern %q", path, pattern))
+		}
+	}
+}
+
+func validateSchemaNumber(n float64, schema map[string]interface{}, path string, errs *[]string) {
+	if minimum, ok := toFloat(schema["minimum"]); ok && n < minimum {
+		*errs = append(*errs, fmt.Sprintf("%s: %v is below minimum %v", path, n, minimum))
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok && n > maximum {
+		*errs = append(*errs, fmt.Sprintf("%s: %v is above maximum %v", path, n, maximum))
+	}
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := jsonTypeName(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeName returns the JSON Schema type of a decoded JSON or TOML value.
+// Integral numbers report "integer".
+func jsonTypeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		if n, ok := toFloat(v); ok {
+			if n == math.Trunc(n) {
+				return "integer"
+			}
+			return "number"
+		}
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, candidate := range enum {
+		if scalarScore(candidate, value) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// ScoreStructuredFields scores actual against every leaf field of expected.
+// Nested objects are compared field by field; arrays score by the share of
+// expected elements found. Strings match case- and whitespace-insensitively,
+// with half credit for containment; numbers match within 1%.
+func ScoreStructuredFields(expected, actual map[string]interface{}) StructuredFieldMetrics {
+	metrics := StructuredFieldMetrics{FieldScores: make(map[string]float64)}
+	collectFieldScores(expected, actual, "", metrics.FieldScores)
+
+	metrics.ExpectedFields = len(metrics.FieldScores)
+	if metrics.ExpectedFields == 0 {
+		return metrics
+	}
+	total := 0.0
+	for _, score := range metrics.FieldScores {
+		total += score
+		if score == 1 {
+			metrics.MatchedFields++
+		}
+	}
+	metrics.Accuracy = total / float64(metrics.ExpectedFields) * 100
+	return metrics
+}
+
+func collectFieldScores(expected, actual map[string]interface{}, prefix string, scores map[string]float64) {
+	for key, want := range expected {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		got, present := actual[key]
+		if nested, ok := want.(map[string]interface{}); ok {
+			gotNested, _ := got.(map[string]interface{})
+			collectFieldScores(nested, gotNested, path, scores)
+			continue
+		}
+		if !present {
+			scores[path] = 0
+			continue
+		}
+		scores[path] = fieldScore(want, got)
+	}
+}
+
+func fieldScore(want, got interface{}) float64 {
+	wantList, wantIsList := want.([]interface{})
+	if !wantIsList {
+		return scalarScore(want, got)
+	}
+	gotList, ok := got.([]interface{})
+	if !ok {
+		gotList = []interface{}{got}
+	}
+	if len(wantList) == 0 {
+		if len(gotList) == 0 {
+			return 1
+		}
+		return 0
+	}
+	found := 0.0
+	for _, w := range wantList {
+		best := 0.0
+		for _, g := range gotList {
+			best = math.Max(best, scalarScore(w, g))
+		}
+		found += best
+	}
+	return found / float64(len(wantList))
+}
+
+func scalarScore(want, got interface{}) float64 {
+	if want == nil || got == nil {
+		if want == nil && got == nil {
+			return 1
+		}
+		return 0
+	}
+	if wantNum, ok := toFloat(want); ok {
+		gotNum, ok := toFloat(got)
+		if !ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(got)), 64)
+			if err != nil {
+				return 0
+			}
+			gotNum = parsed
+		}
+		if wantNum == gotNum || math.Abs(wantNum-gotNum) <= math.Abs(wantNum)*0.01 {
+			return 1
+		}
+		return 0
+	}
+	if wantBool, ok := want.(bool); ok {
+		if gotBool, ok := got.(bool); ok && gotBool == wantBool {
+			return 1
+		}
+		return 0
+	}
+
+	w := normalizeFieldText(fmt.Sprint(want))
+	g := normalizeFieldText(fmt.Sprint(got))
+	switch {
+	case w == g:
+		return 1
+	case w != "" && g != "" && (strings.Contains(g, w) || strings.Contains(w, g)):
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+func normalizeFieldText(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	return strings.Trim(s, " .,;:!\"'")
+}
@@ -0,0 +1,93 @@
+package quality
+
+import (
+	"strings"
+	"testing"
+)
+
+func productSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"required":             []interface{}{"name", "price"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"name":     map[string]interface{}{"type": "string", "minLength": float64(1)},
+			"price":    map[string]interface{}{"type": "number", "minimum": float64(0)},
+			"currency": map[string]interface{}{"type": "string", "enum": []interface{}{"USD", "EUR"}},
+			"tags":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"stock":    map[string]interface{}{"type": "integer"},
+		},
+	}
+}
+
+func TestValidateJSONSchema_Valid(t *testing.T) {
+	data := map[string]interface{}{
+		"name":     "Widget",
+		"price":    float64(12),
+		"currency": "USD",
+		"tags":     []interface{}{"a", "b"},
+		"stock":    float64(3),
+	}
+	if errs := ValidateJSONSchema(data, productSchema()); len(errs) != 0 {
+		t.Fatalf("expected no schema errors, got %v", errs)
+	}
+}
+
+func TestValidateJSONSchema_ReportsViolations(t *testing.T) {
+	data := map[string]interface{}{
+		"price":    float64(-1),
+		"currency": "GBP",
+		"tags":     []interface{}{"a", float64(2)},
+		"stock":    1.5,
+		"extra":    true,
+	}
+	errs := ValidateJSONSchema(data, productSchema())
+	joined := strings.Join(errs, "\n")
+	for _, want := range []string{
+		`$: missing required property "name"`,
+		"$.price: -1 is below minimum 0",
+		"$.currency: value GBP not in enum",
+		"$.tags[1]: expected string, got integer",
+		"$.stock: expected integer, got number",
+		`$: unexpected property "extra"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected violation %q in:\n%s", want, joined)
+		}
+	}
+}
+
+func TestScoreStructuredFields(t *testing.T) {
+	expected := map[string]interface{}{
+		"name":  "Acme Widget",
+		"price": int64(1299),
+		"tags":  []interface{}{"home", "outdoor"},
+		"specs": map[string]interface{}{
+			"waterproof": true,
+			"weight":     "1.2 kg",
+		},
+	}
+	actual := map[string]interface{}{
+		"name":  "acme  widget.",
+		"price": 1300.0,
+		"tags":  []interface{}{"Home"},
+		"specs": map[string]interface{}{
+			"waterproof": true,
+			"weight":     "approx. 1.2 kg",
+		},
+	}
+
+	got := ScoreStructuredFields(expected, actual)
+	if got.ExpectedFields != 5 {
+		t.Fatalf("ExpectedFields = %d, want 5", got.ExpectedFields)
+	}
+	if got.MatchedFields != 3 {
+		t.Fatalf("MatchedFields = %d, want 3 (%v)", got.MatchedFields, got.FieldScores)
+	}
+	if got.FieldScores["tags"] != 0.5 || got.FieldScores["specs.weight"] != 0.5 {
+		t.Fatalf("unexpected partial scores: %v", got.FieldScores)
+	}
+	if got.Accuracy != 80 {
+		t.Fatalf("Accuracy = %.1f, want 80", got.Accuracy)
+	}
+}
@@ -0,0 +1,183 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// documentGroup aggregates a provider's document extraction results for one document type.
+type documentGroup struct {
+	provider      string
+	documentType  string
+	tests         int
+	handled       int
+	fidelityTests int
+	fidelityTotal float64
+	pageChecks    int
+	pagesCorrect  int
+	tableChecks   int
+	tablesCorrect int
+}
+
+// documentGroups groups executed document tests by provider and document type.
+func (g *Generator) documentGroups(providers []string) []*documentGroup {
+	var out []*documentGroup
+	for _, provider := range providers {
+		byType := make(map[string]*documentGroup)
+		for _, r := range g.collector.GetResultsByProvider(provider) {
+			if r.Skipped || r.Document == nil {
+				continue
+			}
+			group := byType[r.Document.DocumentType]
+			if group == nil {
+				group = &documentGroup{provider: provider, documentType: r.Document.DocumentType}
+				byType[r.Document.DocumentType] = group
+			}
+			group.add(r.Document)
+		}
+
+		types := make([]string, 0, len(byType))
+		for docType := range byType {
+			types = append(types, docType)
+		}
+		sort.Strings(types)
+		for _, docType := range types {
+			out = append(out, byType[docType])
+		}
+	}
+	return out
+}
+
+func (d *documentGroup) add(stats *benchmetrics.DocumentStats) {
+	d.tests++
+	if stats.Handled {
+		d.handled++
+	}
+	if stats.ReferenceAvailable {
+		d.fidelityTests++
+		d.fidelityTotal += stats.TextFidelity
+	}
+	if stats.ExpectedPages != nil {
+		d.pageChecks++
+		if stats.Pages == *stats.ExpectedPages {
+			d.pagesCorrect++
+		}
+	}
+	if stats.ExpectedTables != nil {
+		d.tableChecks++
+		if stats.Tables == *stats.ExpectedTables {
+			d.tablesCorrect++
+		}
+	}
+}
+
+func (d *documentGroup) fidelity() string {
+	if d.fidelityTests == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", d.fidelityTotal/float64(d.fidelityTests))
+}
+
+func formatCheckRatio(correct, checks int) string {
+	if checks == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d/%d", correct, checks)
+}
+
+func formatExtractDetails(r benchmetrics.Result) string {
+	details := fmt.Sprintf("%d chars", r.ContentLength)
+	if r.Document == nil {
+		return details
+	}
+	details += ", " + r.Document.DocumentType
+	if r.Document.Pages > 0 {
+		details += fmt.Sprintf(", %d pages", r.Document.Pages)
+	}
+	if r.Document.ReferenceAvailable {
+		details += fmt.Sprintf(", %.0f%% fidelity", r.Document.TextFidelity)
+	}
+	return details
+}
+
+// writeDocumentExtraction writes per-provider handling of PDF, DOCX and text documents.
+func (g *Generator) writeDocumentExtraction(sb *strings.Builder, providers []string) {
+	groups := g.documentGroups(providers)
+	if len(groups) == 0 {
+		return
+	}
+
+	sb.WriteString("### Document Extraction\n\n")
+	sb.WriteString("Handled counts extractions that returned text for the expected document type. Text fidelity is the word-level F1 against the reference text; pages and tables show results matching the expected counts.\n\n")
+	sb.WriteString("| Provider | Type | Tests | Handled | Text Fidelity | Pages | Tables |\n")
+	sb.WriteString("|----------|------|-------|---------|---------------|-------|--------|\n")
+	for _, d := range groups {
+		fmt.Fprintf(sb, "| %s | %s | %d | %d/%d | %s | %s | %s |\n",
+			d.provider,
+			d.documentType,
+			d.tests,
+			d.handled, d.tests,
+			d.fidelity(),
+			formatCheckRatio(d.pagesCorrect, d.pageChecks),
+			formatCheckRatio(d.tablesCorrect, d.tableChecks),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generateDocumentSection returns the document extraction table HTML when document tests ran.
+func (g *Generator) generateDocumentSection() string {
+	groups := g.documentGroups(g.collector.GetAllProviders())
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, d := range groups {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%s</td>
+                        <td>%d</td>
+                        <td>%d/%d</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>`,
+			d.provider,
+			capitalize(d.provider),
+			d.documentType,
+			d.tests,
+			d.handled, d.tests,
+			d.fidelity(),
+			formatCheckRatio(d.pagesCorrect, d.pageChecks),
+			formatCheckRatio(d.tablesCorrect, d.tableChecks),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Document Extraction</h2>
+            <p class="quality-note">Handled counts extractions that returned text for the expected document type. Text fidelity is the word-level F1 against the reference text; pages and tables show results matching the expected counts.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Type</th>
+                        <th>Tests</th>
+                        <th>Handled</th>
+                        <th>Text Fidelity</th>
+                        <th>Pages</th>
+                        <th>Tables</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
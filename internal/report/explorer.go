@@ -0,0 +1,293 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// explorerTest is one test in the result explorer with the result shown for
+// each provider.
+type explorerTest struct {
+	name     string
+	testType string
+	output   *benchmetrics.Output // query, URL and terms, from any provider
+	columns  []explorerColumn
+}
+
+type explorerColumn struct {
+	provider string
+	result   benchmetrics.Result // first run with output, else the first run
+	runs     int
+}
+
+// explorerTests groups results with captured output by test. Providers
+// without output for a test still get a column showing why.
+func (g *Generator) explorerTests() []explorerTest {
+	var tests []explorerTest
+	providers := g.collector.GetAllProviders()
+	for _, name := range g.collector.GetAllTests() {
+		byProvider := make(map[string][]benchmetrics.Result)
+		for _, r := range g.collector.GetResultsByTest(name) {
+			byProvider[r.Provider] = append(byProvider[r.Provider], r)
+		}
+
+		test := explorerTest{name: name}
+		for _, provider := range providers {
+			results := byProvider[provider]
+			if len(results) == 0 {
+				continue
+			}
+			sort.SliceStable(results, func(i, j int) bool { return results[i].Repeat < results[j].Repeat })
+			column := explorerColumn{provider: provider, result: results[0], runs: len(results)}
+			for _, r := range results {
+				if r.Output != nil {
+					column.result = r
+					break
+				}
+			}
+			if test.output == nil && column.result.Output != nil {
+				test.output = column.result.Output
+			}
+			test.testType = column.result.TestType
+			test.columns = append(test.columns, column)
+		}
+		if test.output != nil {
+			tests = append(tests, test)
+		}
+	}
+	return tests
+}
+
+const explorerNote = "What each provider returned, from its first run with output. Results are in provider rank order and cut to the capture limits; expected URLs are marked and query and expected terms are highlighted."
+
+// generateExplorerSection returns the side-by-side result explorer HTML when
+// any result has captured output.
+func (g *Generator) generateExplorerSection() string {
+	tests := g.explorerTests()
+	if len(tests) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`
+        <div class="section">
+            <h2>Result Explorer</h2>
+            <p class="quality-note">` + explorerNote + `</p>
+            <select class="explorer-select" onchange="for (const el of document.querySelectorAll('.explorer-test')) el.hidden = el.dataset.test !== this.value">`)
+	for i, test := range tests {
+		fmt.Fprintf(&sb, `
+                <option value="%d">%s (%s)</option>`, i, html.EscapeString(test.name), html.EscapeString(test.testType))
+	}
+	sb.WriteString(`
+            </select>`)
+
+	for i, test := range tests {
+		hidden := ""
+		if i > 0 {
+			hidden = " hidden"
+		}
+		fmt.Fprintf(&sb, `
+            <div class="explorer-test" data-test="%d"%s>`, i, hidden)
+		writeExplorerTarget(&sb, test.output)
+		sb.WriteString(`
+                <div class="explorer-grid">`)
+		terms := termPattern(test.output.Terms)
+		for _, column := range test.columns {
+			writeExplorerColumn(&sb, column, terms)
+		}
+		sb.WriteString(`
+                </div>
+            </div>`)
+	}
+	sb.WriteString(`
+        </div>
+`)
+	return sb.String()
+}
+
+// writeExplorerTarget writes the query or URL and the expected URLs.
+func writeExplorerTarget(sb *strings.Builder, output *benchmetrics.Output) {
+	sb.WriteString(`
+                <p class="explorer-target">`)
+	if output.Query != "" {
+		fmt.Fprintf(sb, "<strong>Query:</strong> %s", html.EscapeString(output.Query))
+	} else {
+		fmt.Fprintf(sb, "<strong>URL:</strong> %s", html.EscapeString(output.URL))
+	}
+	if len(output.ExpectedURLs) > 0 {
+		fmt.Fprintf(sb, "<br><strong>Expected URLs:</strong> %s", html.EscapeString(strings.Join(output.ExpectedURLs, ", ")))
+	}
+	sb.WriteString("</p>")
+}
+
+func writeExplorerColumn(sb *strings.Builder, column explorerColumn, terms *regexp.Regexp) {
+	r := column.result
+	fmt.Fprintf(sb, `
+                    <div class="explorer-column">
+                        <span class="provider-badge provider-%s">%s</span>
+                        <div class="explorer-meta">%s</div>`,
+		html.EscapeString(r.Provider), html.EscapeString(capitalize(r.Provider)), html.EscapeString(explorerMeta(column)))
+
+	switch {
+	case r.Output != nil:
+		writeExplorerItems(sb, r.Output, terms)
+	case r.Skipped:
+		fmt.Fprintf(sb, `
+                        <p class="skipped">Skipped: %s</p>`, html.EscapeString(r.SkipReason))
+	case !r.Success:
+		fmt.Fprintf(sb, `
+                        <p class="failure">Failed: %s</p>`, html.EscapeString(r.Error))
+	default:
+		sb.WriteString(`
+                        <p class="skipped">No output captured</p>`)
+	}
+
+	if len(r.RawQualityMetrics) > 0 {
+		sb.WriteString(`
+                        <details>
+                            <summary>Quality metrics</summary>
+                            <table class="explorer-metrics">`)
+		keys := make([]string, 0, len(r.RawQualityMetrics))
+		for key := range r.RawQualityMetrics {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(sb, `
+                                <tr><td>%s</td><td>%s</td></tr>`, html.EscapeString(key), html.EscapeString(formatMetric(r.RawQualityMetrics[key])))
+		}
+		sb.WriteString(`
+                            </table>
+                        </details>`)
+	}
+	sb.WriteString(`
+                    </div>`)
+}
+
+// explorerMeta summarizes the shown run, e.g. "run 1 of 3 · 5 of 10 results · quality 82.5 · 1200ms".
+func explorerMeta(column explorerColumn) string {
+	r := column.result
+	parts := []string{fmt.Sprintf("run %d of %d", max(r.Repeat, 1), column.runs)}
+	if r.Output != nil {
+		unit := "results"
+		if r.TestType != "search" {
+			unit = "pages"
+		}
+		parts = append(parts, fmt.Sprintf("%d of %d %s", len(r.Output.Items), r.Output.TotalItems, unit))
+	}
+	if r.QualityScored {
+		parts = append(parts, fmt.Sprintf("quality %.1f", r.QualityScore))
+	}
+	if r.Latency > 0 {
+		parts = append(parts, formatLatency(float64(r.Latency.Milliseconds())))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func writeExplorerItems(sb *strings.Builder, output *benchmetrics.Output, terms *regexp.Regexp) {
+	if len(output.Items) == 0 {
+		sb.WriteString(`
+                        <p class="skipped">No results</p>`)
+		return
+	}
+	sb.WriteString(`
+                        <ol class="explorer-items">`)
+	for _, item := range output.Items {
+		class := "explorer-item"
+		if item.Matched {
+			class += " matched"
+		}
+		title := item.Title
+		if title == "" {
+			title = item.URL
+		}
+		fmt.Fprintf(sb, `
+                            <li class="%s">
+                                %s
+                                <span class="explorer-url">%s</span>
+                                <div class="explorer-content">%s</div>`,
+			class, explorerLink(item.URL, highlight(title, terms)), html.EscapeString(item.URL), highlight(item.Content, terms))
+		if tags := explorerTags(item); tags != "" {
+			fmt.Fprintf(sb, `
+                                <span class="explorer-tags">%s</span>`, html.EscapeString(tags))
+		}
+		sb.WriteString(`
+                            </li>`)
+	}
+	sb.WriteString(`
+                        </ol>`)
+	if output.Truncated {
+		sb.WriteString(`
+                        <p class="explorer-tags">Output cut to the capture limits.</p>`)
+	}
+}
+
+// explorerLink links http(s) URLs; other schemes are shown as text only.
+func explorerLink(url, label string) string {
+	lower := strings.ToLower(url)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return "<strong>" + label + "</strong>"
+	}
+	return `<a href="` + html.EscapeString(url) + `" target="_blank" rel="noopener noreferrer">` + label + "</a>"
+}
+
+func explorerTags(item benchmetrics.OutputItem) string {
+	var tags []string
+	if item.Matched {
+		tags = append(tags, "✓ expected URL")
+	}
+	if item.Score != 0 {
+		tags = append(tags, fmt.Sprintf("score %.3f", item.Score))
+	}
+	if item.PublishedAt != nil {
+		tags = append(tags, "published "+item.PublishedAt.Format("2006-01-02"))
+	}
+	if len([]rune(item.Content)) < item.ContentLength {
+		tags = append(tags, fmt.Sprintf("%d chars", item.ContentLength))
+	}
+	return strings.Join(tags, " · ")
+}
+
+// termPattern matches any of the terms case-insensitively, longest first.
+// It returns nil when there are no terms.
+func termPattern(terms []string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), terms...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	quoted := make([]string, len(sorted))
+	for i, term := range sorted {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
+
+// highlight escapes text for HTML and wraps matches of terms in <mark>.
+func highlight(text string, terms *regexp.Regexp) string {
+	if terms == nil {
+		return html.EscapeString(text)
+	}
+	var sb strings.Builder
+	last := 0
+	for _, loc := range terms.FindAllStringIndex(text, -1) {
+		sb.WriteString(html.EscapeString(text[last:loc[0]]))
+		sb.WriteString("<mark>" + html.EscapeString(text[loc[0]:loc[1]]) + "</mark>")
+		last = loc[1]
+	}
+	sb.WriteString(html.EscapeString(text[last:]))
+	return sb.String()
+}
+
+// formatMetric renders a raw quality metric value.
+func formatMetric(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return fmt.Sprintf("%.2f", f)
+	}
+	return jsonText(v)
+}
@@ -0,0 +1,263 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// ExportSchemaVersion versions the columns of the CSV and Parquet exports.
+// Fixed columns are only added within a version; renaming, removing or
+// retyping one bumps it. The quality_* and domain_* columns follow the
+// metrics present in the results.
+const ExportSchemaVersion = "v1"
+
+// exportSchemaKey names the Parquet metadata entry holding ExportSchemaVersion.
+const exportSchemaKey = "sanitywebeval.export_schema_version"
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindTimestamp
+)
+
+// column is one column of the flat results table. Missing values are nil.
+type column struct {
+	name   string
+	kind   columnKind
+	values []interface{}
+}
+
+// fixedColumns are the columns every export has, in order.
+var fixedColumns = []struct {
+	name  string
+	kind  columnKind
+	value func(r benchmetrics.Result) interface{}
+}{
+	{"schema_version", kindString, func(benchmetrics.Result) interface{} { return ExportSchemaVersion }},
+	{"timestamp", kindTimestamp, func(r benchmetrics.Result) interface{} { return r.Timestamp }},
+	{"test_name", kindString, func(r benchmetrics.Result) interface{} { return r.TestName }},
+	{"test_type", kindString, func(r benchmetrics.Result) interface{} { return r.TestType }},
+	{"provider", kindString, func(r benchmetrics.Result) interface{} { return r.Provider }},
+	{"provider_type", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.ProviderType) }},
+	{"mode", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.RunMode) }},
+	{"repeat", kindInt, func(r benchmetrics.Result) interface{} { return int64(r.Repeat) }},
+	{"implementation_type", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.ImplementationType) }},
+	{"excluded_from_primary", kindBool, func(r benchmetrics.Result) interface{} { return r.ExcludedFromPrimary }},
+	{"exclusion_reason", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.ExclusionReason) }},
+	{"success", kindBool, func(r benchmetrics.Result) interface{} { return r.Success }},
+	{"skipped", kindBool, func(r benchmetrics.Result) interface{} { return r.Skipped }},
+	{"skip_reason", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.SkipReason) }},
+	{"error", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.Error) }},
+	{"error_category", kindString, func(r benchmetrics.Result) interface{} { return nonEmpty(r.ErrorCategory) }},
+	{"latency_ms", kindFloat, func(r benchmetrics.Result) interface{} { return durationMS(r.Latency) }},
+	{"provider_latency_ms", kindFloat, func(r benchmetrics.Result) interface{} {
+		if r.ProviderLatency == 0 {
+			return nil
+		}
+		return durationMS(r.ProviderLatency)
+	}},
+	{"credits_used", kindInt, func(r benchmetrics.Result) interface{} { return int64(r.CreditsUsed) }},
+	{"request_count", kindInt, func(r benchmetrics.Result) interface{} { return int64(r.RequestCount) }},
+	{"usage_reported", kindBool, func(r benchmetrics.Result) interface{} { return r.UsageReported }},
+	{"cost_usd", kindFloat, func(r benchmetrics.Result) interface{} { return r.CostUSD }},
+	{"results_count", kindInt, func(r benchmetrics.Result) interface{} { return int64(r.ResultsCount) }},
+	{"content_length", kindInt, func(r benchmetrics.Result) interface{} { return int64(r.ContentLength) }},
+	{"quality_scored", kindBool, func(r benchmetrics.Result) interface{} { return r.QualityScored }},
+	{"quality_score", kindFloat, func(r benchmetrics.Result) interface{} {
+		if !r.QualityScored {
+			return nil
+		}
+		return r.QualityScore
+	}},
+	{"semantic_score", kindFloat, func(r benchmetrics.Result) interface{} { return nonZero(r.SemanticScore) }},
+	{"reranker_score", kindFloat, func(r benchmetrics.Result) interface{} { return nonZero(r.RerankerScore) }},
+}
+
+func nonEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nonZero(v float64) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// resultTable flattens the results into one row per result: the fixed
+// columns, then a quality_<metric> column per RawQualityMetrics key and a
+// domain_<domain> column per DomainScores key, each in sorted order.
+func resultTable(results []benchmetrics.Result) []column {
+	columns := make([]column, 0, len(fixedColumns))
+	for _, fc := range fixedColumns {
+		col := column{name: fc.name, kind: fc.kind, values: make([]interface{}, len(results))}
+		for i, r := range results {
+			col.values[i] = fc.value(r)
+		}
+		columns = append(columns, col)
+	}
+
+	qualityKeys := make(map[string]struct{})
+	domainKeys := make(map[string]struct{})
+	for _, r := range results {
+		for key := range r.RawQualityMetrics {
+			qualityKeys[key] = struct{}{}
+		}
+		for key := range r.DomainScores {
+			domainKeys[key] = struct{}{}
+		}
+	}
+	for _, key := range sortedKeys(qualityKeys) {
+		col := column{name: "quality_" + key, values: make([]interface{}, len(results))}
+		for i, r := range results {
+			col.values[i] = r.RawQualityMetrics[key]
+		}
+		columns = append(columns, normalizeColumn(col))
+	}
+	for _, key := range sortedKeys(domainKeys) {
+		col := column{name: "domain_" + key, kind: kindFloat, values: make([]interface{}, len(results))}
+		for i, r := range results {
+			if score, ok := r.DomainScores[key]; ok {
+				col.values[i] = score
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalizeColumn types a quality metric column from its values: numbers
+// become floats and booleans stay booleans; any other or mixed values are
+// stored as JSON text.
+func normalizeColumn(col column) column {
+	allFloat, allBool := true, true
+	for i, v := range col.values {
+		switch n := v.(type) {
+		case nil:
+		case float64:
+			allBool = false
+		case float32:
+			col.values[i] = float64(n)
+			allBool = false
+		case int:
+			col.values[i] = float64(n)
+			allBool = false
+		case int64:
+			col.values[i] = float64(n)
+			allBool = false
+		case bool:
+			allFloat = false
+		default:
+			allFloat, allBool = false, false
+		}
+	}
+	switch {
+	case allFloat:
+		col.kind = kindFloat
+	case allBool:
+		col.kind = kindBool
+	default:
+		col.kind = kindString
+		for i, v := range col.values {
+			if v != nil {
+				col.values[i] = jsonText(v)
+			}
+		}
+	}
+	return col
+}
+
+func jsonText(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// formatCell renders a value for CSV; missing values are empty.
+func formatCell(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case string:
+		return x
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// GenerateCSV writes results.csv: one flat row per result.
+func (g *Generator) GenerateCSV() error {
+	columns := resultTable(g.collector.GetResults())
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0].values)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	record := make([]string, len(columns))
+	for i, col := range columns {
+		record[i] = col.name
+	}
+	if err := w.Write(record); err != nil {
+		return err
+	}
+	for row := 0; row < rows; row++ {
+		for i, col := range columns {
+			record[i] = formatCell(col.values[row])
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	outputPath := filepath.Join(g.outputDir, "results.csv")
+	// #nosec G306 - 0640 allows owner/group to read, which is appropriate for report files
+	return os.WriteFile(outputPath, buf.Bytes(), 0640)
+}
@@ -0,0 +1,394 @@
+package report
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+func exportCollector() *benchmetrics.Collector {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:           "Search Test",
+		TestType:           "search",
+		Provider:           "exa",
+		ProviderType:       "exa",
+		RunMode:            "normalized",
+		Repeat:             1,
+		ImplementationType: "native",
+		Success:            true,
+		Latency:            1500 * time.Millisecond,
+		ProviderLatency:    1200 * time.Millisecond,
+		CreditsUsed:        2,
+		CostUSD:            0.01,
+		ResultsCount:       5,
+		QualityScore:       82.5,
+		QualityScored:      true,
+		DomainScores:       map[string]float64{"docs": 90},
+		RawQualityMetrics:  map[string]interface{}{"relevance": 0.8, "fresh": true},
+		Timestamp:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName:  "Search Test",
+		TestType:  "search",
+		Provider:  "tavily",
+		Repeat:    2,
+		Error:     "HTTP 500",
+		Latency:   300 * time.Millisecond,
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC),
+	})
+	return c
+}
+
+func TestGenerateCSV(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := NewGenerator(exportCollector(), tmpDir).GenerateCSV(); err != nil {
+		t.Fatalf("GenerateCSV failed: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(tmpDir, "results.csv"))
+	if err != nil {
+		t.Fatalf("results.csv not created: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(records))
+	}
+
+	rows := make([]map[string]string, 0, 2)
+	for _, record := range records[1:] {
+		row := make(map[string]string)
+		for i, name := range records[0] {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	if records[0][0] != "schema_version" || rows[0]["schema_version"] != ExportSchemaVersion {
+		t.Errorf("expected schema_version as the first column, got %v", records[0])
+	}
+	want := map[string]string{
+		"timestamp":           "2026-01-02T03:04:05Z",
+		"mode":                "normalized",
+		"repeat":              "1",
+		"implementation_type": "native",
+		"latency_ms":          "1500",
+		"provider_latency_ms": "1200",
+		"credits_used":        "2",
+		"cost_usd":            "0.01",
+		"quality_score":       "82.5",
+		"quality_relevance":   "0.8",
+		"quality_fresh":       "true",
+		"domain_docs":         "90",
+	}
+	for name, value := range want {
+		if rows[0][name] != value {
+			t.Errorf("%s: expected %q, got %q", name, value, rows[0][name])
+		}
+	}
+	for _, name := range []string{"provider_latency_ms", "quality_score", "quality_relevance", "domain_docs"} {
+		if rows[1][name] != "" {
+			t.Errorf("%s: expected an empty cell for the failed run, got %q", name, rows[1][name])
+		}
+	}
+	if rows[1]["error"] != "HTTP 500" || rows[1]["success"] != "false" {
+		t.Errorf("unexpected failed row: %v", rows[1])
+	}
+}
+
+func TestNormalizeColumn(t *testing.T) {
+	col := normalizeColumn(column{values: []interface{}{1, nil, int64(2), 0.5}})
+	if col.kind != kindFloat || col.values[0] != 1.0 || col.values[1] != nil {
+		t.Errorf("expected a float column, got %+v", col)
+	}
+	col = normalizeColumn(column{values: []interface{}{true, "x", []string{"a"}}})
+	if col.kind != kindString || col.values[0] != "true" || col.values[1] != "x" || col.values[2] != `["a"]` {
+		t.Errorf("expected a JSON text column, got %+v", col)
+	}
+}
+
+// thriftReader decodes the subset of the Thrift compact protocol the Parquet
+// footer uses into nested maps keyed by field id.
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case compactI32, compactI64:
+		return r.zigzag()
+	case compactBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case compactList:
+		header := r.data[r.pos]
+		r.pos++
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			items[i] = r.value(header & 0x0f)
+		}
+		return items
+	case compactStruct:
+		return r.readStruct()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) readStruct() map[int]interface{} {
+	fields := make(map[int]interface{})
+	id := 0
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+	}
+}
+
+func structAt(t *testing.T, v interface{}) map[int]interface{} {
+	t.Helper()
+	s, ok := v.(map[int]interface{})
+	if !ok {
+		t.Fatalf("expected a struct, got %T", v)
+	}
+	return s
+}
+
+func listAt(t *testing.T, v interface{}) []interface{} {
+	t.Helper()
+	l, ok := v.([]interface{})
+	if !ok {
+		t.Fatalf("expected a list, got %T", v)
+	}
+	return l
+}
+
+func TestGenerateParquet(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := NewGenerator(exportCollector(), tmpDir).GenerateParquet(); err != nil {
+		t.Fatalf("GenerateParquet failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "results.parquet"))
+	if err != nil {
+		t.Fatalf("results.parquet not created: %v", err)
+	}
+	if string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatalf("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := (&thriftReader{data: data[len(data)-8-footerLen : len(data)-8]}).readStruct()
+
+	if footer[3] != int64(2) {
+		t.Errorf("expected 2 rows, got %v", footer[3])
+	}
+	kv := structAt(t, listAt(t, footer[5])[0])
+	if kv[1] != exportSchemaKey || kv[2] != ExportSchemaVersion {
+		t.Errorf("expected the schema version in the file metadata, got %v", kv)
+	}
+
+	schema := listAt(t, footer[2])
+	columns := resultTable(exportCollector().GetResults())
+	if len(schema) != len(columns)+1 || structAt(t, schema[0])[5] != int64(len(columns)) {
+		t.Fatalf("expected a root and %d leaves, got %d schema elements", len(columns), len(schema))
+	}
+	latencyIndex := -1
+	for i, col := range columns {
+		leaf := structAt(t, schema[i+1])
+		if leaf[4] != col.name || leaf[3] != int64(repetitionOptional) {
+			t.Errorf("schema element %d: expected optional %q, got %v", i, col.name, leaf)
+		}
+		if col.name == "provider_latency_ms" {
+			latencyIndex = i
+		}
+	}
+
+	// Decode the provider_latency_ms page: the second row is null.
+	rowGroup := structAt(t, listAt(t, footer[4])[0])
+	chunk := structAt(t, listAt(t, rowGroup[1])[latencyIndex])
+	meta := structAt(t, chunk[3])
+	if meta[1] != int64(parquetDouble) {
+		t.Fatalf("expected a DOUBLE column, got %v", meta[1])
+	}
+	offset, ok := meta[9].(int64)
+	if !ok {
+		t.Fatalf("missing data page offset")
+	}
+	page := &thriftReader{data: data, pos: int(offset)}
+	header := page.readStruct()
+	if structAt(t, header[5])[1] != int64(2) {
+		t.Errorf("expected 2 values in the page, got %v", header)
+	}
+	body := data[page.pos:]
+	levelsLen := int(binary.LittleEndian.Uint32(body))
+	levels := body[4 : 4+levelsLen]
+	if levels[len(levels)-1] != 0b01 {
+		t.Errorf("expected only the first row to be defined, got %08b", levels[len(levels)-1])
+	}
+	value := math.Float64frombits(binary.LittleEndian.Uint64(body[4+levelsLen:]))
+	if value != 1200 {
+		t.Errorf("expected provider_latency_ms 1200, got %v", value)
+	}
+}
+
+func TestGenerateParquet_EmptyResults(t *testing.T) {
+	data := encodeParquet(resultTable(nil), map[string]string{exportSchemaKey: ExportSchemaVersion})
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if 4+footerLen+8 != len(data) {
+		t.Fatalf("expected only the footer after the magic, got %d bytes", len(data))
+	}
+	footer := (&thriftReader{data: data[4 : 4+footerLen]}).readStruct()
+	if footer[3] != int64(0) || len(listAt(t, footer[4])) != 0 {
+		t.Errorf("expected no rows and no row groups, got %v", footer)
+	}
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenParquetPath holds encodeParquet's output for goldenColumns, so any
+// change to the bytes written is deliberate. TestEncodeParquet_ReadsWithPyArrow
+// checks the file with pyarrow; run it after -update where pyarrow is
+// installed.
+const goldenParquetPath = "testdata/columns.parquet"
+
+// goldenColumns covers every column kind, with nulls at the start, middle
+// and end of the columns.
+func goldenColumns() []column {
+	return []column{
+		{name: "name", kind: kindString, values: []interface{}{"exa", nil, "", "tavily"}},
+		{name: "count", kind: kindInt, values: []interface{}{int64(5), int64(-1), nil, int64(1) << 40}},
+		{name: "score", kind: kindFloat, values: []interface{}{nil, 82.5, -0.25, 1e-9}},
+		{name: "success", kind: kindBool, values: []interface{}{true, false, nil, true}},
+		{name: "timestamp", kind: kindTimestamp, values: []interface{}{
+			time.Date(2026, 1, 2, 3, 4, 5, 6e6, time.UTC), nil, nil, time.Unix(0, 0).UTC(),
+		}},
+	}
+}
+
+func TestEncodeParquet_Golden(t *testing.T) {
+	data := encodeParquet(goldenColumns(), map[string]string{exportSchemaKey: ExportSchemaVersion, "note": "golden"})
+	if *updateGolden {
+		if err := os.WriteFile(goldenParquetPath, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenParquetPath)
+	if err != nil {
+		t.Fatalf("missing golden file (run with -update): %v", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("encodeParquet output differs from %s; if the change is intended, run with -update and check the file with a Parquet reader", goldenParquetPath)
+	}
+}
+
+// pyarrowReadScript prints a Parquet file's rows and key/value metadata as
+// JSON, with timestamps as epoch milliseconds.
+const pyarrowReadScript = `
+import json, sys
+import pyarrow.parquet as pq
+table = pq.read_table(sys.argv[1])
+rows = {}
+for name in table.column_names:
+    values = []
+    for v in table.column(name).to_pylist():
+        if hasattr(v, "timestamp"):
+            v = round(v.replace(tzinfo=__import__("datetime").timezone.utc).timestamp() * 1000)
+        values.append(v)
+    rows[name] = values
+meta = {k.decode(): v.decode() for k, v in (table.schema.metadata or {}).items()}
+print(json.dumps({"rows": rows, "types": [str(t) for t in table.schema.types], "metadata": meta}))
+`
+
+// TestEncodeParquet_ReadsWithPyArrow reads the golden file with pyarrow,
+// an independent Parquet implementation. It is skipped where pyarrow is
+// not installed.
+func TestEncodeParquet_ReadsWithPyArrow(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not found")
+	}
+	if exec.Command(python, "-c", "import pyarrow").Run() != nil {
+		t.Skip("pyarrow not installed")
+	}
+	out, err := exec.Command(python, "-c", pyarrowReadScript, goldenParquetPath).Output()
+	if err != nil {
+		t.Fatalf("pyarrow could not read %s: %v", goldenParquetPath, err)
+	}
+	var got struct {
+		Rows     map[string][]interface{} `json:"rows"`
+		Types    []string                 `json:"types"`
+		Metadata map[string]string        `json:"metadata"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("unexpected pyarrow output %s: %v", out, err)
+	}
+
+	// TIMESTAMP_MILLIS is UTC-adjusted, which pyarrow may show as tz=UTC.
+	wantTypes := []string{"string", "int64", "double", "bool", "timestamp[ms"}
+	if len(got.Types) != len(wantTypes) {
+		t.Fatalf("types = %v, want %v", got.Types, wantTypes)
+	}
+	for i, want := range wantTypes {
+		if !strings.HasPrefix(got.Types[i], want) {
+			t.Errorf("type of column %d = %s, want %s", i, got.Types[i], want)
+		}
+	}
+	if got.Metadata[exportSchemaKey] != ExportSchemaVersion || got.Metadata["note"] != "golden" {
+		t.Errorf("unexpected metadata %v", got.Metadata)
+	}
+	for _, col := range goldenColumns() {
+		want := make([]interface{}, len(col.values))
+		for i, v := range col.values {
+			switch x := v.(type) {
+			case int64:
+				want[i] = float64(x)
+			case time.Time:
+				want[i] = float64(x.UnixMilli())
+			default:
+				want[i] = x
+			}
+		}
+		if !reflect.DeepEqual(got.Rows[col.name], want) {
+			t.Errorf("column %s = %v, want %v", col.name, got.Rows[col.name], want)
+		}
+	}
+}
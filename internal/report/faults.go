@@ -0,0 +1,160 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/faultproxy"
+)
+
+// faultGroup aggregates a provider's results under fault injection.
+type faultGroup struct {
+	provider      string
+	tests         int
+	succeeded     int
+	attempts      int
+	injected      map[string]int
+	faultedTests  int
+	recovered     int
+	retries       int
+	wastedRetries int
+	totalLatency  time.Duration
+}
+
+// faultGroups groups proxied results by provider.
+func (g *Generator) faultGroups(providers []string) []*faultGroup {
+	var out []*faultGroup
+	for _, provider := range providers {
+		group := &faultGroup{provider: provider, injected: make(map[string]int)}
+		for _, r := range g.collector.GetResultsByProvider(provider) {
+			if r.Skipped || r.Faults == nil {
+				continue
+			}
+			group.tests++
+			group.attempts += r.Faults.Attempts
+			group.retries += r.Faults.Retries
+			group.wastedRetries += r.Faults.WastedRetries
+			group.totalLatency += r.Latency
+			for fault, n := range r.Faults.Injected {
+				group.injected[fault] += n
+			}
+			if r.Success {
+				group.succeeded++
+			}
+			if r.Faults.Faulted > 0 {
+				group.faultedTests++
+				if r.Success {
+					group.recovered++
+				}
+			}
+		}
+		if group.tests > 0 {
+			out = append(out, group)
+		}
+	}
+	return out
+}
+
+// injectedSummary lists injected faults in a fixed order, e.g. "rate_limit 3, latency 1".
+func (f *faultGroup) injectedSummary() string {
+	var parts []string
+	for _, fault := range faultproxy.Faults {
+		if n := f.injected[string(fault)]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", fault, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+const faultNote = "Provider APIs ran through a local proxy that injected latency, 429s with Retry-After, 503s, connection resets, truncated bodies and malformed JSON. Retries are proxy requests beyond what each operation needed; wasted retries belong to tests that still failed. Recovered counts tests hit by a fault that succeeded anyway."
+
+// writeFaultInjection writes how each client's retry logic coped with injected faults.
+func (g *Generator) writeFaultInjection(sb *strings.Builder, providers []string) {
+	groups := g.faultGroups(providers)
+	if len(groups) == 0 {
+		return
+	}
+
+	sb.WriteString("### Fault Injection\n\n")
+	sb.WriteString(faultNote + "\n\n")
+	sb.WriteString("| Provider | Tests | Requests | Injected | Retries | Wasted Retries | Recovered | Success | Total Latency |\n")
+	sb.WriteString("|----------|-------|----------|----------|---------|----------------|-----------|---------|---------------|\n")
+	for _, f := range groups {
+		fmt.Fprintf(sb, "| %s | %d | %d | %s | %d | %d | %s | %d/%d | %s |\n",
+			f.provider,
+			f.tests,
+			f.attempts,
+			f.injectedSummary(),
+			f.retries,
+			f.wastedRetries,
+			formatCheckRatio(f.recovered, f.faultedTests),
+			f.succeeded, f.tests,
+			formatLatency(float64(f.totalLatency.Milliseconds())),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generateFaultSection returns the fault injection table HTML when the run used the proxy.
+func (g *Generator) generateFaultSection() string {
+	groups := g.faultGroups(g.collector.GetAllProviders())
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, f := range groups {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%d</td>
+                        <td>%d</td>
+                        <td>%s</td>
+                        <td>%d</td>
+                        <td>%d</td>
+                        <td>%s</td>
+                        <td>%d/%d</td>
+                        <td>%s</td>
+                    </tr>`,
+			f.provider,
+			capitalize(f.provider),
+			f.tests,
+			f.attempts,
+			f.injectedSummary(),
+			f.retries,
+			f.wastedRetries,
+			formatCheckRatio(f.recovered, f.faultedTests),
+			f.succeeded, f.tests,
+			formatLatency(float64(f.totalLatency.Milliseconds())),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Fault Injection</h2>
+            <p class="quality-note">` + faultNote + `</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Tests</th>
+                        <th>Requests</th>
+                        <th>Injected</th>
+                        <th>Retries</th>
+                        <th>Wasted Retries</th>
+                        <th>Recovered</th>
+                        <th>Success</th>
+                        <th>Total Latency</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// freshnessSummaries returns summaries for providers that ran time-range tests.
+func (g *Generator) freshnessSummaries(providers []string) []providerSummary {
+	out := make([]providerSummary, 0, len(providers))
+	for _, provider := range providers {
+		summary := g.collector.ComputeSummary(provider)
+		if summary.FreshnessTests > 0 {
+			out = append(out, providerSummary{name: provider, summary: summary})
+		}
+	}
+	return out
+}
+
+func formatMedianAge(summary *benchmetrics.Summary) string {
+	if summary.FreshnessUndatedShare >= 100 {
+		return "-"
+	}
+	if summary.FreshnessMedianAgeHours < 48 {
+		return fmt.Sprintf("%.1fh", summary.FreshnessMedianAgeHours)
+	}
+	return fmt.Sprintf("%.1fd", summary.FreshnessMedianAgeHours/24)
+}
+
+// writeFreshnessCompliance writes time-range filter compliance per provider.
+func (g *Generator) writeFreshnessCompliance(sb *strings.Builder, providers []string) {
+	summaries := g.freshnessSummaries(providers)
+	if len(summaries) == 0 {
+		return
+	}
+
+	sb.WriteString("### Freshness Compliance\n\n")
+	sb.WriteString("Share of dated results published inside the requested `time_range`. Undated results are excluded from the compliance rate.\n\n")
+	sb.WriteString("| Provider | Time-Range Tests | Compliance | Undated Share | Median Age |\n")
+	sb.WriteString("|----------|------------------|------------|---------------|------------|\n")
+	for _, ps := range summaries {
+		fmt.Fprintf(sb, "| %s | %d | %.1f%% | %.1f%% | %s |\n",
+			ps.name,
+			ps.summary.FreshnessTests,
+			ps.summary.FreshnessComplianceRate,
+			ps.summary.FreshnessUndatedShare,
+			formatMedianAge(ps.summary),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generateFreshnessSection returns the freshness compliance table HTML when time-range tests ran.
+func (g *Generator) generateFreshnessSection() string {
+	summaries := g.freshnessSummaries(g.collector.GetAllProviders())
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, ps := range summaries {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%d</td>
+                        <td>%.1f%%</td>
+                        <td>%.1f%%</td>
+                        <td>%s</td>
+                    </tr>`,
+			ps.name,
+			capitalize(ps.name),
+			ps.summary.FreshnessTests,
+			ps.summary.FreshnessComplianceRate,
+			ps.summary.FreshnessUndatedShare,
+			formatMedianAge(ps.summary),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Freshness Compliance</h2>
+            <p class="quality-note">Share of dated results published inside the requested time range. Undated results are excluded from the compliance rate and reported separately.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Time-Range Tests</th>
+                        <th>Compliance</th>
+                        <th>Undated Share</th>
+                        <th>Median Age</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
@@ -448,6 +448,469 @@ func TestGenerateMarkdown_IncludesQualityByTestTypeSection(t *testing.T) {
 	}
 }
 
+func TestGenerateMarkdown_IncludesFreshnessCompliance(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName: "Recent",
+		Provider: "provider1",
+		TestType: "search",
+		Success:  true,
+		Freshness: &benchmetrics.FreshnessStats{
+			TimeRange:       "week",
+			WindowHours:     168,
+			TotalResults:    4,
+			DatedResults:    3,
+			UndatedResults:  1,
+			InWindowResults: 2,
+			AgesHours:       []float64{12, 36, 720},
+		},
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName: "Recent",
+		Provider: "provider2",
+		TestType: "search",
+		Success:  true,
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Freshness Compliance") {
+		t.Fatal("expected freshness compliance section")
+	}
+	if !strings.Contains(report, "| provider1 | 1 | 66.7% | 25.0% | 36.0h |") {
+		t.Fatal("expected provider1 freshness row")
+	}
+	if strings.Contains(report, "| provider2 | 0 |") {
+		t.Fatal("expected providers without time-range tests to be omitted")
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Freshness Compliance") {
+		t.Fatal("expected freshness compliance section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesStructuredExtraction(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:           "Product",
+		Provider:           "provider1",
+		TestType:           "structured_extract",
+		ImplementationType: "native",
+		Success:            true,
+		QualityScore:       85,
+		QualityScored:      true,
+		Structured: &benchmetrics.StructuredStats{
+			SchemaValid:    true,
+			ExpectedFields: 4,
+			MatchedFields:  3,
+			FieldAccuracy:  75,
+		},
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Structured Extraction") {
+		t.Fatal("expected structured extraction section")
+	}
+	if !strings.Contains(report, "| provider1 | 1 | 100.0% | 75.0% | native |") {
+		t.Fatal("expected provider1 structured extraction row")
+	}
+	if !strings.Contains(report, "3/4 fields, schema valid") {
+		t.Fatal("expected structured details in results table")
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Structured Extraction") {
+		t.Fatal("expected structured extraction section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesDocumentExtraction(t *testing.T) {
+	pages, tables := 3, 1
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:      "Annual Report",
+		Provider:      "local",
+		TestType:      "extract",
+		Success:       true,
+		ContentLength: 1200,
+		Document: &benchmetrics.DocumentStats{
+			DocumentType:       "pdf",
+			Handled:            true,
+			ReferenceAvailable: true,
+			TextFidelity:       91.5,
+			ExpectedPages:      &pages,
+			Pages:              3,
+			ExpectedTables:     &tables,
+			Tables:             0,
+		},
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName: "Annual Report",
+		Provider: "provider1",
+		TestType: "extract",
+		Success:  false,
+		Document: &benchmetrics.DocumentStats{DocumentType: "pdf", ReferenceAvailable: true},
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Document Extraction") {
+		t.Fatal("expected document extraction section")
+	}
+	if !strings.Contains(report, "| local | pdf | 1 | 1/1 | 91.5 | 1/1 | 0/1 |") {
+		t.Fatal("expected local document extraction row")
+	}
+	if !strings.Contains(report, "| provider1 | pdf | 1 | 0/1 | 0.0 | - | - |") {
+		t.Fatal("expected failed provider document extraction row")
+	}
+	if !strings.Contains(report, "1200 chars, pdf, 3 pages, 92% fidelity") {
+		t.Fatal("expected document details in results table")
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Document Extraction") {
+		t.Fatal("expected document extraction section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesLanguageCoverage(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName: "Japanese Search",
+		Provider: "provider1",
+		TestType: "search",
+		Success:  true,
+		Language: &benchmetrics.LanguageStats{Expected: "ja", Items: 5, Detected: 4, Matched: 3},
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName: "Russian Extract",
+		Provider: "provider1",
+		TestType: "extract",
+		Success:  true,
+		Language: &benchmetrics.LanguageStats{Expected: "ru", Items: 1, Detected: 1, Matched: 1, HeuristicsSkipped: true},
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Language Coverage") {
+		t.Fatal("expected language coverage section")
+	}
+	if !strings.Contains(report, "| provider1 | ja | 1 | 4/5 | 75.0% | localized |") {
+		t.Fatal("expected Japanese language row")
+	}
+	if !strings.Contains(report, "| provider1 | ru | 1 | 1/1 | 100.0% | skipped |") {
+		t.Fatal("expected Russian language row with skipped heuristics")
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Language Coverage") {
+		t.Fatal("expected language coverage section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesQueryRobustness(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:      "Search - Rust",
+		Provider:      "provider1",
+		TestType:      "search",
+		Success:       true,
+		QualityScore:  80,
+		QualityScored: true,
+		Perturbations: []benchmetrics.PerturbationResult{
+			{Kind: "typo", Success: true, URLOverlap: 60, QualityScored: true, QualityDelta: -12.5},
+			{Kind: "reorder", Success: true, URLOverlap: 100},
+			{Kind: "typo", Error: "timeout"},
+		},
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Query Robustness") {
+		t.Fatal("expected query robustness section")
+	}
+	if !strings.Contains(report, "| provider1 | typo | 2 | 1/2 | 60.0% | -12.5 |") {
+		t.Fatal("expected typo row")
+	}
+	if !strings.Contains(report, "| provider1 | reorder | 1 | 1/1 | 100.0% | - |") {
+		t.Fatal("expected reorder row")
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Query Robustness") {
+		t.Fatal("expected query robustness section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesFaultInjection(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName: "Search - Rust",
+		Provider: "provider1",
+		TestType: "search",
+		Success:  true,
+		Latency:  1500 * time.Millisecond,
+		Faults: &benchmetrics.FaultStats{
+			Attempts: 3,
+			Injected: map[string]int{"rate_limit": 1, "server_error": 1},
+			Faulted:  2,
+			Retries:  2,
+		},
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName: "Search - Go",
+		Provider: "provider1",
+		TestType: "search",
+		Error:    "connection reset",
+		Latency:  500 * time.Millisecond,
+		Faults: &benchmetrics.FaultStats{
+			Attempts:      4,
+			Injected:      map[string]int{"connection_reset": 4},
+			Faulted:       4,
+			Retries:       3,
+			WastedRetries: 3,
+		},
+	})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Fault Injection") {
+		t.Fatal("expected fault injection section")
+	}
+	if !strings.Contains(report, "| provider1 | 2 | 7 | rate_limit 1, server_error 1, connection_reset 4 | 5 | 3 | 1/2 | 1/2 |") {
+		t.Fatalf("expected provider1 fault row, got:\n%s", report)
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Fault Injection") {
+		t.Fatal("expected fault injection section in HTML")
+	}
+}
+
+func TestGenerateMarkdown_IncludesPricing(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:    "Search - Rust",
+		Provider:    "brave",
+		TestType:    "search",
+		Success:     true,
+		CreditsUsed: 1,
+		CostUSD:     0.005,
+		Latency:     time.Second,
+	})
+	c.SetPricingProfile(benchmetrics.DefaultPricingProfile().Merge(benchmetrics.PricingProfile{
+		Name: "negotiated",
+		Providers: map[string]benchmetrics.ProviderPricing{
+			"brave": {
+				Plan:           "Enterprise",
+				Unit:           "request",
+				Tiers:          []benchmetrics.PriceTier{{UpTo: 1000, UnitPrice: 0.004}, {UnitPrice: 0.003}},
+				MonthlyMinimum: 250,
+			},
+		},
+	}))
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "**Pricing profile:** negotiated") {
+		t.Fatal("expected pricing profile line")
+	}
+	if !strings.Contains(report, "| brave | Enterprise | request | $0.004 to 1000, then $0.003 | $250.00 |") {
+		t.Fatalf("expected brave pricing row, got:\n%s", report)
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "Pricing (negotiated)") {
+		t.Fatal("expected pricing section in HTML")
+	}
+}
+
+func TestGenerateHTML_EscapesPricingProfile(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{TestName: "Search - Rust", Provider: "brave", TestType: "search", Success: true, Latency: time.Second})
+	c.SetPricingProfile(benchmetrics.DefaultPricingProfile().Merge(benchmetrics.PricingProfile{
+		Name: "<b>acme</b>",
+		Providers: map[string]benchmetrics.ProviderPricing{
+			"brave": {Plan: "Pro & Co", Unit: "<script>", UnitPrice: 0.004},
+		},
+	}))
+
+	tmpDir := t.TempDir()
+	if err := NewGenerator(c, tmpDir).GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	page := string(content)
+	for _, want := range []string{"Pricing (&lt;b&gt;acme&lt;/b&gt;)", "<td>Pro &amp; Co</td>", "<td>&lt;script&gt;</td>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in the pricing section", want)
+		}
+	}
+	if strings.Contains(page, "<b>acme</b>") || strings.Contains(page, "<td><script></td>") {
+		t.Error("expected pricing values to be escaped")
+	}
+}
+
+func TestLoadJSON_RepriceRoundtrip(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	c.AddResult(benchmetrics.Result{
+		TestName:    "Search - Rust",
+		Provider:    "tavily",
+		TestType:    "search",
+		Success:     true,
+		CreditsUsed: 2,
+		CostUSD:     0.016,
+		Timestamp:   time.Now(),
+	})
+	c.SetPricingProfile(benchmetrics.DefaultPricingProfile())
+
+	tmpDir := t.TempDir()
+	if err := NewGenerator(c, tmpDir).GenerateJSON(); err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+
+	loaded, err := LoadJSON(filepath.Join(tmpDir, "report.json"))
+	if err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	profile, ok := loaded.PricingProfile()
+	if !ok || profile.Name != benchmetrics.DefaultPricingProfileName {
+		t.Fatalf("expected recorded default profile, got %q (%v)", profile.Name, ok)
+	}
+
+	loaded.Reprice(benchmetrics.DefaultPricingProfile().Merge(benchmetrics.PricingProfile{
+		Name:      "discounted",
+		Providers: map[string]benchmetrics.ProviderPricing{"tavily": {UnitPrice: 0.004}},
+	}))
+	if got := loaded.GetResults()[0].CostUSD; got != 0.008 {
+		t.Errorf("expected re-priced cost 0.008, got %v", got)
+	}
+
+	if _, err := LoadJSON(filepath.Join(tmpDir, "missing.json")); err == nil {
+		t.Error("expected error for missing report")
+	}
+}
+
+func TestGenerateMarkdown_IncludesProjection(t *testing.T) {
+	c := benchmetrics.NewCollector()
+	for _, r := range []benchmetrics.Result{
+		{TestName: "Search", Provider: "brave", TestType: "search", Success: true, CreditsUsed: 1, QualityScore: 70, QualityScored: true},
+		{TestName: "Search", Provider: "tavily", TestType: "search", Success: true, CreditsUsed: 2, QualityScore: 80, QualityScored: true},
+		{TestName: "Search", Provider: "exa", TestType: "search", Success: true, CreditsUsed: 1, QualityScore: 60, QualityScored: true},
+		{TestName: "Search", Provider: "exa", TestType: "search", Error: "timeout"},
+	} {
+		c.AddResult(r)
+	}
+	c.SetPricingProfile(benchmetrics.DefaultPricingProfile())
+	c.SetWorkload(benchmetrics.Workload{Searches: 1e6})
+
+	tmpDir := t.TempDir()
+	gen := NewGenerator(c, tmpDir)
+	if err := gen.GenerateMarkdown(); err != nil {
+		t.Fatalf("GenerateMarkdown failed: %v", err)
+	}
+	if err := gen.GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	if err := gen.GenerateJSON(); err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	report := string(content)
+	if !strings.Contains(report, "### Monthly Cost Projection") || !strings.Contains(report, "Workload: 1M searches, 0 extracts, 0 crawl pages per month") {
+		t.Fatalf("expected projection section, got:\n%s", report)
+	}
+	// brave: 1M requests at $0.005; tavily: 2M credits at $0.008; exa: 2 attempts per success.
+	for _, row := range []string{
+		"| brave | $5000.00 | - | - | $5000.00 | - | $5000.00 | 70.0 | ✓ |",
+		"| tavily | $16000.00 | - | - | $16000.00 | - | $16000.00 | 80.0 | ✓ |",
+		"| exa | $10000.00 (×2.00) | - | - | $10000.00 | - | $10000.00 | 60.0 |  |",
+	} {
+		if !strings.Contains(report, row) {
+			t.Errorf("expected row %q, got:\n%s", row, report)
+		}
+	}
+
+	htmlContent, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(htmlContent), "projectionFrontierChart") || !strings.Contains(string(htmlContent), "Pareto frontier") {
+		t.Fatal("expected projection frontier chart in HTML")
+	}
+
+	loaded, err := LoadJSON(filepath.Join(tmpDir, "report.json"))
+	if err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	if w, ok := loaded.Workload(); !ok || w.Searches != 1e6 {
+		t.Errorf("expected workload to round-trip, got %+v (%v)", w, ok)
+	}
+}
+
 func TestFormatCostUSD_TinyNonZero(t *testing.T) {
 	if got := formatCostUSD(0.000002); got != "<$0.0001" {
 		t.Fatalf("expected '<$0.0001' for tiny non-zero cost, got %q", got)
@@ -852,6 +1315,145 @@ func TestGenerateHTML_WritesFile(t *testing.T) {
 	}
 }
 
+func explorerCollector() *benchmetrics.Collector {
+	c := benchmetrics.NewCollector()
+	output := &benchmetrics.Output{
+		Query:        "CRISPR off-target",
+		ExpectedURLs: []string{"nature.com/crispr"},
+		Terms:        []string{"CRISPR", "off-target"},
+		Items: []benchmetrics.OutputItem{
+			{Rank: 1, Title: "CRISPR <review>", URL: "https://nature.com/crispr", Content: "Measuring off-target edits", ContentLength: 26, Matched: true},
+			{Rank: 2, Title: "Other", URL: "javascript:alert(1)", Content: "unrelated", ContentLength: 9},
+		},
+		TotalItems: 2,
+	}
+	c.AddResult(benchmetrics.Result{
+		TestName:          "Search - CRISPR",
+		Provider:          "exa",
+		TestType:          "search",
+		Repeat:            1,
+		Success:           true,
+		Latency:           1200 * time.Millisecond,
+		QualityScore:      82.5,
+		QualityScored:     true,
+		RawQualityMetrics: map[string]interface{}{"url_recall": 100.0},
+		Output:            output,
+	})
+	c.AddResult(benchmetrics.Result{
+		TestName: "Search - CRISPR",
+		Provider: "tavily",
+		TestType: "search",
+		Repeat:   1,
+		Error:    "HTTP 500",
+	})
+	c.AddResult(benchmetrics.Result{TestName: "Crawl - Docs", Provider: "exa", TestType: "crawl", Repeat: 1, Success: true})
+	return c
+}
+
+func TestGenerateHTML_IncludesResultExplorer(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := NewGenerator(explorerCollector(), tmpDir).GenerateHTML(); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if err != nil {
+		t.Fatalf("failed to read report.html: %v", err)
+	}
+	html := string(data)
+
+	for _, want := range []string{
+		"<h2>Result Explorer</h2>",
+		`<option value="0">Search - CRISPR (search)</option>`,
+		"<strong>Query:</strong> CRISPR off-target",
+		`<li class="explorer-item matched">`,
+		`<a href="https://nature.com/crispr" target="_blank" rel="noopener noreferrer"><mark>CRISPR</mark> &lt;review&gt;</a>`,
+		"Measuring <mark>off-target</mark> edits",
+		"<strong>Other</strong>",
+		"✓ expected URL",
+		"run 1 of 1 · 2 of 2 results · quality 82.5 · 1200ms",
+		`<p class="failure">Failed: HTTP 500</p>`,
+		"<tr><td>url_recall</td><td>100.00</td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected report to contain %q", want)
+		}
+	}
+	if strings.Contains(html, `href="javascript:`) {
+		t.Error("expected non-http URLs not to be linked")
+	}
+	if strings.Contains(html, "Crawl - Docs (crawl)") {
+		t.Error("expected tests without captured output to be left out")
+	}
+}
+
+func TestGenerateJSON_OutputSidecar(t *testing.T) {
+	c := explorerCollector()
+	c.SetOutputSidecar(true)
+	tmpDir := t.TempDir()
+	if err := NewGenerator(c, tmpDir).GenerateJSON(); err != nil {
+		t.Fatalf("GenerateJSON failed: %v", err)
+	}
+
+	report, err := os.ReadFile(filepath.Join(tmpDir, "report.json"))
+	if err != nil {
+		t.Fatalf("failed to read report.json: %v", err)
+	}
+	if strings.Contains(string(report), `"output"`) {
+		t.Error("expected outputs to be left out of report.json")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "outputs.json")); err != nil {
+		t.Fatalf("outputs.json was not created: %v", err)
+	}
+	if c.GetResults()[0].Output == nil {
+		t.Error("expected the collector's results to keep their outputs")
+	}
+
+	loaded, err := LoadJSON(filepath.Join(tmpDir, "report.json"))
+	if err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	if !loaded.OutputSidecar() {
+		t.Error("expected the sidecar setting to be restored")
+	}
+	for _, r := range loaded.GetResults() {
+		if got := r.Output != nil; got != (r.Provider == "exa" && r.TestType == "search") {
+			t.Errorf("%s/%s: unexpected output %+v", r.TestName, r.Provider, r.Output)
+		}
+	}
+}
+
+func TestGenerateAll_RecordsSelection(t *testing.T) {
+	c := setupMockCollector()
+	c.SetSelection(benchmetrics.Selection{Run: "Extract.*", Tags: []string{"academic", "code"}, Selected: 2, Configured: 12})
+	tmpDir := t.TempDir()
+	if err := NewGenerator(c, tmpDir).GenerateAll(); err != nil {
+		t.Fatalf("GenerateAll failed: %v", err)
+	}
+
+	const want = "2 of 12 tests: -run 'Extract.*' -tags academic,code"
+	md, _ := os.ReadFile(filepath.Join(tmpDir, "report.md"))
+	if !strings.Contains(string(md), "**Selection:** "+want) {
+		t.Errorf("expected the selection in report.md")
+	}
+	html, _ := os.ReadFile(filepath.Join(tmpDir, "report.html"))
+	if !strings.Contains(string(html), "Selection: 2 of 12 tests: -run &#39;Extract.*&#39; -tags academic,code") {
+		t.Errorf("expected the escaped selection in report.html")
+	}
+	loaded, err := LoadJSON(filepath.Join(tmpDir, "report.json"))
+	if err != nil {
+		t.Fatalf("LoadJSON failed: %v", err)
+	}
+	if selection, ok := loaded.Selection(); !ok || selection.String() != want {
+		t.Errorf("expected the selection restored from report.json, got %+v", selection)
+	}
+
+	full := setupMockCollector()
+	full.SetSelection(benchmetrics.Selection{Selected: 12, Configured: 12})
+	if _, ok := full.Selection(); ok {
+		t.Error("expected no selection recorded when every test ran")
+	}
+}
+
 func TestGenerateAll_CreatesAll(t *testing.T) {
 	c := setupMockCollector()
 	tmpDir := t.TempDir()
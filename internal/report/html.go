@@ -3,6 +3,7 @@ package report
 
 import (
 	"fmt"
+	"html"
 	"math"
 	"os"
 	"path/filepath"
@@ -13,6 +14,17 @@ import (
 	"github.com/lamim/SanityWebEval/internal/benchmetrics"
 )
 
+// selectionNote returns the header line naming the test selection when the
+// run was limited to a subset of the configured tests.
+func (g *Generator) selectionNote() string {
+	selection, ok := g.collector.Selection()
+	if !ok {
+		return ""
+	}
+	return `
+        <p class="timestamp">Selection: ` + html.EscapeString(selection.String()) + `</p>`
+}
+
 // GenerateHTML creates an HTML report with charts
 func (g *Generator) GenerateHTML() error {
 	providers := g.collector.GetAllProviders()
@@ -77,6 +89,19 @@ func (g *Generator) GenerateHTML() error {
         .heatmap-grid { display: grid; gap: 4px; margin-top: 20px; }
         .heatmap-header { font-weight: 600; font-size: 0.85em; color: #666; padding: 8px; text-align: center; }
         .heatmap-provider { font-weight: 600; font-size: 0.85em; color: #333; padding: 8px; display: flex; align-items: center; }
+        .explorer-select { padding: 6px 10px; margin-bottom: 16px; font-size: 0.95em; }
+        .explorer-target { color: #666; margin-bottom: 12px; font-size: 0.9em; word-break: break-all; }
+        .explorer-grid { display: grid; grid-template-columns: repeat(auto-fit, minmax(280px, 1fr)); gap: 16px; }
+        .explorer-column { background: white; padding: 16px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.1); min-width: 0; }
+        .explorer-meta { color: #666; font-size: 0.85em; margin: 8px 0 12px; }
+        .explorer-items { padding-left: 22px; }
+        .explorer-item { margin-bottom: 14px; }
+        .explorer-item.matched { border-left: 3px solid #27ae60; padding-left: 6px; }
+        .explorer-url { display: block; color: #7f8c8d; font-size: 0.8em; word-break: break-all; }
+        .explorer-content { font-size: 0.85em; white-space: pre-wrap; max-height: 180px; overflow: auto; color: #444; margin-top: 4px; }
+        .explorer-tags { display: block; color: #999; font-size: 0.8em; margin-top: 4px; }
+        .explorer-metrics td { padding: 4px 8px; font-size: 0.85em; }
+        mark { background: #fff3a3; padding: 0 1px; }
         .scatter-tooltip { background: rgba(0,0,0,0.8); color: white; padding: 8px 12px; border-radius: 4px; font-size: 0.85em; }
     </style>
 </head>
@@ -85,7 +110,9 @@ func (g *Generator) GenerateHTML() error {
         <h1>SanityWebEval Report</h1>
         <p class="timestamp">Generated: `)
 	html.WriteString(timestamp)
-	html.WriteString(`</p>
+	html.WriteString(`</p>`)
+	html.WriteString(g.selectionNote())
+	html.WriteString(`
         
         <div class="section">
             <div class="cards">
@@ -144,7 +171,7 @@ func (g *Generator) GenerateHTML() error {
             </div>
         </div>
 
-` + g.generateQualitySection() + g.generateQualityByTestTypeSection() + g.generateSemanticRerankerSection() + g.generateAdvancedAnalyticsSection() + `
+` + g.generateQualitySection() + g.generateQualityByTestTypeSection() + g.generateFreshnessSection() + g.generateStructuredSection() + g.generateDocumentSection() + g.generateLanguageSection() + g.generatePerturbationSection() + g.generateFaultSection() + g.generatePricingSection() + g.generateProjectionSection() + g.generateSemanticRerankerSection() + g.generateAdvancedAnalyticsSection() + g.generateExplorerSection() + `
         <div class="section">
             <h2>Detailed Results</h2>
             <table>
@@ -368,9 +395,11 @@ func (g *Generator) generateTableRows() string {
 			case "search":
 				details = fmt.Sprintf("%d results", r.ResultsCount)
 			case "extract":
-				details = fmt.Sprintf("%d chars", r.ContentLength)
+				details = formatExtractDetails(r)
 			case "crawl":
 				details = fmt.Sprintf("%d pages, %d chars", r.ResultsCount, r.ContentLength)
+			case "structured_extract":
+				details = formatStructuredDetails(r)
 			}
 
 			providerClass := "provider-" + r.Provider
@@ -1186,7 +1215,7 @@ func (g *Generator) generateAdvancedChartScripts(providers []string, providerNam
 		costSpeedDatasets, costMid, speedMid, costAxisType, costBounds.Min, costBounds.Max, costTickPrecision, speedAxisType, speedBounds.Min, speedBounds.Max,
 		joinStrings(providerNames),
 		errorDatasets, !errorHasData,
-		heatmapScript)
+		heatmapScript+g.generateProjectionChartScript(providers, scatterData, baseColors))
 }
 
 func joinStrings(strs []string) string {
@@ -1427,6 +1456,22 @@ type scatterData struct {
 	SearchRelevance float64
 	Speed           float64
 	SuccessRate     float64
+
+	// MonthlyCost is the projected monthly cost for the recorded workload,
+	// set when Projected is true.
+	MonthlyCost        float64
+	Projected          bool
+	ProjectionComplete bool
+	// ParetoOptimal marks providers on the cost vs search relevance frontier,
+	// using the monthly cost when projected and cost per result otherwise.
+	ParetoOptimal bool
+}
+
+func (d scatterData) frontierCost() float64 {
+	if d.Projected {
+		return d.MonthlyCost
+	}
+	return d.CostPerResult
 }
 
 // computeQuadrantMidpoints returns median cost, speed, and relevance across
@@ -1602,17 +1647,29 @@ func (g *Generator) prepareLatencyDistributionData(providers []string) []latency
 func (g *Generator) prepareScatterData(providers []string) []scatterData {
 	data := make([]scatterData, len(providers))
 
+	workload, projected := g.collector.Workload()
+
 	for i, p := range providers {
 		s := g.collector.ComputeSummary(p)
 
 		data[i] = scatterData{
-			CostPerResult:   s.CostPerResult,
-			SearchRelevance: g.computeProviderQualityByTestType(p).Search.AvgQuality,
-			Speed:           float64(s.AvgLatency.Milliseconds()),
-			SuccessRate:     s.SuccessRate,
+			CostPerResult:      s.CostPerResult,
+			SearchRelevance:    g.computeProviderQualityByTestType(p).Search.AvgQuality,
+			Speed:              float64(s.AvgLatency.Milliseconds()),
+			SuccessRate:        s.SuccessRate,
+			ProjectionComplete: true,
+		}
+		if projected {
+			projection := g.collector.ProjectMonthlyCost(p, workload)
+			data[i].MonthlyCost = projection.MonthlyCostUSD
+			data[i].Projected = true
+			data[i].ProjectionComplete = projection.Complete
 		}
 	}
 
+	for i, onFrontier := range paretoFrontier(data) {
+		data[i].ParetoOptimal = onFrontier
+	}
 	return data
 }
 
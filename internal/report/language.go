@@ -0,0 +1,150 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// languageGroup aggregates a provider's language-tagged results for one language.
+type languageGroup struct {
+	provider          string
+	language          string
+	tests             int
+	items             int
+	detected          int
+	matched           int
+	heuristicsSkipped bool
+}
+
+// languageGroups groups successful language-tagged tests by provider and language.
+func (g *Generator) languageGroups(providers []string) []*languageGroup {
+	var out []*languageGroup
+	for _, provider := range providers {
+		byLanguage := make(map[string]*languageGroup)
+		for _, r := range g.collector.GetResultsByProvider(provider) {
+			if r.Skipped || !r.Success || r.Language == nil {
+				continue
+			}
+			group := byLanguage[r.Language.Expected]
+			if group == nil {
+				group = &languageGroup{provider: provider, language: r.Language.Expected}
+				byLanguage[r.Language.Expected] = group
+			}
+			group.add(r.Language)
+		}
+
+		languages := make([]string, 0, len(byLanguage))
+		for language := range byLanguage {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		for _, language := range languages {
+			out = append(out, byLanguage[language])
+		}
+	}
+	return out
+}
+
+func (l *languageGroup) add(stats *benchmetrics.LanguageStats) {
+	l.tests++
+	l.items += stats.Items
+	l.detected += stats.Detected
+	l.matched += stats.Matched
+	if stats.HeuristicsSkipped {
+		l.heuristicsSkipped = true
+	}
+}
+
+func (l *languageGroup) matchRate() string {
+	if l.detected == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(l.matched)/float64(l.detected)*100)
+}
+
+func (l *languageGroup) heuristics() string {
+	if l.heuristicsSkipped {
+		return "skipped"
+	}
+	return "localized"
+}
+
+const languageNote = "Match rate is the share of returned items whose detected language equals the test's language; items too short to classify are excluded. Heuristics shows whether the quality checks had phrase lists for the language or skipped their locale-dependent parts."
+
+// writeLanguageCoverage writes per-provider language match rates for language-tagged tests.
+func (g *Generator) writeLanguageCoverage(sb *strings.Builder, providers []string) {
+	groups := g.languageGroups(providers)
+	if len(groups) == 0 {
+		return
+	}
+
+	sb.WriteString("### Language Coverage\n\n")
+	sb.WriteString(languageNote + "\n\n")
+	sb.WriteString("| Provider | Language | Tests | Detected | Match Rate | Heuristics |\n")
+	sb.WriteString("|----------|----------|-------|----------|------------|------------|\n")
+	for _, l := range groups {
+		fmt.Fprintf(sb, "| %s | %s | %d | %d/%d | %s | %s |\n",
+			l.provider,
+			l.language,
+			l.tests,
+			l.detected, l.items,
+			l.matchRate(),
+			l.heuristics(),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generateLanguageSection returns the language coverage table HTML when language-tagged tests ran.
+func (g *Generator) generateLanguageSection() string {
+	groups := g.languageGroups(g.collector.GetAllProviders())
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, l := range groups {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%s</td>
+                        <td>%d</td>
+                        <td>%d/%d</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>`,
+			l.provider,
+			capitalize(l.provider),
+			l.language,
+			l.tests,
+			l.detected, l.items,
+			l.matchRate(),
+			l.heuristics(),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Language Coverage</h2>
+            <p class="quality-note">` + languageNote + `</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Language</th>
+                        <th>Tests</th>
+                        <th>Detected</th>
+                        <th>Match Rate</th>
+                        <th>Heuristics</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
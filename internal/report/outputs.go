@@ -0,0 +1,88 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// outputsFile is the sidecar holding captured outputs when [capture]
+// sidecar is set, next to report.json.
+const outputsFile = "outputs.json"
+
+// outputEntry ties a captured output to its result.
+type outputEntry struct {
+	TestName string               `json:"test_name"`
+	Provider string               `json:"provider"`
+	Repeat   int                  `json:"repeat"`
+	Output   *benchmetrics.Output `json:"output"`
+}
+
+type outputKey struct {
+	test, provider string
+	repeat         int
+}
+
+// splitOutputs moves the outputs out of results into sidecar entries.
+func splitOutputs(results []benchmetrics.Result) []outputEntry {
+	var entries []outputEntry
+	for i := range results {
+		r := &results[i]
+		if r.Output == nil {
+			continue
+		}
+		entries = append(entries, outputEntry{TestName: r.TestName, Provider: r.Provider, Repeat: r.Repeat, Output: r.Output})
+		r.Output = nil
+	}
+	return entries
+}
+
+// writeOutputs writes the sidecar file.
+func (g *Generator) writeOutputs(entries []outputEntry) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"schema_version": "v1",
+		"outputs":        entries,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	outputPath := filepath.Join(g.outputDir, outputsFile)
+	// #nosec G306 - 0640 allows owner/group to read, which is appropriate for report files
+	return os.WriteFile(outputPath, data, 0640)
+}
+
+// loadOutputs reattaches outputs from the sidecar next to a report.json. It
+// reports whether a sidecar was found.
+func loadOutputs(reportPath string, results []benchmetrics.Result) (bool, error) {
+	path := filepath.Join(filepath.Dir(reportPath), outputsFile)
+	// #nosec G304 - Path is derived from the user-provided report file
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read outputs: %w", err)
+	}
+
+	var payload struct {
+		Outputs []outputEntry `json:"outputs"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return false, fmt.Errorf("failed to parse outputs: %w", err)
+	}
+	byKey := make(map[outputKey]*benchmetrics.Output, len(payload.Outputs))
+	for _, entry := range payload.Outputs {
+		byKey[outputKey{entry.TestName, entry.Provider, entry.Repeat}] = entry.Output
+	}
+	for i := range results {
+		r := &results[i]
+		if output, ok := byKey[outputKey{r.TestName, r.Provider, r.Repeat}]; ok && r.Output == nil {
+			r.Output = output
+		}
+	}
+	return true, nil
+}
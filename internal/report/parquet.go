@@ -0,0 +1,308 @@
+package report
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// The Parquet writer covers what the results export needs: one row group,
+// one uncompressed PLAIN-encoded data page per column, and optional
+// (nullable) flat columns. Metadata uses the Thrift compact protocol.
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types, repetition types, converted types, encodings and
+// page types from parquet.thrift.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+)
+
+// Thrift compact protocol type ids.
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes Thrift structs with the compact protocol.
+type compactWriter struct {
+	buf    bytes.Buffer
+	lastID []int16 // last field id of each open struct
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf.Write(b[:n])
+}
+
+func (w *compactWriter) zigzag(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) beginStruct() {
+	w.lastID = append(w.lastID, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastID = w.lastID[:len(w.lastID)-1]
+}
+
+func (w *compactWriter) field(id int16, typ byte) {
+	last := &w.lastID[len(w.lastID)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, compactI32)
+	w.zigzag(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, compactI64)
+	w.zigzag(v)
+}
+
+func (w *compactWriter) str(id int16, s string) {
+	w.field(id, compactBinary)
+	w.rawString(s)
+}
+
+func (w *compactWriter) rawString(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+// list writes a list field header; the caller writes the n elements.
+func (w *compactWriter) list(id int16, elemType byte, n int) {
+	w.field(id, compactList)
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(n))
+}
+
+// structField opens a struct-valued field; close it with endStruct.
+func (w *compactWriter) structField(id int16) {
+	w.field(id, compactStruct)
+	w.beginStruct()
+}
+
+// parquetType maps a column kind to its physical and converted type; a
+// converted type of -1 means none.
+func parquetType(kind columnKind) (int32, int32) {
+	switch kind {
+	case kindInt:
+		return parquetInt64, -1
+	case kindFloat:
+		return parquetDouble, -1
+	case kindBool:
+		return parquetBoolean, -1
+	case kindTimestamp:
+		return parquetInt64, convertedTimestampMillis
+	default:
+		return parquetByteArray, convertedUTF8
+	}
+}
+
+// columnPage encodes a column as a data page body: definition levels for
+// the nullable column, then the PLAIN-encoded non-null values.
+func columnPage(col column) []byte {
+	var levels bytes.Buffer
+	packed := make([]byte, (len(col.values)+7)/8)
+	var values bytes.Buffer
+	var bools []bool
+	for i, v := range col.values {
+		if v == nil {
+			continue
+		}
+		packed[i/8] |= 1 << (i % 8)
+		switch x := v.(type) {
+		case int64:
+			_ = binary.Write(&values, binary.LittleEndian, x)
+		case float64:
+			_ = binary.Write(&values, binary.LittleEndian, math.Float64bits(x))
+		case bool:
+			bools = append(bools, x)
+		case time.Time:
+			_ = binary.Write(&values, binary.LittleEndian, x.UnixMilli())
+		case string:
+			_ = binary.Write(&values, binary.LittleEndian, uint32(len(x)))
+			values.WriteString(x)
+		}
+	}
+	if len(bools) > 0 {
+		bits := make([]byte, (len(bools)+7)/8)
+		for i, b := range bools {
+			if b {
+				bits[i/8] |= 1 << (i % 8)
+			}
+		}
+		values.Write(bits)
+	}
+
+	// Definition levels (bit width 1) as one bit-packed run of the RLE/bit-
+	// packing hybrid, prefixed with its length.
+	var run bytes.Buffer
+	var header [binary.MaxVarintLen64]byte
+	run.Write(header[:binary.PutUvarint(header[:], uint64(len(packed))<<1|1)])
+	run.Write(packed)
+	_ = binary.Write(&levels, binary.LittleEndian, uint32(run.Len()))
+	levels.Write(run.Bytes())
+
+	levels.Write(values.Bytes())
+	return levels.Bytes()
+}
+
+// columnMeta records where a column chunk was written.
+type columnMeta struct {
+	offset int64
+	size   int64
+}
+
+// encodeParquet writes the columns as a Parquet file with the given
+// key/value metadata.
+func encodeParquet(columns []column, metadata map[string]string) []byte {
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0].values)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(parquetMagic)
+	metas := make([]columnMeta, len(columns))
+	if rows > 0 {
+		for i, col := range columns {
+			page := columnPage(col)
+			var header compactWriter
+			header.beginStruct()
+			header.i32(1, pageTypeData)
+			header.i32(2, int32(len(page))) //nolint:gosec // pages are far below 2 GiB
+			header.i32(3, int32(len(page))) //nolint:gosec // pages are far below 2 GiB
+			header.structField(5)
+			header.i32(1, int32(rows)) //nolint:gosec // row counts are far below 2^31
+			header.i32(2, encodingPlain)
+			header.i32(3, encodingRLE)
+			header.i32(4, encodingRLE)
+			header.endStruct()
+			header.endStruct()
+
+			metas[i] = columnMeta{offset: int64(out.Len()), size: int64(header.buf.Len() + len(page))}
+			out.Write(header.buf.Bytes())
+			out.Write(page)
+		}
+	}
+
+	var footer compactWriter
+	footer.beginStruct()
+	footer.i32(1, 1) // version
+	footer.list(2, compactStruct, len(columns)+1)
+	footer.beginStruct()
+	footer.str(4, "schema")
+	footer.i32(5, int32(len(columns))) //nolint:gosec // column counts are small
+	footer.endStruct()
+	for _, col := range columns {
+		physical, converted := parquetType(col.kind)
+		footer.beginStruct()
+		footer.i32(1, physical)
+		footer.i32(3, repetitionOptional)
+		footer.str(4, col.name)
+		if converted >= 0 {
+			footer.i32(6, converted)
+		}
+		footer.endStruct()
+	}
+	footer.i64(3, int64(rows))
+	if rows == 0 {
+		footer.list(4, compactStruct, 0)
+	} else {
+		footer.list(4, compactStruct, 1)
+		footer.beginStruct()
+		footer.list(1, compactStruct, len(columns))
+		var totalSize int64
+		for i, col := range columns {
+			physical, _ := parquetType(col.kind)
+			footer.beginStruct()
+			footer.i64(2, metas[i].offset)
+			footer.structField(3)
+			footer.i32(1, physical)
+			footer.list(2, compactI32, 2)
+			footer.zigzag(encodingPlain)
+			footer.zigzag(encodingRLE)
+			footer.list(3, compactBinary, 1)
+			footer.rawString(col.name)
+			footer.i32(4, 0) // uncompressed
+			footer.i64(5, int64(rows))
+			footer.i64(6, metas[i].size)
+			footer.i64(7, metas[i].size)
+			footer.i64(9, metas[i].offset)
+			footer.endStruct()
+			footer.endStruct()
+			totalSize += metas[i].size
+		}
+		footer.i64(2, totalSize)
+		footer.i64(3, int64(rows))
+		footer.endStruct()
+	}
+	footer.list(5, compactStruct, len(metadata))
+	for _, key := range sortedKeys(keySet(metadata)) {
+		footer.beginStruct()
+		footer.str(1, key)
+		footer.str(2, metadata[key])
+		footer.endStruct()
+	}
+	footer.str(6, "SanityWebEval")
+	footer.endStruct()
+
+	out.Write(footer.buf.Bytes())
+	_ = binary.Write(&out, binary.LittleEndian, uint32(footer.buf.Len())) //nolint:gosec // footers are small
+	out.WriteString(parquetMagic)
+	return out.Bytes()
+}
+
+func keySet(m map[string]string) map[string]struct{} {
+	set := make(map[string]struct{}, len(m))
+	for key := range m {
+		set[key] = struct{}{}
+	}
+	return set
+}
+
+// GenerateParquet writes results.parquet: one flat row per result, with the
+// export schema version in the file metadata.
+func (g *Generator) GenerateParquet() error {
+	columns := resultTable(g.collector.GetResults())
+	data := encodeParquet(columns, map[string]string{exportSchemaKey: ExportSchemaVersion})
+
+	outputPath := filepath.Join(g.outputDir, "results.parquet")
+	// #nosec G306 - 0640 allows owner/group to read, which is appropriate for report files
+	return os.WriteFile(outputPath, data, 0640)
+}
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/robustness"
+)
+
+// perturbationGroup aggregates a provider's query variants of one kind.
+type perturbationGroup struct {
+	provider     string
+	kind         string
+	variants     int
+	succeeded    int
+	overlapTotal float64
+	scored       int
+	deltaTotal   float64
+}
+
+// perturbationGroups groups query variants by provider and perturbation kind.
+func (g *Generator) perturbationGroups(providers []string) []*perturbationGroup {
+	var out []*perturbationGroup
+	for _, provider := range providers {
+		byKind := make(map[string]*perturbationGroup)
+		for _, r := range g.collector.GetResultsByProvider(provider) {
+			for _, p := range r.Perturbations {
+				group := byKind[p.Kind]
+				if group == nil {
+					group = &perturbationGroup{provider: provider, kind: p.Kind}
+					byKind[p.Kind] = group
+				}
+				group.add(p)
+			}
+		}
+		for _, kind := range robustness.AllPerturbationKinds {
+			if group := byKind[string(kind)]; group != nil {
+				out = append(out, group)
+			}
+		}
+	}
+	return out
+}
+
+func (p *perturbationGroup) add(result benchmetrics.PerturbationResult) {
+	p.variants++
+	if !result.Success {
+		return
+	}
+	p.succeeded++
+	p.overlapTotal += result.URLOverlap
+	if result.QualityScored {
+		p.scored++
+		p.deltaTotal += result.QualityDelta
+	}
+}
+
+func (p *perturbationGroup) urlOverlap() string {
+	if p.succeeded == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", p.overlapTotal/float64(p.succeeded))
+}
+
+func (p *perturbationGroup) qualityDelta() string {
+	if p.scored == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f", p.deltaTotal/float64(p.scored))
+}
+
+const perturbationNote = "Each search query is re-run with typo, paraphrase, case and word-order variants. URL overlap is the share of the original result URLs the variant also returned; quality delta is the variant's quality score minus the original's. Variant cost is not included in the totals above."
+
+// writeQueryRobustness writes per-provider consistency under query perturbations.
+func (g *Generator) writeQueryRobustness(sb *strings.Builder, providers []string) {
+	groups := g.perturbationGroups(providers)
+	if len(groups) == 0 {
+		return
+	}
+
+	sb.WriteString("### Query Robustness\n\n")
+	sb.WriteString(perturbationNote + "\n\n")
+	sb.WriteString("| Provider | Perturbation | Variants | Succeeded | URL Overlap | Quality Delta |\n")
+	sb.WriteString("|----------|--------------|----------|-----------|-------------|---------------|\n")
+	for _, p := range groups {
+		fmt.Fprintf(sb, "| %s | %s | %d | %d/%d | %s | %s |\n",
+			p.provider,
+			p.kind,
+			p.variants,
+			p.succeeded, p.variants,
+			p.urlOverlap(),
+			p.qualityDelta(),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generatePerturbationSection returns the query robustness table HTML when query variants ran.
+func (g *Generator) generatePerturbationSection() string {
+	groups := g.perturbationGroups(g.collector.GetAllProviders())
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, p := range groups {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%s</td>
+                        <td>%d</td>
+                        <td>%d/%d</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>`,
+			p.provider,
+			capitalize(p.provider),
+			p.kind,
+			p.variants,
+			p.succeeded, p.variants,
+			p.urlOverlap(),
+			p.qualityDelta(),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Query Robustness</h2>
+            <p class="quality-note">` + perturbationNote + `</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Perturbation</th>
+                        <th>Variants</th>
+                        <th>Succeeded</th>
+                        <th>URL Overlap</th>
+                        <th>Quality Delta</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
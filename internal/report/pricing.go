@@ -0,0 +1,171 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// pricingRow is one provider's price list as applied to the run.
+type pricingRow struct {
+	provider string
+	pricing  benchmetrics.ProviderPricing
+}
+
+// pricingRows lists the pricing applied to each provider in the run, using
+// the provider's type for named instances without their own entry.
+func (g *Generator) pricingRows(providers []string) (benchmetrics.PricingProfile, []pricingRow, bool) {
+	profile, ok := g.collector.PricingProfile()
+	if !ok {
+		return benchmetrics.PricingProfile{}, nil, false
+	}
+	rows := make([]pricingRow, 0, len(providers))
+	for _, provider := range providers {
+		providerType := ""
+		if results := g.collector.GetResultsByProvider(provider); len(results) > 0 {
+			providerType = results[0].ProviderType
+		}
+		pricing, found := profile.For(provider, providerType)
+		if !found {
+			continue
+		}
+		rows = append(rows, pricingRow{provider: provider, pricing: pricing})
+	}
+	return profile, rows, true
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatMonthlyMinimum(v float64) string {
+	if v <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+const pricingNote = "Costs are billed units (credits, requests, tokens or queries) priced with this profile. Graduated tiers apply to cumulative usage across the run in completion order. Monthly minimums are listed for reference and are not added to per-test costs."
+
+// writePricing writes the pricing profile and per-provider price lists.
+func (g *Generator) writePricing(sb *strings.Builder, providers []string) {
+	profile, rows, ok := g.pricingRows(providers)
+	if !ok || len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(sb, "### Pricing (%s)\n\n", profile.Name)
+	sb.WriteString(pricingNote + "\n\n")
+	sb.WriteString("| Provider | Plan | Unit | Unit Price | Monthly Minimum |\n")
+	sb.WriteString("|----------|------|------|------------|-----------------|\n")
+	for _, row := range rows {
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s |\n",
+			row.provider,
+			orDash(row.pricing.Plan),
+			orDash(row.pricing.Unit),
+			row.pricing.PriceSummary(),
+			formatMonthlyMinimum(row.pricing.MonthlyMinimum),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generatePricingSection returns the pricing table HTML.
+func (g *Generator) generatePricingSection() string {
+	profile, rows, ok := g.pricingRows(g.collector.GetAllProviders())
+	if !ok || len(rows) == 0 {
+		return ""
+	}
+
+	var body strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&body, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>`,
+			row.provider,
+			capitalize(row.provider),
+			html.EscapeString(orDash(row.pricing.Plan)),
+			html.EscapeString(orDash(row.pricing.Unit)),
+			html.EscapeString(row.pricing.PriceSummary()),
+			formatMonthlyMinimum(row.pricing.MonthlyMinimum),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Pricing (` + html.EscapeString(profile.Name) + `)</h2>
+            <p class="quality-note">` + pricingNote + `</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Plan</th>
+                        <th>Unit</th>
+                        <th>Unit Price</th>
+                        <th>Monthly Minimum</th>
+                    </tr>
+                </thead>
+                <tbody>` + body.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
+
+// LoadJSON reads the results of an earlier run from its report.json into a
+// collector, together with the pricing profile they were priced with, the
+// projected workload and the captured outputs from an outputs.json sidecar.
+func LoadJSON(path string) (*benchmetrics.Collector, error) {
+	// #nosec G304 - Path is an explicit user-provided report file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var payload struct {
+		Results   []benchmetrics.Result        `json:"results"`
+		Pricing   *benchmetrics.PricingProfile `json:"pricing"`
+		Workload  *benchmetrics.Workload       `json:"workload"`
+		Selection *benchmetrics.Selection      `json:"selection"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	if len(payload.Results) == 0 {
+		return nil, fmt.Errorf("report %s contains no results", path)
+	}
+
+	sidecar, err := loadOutputs(path, payload.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := benchmetrics.NewCollector()
+	collector.SetOutputSidecar(sidecar)
+	for _, r := range payload.Results {
+		collector.AddResult(r)
+	}
+	if payload.Pricing != nil {
+		collector.SetPricingProfile(*payload.Pricing)
+	}
+	if payload.Workload != nil {
+		collector.SetWorkload(*payload.Workload)
+	}
+	if payload.Selection != nil {
+		collector.SetSelection(*payload.Selection)
+	}
+	return collector, nil
+}
@@ -0,0 +1,272 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// projectionRows projects each provider's monthly cost for the recorded
+// workload.
+func (g *Generator) projectionRows(providers []string) (benchmetrics.Workload, []benchmetrics.CostProjection, bool) {
+	workload, ok := g.collector.Workload()
+	if !ok {
+		return benchmetrics.Workload{}, nil, false
+	}
+	rows := make([]benchmetrics.CostProjection, 0, len(providers))
+	for _, provider := range providers {
+		rows = append(rows, g.collector.ProjectMonthlyCost(provider, workload))
+	}
+	return workload, rows, true
+}
+
+// paretoFrontier marks the providers on the cost vs search relevance
+// frontier. Providers with an incomplete projection are left off and cannot
+// dominate others, since part of their cost is unknown.
+func paretoFrontier(data []scatterData) []bool {
+	var costs, relevances []float64
+	var index []int
+	for i, d := range data {
+		if !d.ProjectionComplete {
+			continue
+		}
+		costs = append(costs, d.frontierCost())
+		relevances = append(relevances, d.SearchRelevance)
+		index = append(index, i)
+	}
+	frontier := make([]bool, len(data))
+	for i, on := range benchmetrics.ParetoFrontier(costs, relevances) {
+		frontier[index[i]] = on
+	}
+	return frontier
+}
+
+func formatOperationCost(op benchmetrics.OperationProjection) string {
+	if !op.Measured() {
+		return "n/a"
+	}
+	cost := formatCostUSD(op.CostUSD)
+	if op.RetryOverhead > 1.005 {
+		cost += fmt.Sprintf(" (×%.2f)", op.RetryOverhead)
+	}
+	return cost
+}
+
+// operationCells returns the search, extract and crawl cells of a projection,
+// "-" for operations outside the workload.
+func operationCells(p benchmetrics.CostProjection) [3]string {
+	cells := [3]string{"-", "-", "-"}
+	for _, op := range p.Operations {
+		switch op.TestType {
+		case "search":
+			cells[0] = formatOperationCost(op)
+		case "extract":
+			cells[1] = formatOperationCost(op)
+		case "crawl":
+			cells[2] = formatOperationCost(op)
+		}
+	}
+	return cells
+}
+
+func formatProjectedCost(p benchmetrics.CostProjection) string {
+	cost := formatCostUSD(p.MonthlyCostUSD)
+	if !p.Complete {
+		cost += "*"
+	}
+	return cost
+}
+
+const projectionNote = "Units per operation are measured from successful tests in this run (per page for crawl) and multiplied by attempts per success (shown as ×N when above 1), assuming failed attempts bill like successful ones. Tiers apply to the projected monthly volume, and the monthly minimum applies when usage costs less. * marks projections missing an operation with no successful tests (n/a)."
+
+func (g *Generator) projectionPricingName() string {
+	if profile, ok := g.collector.PricingProfile(); ok {
+		return profile.Name
+	}
+	return benchmetrics.DefaultPricingProfileName
+}
+
+// writeProjection writes the monthly cost projection for the workload.
+func (g *Generator) writeProjection(sb *strings.Builder, providers []string) {
+	workload, rows, ok := g.projectionRows(providers)
+	if !ok || len(rows) == 0 {
+		return
+	}
+	showFrontier := g.hasQualityScores()
+	scatter := g.prepareScatterData(providers)
+
+	sb.WriteString("### Monthly Cost Projection\n\n")
+	fmt.Fprintf(sb, "Workload: %s per month, priced with %s. %s\n\n", workload, g.projectionPricingName(), projectionNote)
+	header := "| Provider | Search | Extract | Crawl | Usage Cost | Monthly Minimum | Projected Monthly |"
+	divider := "|----------|--------|---------|-------|------------|-----------------|-------------------|"
+	if showFrontier {
+		header += " Search Relevance | Pareto |"
+		divider += "------------------|--------|"
+	}
+	sb.WriteString(header + "\n" + divider + "\n")
+	for i, row := range rows {
+		cells := operationCells(row)
+		fmt.Fprintf(sb, "| %s | %s | %s | %s | %s | %s | %s |",
+			row.Provider,
+			cells[0], cells[1], cells[2],
+			formatCostUSD(row.UsageCostUSD),
+			formatMonthlyMinimum(row.MonthlyMinimum),
+			formatProjectedCost(row),
+		)
+		if showFrontier {
+			frontier := ""
+			if scatter[i].ParetoOptimal {
+				frontier = "✓"
+			}
+			fmt.Fprintf(sb, " %.1f | %s |", scatter[i].SearchRelevance, frontier)
+		}
+		sb.WriteString("\n")
+	}
+	if showFrontier {
+		sb.WriteString("\nPareto marks providers no other provider beats on both projected monthly cost and search relevance.\n")
+	}
+	sb.WriteString("\n")
+}
+
+// generateProjectionSection returns the monthly cost projection HTML, with a
+// canvas for the cost-quality frontier chart when it can be drawn.
+func (g *Generator) generateProjectionSection() string {
+	providers := g.collector.GetAllProviders()
+	workload, rows, ok := g.projectionRows(providers)
+	if !ok || len(rows) == 0 {
+		return ""
+	}
+
+	var body strings.Builder
+	for _, row := range rows {
+		cells := operationCells(row)
+		fmt.Fprintf(&body, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                        <td><strong>%s</strong></td>
+                    </tr>`,
+			row.Provider,
+			capitalize(row.Provider),
+			cells[0], cells[1], cells[2],
+			formatCostUSD(row.UsageCostUSD),
+			formatMonthlyMinimum(row.MonthlyMinimum),
+			formatProjectedCost(row),
+		)
+	}
+
+	chart := ""
+	if len(providers) >= 2 && g.hasQualityScores() {
+		chart = `
+            <div class="chart-container">
+                <div class="chart-wrapper">
+                    <canvas id="projectionFrontierChart"></canvas>
+                </div>
+            </div>`
+	}
+
+	return `
+        <div class="section">
+            <h2>Monthly Cost Projection</h2>
+            <p class="quality-note">Workload: ` + workload.String() + ` per month, priced with ` + g.projectionPricingName() + `. ` + projectionNote + `</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Search</th>
+                        <th>Extract</th>
+                        <th>Crawl</th>
+                        <th>Usage Cost</th>
+                        <th>Monthly Minimum</th>
+                        <th>Projected Monthly</th>
+                    </tr>
+                </thead>
+                <tbody>` + body.String() + `
+                </tbody>
+            </table>` + chart + `
+        </div>
+
+`
+}
+
+// generateProjectionChartScript plots projected monthly cost against search
+// relevance with a line through the Pareto frontier.
+func (g *Generator) generateProjectionChartScript(providers []string, data []scatterData, baseColors []string) string {
+	if _, ok := g.collector.Workload(); !ok || !g.hasQualityScores() {
+		return ""
+	}
+
+	var datasets strings.Builder
+	type point struct{ cost, relevance float64 }
+	var frontier []point
+	for i, p := range providers {
+		color := baseColors[i%len(baseColors)]
+		fmt.Fprintf(&datasets, `{
+                    label: '%s',
+                    data: [{x: %f, y: %f}],
+                    backgroundColor: %s,
+                    borderColor: %s,
+                    borderWidth: 2,
+                    pointRadius: 8,
+                    pointHoverRadius: 11
+                },`, capitalize(p), data[i].MonthlyCost, data[i].SearchRelevance, color, color)
+		if data[i].ParetoOptimal {
+			frontier = append(frontier, point{data[i].MonthlyCost, data[i].SearchRelevance})
+		}
+	}
+	sort.Slice(frontier, func(a, b int) bool { return frontier[a].cost < frontier[b].cost })
+	points := make([]string, 0, len(frontier))
+	for _, p := range frontier {
+		points = append(points, fmt.Sprintf("{x: %f, y: %f}", p.cost, p.relevance))
+	}
+	fmt.Fprintf(&datasets, `{
+                    label: 'Pareto frontier',
+                    data: [%s],
+                    showLine: true,
+                    borderColor: 'rgba(44, 62, 80, 0.6)',
+                    borderDash: [6, 4],
+                    pointRadius: 0,
+                    fill: false
+                }`, strings.Join(points, ", "))
+
+	return fmt.Sprintf(`
+        // Projected Monthly Cost vs Search Relevance
+        new Chart(document.getElementById('projectionFrontierChart'), {
+            type: 'scatter',
+            data: { datasets: [%s] },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                plugins: {
+                    title: { display: true, text: 'Projected Monthly Cost vs Search Relevance', font: { size: 14 } },
+                    legend: { display: true, position: 'bottom' },
+                    tooltip: {
+                        callbacks: {
+                            label: function(context) {
+                                return context.dataset.label + ': $' + context.raw.x.toLocaleString(undefined, {maximumFractionDigits: 2}) + '/month, relevance ' + context.raw.y.toFixed(1);
+                            }
+                        }
+                    }
+                },
+                scales: {
+                    x: {
+                        title: { display: true, text: 'Projected Monthly Cost (USD)' },
+                        beginAtZero: true,
+                        ticks: { callback: function(v) { return '$' + Number(v).toLocaleString(); } }
+                    },
+                    y: {
+                        title: { display: true, text: 'Search Relevance (0-100)' },
+                        min: 0,
+                        max: 100
+                    }
+                }
+            }
+        });
+`, datasets.String())
+}
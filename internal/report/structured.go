@@ -0,0 +1,129 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+)
+
+// structuredSummaries returns summaries for providers that ran structured_extract tests.
+func (g *Generator) structuredSummaries(providers []string) []providerSummary {
+	out := make([]providerSummary, 0, len(providers))
+	for _, provider := range providers {
+		summary := g.collector.ComputeSummary(provider)
+		if summary.StructuredTests > 0 {
+			out = append(out, providerSummary{name: provider, summary: summary})
+		}
+	}
+	return out
+}
+
+// structuredModes describes whether a provider's structured extractions ran natively or emulated.
+func (g *Generator) structuredModes(provider string) string {
+	seen := make(map[string]bool)
+	var modes []string
+	for _, r := range g.collector.GetResultsByProvider(provider) {
+		if r.TestType != "structured_extract" || r.Skipped || r.ImplementationType == "" || seen[r.ImplementationType] {
+			continue
+		}
+		seen[r.ImplementationType] = true
+		modes = append(modes, r.ImplementationType)
+	}
+	if len(modes) == 0 {
+		return "-"
+	}
+	return strings.Join(modes, ", ")
+}
+
+func formatStructuredDetails(r benchmetrics.Result) string {
+	if r.Structured == nil {
+		return fmt.Sprintf("%d fields", r.ResultsCount)
+	}
+	validity := "schema valid"
+	if !r.Structured.SchemaValid {
+		validity = fmt.Sprintf("%d schema errors", len(r.Structured.SchemaErrors))
+	}
+	if r.Structured.ExpectedFields > 0 {
+		return fmt.Sprintf("%d/%d fields, %s", r.Structured.MatchedFields, r.Structured.ExpectedFields, validity)
+	}
+	return fmt.Sprintf("%d fields, %s", r.ResultsCount, validity)
+}
+
+func formatStructuredAccuracy(summary *benchmetrics.Summary) string {
+	if summary.StructuredFieldAccuracy == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", summary.StructuredFieldAccuracy)
+}
+
+// writeStructuredExtraction writes schema validity and field accuracy per provider.
+func (g *Generator) writeStructuredExtraction(sb *strings.Builder, providers []string) {
+	summaries := g.structuredSummaries(providers)
+	if len(summaries) == 0 {
+		return
+	}
+
+	sb.WriteString("### Structured Extraction\n\n")
+	sb.WriteString("Schema validity of the returned JSON and field-level accuracy against `expected` values. Emulated providers use plain extract plus a local field parser.\n\n")
+	sb.WriteString("| Provider | Tests | Schema Valid | Field Accuracy | Mode |\n")
+	sb.WriteString("|----------|-------|--------------|----------------|------|\n")
+	for _, ps := range summaries {
+		fmt.Fprintf(sb, "| %s | %d | %.1f%% | %s | %s |\n",
+			ps.name,
+			ps.summary.StructuredTests,
+			ps.summary.StructuredSchemaValidRate,
+			formatStructuredAccuracy(ps.summary),
+			g.structuredModes(ps.name),
+		)
+	}
+	sb.WriteString("\n")
+}
+
+// generateStructuredSection returns the structured extraction table HTML when structured_extract tests ran.
+func (g *Generator) generateStructuredSection() string {
+	summaries := g.structuredSummaries(g.collector.GetAllProviders())
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	var rows strings.Builder
+	for _, ps := range summaries {
+		fmt.Fprintf(&rows, `
+                    <tr>
+                        <td><span class="provider-badge provider-%s">%s</span></td>
+                        <td>%d</td>
+                        <td>%.1f%%</td>
+                        <td>%s</td>
+                        <td>%s</td>
+                    </tr>`,
+			ps.name,
+			capitalize(ps.name),
+			ps.summary.StructuredTests,
+			ps.summary.StructuredSchemaValidRate,
+			formatStructuredAccuracy(ps.summary),
+			g.structuredModes(ps.name),
+		)
+	}
+
+	return `
+        <div class="section">
+            <h2>Structured Extraction</h2>
+            <p class="quality-note">Schema validity of the returned JSON and field-level accuracy against expected values. Emulated providers use plain extract plus a local field parser.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Provider</th>
+                        <th>Tests</th>
+                        <th>Schema Valid</th>
+                        <th>Field Accuracy</th>
+                        <th>Mode</th>
+                    </tr>
+                </thead>
+                <tbody>` + rows.String() + `
+                </tbody>
+            </table>
+        </div>
+
+`
+}
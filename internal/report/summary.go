@@ -195,6 +195,8 @@ func scoreLabelForTestType(testType string) string {
 		return "Extraction Heuristic"
 	case "crawl":
 		return "Crawl Heuristic"
+	case "structured_extract":
+		return "Structured Field Accuracy"
 	default:
 		return "-"
 	}
@@ -469,6 +471,12 @@ func (g *Generator) GenerateMarkdown() error {
 	var sb strings.Builder
 	sb.WriteString("# SanityWebEval Report\n\n")
 	fmt.Fprintf(&sb, "**Generated:** %s\n\n", timestamp)
+	if profile, ok := g.collector.PricingProfile(); ok {
+		fmt.Fprintf(&sb, "**Pricing profile:** %s\n\n", profile.Name)
+	}
+	if selection, ok := g.collector.Selection(); ok {
+		fmt.Fprintf(&sb, "**Selection:** %s\n\n", selection)
+	}
 
 	// Overview table
 	sb.WriteString("## Summary\n\n")
@@ -537,9 +545,11 @@ func (g *Generator) GenerateMarkdown() error {
 			case "search":
 				details = fmt.Sprintf("%d results", r.ResultsCount)
 			case "extract":
-				details = fmt.Sprintf("%d chars", r.ContentLength)
+				details = formatExtractDetails(r)
 			case "crawl":
 				details = fmt.Sprintf("%d pages, %d chars", r.ResultsCount, r.ContentLength)
+			case "structured_extract":
+				details = formatStructuredDetails(r)
 			}
 
 			if hasQualityScores {
@@ -588,6 +598,14 @@ func (g *Generator) GenerateMarkdown() error {
 	// Rankings (for 2+ providers)
 	g.writeRankings(&sb, providers)
 	g.writeQualityByTestType(&sb, providers)
+	g.writeFreshnessCompliance(&sb, providers)
+	g.writeStructuredExtraction(&sb, providers)
+	g.writeDocumentExtraction(&sb, providers)
+	g.writeLanguageCoverage(&sb, providers)
+	g.writeQueryRobustness(&sb, providers)
+	g.writeFaultInjection(&sb, providers)
+	g.writePricing(&sb, providers)
+	g.writeProjection(&sb, providers)
 
 	// Pairwise comparison for exactly 2 providers (original detailed comparison)
 	g.writePairwiseComparison(&sb, providers)
@@ -598,14 +616,32 @@ func (g *Generator) GenerateMarkdown() error {
 	return os.WriteFile(outputPath, []byte(sb.String()), 0640)
 }
 
-// GenerateJSON creates a JSON report with raw data
+// GenerateJSON creates a JSON report with raw data. With the output sidecar
+// enabled, captured outputs go to outputs.json instead.
 func (g *Generator) GenerateJSON() error {
+	results := g.collector.GetResults()
+	if g.collector.OutputSidecar() {
+		if err := g.writeOutputs(splitOutputs(results)); err != nil {
+			return fmt.Errorf("failed to write outputs: %w", err)
+		}
+	}
+
 	data := map[string]interface{}{
 		"schema_version": "v2",
 		"timestamp":      time.Now(),
 		"providers":      g.collector.GetAllProviders(),
 		"tests":          g.collector.GetAllTests(),
-		"results":        g.collector.GetResults(),
+		"results":        results,
+	}
+	if profile, ok := g.collector.PricingProfile(); ok {
+		data["pricing"] = profile
+	}
+	if selection, ok := g.collector.Selection(); ok {
+		data["selection"] = selection
+	}
+	if workload, projections, ok := g.projectionRows(g.collector.GetAllProviders()); ok {
+		data["workload"] = workload
+		data["projections"] = projections
 	}
 
 	// Add summaries
@@ -0,0 +1,239 @@
+package robustness
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PerturbationKind names a way of rewriting a query while keeping its intent.
+type PerturbationKind string
+
+const (
+	// PerturbTypo introduces character-level typing mistakes.
+	PerturbTypo PerturbationKind = "typo"
+	// PerturbParaphrase rewrites the query with a phrasing template.
+	PerturbParaphrase PerturbationKind = "paraphrase"
+	// PerturbCase changes the letter case of the query.
+	PerturbCase PerturbationKind = "case"
+	// PerturbReorder changes the word order of the query.
+	PerturbReorder PerturbationKind = "reorder"
+)
+
+// AllPerturbationKinds lists every perturbation in generation order.
+var AllPerturbationKinds = []PerturbationKind{PerturbTypo, PerturbParaphrase, PerturbCase, PerturbReorder}
+
+// ParsePerturbationKinds parses a comma-separated list of perturbation kinds.
+// "all" selects every kind; an empty value selects none.
+func ParsePerturbationKinds(value string) ([]PerturbationKind, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+	if value == "all" {
+		return append([]PerturbationKind(nil), AllPerturbationKinds...), nil
+	}
+
+	var kinds []PerturbationKind
+	seen := make(map[PerturbationKind]bool)
+	for _, part := range strings.Split(value, ",") {
+		kind := PerturbationKind(strings.TrimSpace(part))
+		if kind == "" || seen[kind] {
+			continue
+		}
+		if !isPerturbationKind(kind) {
+			return nil, fmt.Errorf("unknown perturbation %q (valid: typo, paraphrase, case, reorder, all)", kind)
+		}
+		seen[kind] = true
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+func isPerturbationKind(kind PerturbationKind) bool {
+	for _, k := range AllPerturbationKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateQueryPerturbations derives variants of a search query that keep its
+// intent. Variants are deterministic for a given query so repeated runs send
+// the same queries. Kinds that cannot change the query (for example
+// reordering a one-word query) are omitted. With no kinds, all are generated.
+func (g *EdgeCaseGenerator) GenerateQueryPerturbations(query string, kinds ...PerturbationKind) []EdgeCase {
+	if len(kinds) == 0 {
+		kinds = AllPerturbationKinds
+	}
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return nil
+	}
+
+	seed := querySeed(query)
+	cases := make([]EdgeCase, 0, len(kinds))
+	for _, kind := range kinds {
+		var variant, description string
+		switch kind {
+		case PerturbTypo:
+			variant, description = typoVariant(query, seed), "Query with character-level typos"
+		case PerturbParaphrase:
+			variant, description = paraphraseVariant(query), "Query rephrased with a template"
+		case PerturbCase:
+			variant, description = caseVariant(query), "Query with changed letter case"
+		case PerturbReorder:
+			variant, description = reorderVariant(query, seed), "Query with reordered words"
+		default:
+			continue
+		}
+		if variant == "" || variant == query {
+			continue
+		}
+		cases = append(cases, EdgeCase{
+			Name:        string(kind),
+			Description: description,
+			Query:       variant,
+		})
+	}
+	return cases
+}
+
+func querySeed(query string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(query))
+	return h.Sum32()
+}
+
+// typoVariant applies one typo per four words (at least one) to words of four
+// or more letters, cycling through swapped, dropped and doubled characters.
+func typoVariant(query string, seed uint32) string {
+	words := strings.Split(query, " ")
+	var candidates []int
+	for i, w := range words {
+		if utf8.RuneCountInString(w) >= 4 && isAlphabetic(w) {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	count := len(words) / 4
+	if count < 1 {
+		count = 1
+	}
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	start := int(seed % uint32(len(candidates)))
+	for n := 0; n < count; n++ {
+		idx := candidates[(start+n*len(candidates)/count)%len(candidates)]
+		runes := []rune(words[idx])
+		// Keep the first letter intact; typos rarely hit it.
+		pos := 1 + int((seed>>8+uint32(n))%uint32(len(runes)-2))
+		words[idx] = applyTypo(runes, pos, (seed+uint32(n))%3)
+	}
+	return strings.Join(words, " ")
+}
+
+// applyTypo swaps, drops or doubles the rune at pos. A swap of two equal
+// runes would be invisible, so it drops one of them instead.
+func applyTypo(runes []rune, pos int, op uint32) string {
+	if op == 0 && runes[pos] != runes[pos+1] {
+		runes[pos], runes[pos+1] = runes[pos+1], runes[pos]
+		return string(runes)
+	}
+	out := make([]rune, 0, len(runes)+1)
+	if op == 2 {
+		out = append(out, runes[:pos+1]...)
+		return string(append(out, runes[pos:]...))
+	}
+	out = append(out, runes[:pos]...)
+	return string(append(out, runes[pos+1:]...))
+}
+
+func isAlphabetic(word string) bool {
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// paraphraseRule rewrites queries matching pattern using template, where $1
+// is the captured remainder of the query.
+type paraphraseRule struct {
+	pattern  *regexp.Regexp
+	template string
+}
+
+var paraphraseRules = []paraphraseRule{
+	{regexp.MustCompile(`(?i)^how (?:do|can) (?:i|you|we) (.+?)\??$`), "how to $1"},
+	{regexp.MustCompile(`(?i)^how to (.+?)\??$`), "steps to $1"},
+	{regexp.MustCompile(`(?i)^what (?:is|are) (?:the )?(.+?)\??$`), "$1 explained"},
+	{regexp.MustCompile(`(?i)^why (?:is|are|do|does) (.+?)\??$`), "reasons $1"},
+	{regexp.MustCompile(`(?i)^(?:best|top) (.+)$`), "recommended $1"},
+	{regexp.MustCompile(`(?i)^(.+?) vs\.? (.+)$`), "$1 compared to $2"},
+}
+
+// paraphraseSynonyms replace a single keyword when no question template applies.
+var paraphraseSynonyms = map[string]string{
+	"comparison":   "versus",
+	"pricing":      "cost",
+	"price":        "cost",
+	"release":      "launch",
+	"features":     "capabilities",
+	"applications": "uses",
+	"strategies":   "approaches",
+	"mitigation":   "reduction",
+	"tutorial":     "guide",
+	"guide":        "tutorial",
+	"population":   "inhabitants",
+	"effects":      "impacts",
+	"diagnosis":    "detection",
+}
+
+// paraphraseVariant rewrites question forms with templates, then falls back
+// to a keyword synonym and finally to turning keywords into a question.
+func paraphraseVariant(query string) string {
+	for _, rule := range paraphraseRules {
+		if rule.pattern.MatchString(query) {
+			return strings.TrimSpace(rule.pattern.ReplaceAllString(query, rule.template))
+		}
+	}
+
+	words := strings.Split(query, " ")
+	for i, w := range words {
+		if synonym, ok := paraphraseSynonyms[strings.ToLower(w)]; ok {
+			words[i] = synonym
+			return strings.Join(words, " ")
+		}
+	}
+
+	return "what should I know about " + strings.TrimRight(query, "?.!")
+}
+
+// caseVariant lowercases queries that contain capitals and uppercases the rest.
+func caseVariant(query string) string {
+	if lower := strings.ToLower(query); lower != query {
+		return lower
+	}
+	return strings.ToUpper(query)
+}
+
+// reorderVariant rotates the words by a seed-derived offset.
+func reorderVariant(query string, seed uint32) string {
+	words := strings.Split(query, " ")
+	if len(words) < 2 {
+		return ""
+	}
+	offset := 1 + int(seed%uint32(len(words)-1))
+	rotated := append(append([]string{}, words[offset:]...), words[:offset]...)
+	return strings.Join(rotated, " ")
+}
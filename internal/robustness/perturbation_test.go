@@ -0,0 +1,102 @@
+package robustness
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestGenerateQueryPerturbations(t *testing.T) {
+	g := NewEdgeCaseGenerator()
+	query := "Rust programming memory safety ownership borrowing"
+
+	cases := g.GenerateQueryPerturbations(query)
+	if len(cases) != len(AllPerturbationKinds) {
+		t.Fatalf("expected %d variants, got %+v", len(AllPerturbationKinds), cases)
+	}
+	byKind := make(map[string]string)
+	for _, c := range cases {
+		if c.Query == query || c.Query == "" {
+			t.Errorf("%s variant did not change the query: %q", c.Name, c.Query)
+		}
+		byKind[c.Name] = c.Query
+	}
+
+	if got := byKind["case"]; got != strings.ToLower(query) {
+		t.Errorf("case variant = %q", got)
+	}
+	reordered := strings.Fields(byKind["reorder"])
+	original := strings.Fields(query)
+	if len(reordered) != len(original) || sortedJoin(reordered) != sortedJoin(original) {
+		t.Errorf("reorder variant should keep the same words: %q", byKind["reorder"])
+	}
+	typoWords := strings.Fields(byKind["typo"])
+	changed := 0
+	for i := range original {
+		if i < len(typoWords) && typoWords[i] != original[i] {
+			changed++
+		}
+	}
+	if len(typoWords) != len(original) || changed == 0 {
+		t.Errorf("typo variant should change at least one word in place: %q", byKind["typo"])
+	}
+
+	again := g.GenerateQueryPerturbations(query)
+	for i := range cases {
+		if cases[i] != again[i] {
+			t.Errorf("variants are not deterministic: %+v vs %+v", cases[i], again[i])
+		}
+	}
+}
+
+func TestGenerateQueryPerturbations_Paraphrase(t *testing.T) {
+	g := NewEdgeCaseGenerator()
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"How do I reset a git branch?", "how to reset a git branch"},
+		{"what is the borrow checker", "borrow checker explained"},
+		{"AWS Azure Google Cloud pricing comparison 2024", "AWS Azure Google Cloud cost comparison 2024"},
+		{"quantum entanglement", "what should I know about quantum entanglement"},
+	}
+	for _, tt := range tests {
+		cases := g.GenerateQueryPerturbations(tt.query, PerturbParaphrase)
+		if len(cases) != 1 || cases[0].Query != tt.want {
+			t.Errorf("paraphrase(%q) = %+v, want %q", tt.query, cases, tt.want)
+		}
+	}
+}
+
+func TestGenerateQueryPerturbations_SkipsNoOpVariants(t *testing.T) {
+	g := NewEdgeCaseGenerator()
+	cases := g.GenerateQueryPerturbations("42", PerturbTypo, PerturbReorder, PerturbCase)
+	if len(cases) != 0 {
+		t.Errorf("expected no variants for a one-word numeric query, got %+v", cases)
+	}
+	if got := g.GenerateQueryPerturbations("   "); got != nil {
+		t.Errorf("expected nil for blank query, got %+v", got)
+	}
+}
+
+func TestParsePerturbationKinds(t *testing.T) {
+	kinds, err := ParsePerturbationKinds("typo, case,typo")
+	if err != nil || len(kinds) != 2 || kinds[0] != PerturbTypo || kinds[1] != PerturbCase {
+		t.Errorf("ParsePerturbationKinds() = %v, %v", kinds, err)
+	}
+	if kinds, _ := ParsePerturbationKinds("all"); len(kinds) != len(AllPerturbationKinds) {
+		t.Errorf("all should select every kind, got %v", kinds)
+	}
+	if kinds, err := ParsePerturbationKinds(""); err != nil || kinds != nil {
+		t.Errorf("empty value should select nothing, got %v, %v", kinds, err)
+	}
+	if _, err := ParsePerturbationKinds("typo,synonym"); err == nil {
+		t.Error("expected error for unknown perturbation")
+	}
+}
+
+func sortedJoin(words []string) string {
+	sorted := append([]string(nil), words...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+// Status is the lifecycle state of a run.
+type Status string
+
+// Run statuses.
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// Finished reports whether the run has reached a final status.
+func (s Status) Finished() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCanceled
+}
+
+// runFile is the run metadata file kept in each run directory.
+const runFile = "run.json"
+
+// Run describes a submitted benchmark run.
+type Run struct {
+	ID         string     `json:"id"`
+	Status     Status     `json:"status"`
+	Config     string     `json:"config"` // config name, or "inline"
+	Providers  []string   `json:"providers"`
+	Mode       string     `json:"mode"`
+	Repeats    int        `json:"repeats"`
+	Tests      int        `json:"tests"`
+	Total      int        `json:"total"`
+	Completed  int        `json:"completed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Reports    []string   `json:"reports,omitempty"` // report formats available for download
+}
+
+// run is the server-side state of a Run.
+type run struct {
+	dir       string
+	cfg       *config.Config
+	providers []providers.Provider
+	opts      evaluator.RunnerOptions
+	saveMu    sync.Mutex
+
+	mu          sync.Mutex
+	info        Run
+	ctx         context.Context
+	cancel      context.CancelFunc
+	events      []event
+	subscribers map[chan struct{}]struct{}
+}
+
+// event is a server-sent event.
+type event struct {
+	name string
+	data []byte
+}
+
+func newRunID() string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(b)
+}
+
+func newRun(ctx context.Context, dir string, info Run, cfg *config.Config, provs []providers.Provider, opts evaluator.RunnerOptions) *run {
+	r := &run{dir: dir, cfg: cfg, providers: provs, opts: opts, info: info, subscribers: make(map[chan struct{}]struct{})}
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	return r
+}
+
+// snapshot returns a copy of the run's description.
+func (r *run) snapshot() Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := r.info
+	info.Providers = append([]string(nil), r.info.Providers...)
+	info.Reports = append([]string(nil), r.info.Reports...)
+	return info
+}
+
+// update applies fn to the run's description, publishes a status event and
+// persists the run.
+func (r *run) update(fn func(*Run)) {
+	r.mu.Lock()
+	fn(&r.info)
+	info := r.info
+	if data, err := json.Marshal(info); err == nil {
+		r.publishLocked("status", data)
+	}
+	r.mu.Unlock()
+
+	// Save the latest state rather than info, which a concurrent update may
+	// already have superseded.
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+	if err := saveRun(r.dir, r.snapshot()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save run %s: %v\n", info.ID, err)
+	}
+}
+
+// publish records an event and wakes the run's subscribers.
+func (r *run) publish(name string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.publishLocked(name, data)
+}
+
+func (r *run) publishLocked(name string, data []byte) {
+	r.events = append(r.events, event{name: name, data: data})
+	for ch := range r.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel signalled when new events are published, and
+// a function to stop the subscription.
+func (r *run) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	r.mu.Lock()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+	return ch, func() {
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		r.mu.Unlock()
+	}
+}
+
+// eventsFrom returns the events published after the first n, and whether
+// the run has finished.
+func (r *run) eventsFrom(n int) ([]event, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []event
+	if n < len(r.events) {
+		out = append(out, r.events[n:]...)
+	}
+	return out, r.info.Status.Finished()
+}
+
+func saveRun(dir string, info Run) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, runFile), data, 0600)
+}
+
+// loadRuns reads the runs recorded under outputDir. Runs that were still
+// queued or running belong to a previous server process and are marked
+// failed.
+func loadRuns(outputDir string) ([]Run, error) {
+	entries, err := os.ReadDir(outputDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+	}
+	var runs []Run
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(outputDir, entry.Name())
+		// #nosec G304 - path is built from a directory listing of the output dir
+		data, err := os.ReadFile(filepath.Join(dir, runFile))
+		if err != nil {
+			continue
+		}
+		var info Run
+		if err := json.Unmarshal(data, &info); err != nil || info.ID != entry.Name() {
+			continue
+		}
+		if !info.Status.Finished() {
+			info.Status = StatusFailed
+			info.Error = "interrupted by a server restart"
+			_ = saveRun(dir, info)
+		}
+		runs = append(runs, info)
+	}
+	return runs, nil
+}
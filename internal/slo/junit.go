@@ -0,0 +1,125 @@
+package slo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// WriteJUnit writes the cases as JUnit XML with one test suite per provider
+// and one test case per test × provider.
+func WriteJUnit(w io.Writer, cases []Case) error {
+	doc := junitSuites{Name: "SanityWebEval"}
+	suites := make(map[string]int)
+	var durations []time.Duration // per suite
+	var total time.Duration
+	for _, c := range cases {
+		i, ok := suites[c.Provider]
+		if !ok {
+			i = len(doc.Suites)
+			suites[c.Provider] = i
+			doc.Suites = append(doc.Suites, junitSuite{Name: c.Provider})
+			durations = append(durations, 0)
+		}
+		suite := &doc.Suites[i]
+		tc := junitCase{
+			Name:      c.Test,
+			ClassName: c.Provider + "." + c.TestType,
+			Time:      seconds(c.Duration),
+		}
+		switch {
+		case c.Failed():
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d assertion(s) violated", len(c.Failures)),
+				Type:    "assertion",
+				Text:    strings.Join(c.Failures, "\n"),
+			}
+			suite.Failures++
+		case c.Skipped:
+			tc.Skipped = &junitSkipped{Message: c.SkipReason}
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+		durations[i] += c.Duration
+		total += c.Duration
+	}
+
+	for i := range doc.Suites {
+		doc.Suites[i].Time = seconds(durations[i])
+		doc.Tests += doc.Suites[i].Tests
+		doc.Failures += doc.Suites[i].Failures
+		doc.Skipped += doc.Suites[i].Skipped
+	}
+	doc.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJUnitFile writes the cases as JUnit XML to path.
+func WriteJUnitFile(path string, cases []Case) error {
+	// #nosec G304 - path comes from the -junit flag or the output directory
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	if err := WriteJUnit(f, cases); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
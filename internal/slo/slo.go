@@ -0,0 +1,122 @@
+// Package slo checks benchmark results against the per-test assertions in
+// the config and reports the outcome as JUnit XML for CI.
+package slo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+)
+
+// Case is the outcome of the assertions for one test × provider, over all of
+// its repeats.
+type Case struct {
+	Test     string
+	TestType string
+	Provider string
+	Runs     int
+	// Skipped is set when every run was skipped, e.g. because the provider
+	// does not support the operation.
+	Skipped    bool
+	SkipReason string
+	Failures   []string
+	Duration   time.Duration // total latency of the runs
+}
+
+// Failed reports whether any assertion was violated.
+func (c Case) Failed() bool {
+	return len(c.Failures) > 0
+}
+
+// Evaluate checks every test × provider in results against the assertions
+// that apply to it. Cases follow the order of the results.
+func Evaluate(cfg *config.Config, results []benchmetrics.Result) []Case {
+	tests := make(map[string]config.TestConfig, len(cfg.Tests))
+	for _, test := range cfg.Tests {
+		if _, exists := tests[test.Name]; !exists {
+			tests[test.Name] = test
+		}
+	}
+
+	var cases []*Case
+	byKey := make(map[string]*Case)
+	for _, r := range results {
+		key := r.TestName + "\xff" + r.Provider
+		c, ok := byKey[key]
+		if !ok {
+			c = &Case{Test: r.TestName, TestType: r.TestType, Provider: r.Provider, Skipped: true}
+			byKey[key] = c
+			cases = append(cases, c)
+		}
+		c.Runs++
+		c.Duration += r.Latency
+		if r.Skipped {
+			c.SkipReason = r.SkipReason
+			continue
+		}
+		c.Skipped = false
+		providerType := r.ProviderType
+		if providerType == "" {
+			providerType = r.Provider
+		}
+		assertions := cfg.AssertionsFor(tests[r.TestName], r.Provider, providerType)
+		c.Failures = append(c.Failures, check(assertions, r)...)
+	}
+
+	out := make([]Case, 0, len(cases))
+	for _, c := range cases {
+		if !c.Skipped {
+			c.SkipReason = ""
+		}
+		out = append(out, *c)
+	}
+	return out
+}
+
+// check returns the assertions one run violates.
+func check(a config.Assertions, r benchmetrics.Result) []string {
+	if a.IsZero() {
+		return nil
+	}
+	run := fmt.Sprintf("run %d", max(r.Repeat, 1))
+	if !r.Success {
+		if a.MustSucceed != nil && !*a.MustSucceed {
+			return nil
+		}
+		return []string{fmt.Sprintf("must_succeed: %s failed: %s", run, strings.TrimSpace(r.Error))}
+	}
+
+	var failures []string
+	if a.MaxLatencyMS != nil && r.Latency.Milliseconds() > *a.MaxLatencyMS {
+		failures = append(failures, fmt.Sprintf("max_latency_ms: %s took %dms (limit %dms)", run, r.Latency.Milliseconds(), *a.MaxLatencyMS))
+	}
+	if a.MinQualityScore != nil {
+		switch {
+		case !r.QualityScored:
+			failures = append(failures, fmt.Sprintf("min_quality_score: %s has no quality score", run))
+		case r.QualityScore < *a.MinQualityScore:
+			failures = append(failures, fmt.Sprintf("min_quality_score: %s scored %.1f (minimum %.1f)", run, r.QualityScore, *a.MinQualityScore))
+		}
+	}
+	if a.MinResults != nil && r.ResultsCount < *a.MinResults {
+		failures = append(failures, fmt.Sprintf("min_results: %s returned %d (minimum %d)", run, r.ResultsCount, *a.MinResults))
+	}
+	if a.MaxCostUSD != nil && r.CostUSD > *a.MaxCostUSD {
+		failures = append(failures, fmt.Sprintf("max_cost_usd: %s cost $%.4f (limit $%.4f)", run, r.CostUSD, *a.MaxCostUSD))
+	}
+	return failures
+}
+
+// Failures counts the cases with violated assertions.
+func Failures(cases []Case) int {
+	n := 0
+	for _, c := range cases {
+		if c.Failed() {
+			n++
+		}
+	}
+	return n
+}
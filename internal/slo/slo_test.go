@@ -0,0 +1,160 @@
+package slo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+)
+
+const assertionsConfig = `
+[assertions]
+max_latency_ms = 1000
+
+[[tests]]
+name = "Search"
+type = "search"
+query = "rust"
+
+[tests.assertions]
+min_results = 3
+min_quality_score = 50
+max_cost_usd = 0.01
+
+[tests.assertions.providers.slow]
+max_latency_ms = 5000
+
+[[tests]]
+name = "Crawl"
+type = "crawl"
+url = "https://example.com"
+
+[tests.assertions]
+must_succeed = false
+`
+
+func loadConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Parse([]byte(assertionsConfig), t.TempDir())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return cfg
+}
+
+func searchResult(provider string, repeat int, latency time.Duration, results int, quality float64) benchmetrics.Result {
+	return benchmetrics.Result{
+		TestName:      "Search",
+		TestType:      "search",
+		Provider:      provider,
+		Repeat:        repeat,
+		Success:       true,
+		Latency:       latency,
+		ResultsCount:  results,
+		QualityScore:  quality,
+		QualityScored: true,
+		CostUSD:       0.005,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	cfg := loadConfig(t)
+	results := []benchmetrics.Result{
+		searchResult("fast", 1, 200*time.Millisecond, 5, 80),
+		searchResult("fast", 2, 300*time.Millisecond, 5, 90),
+		searchResult("slow", 1, 3*time.Second, 5, 70),
+		searchResult("weak", 1, 1500*time.Millisecond, 1, 20),
+		{TestName: "Search", TestType: "search", Provider: "broken", Repeat: 1, Error: "HTTP 500"},
+		{TestName: "Crawl", TestType: "crawl", Provider: "fast", Repeat: 1, Skipped: true, SkipReason: "unsupported"},
+		{TestName: "Crawl", TestType: "crawl", Provider: "broken", Repeat: 1, Error: "timeout"},
+	}
+
+	cases := Evaluate(cfg, results)
+	if len(cases) != 6 {
+		t.Fatalf("expected 6 cases, got %d: %+v", len(cases), cases)
+	}
+	byName := make(map[string]Case)
+	for _, c := range cases {
+		byName[c.Test+"/"+c.Provider] = c
+	}
+
+	if c := byName["Search/fast"]; c.Failed() || c.Runs != 2 || c.Duration != 500*time.Millisecond {
+		t.Errorf("expected passing fast search over 2 runs, got %+v", c)
+	}
+	if c := byName["Search/slow"]; c.Failed() {
+		t.Errorf("expected the provider latency override to apply, got %+v", c.Failures)
+	}
+	weak := byName["Search/weak"]
+	if len(weak.Failures) != 3 {
+		t.Fatalf("expected latency, quality and result failures, got %v", weak.Failures)
+	}
+	for i, prefix := range []string{"max_latency_ms: run 1 took 1500ms", "min_quality_score: run 1 scored 20.0", "min_results: run 1 returned 1"} {
+		if !strings.HasPrefix(weak.Failures[i], prefix) {
+			t.Errorf("failure %d: expected prefix %q, got %q", i, prefix, weak.Failures[i])
+		}
+	}
+	if c := byName["Search/broken"]; len(c.Failures) != 1 || !strings.Contains(c.Failures[0], "run 1 failed: HTTP 500") {
+		t.Errorf("expected failed run to violate the assertions, got %v", c.Failures)
+	}
+	if c := byName["Crawl/fast"]; !c.Skipped || c.SkipReason != "unsupported" || c.Failed() {
+		t.Errorf("expected skipped crawl case, got %+v", c)
+	}
+	if c := byName["Crawl/broken"]; c.Failed() {
+		t.Errorf("expected must_succeed = false to tolerate the failed run, got %v", c.Failures)
+	}
+	if got := Failures(cases); got != 2 {
+		t.Errorf("expected 2 failed cases, got %d", got)
+	}
+}
+
+func TestEvaluate_NoAssertions(t *testing.T) {
+	cfg, err := config.Parse([]byte("[[tests]]\nname = \"Search\"\ntype = \"search\"\nquery = \"rust\"\n"), "")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	cases := Evaluate(cfg, []benchmetrics.Result{{TestName: "Search", TestType: "search", Provider: "exa", Error: "HTTP 500"}})
+	if len(cases) != 1 || cases[0].Failed() {
+		t.Errorf("expected a passing case without assertions, got %+v", cases)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	cases := []Case{
+		{Test: "Search", TestType: "search", Provider: "exa", Runs: 1, Duration: 1500 * time.Millisecond},
+		{Test: "Crawl", TestType: "crawl", Provider: "exa", Runs: 1, Skipped: true, SkipReason: "unsupported"},
+		{Test: "Search", TestType: "search", Provider: "tavily", Runs: 1, Duration: 2 * time.Second, Failures: []string{"min_results: run 1 returned 0 (minimum 3)", "max_latency_ms: run 1 took 2000ms (limit 1000ms)"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, cases); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Errorf("expected XML header, got %q", out[:40])
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, out)
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Skipped != 1 || doc.Time != "3.500" {
+		t.Errorf("unexpected totals: %+v", doc)
+	}
+	if len(doc.Suites) != 2 || doc.Suites[0].Name != "exa" || doc.Suites[0].Tests != 2 || doc.Suites[1].Time != "2.000" {
+		t.Fatalf("unexpected suites: %+v", doc.Suites)
+	}
+	failed := doc.Suites[1].Cases[0]
+	if failed.ClassName != "tavily.search" || failed.Failure == nil || failed.Failure.Message != "2 assertion(s) violated" {
+		t.Errorf("unexpected failed case: %+v", failed)
+	}
+	if !strings.Contains(failed.Failure.Text, "min_results: run 1 returned 0") {
+		t.Errorf("expected failure details, got %q", failed.Failure.Text)
+	}
+	if skipped := doc.Suites[0].Cases[1]; skipped.Skipped == nil || skipped.Skipped.Message != "unsupported" {
+		t.Errorf("unexpected skipped case: %+v", skipped)
+	}
+}
@@ -0,0 +1,282 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultServiceName = "sanitywebeval"
+	defaultTimeout     = 10 * time.Second
+	scopeName          = "github.com/lamim/SanityWebEval"
+)
+
+// Config configures the OTLP/HTTP JSON exporter.
+type Config struct {
+	// Endpoint is the full traces URL, e.g. http://localhost:4318/v1/traces.
+	Endpoint           string
+	Headers            map[string]string
+	ServiceName        string
+	ResourceAttributes map[string]string
+	Timeout            time.Duration
+}
+
+// ConfigFromEnv reads the exporter configuration from the standard OTEL_*
+// variables. Tracing is enabled when OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or
+// OTEL_EXPORTER_OTLP_ENDPOINT is set, unless OTEL_SDK_DISABLED=true or
+// OTEL_TRACES_EXPORTER=none. Only the http/json protocol is supported.
+func ConfigFromEnv() (Config, bool, error) {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("OTEL_SDK_DISABLED")), "true") {
+		return Config{}, false, nil
+	}
+	switch exporter := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_TRACES_EXPORTER"))); exporter {
+	case "", "otlp":
+	case "none":
+		return Config{}, false, nil
+	default:
+		return Config{}, false, fmt.Errorf("unsupported OTEL_TRACES_EXPORTER %q (supported: otlp, none)", exporter)
+	}
+
+	cfg := Config{ServiceName: defaultServiceName, Timeout: defaultTimeout}
+	if endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")); endpoint != "" {
+		cfg.Endpoint = endpoint
+	} else if endpoint := strings.TrimSpace(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")); endpoint != "" {
+		cfg.Endpoint = strings.TrimRight(endpoint, "/") + "/v1/traces"
+	} else {
+		return Config{}, false, nil
+	}
+	if u, err := url.Parse(cfg.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Config{}, false, fmt.Errorf("invalid OTLP traces endpoint %q: must be an http(s) URL", cfg.Endpoint)
+	}
+
+	protocol := firstEnv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL")
+	if protocol != "" && protocol != "http/json" {
+		return Config{}, false, fmt.Errorf("unsupported OTLP protocol %q: only http/json is supported", protocol)
+	}
+
+	var err error
+	if cfg.Headers, err = parseKeyValues(firstEnv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "OTEL_EXPORTER_OTLP_HEADERS")); err != nil {
+		return Config{}, false, fmt.Errorf("invalid OTLP headers: %w", err)
+	}
+	if cfg.ResourceAttributes, err = parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES")); err != nil {
+		return Config{}, false, fmt.Errorf("invalid OTEL_RESOURCE_ATTRIBUTES: %w", err)
+	}
+	if name := strings.TrimSpace(os.Getenv("OTEL_SERVICE_NAME")); name != "" {
+		cfg.ServiceName = name
+	} else if name := cfg.ResourceAttributes["service.name"]; name != "" {
+		cfg.ServiceName = name
+	}
+	if raw := firstEnv("OTEL_EXPORTER_OTLP_TRACES_TIMEOUT", "OTEL_EXPORTER_OTLP_TIMEOUT"); raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			return Config{}, false, fmt.Errorf("invalid OTLP timeout %q: expected milliseconds", raw)
+		}
+		cfg.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	return cfg, true, nil
+}
+
+func firstEnv(keys ...string) string {
+	for _, key := range keys {
+		if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseKeyValues parses the OTEL "key1=value1,key2=value2" list format with
+// URL-encoded values.
+func parseKeyValues(value string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", part)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		out[key] = decoded
+	}
+	return out, nil
+}
+
+type exporter struct {
+	cfg    Config
+	client *http.Client
+}
+
+func newExporter(cfg Config) *exporter {
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	return &exporter{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (e *exporter) export(ctx context.Context, batch []*Span) error {
+	payload, err := json.Marshal(e.request(batch))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req) //nolint:gosec // Endpoint comes from the user's OTLP configuration
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// OTLP JSON encoding of ExportTraceServiceRequest. IDs are hex strings and
+// 64-bit integers are decimal strings, as the OTLP/JSON mapping requires.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *exporter) request(batch []*Span) otlpRequest {
+	resource := map[string]string{"service.name": e.cfg.ServiceName}
+	for key, value := range e.cfg.ResourceAttributes {
+		if key != "service.name" {
+			resource[key] = value
+		}
+	}
+	keys := make([]string, 0, len(resource))
+	for key := range resource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	resourceAttrs := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		resourceAttrs = append(resourceAttrs, otlpAttribute(String(key, resource[key])))
+	}
+
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, s.otlp())
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resourceAttrs},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: scopeName}, Spans: spans}},
+	}}}
+}
+
+func (s *Span) otlp() otlpSpan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := otlpSpan{
+		TraceID:           s.traceID,
+		SpanID:            s.spanID,
+		ParentSpanID:      s.parentID,
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Status:            otlpStatus{Code: s.statusCode, Message: s.statusMsg},
+	}
+	for _, attr := range s.attrs {
+		out.Attributes = append(out.Attributes, otlpAttribute(attr))
+	}
+	return out
+}
+
+func otlpAttribute(attr Attribute) otlpKeyValue {
+	kv := otlpKeyValue{Key: attr.Key}
+	switch v := attr.Value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case int:
+		s := strconv.Itoa(v)
+		kv.Value.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
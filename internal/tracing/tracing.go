@@ -0,0 +1,272 @@
+// Package tracing emits OpenTelemetry spans for benchmark runs over OTLP/HTTP
+// with JSON encoding, configured through the standard OTEL_* environment
+// variables. A nil *Tracer and nil *Span are valid and do nothing, so callers
+// need no checks when tracing is off.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Span kinds, as numbered by OTLP.
+const (
+	KindInternal = 1
+	KindClient   = 3
+)
+
+// Span status codes, as numbered by OTLP.
+const (
+	statusOK    = 1
+	statusError = 2
+)
+
+// batchSize is the number of ended spans exported together.
+const batchSize = 256
+
+// Attribute is a span attribute. Value is a string, bool, int, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Float64 returns a floating-point attribute.
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Tracer records spans and exports them in batches.
+type Tracer struct {
+	exporter *exporter
+
+	mu      sync.Mutex
+	pending []*Span
+	errs    []error
+}
+
+// New creates a tracer exporting to cfg.Endpoint.
+func New(cfg Config) *Tracer {
+	return &Tracer{exporter: newExporter(cfg)}
+}
+
+// FromEnv creates a tracer from the OTEL_* environment variables, or returns
+// nil when no OTLP endpoint is configured or the SDK is disabled.
+func FromEnv() (*Tracer, error) {
+	cfg, enabled, err := ConfigFromEnv()
+	if err != nil || !enabled {
+		return nil, err
+	}
+	return New(cfg), nil
+}
+
+// Endpoint returns the traces endpoint spans are exported to.
+func (t *Tracer) Endpoint() string {
+	if t == nil {
+		return ""
+	}
+	return t.exporter.cfg.Endpoint
+}
+
+// Start begins a root span, or a child of the span in ctx.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   KindInternal,
+		start:  time.Now(),
+		attrs:  append([]Attribute(nil), attrs...),
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		span.traceID = newID(16)
+	}
+	span.spanID = newID(8)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Start begins a child of the span in ctx. Without one, tracing is off for
+// this context and a nil span is returned.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, attrs...)
+}
+
+// StartClient begins a client span for an outgoing request; see Start.
+func StartClient(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	ctx, span := Start(ctx, name, attrs...)
+	if span != nil {
+		span.kind = KindClient
+	}
+	return ctx, span
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the active span in ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Shutdown exports the remaining spans and returns any export errors.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	batch := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	t.export(ctx, batch)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.errs) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("failed to export %d span batch(es): %w", len(t.errs), t.errs[0])
+	t.errs = nil
+	return err
+}
+
+func (t *Tracer) finish(span *Span) {
+	t.mu.Lock()
+	t.pending = append(t.pending, span)
+	var batch []*Span
+	if len(t.pending) >= batchSize {
+		batch = t.pending
+		t.pending = nil
+	}
+	t.mu.Unlock()
+	if batch != nil {
+		t.export(context.Background(), batch)
+	}
+}
+
+func (t *Tracer) export(ctx context.Context, batch []*Span) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := t.exporter.export(ctx, batch); err != nil {
+		t.mu.Lock()
+		t.errs = append(t.errs, err)
+		t.mu.Unlock()
+	}
+}
+
+// Span is a timed operation within a trace.
+type Span struct {
+	tracer   *Tracer
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	kind     int
+
+	mu         sync.Mutex
+	start      time.Time
+	end        time.Time
+	attrs      []Attribute
+	statusCode int
+	statusMsg  string
+	ended      bool
+}
+
+// SetAttributes adds attributes, replacing earlier values for the same keys.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		replaced := false
+		for i := range s.attrs {
+			if s.attrs[i].Key == attr.Key {
+				s.attrs[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attrs = append(s.attrs, attr)
+		}
+	}
+}
+
+// SetError marks the span as failed with a message.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusCode = statusError
+	s.statusMsg = message
+}
+
+// SetOK marks the span as successful.
+func (s *Span) SetOK() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusCode = statusOK
+	s.statusMsg = ""
+}
+
+// End ends the span and queues it for export. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.finish(s)
+}
+
+// TraceID returns the span's trace ID in hex, or "" for a nil span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.traceID
+}
+
+func newID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to time.
+		now := time.Now().UnixNano()
+		for i := range b {
+			b[i] = byte(now >> (8 * (i % 8)))
+		}
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/tracing/tracingtest"
+)
+
+func TestTracer_ExportsSpanTree(t *testing.T) {
+	collector := tracingtest.NewCollector(t)
+	tracer := New(Config{
+		Endpoint:           collector.URL,
+		Headers:            map[string]string{"Authorization": "Bearer token"},
+		ResourceAttributes: map[string]string{"deployment.environment": "ci"},
+	})
+
+	ctx, root := tracer.Start(context.Background(), "benchmark run", Int("bench.tests", 2))
+	childCtx, child := Start(ctx, "test search", String("bench.provider", "exa"))
+	_, attempt := StartClient(childCtx, "POST", String("http.request.method", "POST"))
+	attempt.SetAttributes(Int("http.response.status_code", 503), Int("http.response.status_code", 200))
+	attempt.End()
+	child.SetAttributes(Float64("bench.quality.score", 87.5), Bool("bench.success", false))
+	child.SetError("timeout")
+	child.End()
+	child.End()
+	root.SetOK()
+	root.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	spans := collector.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	if got := collector.Headers()[0].Get("Authorization"); got != "Bearer token" {
+		t.Errorf("expected configured header on export, got %q", got)
+	}
+
+	byName := make(map[string]tracingtest.Span)
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+	run, test, http := byName["benchmark run"], byName["test search"], byName["POST"]
+	if run.ParentSpanID != "" || len(run.TraceID) != 32 || len(run.SpanID) != 16 {
+		t.Errorf("unexpected root span IDs: %+v", run)
+	}
+	if test.TraceID != run.TraceID || test.ParentSpanID != run.SpanID || http.ParentSpanID != test.SpanID {
+		t.Error("expected run > test > HTTP attempt to share a trace and nest")
+	}
+	if run.StatusCode != statusOK || test.StatusCode != statusError || test.StatusMessage != "timeout" {
+		t.Errorf("unexpected statuses: run %d, test %d %q", run.StatusCode, test.StatusCode, test.StatusMessage)
+	}
+	if http.Kind != KindClient || test.Kind != KindInternal {
+		t.Errorf("unexpected kinds: http %d, test %d", http.Kind, test.Kind)
+	}
+	if run.Attributes["bench.tests"] != int64(2) || test.Attributes["bench.provider"] != "exa" ||
+		test.Attributes["bench.quality.score"] != 87.5 || test.Attributes["bench.success"] != false {
+		t.Errorf("unexpected attributes: run %v, test %v", run.Attributes, test.Attributes)
+	}
+	if http.Attributes["http.response.status_code"] != int64(200) {
+		t.Errorf("expected replaced status code attribute, got %v", http.Attributes)
+	}
+	if run.Resource["service.name"] != defaultServiceName || run.Resource["deployment.environment"] != "ci" {
+		t.Errorf("unexpected resource: %v", run.Resource)
+	}
+}
+
+func TestTracer_NilIsNoop(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "run")
+	if span != nil || SpanFromContext(ctx) != nil {
+		t.Fatal("expected nil tracer to start no span")
+	}
+	_, child := Start(ctx, "test")
+	child.SetAttributes(String("k", "v"))
+	child.SetError("boom")
+	child.End()
+	if child != nil || child.TraceID() != "" {
+		t.Error("expected no span without a parent in context")
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("expected nil tracer shutdown to succeed, got %v", err)
+	}
+}
+
+func TestTracer_ShutdownReportsExportErrors(t *testing.T) {
+	tracer := New(Config{Endpoint: "http://127.0.0.1:1/v1/traces", Timeout: time.Second})
+	_, span := tracer.Start(context.Background(), "run")
+	span.End()
+	if err := tracer.Shutdown(context.Background()); err == nil {
+		t.Fatal("expected export error for an unreachable collector")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	for _, key := range []string{
+		"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_PROTOCOL", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_TRACES_HEADERS",
+		"OTEL_EXPORTER_OTLP_TRACES_TIMEOUT", "OTEL_EXPORTER_OTLP_TIMEOUT", "OTEL_RESOURCE_ATTRIBUTES",
+	} {
+		t.Setenv(key, "")
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if _, enabled, err := ConfigFromEnv(); enabled || err != nil {
+		t.Fatalf("expected tracing off without an endpoint, got enabled=%v err=%v", enabled, err)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=abc%20def,x-team=search")
+	t.Setenv("OTEL_SERVICE_NAME", "bench-ci")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "2500")
+	cfg, enabled, err := ConfigFromEnv()
+	if err != nil || !enabled {
+		t.Fatalf("expected tracing enabled, got enabled=%v err=%v", enabled, err)
+	}
+	if cfg.Endpoint != "http://collector:4318/v1/traces" {
+		t.Errorf("Endpoint = %q", cfg.Endpoint)
+	}
+	if cfg.Headers["api-key"] != "abc def" || cfg.Headers["x-team"] != "search" {
+		t.Errorf("unexpected headers: %v", cfg.Headers)
+	}
+	if cfg.ServiceName != "bench-ci" || cfg.Timeout != 2500*time.Millisecond {
+		t.Errorf("unexpected service name %q or timeout %s", cfg.ServiceName, cfg.Timeout)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://traces.example.com/custom")
+	if cfg, _, _ := ConfigFromEnv(); cfg.Endpoint != "https://traces.example.com/custom" {
+		t.Errorf("expected signal-specific endpoint to win, got %q", cfg.Endpoint)
+	}
+
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	if _, enabled, _ := ConfigFromEnv(); enabled {
+		t.Error("expected OTEL_TRACES_EXPORTER=none to disable tracing")
+	}
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	if _, enabled, _ := ConfigFromEnv(); enabled {
+		t.Error("expected OTEL_SDK_DISABLED=true to disable tracing")
+	}
+	t.Setenv("OTEL_SDK_DISABLED", "")
+
+	for key, value := range map[string]string{
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+		"OTEL_TRACES_EXPORTER":        "zipkin",
+		"OTEL_EXPORTER_OTLP_TIMEOUT":  "soon",
+		"OTEL_RESOURCE_ATTRIBUTES":    "novalue",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			if _, _, err := ConfigFromEnv(); err == nil {
+				t.Errorf("expected error for %s=%s", key, value)
+			}
+		})
+	}
+}
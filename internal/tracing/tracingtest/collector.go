@@ -0,0 +1,160 @@
+// Package tracingtest provides a local OTLP/HTTP JSON collector stand-in for
+// tests that exercise tracing.
+package tracingtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/lamim/SanityWebEval/internal/providers/testutil"
+)
+
+// Span is a span as received by the collector. Attribute values are strings,
+// bools, int64s or float64s.
+type Span struct {
+	Resource      map[string]interface{}
+	TraceID       string
+	SpanID        string
+	ParentSpanID  string
+	Name          string
+	Kind          int
+	StatusCode    int
+	StatusMessage string
+	Attributes    map[string]interface{}
+}
+
+// Collector accepts OTLP trace export requests and records their spans.
+type Collector struct {
+	URL string
+
+	mu      sync.Mutex
+	spans   []Span
+	headers []http.Header
+}
+
+// NewCollector starts a collector on 127.0.0.1. Its URL is the traces
+// endpoint to export to.
+func NewCollector(t testing.TB) *Collector {
+	t.Helper()
+	c := &Collector{}
+	server := testutil.NewIPv4Server(t, http.HandlerFunc(c.handle))
+	c.URL = server.URL + "/v1/traces"
+	return c
+}
+
+// Spans returns the spans received so far.
+func (c *Collector) Spans() []Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Span(nil), c.spans...)
+}
+
+// Named returns the received spans with the given name.
+func (c *Collector) Named(name string) []Span {
+	var out []Span
+	for _, span := range c.Spans() {
+		if span.Name == name {
+			out = append(out, span)
+		}
+	}
+	return out
+}
+
+// Headers returns the headers of each export request received.
+func (c *Collector) Headers() []http.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]http.Header(nil), c.headers...)
+}
+
+type exportRequest struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []keyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []struct {
+			Spans []struct {
+				TraceID      string     `json:"traceId"`
+				SpanID       string     `json:"spanId"`
+				ParentSpanID string     `json:"parentSpanId"`
+				Name         string     `json:"name"`
+				Kind         int        `json:"kind"`
+				Attributes   []keyValue `json:"attributes"`
+				Status       struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"status"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string  `json:"stringValue"`
+		BoolValue   *bool    `json:"boolValue"`
+		IntValue    *string  `json:"intValue"`
+		DoubleValue *float64 `json:"doubleValue"`
+	} `json:"value"`
+}
+
+func (c *Collector) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "expected POST with application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var spans []Span
+	for _, rs := range req.ResourceSpans {
+		resource := attributes(rs.Resource.Attributes)
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				spans = append(spans, Span{
+					Resource:      resource,
+					TraceID:       s.TraceID,
+					SpanID:        s.SpanID,
+					ParentSpanID:  s.ParentSpanID,
+					Name:          s.Name,
+					Kind:          s.Kind,
+					StatusCode:    s.Status.Code,
+					StatusMessage: s.Status.Message,
+					Attributes:    attributes(s.Attributes),
+				})
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.spans = append(c.spans, spans...)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("{}"))
+}
+
+func attributes(kvs []keyValue) map[string]interface{} {
+	out := make(map[string]interface{}, len(kvs))
+	for _, kv := range kvs {
+		switch {
+		case kv.Value.StringValue != nil:
+			out[kv.Key] = *kv.Value.StringValue
+		case kv.Value.BoolValue != nil:
+			out[kv.Key] = *kv.Value.BoolValue
+		case kv.Value.IntValue != nil:
+			n, _ := strconv.ParseInt(*kv.Value.IntValue, 10, 64)
+			out[kv.Key] = n
+		case kv.Value.DoubleValue != nil:
+			out[kv.Key] = *kv.Value.DoubleValue
+		}
+	}
+	return out
+}
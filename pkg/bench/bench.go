@@ -0,0 +1,293 @@
+// Package bench is the public Go API of the benchmark engine. It runs tests
+// defined in code against built-in and in-house providers, streams results
+// as tests finish and writes the same reports as the bench command.
+//
+// The exported types are versioned by APIVersion: within a version, fields
+// and functions are only added, never removed or changed in meaning.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/benchmetrics"
+	"github.com/lamim/SanityWebEval/internal/config"
+	"github.com/lamim/SanityWebEval/internal/evaluator"
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/report"
+)
+
+// APIVersion is the version of the types in this package.
+const APIVersion = "v1"
+
+// Mode is the benchmark execution mode.
+type Mode string
+
+// Modes.
+const (
+	// ModeNormalized uses mostly comparable settings across providers.
+	ModeNormalized Mode = "normalized"
+	// ModeNative lets each provider use its optimized native settings.
+	ModeNative Mode = "native"
+)
+
+// Options configure a run. Zero values use the bench command's defaults.
+type Options struct {
+	Mode    Mode // default ModeNormalized
+	Repeats int  // default 1
+	// Concurrency limits how many tests run at once (default 5).
+	Concurrency int
+	// ProviderConcurrency limits concurrent tests per provider name.
+	ProviderConcurrency map[string]int
+	// Timeout bounds each test (default 30s).
+	Timeout time.Duration
+	// FixturesDir holds documents served locally for fixture:// test URLs.
+	FixturesDir string
+	// PricingFile is a pricing profile TOML file that overrides the
+	// built-in pay-as-you-go rates for the providers it lists.
+	PricingFile string
+}
+
+// Result is the outcome of one test against one provider.
+type Result struct {
+	Test          string        `json:"test"`
+	Provider      string        `json:"provider"`
+	ProviderType  string        `json:"provider_type,omitempty"`
+	Type          TestType      `json:"type"`
+	Mode          Mode          `json:"mode"`
+	Repeat        int           `json:"repeat"`
+	Success       bool          `json:"success"`
+	Skipped       bool          `json:"skipped,omitempty"`
+	SkipReason    string        `json:"skip_reason,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	ErrorCategory string        `json:"error_category,omitempty"`
+	Latency       time.Duration `json:"latency"`
+	CreditsUsed   int           `json:"credits_used"`
+	RequestCount  int           `json:"request_count"`
+	CostUSD       float64       `json:"cost_usd"`
+	ResultsCount  int           `json:"results_count"`
+	ContentLength int           `json:"content_length"`
+	// QualityScore (0-100) is set when QualityScored is.
+	QualityScore  float64   `json:"quality_score,omitempty"`
+	QualityScored bool      `json:"quality_scored,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func resultFrom(r benchmetrics.Result) Result {
+	return Result{
+		Test:          r.TestName,
+		Provider:      r.Provider,
+		ProviderType:  r.ProviderType,
+		Type:          TestType(r.TestType),
+		Mode:          Mode(r.RunMode),
+		Repeat:        r.Repeat,
+		Success:       r.Success,
+		Skipped:       r.Skipped,
+		SkipReason:    r.SkipReason,
+		Error:         r.Error,
+		ErrorCategory: r.ErrorCategory,
+		Latency:       r.Latency,
+		CreditsUsed:   r.CreditsUsed,
+		RequestCount:  r.RequestCount,
+		CostUSD:       r.CostUSD,
+		ResultsCount:  r.ResultsCount,
+		ContentLength: r.ContentLength,
+		QualityScore:  r.QualityScore,
+		QualityScored: r.QualityScored,
+		Timestamp:     r.Timestamp,
+	}
+}
+
+// Results holds the results of a finished run.
+type Results struct {
+	APIVersion string   `json:"api_version"`
+	Results    []Result `json:"results"`
+
+	collector *benchmetrics.Collector
+}
+
+// ReportFormat is a report file format.
+type ReportFormat string
+
+// Report formats. ReportCSV and ReportParquet write the flat raw results
+// (results.csv, results.parquet) rather than a report.
+const (
+	ReportMarkdown ReportFormat = "md"
+	ReportJSON     ReportFormat = "json"
+	ReportHTML     ReportFormat = "html"
+	ReportCSV      ReportFormat = "csv"
+	ReportParquet  ReportFormat = "parquet"
+)
+
+// WriteReports writes the given formats to dir, which must exist. With
+// no formats it writes the Markdown, JSON and HTML reports.
+func (r *Results) WriteReports(dir string, formats ...ReportFormat) error {
+	gen := report.NewGenerator(r.collector, dir)
+	if len(formats) == 0 {
+		return gen.GenerateAll()
+	}
+	for _, format := range formats {
+		var err error
+		switch format {
+		case ReportMarkdown:
+			err = gen.GenerateMarkdown()
+		case ReportJSON:
+			err = gen.GenerateJSON()
+		case ReportHTML:
+			err = gen.GenerateHTML()
+		case ReportCSV:
+			err = gen.GenerateCSV()
+		case ReportParquet:
+			err = gen.GenerateParquet()
+		default:
+			return fmt.Errorf("unknown report format: %s", format)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate %s report: %w", format, err)
+		}
+	}
+	return nil
+}
+
+// Session is a run in progress.
+type Session struct {
+	results chan Result
+	done    chan struct{}
+	out     *Results
+	err     error
+}
+
+// Start validates the tests and options and runs every test against every
+// provider in the background.
+func Start(ctx context.Context, tests []Test, provs []Provider, opts Options) (*Session, error) {
+	cfg, runnerOpts, err := prepare(tests, provs, opts)
+	if err != nil {
+		return nil, err
+	}
+	engineProvs := make([]providers.Provider, len(provs))
+	for i, prov := range provs {
+		engineProvs[i] = toEngine(prov)
+	}
+
+	// The channel holds every result, so a caller that never reads it does
+	// not hold up the run.
+	s := &Session{
+		results: make(chan Result, len(tests)*len(provs)*runnerOpts.Repeats),
+		done:    make(chan struct{}),
+	}
+	runnerOpts.OnEvent = func(e evaluator.Event) {
+		if e.Type == evaluator.EventTestCompleted && e.Result != nil {
+			s.results <- resultFrom(*e.Result)
+		}
+	}
+	runner := evaluator.NewRunner(cfg, engineProvs, nil, nil, nil, runnerOpts)
+	go func() {
+		defer close(s.done)
+		defer close(s.results)
+		if s.err = runner.Run(ctx); s.err == nil {
+			s.err = ctx.Err()
+		}
+		collector := runner.GetCollector()
+		s.out = &Results{APIVersion: APIVersion, collector: collector}
+		for _, r := range collector.GetResults() {
+			s.out.Results = append(s.out.Results, resultFrom(r))
+		}
+	}()
+	return s, nil
+}
+
+// Results streams each result as its test finishes. The channel is closed
+// when the run ends.
+func (s *Session) Results() <-chan Result {
+	return s.results
+}
+
+// Wait blocks until the run ends and returns its results. When ctx was
+// canceled it returns the results gathered so far with the context's error.
+func (s *Session) Wait() (*Results, error) {
+	<-s.done
+	return s.out, s.err
+}
+
+// Run runs every test against every provider and returns the results.
+func Run(ctx context.Context, tests []Test, provs []Provider, opts Options) (*Results, error) {
+	s, err := Start(ctx, tests, provs, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.Wait()
+}
+
+// prepare builds and validates the runner configuration for a run.
+func prepare(tests []Test, provs []Provider, opts Options) (*config.Config, evaluator.RunnerOptions, error) {
+	runnerOpts := evaluator.DefaultRunnerOptions()
+	if err := validateProviders(provs); err != nil {
+		return nil, runnerOpts, err
+	}
+
+	switch opts.Mode {
+	case "":
+	case ModeNormalized, ModeNative:
+		runnerOpts.Mode = providers.RunMode(opts.Mode)
+	default:
+		return nil, runnerOpts, fmt.Errorf("invalid mode: %s (valid: normalized, native)", opts.Mode)
+	}
+	if opts.Repeats < 0 {
+		return nil, runnerOpts, fmt.Errorf("repeats must be >= 0")
+	}
+	if opts.Repeats > 0 {
+		runnerOpts.Repeats = opts.Repeats
+	}
+	if opts.Timeout < 0 {
+		return nil, runnerOpts, fmt.Errorf("timeout must not be negative")
+	}
+
+	cfg := &config.Config{
+		General: config.GeneralConfig{
+			Concurrency:         opts.Concurrency,
+			ProviderConcurrency: opts.ProviderConcurrency,
+			FixturesDir:         opts.FixturesDir,
+		},
+		Pricing: config.PricingConfig{File: opts.PricingFile},
+	}
+	if opts.Timeout > 0 {
+		cfg.General.Timeout = opts.Timeout.String()
+	}
+	for _, test := range tests {
+		cfg.Tests = append(cfg.Tests, test.config())
+	}
+	if err := cfg.Prepare(""); err != nil {
+		return nil, runnerOpts, err
+	}
+	pricing, err := cfg.Pricing.Profile()
+	if err != nil {
+		return nil, runnerOpts, err
+	}
+	runnerOpts.Pricing = &pricing
+	return cfg, runnerOpts, nil
+}
+
+// validateProviders checks that there are providers and that their names,
+// which key the per-provider limits and results, are set and unique.
+func validateProviders(provs []Provider) error {
+	if len(provs) == 0 {
+		return fmt.Errorf("no providers to run")
+	}
+	seen := make(map[string]bool, len(provs))
+	for _, prov := range provs {
+		if prov == nil {
+			return fmt.Errorf("provider must not be nil")
+		}
+		name := strings.ToLower(strings.TrimSpace(prov.Name()))
+		if name == "" {
+			return fmt.Errorf("provider name must not be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate provider name: %s", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// inHouseProvider is a search-only provider defined outside the engine.
+type inHouseProvider struct {
+	name string
+	fail bool
+}
+
+func (p *inHouseProvider) Name() string { return p.name }
+
+func (p *inHouseProvider) Capabilities() Capabilities {
+	return Capabilities{Search: SupportNative}
+}
+
+func (p *inHouseProvider) Search(_ context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	if p.fail {
+		return nil, errors.New("backend unavailable")
+	}
+	items := []SearchItem{
+		{Title: "Go", URL: "https://go.dev/", Content: "The Go programming language " + query},
+		{Title: "Tour", URL: "https://go.dev/tour/", Content: "A tour of Go"},
+	}
+	if opts.MaxResults > 0 && len(items) > opts.MaxResults {
+		items = items[:opts.MaxResults]
+	}
+	return &SearchResult{Query: query, Results: items, Usage: Usage{CreditsUsed: 1, RequestCount: 1}}, nil
+}
+
+func (p *inHouseProvider) Extract(context.Context, string, ExtractOptions) (*ExtractResult, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *inHouseProvider) Crawl(context.Context, string, CrawlOptions) (*CrawlResult, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestRun_InHouseProvider(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("InHouse", func(s ProviderSettings) (Provider, error) {
+		return &inHouseProvider{name: s.Name}, nil
+	}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	provs, err := registry.Providers("inhouse")
+	if err != nil {
+		t.Fatalf("Providers failed: %v", err)
+	}
+	if provs[0].Name() != "inhouse" {
+		t.Errorf("expected the registered name as instance name, got %q", provs[0].Name())
+	}
+
+	search := SearchTest("Go search", "golang")
+	search.ExpectedURLs = []string{"https://go.dev/"}
+	tests := []Test{search, ExtractTest("Go docs", "https://go.dev/doc/")}
+
+	session, err := Start(context.Background(), tests, provs, Options{Repeats: 2, Mode: ModeNative})
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	var streamed []Result
+	for r := range session.Results() {
+		streamed = append(streamed, r)
+	}
+	results, err := session.Wait()
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(streamed) != 4 || len(results.Results) != 4 {
+		t.Fatalf("expected 4 streamed and 4 final results, got %d and %d", len(streamed), len(results.Results))
+	}
+	if results.APIVersion != APIVersion {
+		t.Errorf("expected API version %s, got %s", APIVersion, results.APIVersion)
+	}
+	for _, r := range results.Results {
+		if r.Provider != "inhouse" || r.Mode != ModeNative {
+			t.Errorf("unexpected result identity: %+v", r)
+		}
+		switch r.Type {
+		case TestSearch:
+			if !r.Success || r.ResultsCount != 2 || r.CreditsUsed != 1 || !r.QualityScored {
+				t.Errorf("unexpected search result: %+v", r)
+			}
+		case TestExtract:
+			if !r.Skipped {
+				t.Errorf("expected unsupported extract to be skipped: %+v", r)
+			}
+		default:
+			t.Errorf("unexpected test type %q", r.Type)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := results.WriteReports(dir, ReportJSON, ReportMarkdown, ReportCSV); err != nil {
+		t.Fatalf("WriteReports failed: %v", err)
+	}
+	for _, name := range []string{"report.json", "report.md", "results.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.html")); !os.IsNotExist(err) {
+		t.Errorf("expected no HTML report, got %v", err)
+	}
+	if err := results.WriteReports(dir, "pdf"); err == nil {
+		t.Error("expected an error for an unknown report format")
+	}
+}
+
+func TestRun_ProviderErrorsBecomeFailedResults(t *testing.T) {
+	results, err := Run(context.Background(), []Test{SearchTest("Search", "golang")}, []Provider{&inHouseProvider{name: "down", fail: true}}, Options{})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results.Results))
+	}
+	if r := results.Results[0]; r.Success || !strings.Contains(r.Error, "backend unavailable") {
+		t.Errorf("expected failed result with the provider error, got %+v", r)
+	}
+}
+
+func TestRun_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := Run(ctx, []Test{SearchTest("Search", "golang")}, []Provider{&inHouseProvider{name: "stub"}}, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if results == nil {
+		t.Error("expected the partial results of a canceled run")
+	}
+}
+
+func TestStart_RejectsInvalidInput(t *testing.T) {
+	stub := &inHouseProvider{name: "stub"}
+	valid := []Test{SearchTest("Search", "golang")}
+	cases := []struct {
+		name  string
+		tests []Test
+		provs []Provider
+		opts  Options
+		want  string
+	}{
+		{"no providers", valid, nil, Options{}, "no providers"},
+		{"duplicate providers", valid, []Provider{stub, &inHouseProvider{name: "STUB"}}, Options{}, "duplicate provider"},
+		{"no tests", nil, []Provider{stub}, Options{}, "no tests"},
+		{"search without query", []Test{{Name: "Search", Type: TestSearch}}, []Provider{stub}, Options{}, "requires a query"},
+		{"unknown type", []Test{{Name: "Map", Type: "map", URL: "https://go.dev"}}, []Provider{stub}, Options{}, "invalid type"},
+		{"invalid mode", valid, []Provider{stub}, Options{Mode: "fast"}, "invalid mode"},
+		{"missing pricing file", valid, []Provider{stub}, Options{PricingFile: filepath.Join(t.TempDir(), "missing.toml")}, "pricing"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Start(context.Background(), tc.tests, tc.provs, tc.opts)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestTestConfig_CrawlLimits(t *testing.T) {
+	crawl := CrawlTest("Crawl", "https://go.dev")
+	if tc := crawl.config(); tc.MaxPages != nil || tc.MaxDepth != nil {
+		t.Errorf("expected default crawl limits, got %v %v", tc.MaxPages, tc.MaxDepth)
+	}
+	crawl.MaxPages, crawl.MaxDepth = 3, 1
+	if tc := crawl.config(); tc.MaxPages == nil || *tc.MaxPages != 3 || tc.MaxDepth == nil || *tc.MaxDepth != 1 {
+		t.Errorf("expected crawl limits 3/1, got %+v", tc)
+	}
+}
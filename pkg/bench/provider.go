@@ -0,0 +1,423 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+	"github.com/lamim/SanityWebEval/internal/providers/brave"
+	"github.com/lamim/SanityWebEval/internal/providers/exa"
+	"github.com/lamim/SanityWebEval/internal/providers/firecrawl"
+	"github.com/lamim/SanityWebEval/internal/providers/jina"
+	"github.com/lamim/SanityWebEval/internal/providers/local"
+	"github.com/lamim/SanityWebEval/internal/providers/mixedbread"
+	"github.com/lamim/SanityWebEval/internal/providers/tavily"
+)
+
+// Support describes how a provider implements an operation.
+type Support string
+
+// Support levels.
+const (
+	SupportNative      Support = "native"
+	SupportEmulated    Support = "emulated"
+	SupportUnsupported Support = "unsupported"
+)
+
+// Capabilities describes provider support by operation. Empty fields mean
+// the operation is unsupported.
+type Capabilities struct {
+	Search  Support
+	Extract Support
+	Crawl   Support
+}
+
+// Provider is a search, extract and crawl backend under test. The runner
+// only calls the operations its Capabilities support; the others produce
+// skipped results.
+type Provider interface {
+	Name() string
+	Capabilities() Capabilities
+	Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error)
+	Extract(ctx context.Context, url string, opts ExtractOptions) (*ExtractResult, error)
+	Crawl(ctx context.Context, url string, opts CrawlOptions) (*CrawlResult, error)
+}
+
+// SearchOptions contains options for search operations.
+type SearchOptions struct {
+	MaxResults     int
+	SearchDepth    string // basic, advanced
+	IncludeImages  bool
+	IncludeAnswer  bool
+	TimeRange      string // day, week, month, year
+	IncludeDomains []string
+	ExcludeDomains []string
+	Country        string // ISO 3166-1 alpha-2, e.g. "us", "de"
+	Language       string // ISO 639-1, e.g. "en", "ja"
+	SafeSearch     string // off, moderate, strict
+}
+
+// ExtractOptions contains options for extract operations.
+type ExtractOptions struct {
+	Format          string // markdown, html, text
+	IncludeMetadata bool
+}
+
+// CrawlOptions contains options for crawl operations.
+type CrawlOptions struct {
+	MaxPages     int
+	MaxDepth     int
+	ExcludePaths []string
+}
+
+// Usage is the cost and timing a provider reports for one operation.
+type Usage struct {
+	Latency      time.Duration
+	CreditsUsed  int
+	RequestCount int
+	// UsageReported indicates credits came from provider usage metadata
+	// rather than an estimate.
+	UsageReported bool
+}
+
+// SearchResult is the result of a search operation.
+type SearchResult struct {
+	Query   string
+	Results []SearchItem
+	// TotalResults is the result count reported; zero counts Results.
+	TotalResults int
+	Usage
+}
+
+// SearchItem is a single search result.
+type SearchItem struct {
+	Title       string
+	URL         string
+	Content     string
+	Score       float64
+	PublishedAt *time.Time
+}
+
+// ExtractResult is the result of a content extraction operation.
+type ExtractResult struct {
+	URL      string
+	Title    string
+	Content  string
+	Markdown string
+	Metadata map[string]interface{}
+	Usage
+}
+
+// CrawlResult is the result of a crawl operation.
+type CrawlResult struct {
+	URL   string
+	Pages []CrawledPage
+	// TotalPages is the page count reported; zero counts Pages.
+	TotalPages int
+	Usage
+}
+
+// CrawledPage is a single page from a crawl.
+type CrawledPage struct {
+	URL      string
+	Title    string
+	Content  string
+	Markdown string
+}
+
+// ProviderSettings adjusts how a provider reaches its API. Zero values keep
+// the provider's defaults. Built-in providers also read <NAME>_* environment
+// variables, which take precedence, and their API key from the environment.
+type ProviderSettings struct {
+	// Name is the instance name reported by Name(); empty uses the
+	// registered name.
+	Name      string
+	BaseURL   string
+	Timeout   time.Duration
+	Headers   map[string]string
+	Proxy     string
+	APIKeyEnv string // environment variable holding the API key
+}
+
+// Factory creates a provider.
+type Factory func(settings ProviderSettings) (Provider, error)
+
+// Registry maps provider names to factories. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry creates a registry holding the built-in providers: brave, exa,
+// firecrawl, jina, local, mixedbread and tavily.
+func NewRegistry() *Registry {
+	return &Registry{factories: map[string]Factory{
+		"brave":      builtin(brave.NewClientWithSettings),
+		"exa":        builtin(exa.NewClientWithSettings),
+		"firecrawl":  builtin(firecrawl.NewClientWithSettings),
+		"jina":       builtin(jina.NewClientWithSettings),
+		"local":      builtin(local.NewClientWithSettings),
+		"mixedbread": builtin(mixedbread.NewClientWithSettings),
+		"tavily":     builtin(tavily.NewClientWithSettings),
+	}}
+}
+
+// Register adds a provider under name. Names are case-insensitive and must
+// not already be registered.
+func (r *Registry) Register(name string, factory Factory) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return fmt.Errorf("provider name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("provider %s has no factory", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.factories[key]; exists {
+		return fmt.Errorf("provider %s is already registered", key)
+	}
+	r.factories[key] = factory
+	return nil
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the provider registered under name.
+func (r *Registry) New(name string, settings ProviderSettings) (Provider, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	r.mu.RLock()
+	factory, ok := r.factories[key]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+	if strings.TrimSpace(settings.Name) == "" {
+		settings.Name = key
+	}
+	prov, err := factory(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize %s: %w", key, err)
+	}
+	return prov, nil
+}
+
+// Providers creates the named providers with default settings.
+func (r *Registry) Providers(names ...string) ([]Provider, error) {
+	provs := make([]Provider, 0, len(names))
+	for _, name := range names {
+		prov, err := r.New(name, ProviderSettings{})
+		if err != nil {
+			return nil, err
+		}
+		provs = append(provs, prov)
+	}
+	return provs, nil
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a provider to the default registry.
+func Register(name string, factory Factory) error {
+	return defaultRegistry.Register(name, factory)
+}
+
+// NewProvider creates a provider from the default registry.
+func NewProvider(name string, settings ProviderSettings) (Provider, error) {
+	return defaultRegistry.New(name, settings)
+}
+
+// builtin adapts a built-in client constructor to a Factory.
+func builtin[C providers.Provider](constructor func(providers.Settings) (C, error)) Factory {
+	return func(settings ProviderSettings) (Provider, error) {
+		client, err := constructor(settings.engine())
+		if err != nil {
+			return nil, err
+		}
+		return builtinProvider{client}, nil
+	}
+}
+
+func (s ProviderSettings) engine() providers.Settings {
+	settings := providers.Settings{
+		Name:      s.Name,
+		Timeout:   s.Timeout,
+		Headers:   s.Headers,
+		Proxy:     s.Proxy,
+		APIKeyEnv: s.APIKeyEnv,
+	}
+	if s.BaseURL != "" {
+		settings.BaseURLs = map[string]string{providers.EndpointAPI: s.BaseURL}
+	}
+	return settings
+}
+
+// builtinProvider exposes a built-in provider through the public interface.
+// The runner uses the wrapped provider directly so it keeps its native
+// structured extraction and instance type.
+type builtinProvider struct {
+	p providers.Provider
+}
+
+func (b builtinProvider) Name() string { return b.p.Name() }
+
+func (b builtinProvider) Capabilities() Capabilities {
+	caps := b.p.Capabilities()
+	return Capabilities{Search: Support(caps.Search), Extract: Support(caps.Extract), Crawl: Support(caps.Crawl)}
+}
+
+func (b builtinProvider) Search(ctx context.Context, query string, opts SearchOptions) (*SearchResult, error) {
+	res, err := b.p.Search(ctx, query, providers.SearchOptions(opts))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	out := &SearchResult{Query: res.Query, TotalResults: res.TotalResults, Usage: usage(res.Latency, res.CreditsUsed, res.RequestCount, res.UsageReported)}
+	for _, item := range res.Results {
+		out.Results = append(out.Results, SearchItem(item))
+	}
+	return out, nil
+}
+
+func (b builtinProvider) Extract(ctx context.Context, url string, opts ExtractOptions) (*ExtractResult, error) {
+	res, err := b.p.Extract(ctx, url, providers.ExtractOptions(opts))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return &ExtractResult{
+		URL:      res.URL,
+		Title:    res.Title,
+		Content:  res.Content,
+		Markdown: res.Markdown,
+		Metadata: res.Metadata,
+		Usage:    usage(res.Latency, res.CreditsUsed, res.RequestCount, res.UsageReported),
+	}, nil
+}
+
+func (b builtinProvider) Crawl(ctx context.Context, url string, opts CrawlOptions) (*CrawlResult, error) {
+	res, err := b.p.Crawl(ctx, url, providers.CrawlOptions(opts))
+	if err != nil || res == nil {
+		return nil, err
+	}
+	out := &CrawlResult{URL: res.URL, TotalPages: res.TotalPages, Usage: usage(res.Latency, res.CreditsUsed, res.RequestCount, res.UsageReported)}
+	for _, page := range res.Pages {
+		out.Pages = append(out.Pages, CrawledPage(page))
+	}
+	return out, nil
+}
+
+func usage(latency time.Duration, credits, requests int, reported bool) Usage {
+	return Usage{Latency: latency, CreditsUsed: credits, RequestCount: requests, UsageReported: reported}
+}
+
+// engineProvider adapts a Provider to the runner's provider interface.
+type engineProvider struct {
+	p Provider
+}
+
+// toEngine returns the runner's view of p.
+func toEngine(p Provider) providers.Provider {
+	if b, ok := p.(builtinProvider); ok {
+		return b.p
+	}
+	return engineProvider{p}
+}
+
+func (e engineProvider) Name() string { return e.p.Name() }
+
+func (e engineProvider) Capabilities() providers.CapabilitySet {
+	caps := e.p.Capabilities()
+	return providers.CapabilitySet{
+		Search:  providers.SupportLevel(caps.Search),
+		Extract: providers.SupportLevel(caps.Extract),
+		Crawl:   providers.SupportLevel(caps.Crawl),
+	}
+}
+
+func (e engineProvider) SupportsOperation(opType string) bool {
+	return e.Capabilities().SupportsOperation(opType)
+}
+
+func (e engineProvider) Search(ctx context.Context, query string, opts providers.SearchOptions) (*providers.SearchResult, error) {
+	res, err := e.p.Search(ctx, query, SearchOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%s returned no search result", e.p.Name())
+	}
+	out := &providers.SearchResult{
+		Query:         res.Query,
+		TotalResults:  res.TotalResults,
+		Latency:       res.Latency,
+		CreditsUsed:   res.CreditsUsed,
+		RequestCount:  res.RequestCount,
+		UsageReported: res.UsageReported,
+	}
+	for _, item := range res.Results {
+		out.Results = append(out.Results, providers.SearchItem(item))
+	}
+	if out.TotalResults == 0 {
+		out.TotalResults = len(out.Results)
+	}
+	return out, nil
+}
+
+func (e engineProvider) Extract(ctx context.Context, url string, opts providers.ExtractOptions) (*providers.ExtractResult, error) {
+	res, err := e.p.Extract(ctx, url, ExtractOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%s returned no extract result", e.p.Name())
+	}
+	return &providers.ExtractResult{
+		URL:           res.URL,
+		Title:         res.Title,
+		Content:       res.Content,
+		Markdown:      res.Markdown,
+		Metadata:      res.Metadata,
+		Latency:       res.Latency,
+		CreditsUsed:   res.CreditsUsed,
+		RequestCount:  res.RequestCount,
+		UsageReported: res.UsageReported,
+	}, nil
+}
+
+func (e engineProvider) Crawl(ctx context.Context, url string, opts providers.CrawlOptions) (*providers.CrawlResult, error) {
+	res, err := e.p.Crawl(ctx, url, CrawlOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%s returned no crawl result", e.p.Name())
+	}
+	out := &providers.CrawlResult{
+		URL:           res.URL,
+		TotalPages:    res.TotalPages,
+		Latency:       res.Latency,
+		CreditsUsed:   res.CreditsUsed,
+		RequestCount:  res.RequestCount,
+		UsageReported: res.UsageReported,
+	}
+	for _, page := range res.Pages {
+		out.Pages = append(out.Pages, providers.CrawledPage(page))
+	}
+	if out.TotalPages == 0 {
+		out.TotalPages = len(out.Pages)
+	}
+	return out, nil
+}
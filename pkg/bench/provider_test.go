@@ -0,0 +1,98 @@
+package bench
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lamim/SanityWebEval/internal/providers"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+	factory := func(ProviderSettings) (Provider, error) { return &inHouseProvider{name: "custom"}, nil }
+
+	for _, tc := range []struct {
+		name    string
+		factory Factory
+		want    string
+	}{
+		{" ", factory, "must not be empty"},
+		{"custom", nil, "no factory"},
+		{"Tavily", factory, "already registered"},
+	} {
+		if err := registry.Register(tc.name, tc.factory); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Register(%q): expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+	if err := registry.Register("custom", factory); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := registry.Register("CUSTOM", factory); err == nil {
+		t.Error("expected names to be case-insensitive")
+	}
+
+	names := strings.Join(registry.Names(), ",")
+	if names != "brave,custom,exa,firecrawl,jina,local,mixedbread,tavily" {
+		t.Errorf("unexpected registered names: %s", names)
+	}
+	if _, err := registry.New("missing", ProviderSettings{}); err == nil || !strings.Contains(err.Error(), "available: brave") {
+		t.Errorf("expected unknown provider error listing the available ones, got %v", err)
+	}
+	// The default registry is separate.
+	if _, err := NewProvider("custom", ProviderSettings{}); err == nil {
+		t.Error("expected custom provider to be missing from the default registry")
+	}
+}
+
+func TestBuiltinProvider_UsedDirectlyByRunner(t *testing.T) {
+	prov, err := NewProvider("local", ProviderSettings{Name: "local-crawler"})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if prov.Name() != "local-crawler" {
+		t.Errorf("expected instance name, got %q", prov.Name())
+	}
+	if caps := prov.Capabilities(); caps.Extract != SupportNative || caps.Search != SupportUnsupported {
+		t.Errorf("unexpected local capabilities: %+v", caps)
+	}
+	engine := toEngine(prov)
+	if _, wrapped := engine.(engineProvider); wrapped {
+		t.Error("expected the runner to use the built-in provider unwrapped")
+	}
+	if providers.TypeOf(engine) != "local" {
+		t.Errorf("expected local provider type, got %q", providers.TypeOf(engine))
+	}
+
+	if _, err := NewProvider("local", ProviderSettings{BaseURL: "http://localhost:1"}); err == nil {
+		t.Error("expected an error for a base URL on a provider without an API")
+	}
+}
+
+func TestEngineProvider_ConvertsCalls(t *testing.T) {
+	engine := toEngine(&inHouseProvider{name: "stub"})
+	if !engine.SupportsOperation("search") || engine.SupportsOperation("crawl") {
+		t.Errorf("unexpected capabilities: %+v", engine.Capabilities())
+	}
+	res, err := engine.Search(context.Background(), "golang", providers.SearchOptions{MaxResults: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(res.Results) != 1 || res.Results[0].URL != "https://go.dev/" || res.CreditsUsed != 1 || res.RequestCount != 1 {
+		t.Errorf("unexpected converted result: %+v", res)
+	}
+
+	empty := toEngine(&nilResultProvider{})
+	if _, err := empty.Search(context.Background(), "golang", providers.SearchOptions{}); err == nil {
+		t.Error("expected an error for a provider returning no result")
+	}
+}
+
+// nilResultProvider returns neither a result nor an error.
+type nilResultProvider struct{ inHouseProvider }
+
+func (*nilResultProvider) Name() string { return "nil" }
+
+func (*nilResultProvider) Search(context.Context, string, SearchOptions) (*SearchResult, error) {
+	return nil, nil
+}
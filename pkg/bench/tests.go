@@ -0,0 +1,98 @@
+package bench
+
+import "github.com/lamim/SanityWebEval/internal/config"
+
+// TestType is the operation a test exercises.
+type TestType string
+
+// Test types.
+const (
+	TestSearch            TestType = "search"
+	TestExtract           TestType = "extract"
+	TestCrawl             TestType = "crawl"
+	TestStructuredExtract TestType = "structured_extract"
+)
+
+// Test is a benchmark test case: the programmatic form of a [[tests]] entry
+// in a config file. Ground-truth fields are optional and feed the quality
+// score.
+type Test struct {
+	Name  string
+	Type  TestType
+	Query string // search tests
+	URL   string // extract, crawl and structured_extract tests
+	// MaxPages and MaxDepth limit crawl tests; zero uses the defaults.
+	MaxPages int
+	MaxDepth int
+
+	ExpectedTopics      []string
+	ExpectedContent     []string
+	ExpectedURLs        []string
+	MustIncludeTerms    []string
+	MustNotIncludeTerms []string
+	// Relevance grades expected URLs or document IDs of search tests, higher
+	// being more relevant, and adds an nDCG metric.
+	Relevance map[string]int
+
+	// Search filters, passed to providers and checked for compliance.
+	TimeRange      string // day, week, month, year
+	IncludeDomains []string
+	ExcludeDomains []string
+	Country        string
+	Language       string
+	SafeSearch     string
+
+	// Structured extraction: a JSON Schema (as a JSON string), the object
+	// the provider is expected to return and an optional extraction prompt.
+	Schema           string
+	Expected         map[string]interface{}
+	ExtractionPrompt string
+}
+
+// SearchTest returns a search test for query.
+func SearchTest(name, query string) Test {
+	return Test{Name: name, Type: TestSearch, Query: query}
+}
+
+// ExtractTest returns an extract test for url.
+func ExtractTest(name, url string) Test {
+	return Test{Name: name, Type: TestExtract, URL: url}
+}
+
+// CrawlTest returns a crawl test starting at url.
+func CrawlTest(name, url string) Test {
+	return Test{Name: name, Type: TestCrawl, URL: url}
+}
+
+func (t Test) config() config.TestConfig {
+	tc := config.TestConfig{
+		Name:                t.Name,
+		Type:                string(t.Type),
+		Query:               t.Query,
+		URL:                 t.URL,
+		ExpectedTopics:      t.ExpectedTopics,
+		ExpectedContent:     t.ExpectedContent,
+		ExpectedURLs:        t.ExpectedURLs,
+		MustIncludeTerms:    t.MustIncludeTerms,
+		MustNotIncludeTerms: t.MustNotIncludeTerms,
+		Relevance:           t.Relevance,
+		TimeRange:           t.TimeRange,
+		IncludeDomains:      t.IncludeDomains,
+		ExcludeDomains:      t.ExcludeDomains,
+		Country:             t.Country,
+		Language:            t.Language,
+		SafeSearch:          t.SafeSearch,
+		Schema:              t.Schema,
+		Expected:            t.Expected,
+		ExtractionPrompt:    t.ExtractionPrompt,
+	}
+	if t.MaxPages > 0 {
+		maxPages := t.MaxPages
+		tc.MaxPages = &maxPages
+	}
+	if t.MaxDepth > 0 {
+		maxDepth := t.MaxDepth
+		tc.MaxDepth = &maxDepth
+	}
+	return tc
+}